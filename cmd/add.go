@@ -48,7 +48,7 @@ hand with "eos stop".`,
 				return helpers.ErrCommandFailed
 			}
 
-			printSelfDetachWarnings(cmd, config.Command)
+			printSelfDetachWarnings(cmd, config)
 
 			absPath, err := filepath.Abs(filepath.Dir(yamlFile))
 			if err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/Elysium-Labs-EU/eos/internal/database"
 	"github.com/Elysium-Labs-EU/eos/internal/manager"
 	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -108,7 +109,7 @@ func TestAddInvalidConfigRejected(t *testing.T) {
 	cmd := newTestRootCmd(manager)
 
 	testFile := testutil.NewTestServiceConfigFile(t, testutil.WithoutRuntime())
-	testFile.Command = ""
+	testFile.Command = types.ServiceCommand{}
 
 	yamlData, err := yaml.Marshal(testFile)
 	if err != nil {
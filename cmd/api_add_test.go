@@ -11,6 +11,7 @@ import (
 	"github.com/Elysium-Labs-EU/eos/internal/database"
 	"github.com/Elysium-Labs-EU/eos/internal/manager"
 	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
 )
 
 func TestAPIAddValidYaml(t *testing.T) {
@@ -43,7 +44,7 @@ func TestAPIAddInvalidConfigRejected(t *testing.T) {
 	cmd, _, errBuf, tempDir := setupAPICmd(t)
 
 	testFile := testutil.NewTestServiceConfigFile(t, testutil.WithoutRuntime())
-	testFile.Command = ""
+	testFile.Command = types.ServiceCommand{}
 	yamlPath := writeServiceFiles(t, tempDir, testFile)
 
 	cmd.SetArgs([]string{"api", "add", yamlPath})
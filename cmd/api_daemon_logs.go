@@ -44,7 +44,7 @@ func newAPIDaemonController(getConfig func() (string, *config.SystemConfig, user
 	if cfg == nil {
 		return nil, errors.New("getting config: got nil config")
 	}
-	return newDaemonController(cfg.Daemon, baseDir, &cfg.Health, cfg.Shutdown, cfg.Telemetry, cfg.UnderSystemd, identity)
+	return newDaemonController(cfg.Daemon, baseDir, cfg.ServicesDir, &cfg.Health, cfg.Shutdown, cfg.Telemetry, cfg.UnderSystemd, identity)
 }
 
 func newAPIDaemonLogsCmd(getConfig func() (string, *config.SystemConfig, userutil.Identity, error)) *cobra.Command {
@@ -54,7 +54,7 @@ Output schema (stdout, JSON):
     "log_path":      string|null      -- absolute path to the stdout log file
     "error_log_path":string|null      -- absolute path to the stderr log file
     "config": {
-      "command":  string  -- command used to start the service
+      "command":  string  -- command used to start the service ([]string for the exec form)
       "port":     int     -- port the service listens on (omitted if unset)
       "runtime": {
         "type": string    -- runtime identifier (e.g. "nodejs")
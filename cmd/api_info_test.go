@@ -288,7 +288,7 @@ func TestAPIInfoOnlyRegisteredServiceCommand(t *testing.T) {
 		t.Fatal("expected config to be present")
 		return
 	}
-	if result.Config.Command != "/home/user/start-script.sh" {
+	if result.Config.Command.Shell != "/home/user/start-script.sh" {
 		t.Errorf("expected command to be '/home/user/start-script.sh', got: %q", result.Config.Command)
 	}
 	if result.Config.Port != 1337 {
@@ -307,7 +307,7 @@ func TestAPIInfoOnlyRegisteredServiceIncompleteCommand(t *testing.T) {
 
 	yamlData, err := yaml.Marshal(&types.ServiceConfig{
 		Name:    "cms",
-		Command: "/home/user/start-script.sh",
+		Command: types.ServiceCommand{Shell: "/home/user/start-script.sh"},
 		Port:    1337,
 	})
 	if err != nil {
@@ -350,7 +350,7 @@ func TestAPIInfoOnlyRegisteredServiceIncompleteCommand(t *testing.T) {
 		t.Fatal("expected config to be present")
 		return
 	}
-	if result.Config.Command != "/home/user/start-script.sh" {
+	if result.Config.Command.Shell != "/home/user/start-script.sh" {
 		t.Errorf("expected command to be '/home/user/start-script.sh', got: %q", result.Config.Command)
 	}
 	if result.Config.Runtime.Type != "" {
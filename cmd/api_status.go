@@ -30,6 +30,9 @@ type apiStatusService struct {
 	OrphanedPGIDs []int `json:"orphaned_pgids,omitempty"`
 	PGID          int   `json:"pgid"`
 	RestartCount  int   `json:"restart_count"`
+	// RestartPending reports that the running launch's config differs from
+	// the service's config on disk (see eos diff).
+	RestartPending bool `json:"restart_pending,omitempty"`
 }
 
 type apiStatusResult struct {
@@ -126,6 +129,9 @@ func apiStatusBuildServiceEntry(ctx context.Context, mgr manager.ServiceManager,
 	// service blocked on depends_on has no process yet, so without this
 	// it's indistinguishable from one that was simply never started.
 	apiStatusApplyDependencyWait(ctx, mgr, reg.Name, &entry)
+	if entry.Status == types.ServiceStatusRunning {
+		entry.RestartPending = statusRestartPending(ctx, mgr, reg.Name)
+	}
 
 	return entry, nil
 }
@@ -69,7 +69,9 @@ Exit codes:
 			}
 			if config != nil {
 				result.Name = config.Name
-				result.Warnings = manager.DetectSelfDetachRisk(config.Command)
+				if manager.ServiceType(config.Type) != manager.ServiceTypeForking {
+					result.Warnings = manager.DetectSelfDetachRisk(config.Command)
+				}
 			}
 			if len(errs) > 0 {
 				result.Errors = make([]string, len(errs))
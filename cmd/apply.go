@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
+	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
+	"github.com/Elysium-Labs-EU/eos/internal/config"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCmd(getManager func() manager.ServiceManager, getConfig func() *config.SystemConfig) *cobra.Command {
+	var file string
+	var dryRun, prune bool
+	cmd := &cobra.Command{
+		Use:   cmdnames.Apply,
+		Short: "Reconcile the registered services with a stack manifest",
+		Long: `Bring the registered services in line with a stack manifest, eos.stack.yaml
+by default, which lists services by the path to their directory or
+service.yaml, or with their config inline.
+
+eos prints a plan first: services to add, catalog entries to update, running
+services whose config changed to restart, and stopped ones to start. It then
+carries it out, starting services after the ones they depends_on. With
+--prune, registered services the manifest doesn't list are stopped and
+removed. --dry-run prints the plan and changes nothing.
+
+An inline config is written to .eos.<name>.yaml in the service's directory,
+which the catalog entry points at.`,
+		Example: `  eos apply                          # apply ./eos.stack.yaml
+  eos apply -f stacks/prod.yaml --dry-run
+  eos apply --prune`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := manager.LoadStackManifest(file)
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("loading %s: %v", file, err))
+				return helpers.ErrCommandFailed
+			}
+			ordered, err := manager.StackStartOrder(entries)
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("ordering %s: %v", file, err))
+				return helpers.ErrCommandFailed
+			}
+
+			mgr := getManager()
+			plan, err := manager.PlanStack(cmd.Context(), mgr, ordered, prune)
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("planning: %v", err))
+				return helpers.ErrCommandFailed
+			}
+			printApplyPlan(cmd, file, plan)
+			if !plan.HasChanges() {
+				cmd.Printf(fmtLabelMsg, ui.LabelSuccess.Render("success"), "every service already matches the manifest")
+				return nil
+			}
+			if dryRun {
+				cmd.Printf(fmtLabelMsg, ui.LabelInfo.Render("info"), "dry run, nothing was changed")
+				return nil
+			}
+			// The services apply starts need supervising after it returns,
+			// which only the daemon does.
+			if _, local := mgr.(*manager.LocalManager); local {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "apply requires the standalone eos daemon")
+				cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run without"), ui.TextCommand.Render("--no-daemon"), ui.TextMuted.Render("to use the daemon"))
+				return helpers.ErrCommandFailed
+			}
+
+			gracePeriod := getConfig().Shutdown.GracePeriod
+			for i := range plan.Steps {
+				if err := applyStep(cmd, mgr, &plan.Steps[i], gracePeriod); err != nil {
+					cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("applying %s: %v", plan.Steps[i].Name, err))
+					cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(cmdnames.HintStatus), ui.TextMuted.Render("to see where it stopped"))
+					return helpers.ErrCommandFailed
+				}
+			}
+			cmd.Printf("\n"+fmtLabelMsg, ui.LabelSuccess.Render("success"), "applied "+file)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", manager.StackManifestFileName, "the stack manifest to apply")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without changing anything")
+	cmd.Flags().BoolVar(&prune, "prune", false, "stop and remove registered services the manifest doesn't list")
+
+	return cmd
+}
+
+func printApplyPlan(cmd *cobra.Command, file string, plan manager.StackPlan) {
+	cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("plan"), "for", ui.TextBold.Render(file))
+	for _, step := range plan.Steps {
+		label := ui.TextMuted.Render(fmt.Sprintf("%-9s", step.Action))
+		if step.Action != manager.StackActionUnchanged {
+			label = ui.LabelStep.Render(fmt.Sprintf("%-9s", step.Action))
+		}
+		cmd.Printf(fmtIndentLabelTwoMsgLn, label, ui.TextBold.Render(step.Name), ui.TextMuted.Render(applyStepDetail(&step)))
+		for _, change := range step.Changes {
+			cmd.Printf("      %s %s\n", ui.TextMuted.Render(change.Field+":"), diffValue(change.Old)+" -> "+diffValue(change.New))
+		}
+		if step.EnvChanged {
+			cmd.Printf("      %s %s\n", ui.TextMuted.Render("env files:"), "contents changed")
+		}
+	}
+	cmd.Println()
+}
+
+func applyStepDetail(step *manager.StackStep) string {
+	switch step.Action {
+	case manager.StackActionAdd:
+		return "from " + step.Entry.DirectoryPath
+	case manager.StackActionUpdate:
+		detail := "config changed"
+		if step.Repoint {
+			detail = "moved to " + step.Entry.DirectoryPath
+		}
+		if step.Running {
+			return detail + ", restarts it"
+		}
+		return detail + ", starts it"
+	case manager.StackActionRestart:
+		return "config changed since it was started"
+	case manager.StackActionStart:
+		return "not running"
+	case manager.StackActionPrune:
+		if step.Running {
+			return "not in the manifest, stops it"
+		}
+		return "not in the manifest"
+	}
+	return ""
+}
+
+// applyStep carries out one step of the plan.
+func applyStep(cmd *cobra.Command, mgr manager.ServiceManager, step *manager.StackStep, gracePeriod time.Duration) error {
+	ctx := cmd.Context()
+	switch step.Action {
+	case manager.StackActionPrune:
+		return applyPrune(cmd, mgr, step, gracePeriod)
+	case manager.StackActionAdd:
+		if err := manager.WriteStackConfig(step.Entry); err != nil {
+			return err
+		}
+		entry, err := manager.NewServiceCatalogEntry(step.Name, step.Entry.DirectoryPath, step.Entry.ConfigFileName)
+		if err != nil {
+			return fmt.Errorf("creating service catalog entry: %w", err)
+		}
+		if err := mgr.AddServiceCatalogEntry(ctx, entry); err != nil {
+			return fmt.Errorf("adding service catalog entry: %w", err)
+		}
+		return applyStart(cmd, mgr, step)
+	case manager.StackActionUpdate:
+		if err := manager.WriteStackConfig(step.Entry); err != nil {
+			return err
+		}
+		if step.Repoint {
+			if err := mgr.UpdateServiceCatalogEntry(ctx, step.Name, step.Entry.DirectoryPath, step.Entry.ConfigFileName); err != nil {
+				return fmt.Errorf("updating service: %w", err)
+			}
+		}
+		if step.Running {
+			return applyRestart(cmd, mgr, step, gracePeriod)
+		}
+		return applyStart(cmd, mgr, step)
+	case manager.StackActionRestart:
+		return applyRestart(cmd, mgr, step, gracePeriod)
+	case manager.StackActionStart:
+		return applyStart(cmd, mgr, step)
+	}
+	return nil
+}
+
+// applyStart starts step's service once its dependencies are ready, and
+// marks it enabled as eos run does.
+func applyStart(cmd *cobra.Command, mgr manager.ServiceManager, step *manager.StackStep) error {
+	if err := mgr.SetServiceEnabled(cmd.Context(), step.Name, true); err != nil {
+		return fmt.Errorf("persisting run state: %w", err)
+	}
+	entry := types.ServiceCatalogEntry{Name: step.Name, DirectoryPath: step.Entry.DirectoryPath, ConfigFileName: step.Entry.ConfigFileName}
+	if err := gateDependencies(cmd.Context(), cmd, mgr, &entry); err != nil {
+		return err
+	}
+	pgid, err := mgr.StartService(cmd.Context(), step.Name)
+	if errors.Is(err, manager.ErrAlreadyRunning) {
+		cmd.Printf(fmtLabelMsgLn, ui.LabelInfo.Render(string(step.Action)), ui.TextBold.Render(step.Name)+" is already running")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("starting service: %w", err)
+	}
+	cmd.Printf(fmtLabelMsgLn, ui.LabelSuccess.Render(string(step.Action)), fmt.Sprintf("%s started with PGID: %d", ui.TextBold.Render(step.Name), pgid))
+	return nil
+}
+
+func applyRestart(cmd *cobra.Command, mgr manager.ServiceManager, step *manager.StackStep, gracePeriod time.Duration) error {
+	pgid, err := mgr.RestartService(cmd.Context(), step.Name, gracePeriod, 200*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("restarting service: %w", err)
+	}
+	cmd.Printf(fmtLabelMsgLn, ui.LabelSuccess.Render(string(step.Action)), fmt.Sprintf("%s restarted with PGID: %d", ui.TextBold.Render(step.Name), pgid))
+	return nil
+}
+
+// applyPrune stops a service the manifest no longer lists and removes it
+// as eos stop and eos remove would.
+func applyPrune(cmd *cobra.Command, mgr manager.ServiceManager, step *manager.StackStep, gracePeriod time.Duration) error {
+	ctx := cmd.Context()
+	if step.Running {
+		if _, err := mgr.StopService(ctx, step.Name, gracePeriod, 200*time.Millisecond); err != nil {
+			return fmt.Errorf("stopping service: %w", err)
+		}
+	}
+	if _, err := mgr.RemoveServiceInstance(ctx, step.Name); err != nil && !errors.Is(err, manager.ErrServiceNotRunning) {
+		return fmt.Errorf("removing service instance: %w", err)
+	}
+	if err := applyRemoveCatalogEntry(ctx, mgr, step.Name); err != nil {
+		return err
+	}
+	cmd.Printf(fmtLabelMsgLn, ui.LabelSuccess.Render(string(step.Action)), ui.TextBold.Render(step.Name)+" removed")
+	return nil
+}
+
+func applyRemoveCatalogEntry(ctx context.Context, mgr manager.ServiceManager, name string) error {
+	removed, err := mgr.RemoveServiceCatalogEntry(ctx, name)
+	if err != nil {
+		return fmt.Errorf("removing service: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("%s could not be removed", name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+)
+
+func TestApplyCommandDryRunPrintsPlanWithoutChanges(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(mgr.WaitPipes)
+	cmd := newTestRootCmd(mgr)
+
+	serviceDir := filepath.Join(tempDir, "api")
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		t.Fatalf("could not create service directory: %v", err)
+	}
+	manifest := filepath.Join(tempDir, manager.StackManifestFileName)
+	data := "services:\n  - directory: api\n    config:\n      name: api\n      command: ./start.sh\n"
+	if err := os.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("could not write manifest: %v", err)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	cmd.SetArgs([]string{"apply", "-f", manifest, "--dry-run"})
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		t.Fatalf("apply --dry-run should not return an error, got: %v (stderr: %s)", err, errBuf.String())
+	}
+
+	output := outBuf.String()
+	for _, want := range []string{"add", "api", "dry run, nothing was changed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(serviceDir, ".eos.api.yaml")); !os.IsNotExist(err) {
+		t.Errorf("dry run should not write the inline config, stat returned: %v", err)
+	}
+	entries, err := mgr.GetAllServiceCatalogEntries(t.Context())
+	if err != nil {
+		t.Fatalf("could not list services: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("dry run should not register services, got %d", len(entries))
+	}
+}
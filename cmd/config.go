@@ -55,6 +55,8 @@ const configInitTemplate = `# eos daemon configuration (~/.eos/config.yaml)
 # log:
 #   maxFiles: {{.LogMaxFiles}}
 #   fileSizeLimitBytes: {{.LogFileSizeLimitBytes}}
+
+# services_dir: ""
 `
 
 func newConfigCmd() *cobra.Command {
@@ -62,7 +64,8 @@ func newConfigCmd() *cobra.Command {
 		Use:   cmdnames.Config,
 		Short: "Inspect and scaffold the eos daemon configuration",
 		Long: `View, scaffold, and validate ~/.eos/config.yaml — the daemon-wide settings for
-the log sink registry, telemetry export, health thresholds, and log rotation.
+the log sink registry, telemetry export, health thresholds, log rotation, and
+the drop-in services directory.
 
 This is distinct from service.yaml, which configures one registered service (see "eos init").`,
 	}
@@ -190,6 +193,13 @@ func printConfigShow(cmd *cobra.Command, path string, fileExists bool, cfg *conf
 	cmd.Printf(fmtHeading, ui.TextBold.Render("Log"))
 	cmd.Printf("  %s %d\n", ui.TextMuted.Render("max files:"), cfg.Log.MaxFiles)
 	cmd.Printf("  %s %d\n\n", ui.TextMuted.Render("file size limit bytes:"), cfg.Log.FileSizeLimitBytes)
+
+	cmd.Printf(fmtHeading, ui.TextBold.Render("Services dir"))
+	if cfg.ServicesDir == "" {
+		cmd.Printf(fmtIndentLabelMsg, ui.TextMuted.Render("path:"), "(none)")
+	} else {
+		cmd.Printf(fmtIndentLabelMsg, ui.TextMuted.Render("path:"), cfg.ServicesDir)
+	}
 }
 
 func sortedSinkNames(sinks map[string]types.LogSink) []string {
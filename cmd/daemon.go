@@ -37,6 +37,7 @@ type DaemonController interface {
 
 type standaloneDaemonController struct {
 	baseDir      string
+	servicesDir  string
 	identity     userutil.Identity
 	telemetry    config.TelemetryConfig
 	cfg          config.StandaloneDaemonConfig
@@ -51,9 +52,29 @@ func (c *standaloneDaemonController) Start(ctx context.Context, _ *cobra.Command
 	}
 	return process.StartStandaloneDaemon(ctx, process.StandaloneDaemonStartOptions{
 		BaseDir:             c.baseDir,
+		ServicesDir:         c.servicesDir,
 		LogToFileAndConsole: logToFileAndConsole,
 		Verbose:             verbose,
 		UnderSystemd:        c.underSystemd,
+		Identity:            c.identity,
+	}, &c.cfg, &c.health, c.shutdown, c.telemetry)
+}
+
+// pid1Starter is implemented by the DaemonControllers that can run the
+// daemon as a container's entrypoint (eos daemon start --pid1). Only the
+// standalone daemon can: systemd, OpenRC and launchd are init systems of
+// their own.
+type pid1Starter interface {
+	StartPID1(ctx context.Context, verbose bool) error
+}
+
+func (c *standaloneDaemonController) StartPID1(ctx context.Context, verbose bool) error {
+	return process.StartStandaloneDaemon(ctx, process.StandaloneDaemonStartOptions{
+		BaseDir:     c.baseDir,
+		ServicesDir: c.servicesDir,
+		Verbose:     verbose,
+		PID1:        true,
+		Identity:    c.identity,
 	}, &c.cfg, &c.health, c.shutdown, c.telemetry)
 }
 
@@ -557,11 +578,12 @@ func (c openrcDaemonController) Logs(cmd *cobra.Command, lines int, follow bool)
 	tailDaemonLogFile(cmd, c.baseDir, config.DaemonLogFileName, lines, follow)
 }
 
-func newDaemonController(cfg config.DaemonConfig, baseDir string, health *config.HealthConfig, shutdown config.ShutdownConfig, telemetry config.TelemetryConfig, underSystemd bool, identity userutil.Identity) (DaemonController, error) {
+func newDaemonController(cfg config.DaemonConfig, baseDir, servicesDir string, health *config.HealthConfig, shutdown config.ShutdownConfig, telemetry config.TelemetryConfig, underSystemd bool, identity userutil.Identity) (DaemonController, error) {
 	if cfg.Standalone != nil {
 		return &standaloneDaemonController{
 			cfg:          *cfg.Standalone,
 			baseDir:      baseDir,
+			servicesDir:  servicesDir,
 			health:       *health,
 			shutdown:     shutdown,
 			telemetry:    telemetry,
@@ -614,7 +636,9 @@ func daemonCmdBuildStartCmd(getCtrl func() DaemonController) *cobra.Command {
 If a systemd unit file is installed, delegates to "systemctl start eos" (requires root).
 If an OpenRC init script is installed, delegates to "rc-service eos start" (requires root).
 
-Otherwise, starts the daemon detached in the background by default; control returns once the PID file is written (timeout: 5s). --detach (-d) is accepted for backward compatibility but is now a no-op. Pass --foreground (-f) to run in the foreground and stream output to the console instead — Ctrl-C will then stop the daemon.`,
+Otherwise, starts the daemon detached in the background by default; control returns once the PID file is written (timeout: 5s). --detach (-d) is accepted for backward compatibility but is now a no-op. Pass --foreground (-f) to run in the foreground and stream output to the console instead — Ctrl-C will then stop the daemon.
+
+Pass --pid1 to run the daemon as a container's entrypoint (Linux only). It runs in the foreground, logs to stdout, reaps every orphaned process below it, and boots the registered services. SIGTERM or SIGINT stops the services in reverse dependency order. The daemon exits once every service is down: with 0 if they all stopped cleanly, 1 if any failed.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -624,6 +648,7 @@ Otherwise, starts the daemon detached in the background by default; control retu
 	startCmd.Flags().BoolP("foreground", "f", false, "run daemon in foreground and stream output (Ctrl-C stops it)")
 	startCmd.Flags().BoolP("detach", "d", false, "run daemon in background (default; kept for backward compatibility)")
 	startCmd.Flags().Bool(flagLogToFileAndConsole, false, "")
+	startCmd.Flags().Bool("pid1", false, "run as a container's entrypoint: foreground, logs to stdout, reaps orphans, exits when every service is down")
 	return startCmd
 }
 
@@ -635,6 +660,9 @@ func daemonCmdRunStart(cmd *cobra.Command, getCtrl func() DaemonController) erro
 	if err != nil {
 		return err
 	}
+	if pid1, _ := cmd.Flags().GetBool("pid1"); pid1 {
+		return daemonCmdRunStartPID1(cmd, ctrl, verbose)
+	}
 
 	daemonCmdPrintStarting(cmd, detach)
 
@@ -650,6 +678,30 @@ func daemonCmdRunStart(cmd *cobra.Command, getCtrl func() DaemonController) erro
 	return nil
 }
 
+// daemonCmdRunStartPID1 runs the daemon as a container's entrypoint until
+// every service is down. Its stdout is the daemon's log, so nothing else is
+// printed there.
+func daemonCmdRunStartPID1(cmd *cobra.Command, ctrl DaemonController, verbose bool) error {
+	if cmd.Flags().Changed("detach") {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "cannot use --pid1 and --detach together")
+		return helpers.ErrCommandFailed
+	}
+	starter, ok := ctrl.(pid1Starter)
+	if !ok {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "--pid1 needs the standalone daemon, not one managed by an init system")
+		return helpers.ErrCommandFailed
+	}
+	if err := starter.StartPID1(cmd.Context(), verbose); err != nil {
+		if errors.Is(err, process.ErrServicesFailed) {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("every service is down: %v", err))
+			return helpers.ErrCommandFailed
+		}
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("starting daemon: %v", err))
+		return helpers.ErrCommandFailed
+	}
+	return nil
+}
+
 // daemonCmdParseStartFlags reads and validates startCmd's flags, resolving
 // --foreground/--detach into a single detach decision.
 func daemonCmdParseStartFlags(cmd *cobra.Command) (detach bool, logToFileAndConsole bool, verbose bool, err error) {
@@ -810,7 +862,7 @@ func resolveDaemonControllerPreRun(cmd *cobra.Command, getConfig func() (string,
 		os.Exit(1)
 		return nil
 	}
-	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
+	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, systemConfig.ServicesDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
 	if err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("resolving daemon mode: %v", err))
 		os.Exit(1)
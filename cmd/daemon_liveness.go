@@ -274,7 +274,7 @@ func printDaemonDownBanner(cmd *cobra.Command, daemon *config.DaemonConfig) {
 // A local start never has a live daemon to compare against: refuseLocalWrite
 // already refuses any local write while one is reachable, so by the time
 // this runs the daemon (if configured at all) is confirmed unreachable.
-func warnCommandMightDivergeUnderDaemon(cmd *cobra.Command, mgr manager.ServiceManager, command string) {
+func warnCommandMightDivergeUnderDaemon(cmd *cobra.Command, mgr manager.ServiceManager, command types.ServiceCommand) {
 	if _, ok := mgr.(*manager.LocalManager); !ok {
 		return
 	}
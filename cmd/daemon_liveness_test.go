@@ -299,7 +299,7 @@ func TestWarnCommandMightDivergeUnderDaemon_LocalManagerResolvableCommand_Warns(
 	cmd := &cobra.Command{}
 	cmd.SetErr(&errBuf)
 
-	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), "sh -c true")
+	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), types.ServiceCommand{Shell: "sh -c true"})
 
 	out := errBuf.String()
 	if !strings.Contains(out, "warning:") {
@@ -316,7 +316,7 @@ func TestWarnCommandMightDivergeUnderDaemon_NotLocalManager_Quiet(t *testing.T)
 	cmd.SetErr(&errBuf)
 
 	dm := manager.NewSupervisedDaemonManager(filepath.Join(shortTempSocketDir(t), "eos.sock"))
-	warnCommandMightDivergeUnderDaemon(cmd, dm, "sh -c true")
+	warnCommandMightDivergeUnderDaemon(cmd, dm, types.ServiceCommand{Shell: "sh -c true"})
 
 	if errBuf.Len() != 0 {
 		t.Errorf("expected no warning for a daemon-routed manager, got: %q", errBuf.String())
@@ -328,7 +328,7 @@ func TestWarnCommandMightDivergeUnderDaemon_UnparsableCommand_Quiet(t *testing.T
 	cmd := &cobra.Command{}
 	cmd.SetErr(&errBuf)
 
-	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), "cd www && sh")
+	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), types.ServiceCommand{Shell: "cd www && sh"})
 
 	if errBuf.Len() != 0 {
 		t.Errorf("expected no warning for a command the preflight would bail on too, got: %q", errBuf.String())
@@ -340,7 +340,7 @@ func TestWarnCommandMightDivergeUnderDaemon_UnresolvableBinary_Quiet(t *testing.
 	cmd := &cobra.Command{}
 	cmd.SetErr(&errBuf)
 
-	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), "nonexistent-binary-xyz-262")
+	warnCommandMightDivergeUnderDaemon(cmd, newTestLocalManager(t), types.ServiceCommand{Shell: "nonexistent-binary-xyz-262"})
 
 	if errBuf.Len() != 0 {
 		t.Errorf("expected no warning when the binary doesn't even resolve here, got: %q", errBuf.String())
@@ -349,7 +349,7 @@ func TestWarnCommandMightDivergeUnderDaemon_UnresolvableBinary_Quiet(t *testing.
 
 func TestRunWarnCommandDivergence_LoadsConfigAndWarns(t *testing.T) {
 	tempDir := t.TempDir()
-	cfg := &types.ServiceConfig{Name: "divergence-svc", Command: "sh -c true"}
+	cfg := &types.ServiceConfig{Name: "divergence-svc", Command: types.ServiceCommand{Shell: "sh -c true"}}
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
@@ -33,7 +33,7 @@ func TestNewDaemonController_OpenRC(t *testing.T) {
 		t.Fatalf("resolving identity: %v", err)
 	}
 	cfg := config.DaemonConfig{OpenRC: &config.OpenRCConfig{InitDir: "/etc/init.d/", InitFileName: "eos"}}
-	ctrl, err := newDaemonController(cfg, t.TempDir(), &config.HealthConfig{}, config.ShutdownConfig{}, config.TelemetryConfig{}, false, identity)
+	ctrl, err := newDaemonController(cfg, t.TempDir(), "", &config.HealthConfig{}, config.ShutdownConfig{}, config.TelemetryConfig{}, false, identity)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -434,6 +434,91 @@ func TestDaemonStartError(t *testing.T) {
 	}
 }
 
+// fakePID1DaemonController is a fakeDaemonController that can also run as
+// PID 1, as the standalone daemon can.
+type fakePID1DaemonController struct {
+	fakeDaemonController
+	pid1Err    error
+	pid1Called bool
+}
+
+func (f *fakePID1DaemonController) StartPID1(_ context.Context, _ bool) error {
+	f.pid1Called = true
+	return f.pid1Err
+}
+
+func TestDaemonStartPID1(t *testing.T) {
+	t.Run("needs a controller that can run as PID 1", func(t *testing.T) {
+		fake := &fakeDaemonController{}
+		cmd := newTestDaemonCmd(fake)
+		var out, errOut strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"start", "--pid1"})
+
+		if err := cmd.ExecuteContext(t.Context()); !errors.Is(err, helpers.ErrCommandFailed) {
+			t.Fatalf("expected ErrCommandFailed, got: %v", err)
+		}
+		if fake.startCalled {
+			t.Fatal("expected Start not to be called for --pid1")
+		}
+		if !strings.Contains(errOut.String(), "--pid1 needs the standalone daemon") {
+			t.Errorf("expected the standalone-only error, got: %s", errOut.String())
+		}
+	})
+
+	t.Run("runs in place of Start and prints nothing on stdout", func(t *testing.T) {
+		fake := &fakePID1DaemonController{}
+		cmd := newTestDaemonCmd(fake)
+		var out, errOut strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"start", "--pid1"})
+
+		if err := cmd.ExecuteContext(t.Context()); err != nil {
+			t.Fatalf("unexpected error: %v (stderr: %s)", err, errOut.String())
+		}
+		if !fake.pid1Called || fake.startCalled {
+			t.Fatalf("expected StartPID1 instead of Start, got pid1Called=%v startCalled=%v", fake.pid1Called, fake.startCalled)
+		}
+		if out.String() != "" {
+			t.Errorf("expected stdout left to the daemon's log, got: %s", out.String())
+		}
+	})
+
+	t.Run("failed services exit non-zero", func(t *testing.T) {
+		fake := &fakePID1DaemonController{pid1Err: fmt.Errorf("%w: api", process.ErrServicesFailed)}
+		cmd := newTestDaemonCmd(fake)
+		var out, errOut strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"start", "--pid1"})
+
+		if err := cmd.ExecuteContext(t.Context()); !errors.Is(err, helpers.ErrCommandFailed) {
+			t.Fatalf("expected ErrCommandFailed, got: %v", err)
+		}
+		if !strings.Contains(errOut.String(), "every service is down: services failed: api") {
+			t.Errorf("expected the failed services in stderr, got: %s", errOut.String())
+		}
+	})
+
+	t.Run("conflicts with --detach", func(t *testing.T) {
+		fake := &fakePID1DaemonController{}
+		cmd := newTestDaemonCmd(fake)
+		var out, errOut strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"start", "--pid1", "--detach"})
+
+		if err := cmd.ExecuteContext(t.Context()); !errors.Is(err, helpers.ErrCommandFailed) {
+			t.Fatalf("expected ErrCommandFailed, got: %v", err)
+		}
+		if fake.pid1Called {
+			t.Fatal("expected StartPID1 not to be called on conflicting flags")
+		}
+	})
+}
+
 func TestDaemonStartDetachSuccessOutput(t *testing.T) {
 	fake := &fakeDaemonController{}
 	cmd := newTestDaemonCmd(fake)
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
+	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
+	"github.com/Elysium-Labs-EU/eos/internal/config"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// serviceDeployer is the daemon-backed capability the deploy and rollback
+// commands need. As with reload (see serviceReloader), only DaemonManager
+// implements it: both finish with a reload inside the supervising daemon.
+type serviceDeployer interface {
+	DeployService(ctx context.Context, name, artifact string, cfg manager.DeployConfig) (manager.DeployResult, error)
+	RollbackService(ctx context.Context, name, release string, cfg manager.ReloadConfig) (manager.DeployResult, error)
+}
+
+func newDeployCmd(getManager func() manager.ServiceManager, getConfig func() *config.SystemConfig) *cobra.Command {
+	var keep int
+	var canary time.Duration
+	cmd := &cobra.Command{
+		Use:   cmdnames.UseDeploy,
+		Short: "Deploy a new release of a service",
+		Long: `Deploy a directory or a .tar, .tar.gz or .tgz archive as a new release of a
+service, with a way back.
+
+eos unpacks the artifact into releases/<timestamp> beside the service, runs the
+service's build: command there if it has one, and atomically switches the
+current symlink to it. The first deploy points the service at current, so the
+releases/ and current entries live in the directory the service was added
+from. The running service is then reloaded onto the release (see eos reload);
+one that had failed is restarted, and one that is stopped runs it the next time
+it is started. If the reload never becomes healthy, current is switched back
+and the old release keeps serving.
+
+The artifact must hold the service's config file at its root. eos keeps the
+last --keep releases and deletes older ones, never the current or the previous
+release.`,
+		Example: `  eos deploy cms ./build          # deploy a directory
+  eos deploy cms cms-1.4.2.tgz    # deploy an archive
+  eos deploy cms ./build --keep 3`,
+		Args:              cobra.ExactArgs(2),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		ValidArgsFunction: helpers.ServiceNameCompletions(getManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serviceName := args[0]
+			// The daemon resolves the artifact, from its own working
+			// directory rather than the caller's.
+			artifact, err := filepath.Abs(args[1])
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("resolving artifact path: %v", err))
+				return helpers.ErrCommandFailed
+			}
+
+			deployer, ok := deployPreflight(cmd, getManager(), serviceName)
+			if !ok {
+				return helpers.ErrCommandFailed
+			}
+			cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "deploying", ui.TextBold.Render(serviceName))
+
+			result, err := deployer.DeployService(cmd.Context(), serviceName, artifact, manager.DeployConfig{
+				ReloadConfig: deployReloadConfig(getConfig(), canary),
+				Keep:         keep,
+			})
+			if err != nil {
+				printDeployFailedOutput(cmd, serviceName, "deploying", result, err)
+				return helpers.ErrCommandFailed
+			}
+			printDeploySuccessOutput(cmd, serviceName, "deployed", result)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&keep, "keep", manager.DeployDefaultKeep, "how many releases to keep")
+	cmd.Flags().DurationVar(&canary, "canary", 0, "serve the old and new release side by side this long before finishing the cutover")
+
+	return cmd
+}
+
+func newRollbackCmd(getManager func() manager.ServiceManager, getConfig func() *config.SystemConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdnames.UseRollback,
+		Short: "Switch a service back to an earlier release",
+		Long: `Switch a deployed service's current symlink back to an earlier release and
+reload it, as eos deploy does.
+
+Without a release name, eos rolls back to the newest release older than the
+current one that didn't fail. eos info lists a service's releases.`,
+		Example: `  eos rollback cms                  # back to the previous release
+  eos rollback cms 20260114093012   # back to a specific release`,
+		Args:              cobra.RangeArgs(1, 2),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		ValidArgsFunction: helpers.ServiceNameCompletions(getManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serviceName := args[0]
+			release := ""
+			if len(args) == 2 {
+				release = args[1]
+			}
+
+			deployer, ok := deployPreflight(cmd, getManager(), serviceName)
+			if !ok {
+				return helpers.ErrCommandFailed
+			}
+			cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "rolling back", ui.TextBold.Render(serviceName))
+
+			result, err := deployer.RollbackService(cmd.Context(), serviceName, release, deployReloadConfig(getConfig(), 0))
+			if err != nil {
+				printDeployFailedOutput(cmd, serviceName, "rolling back", result, err)
+				return helpers.ErrCommandFailed
+			}
+			printDeploySuccessOutput(cmd, serviceName, "rolled back", result)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// deployPreflight checks serviceName is registered and mgr can deploy,
+// printing why not.
+func deployPreflight(cmd *cobra.Command, mgr manager.ServiceManager, serviceName string) (serviceDeployer, bool) {
+	exists, err := mgr.IsServiceRegistered(cmd.Context(), serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("checking service: %v", err))
+		return nil, false
+	}
+	if !exists {
+		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(serviceName), "is not registered")
+		cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(cmdnames.HintAdd), ui.TextMuted.Render("to register it"))
+		return nil, false
+	}
+	deployer, ok := mgr.(serviceDeployer)
+	if !ok {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "deploy and rollback require the standalone eos daemon")
+		cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run without"), ui.TextCommand.Render("--no-daemon"), ui.TextMuted.Render("to use the daemon"))
+		return nil, false
+	}
+	return deployer, true
+}
+
+// deployReloadConfig is the reload a deploy or rollback finishes with, timed
+// as eos reload's.
+func deployReloadConfig(cfg *config.SystemConfig, canary time.Duration) manager.ReloadConfig {
+	return manager.ReloadConfig{
+		GracePeriod:      cfg.Shutdown.GracePeriod,
+		TickerPeriod:     reloadTickerPeriod,
+		ReadinessTimeout: reloadReadinessTimeout,
+		ProbeInterval:    reloadProbeInterval,
+		Canary:           canary,
+	}
+}
+
+func printDeploySuccessOutput(cmd *cobra.Command, serviceName, verb string, result manager.DeployResult) {
+	cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(serviceName), fmt.Sprintf("%s to release %s", verb, result.Release.Name))
+	switch result.Activation {
+	case types.DeployActivationReloaded:
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("reloaded:"), fmt.Sprintf("PGID %d to %d", result.Reload.OldPGID, result.Reload.NewPGID))
+	case types.DeployActivationRestarted:
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("restarted:"), fmt.Sprintf("PGID %d", result.Reload.NewPGID))
+	case types.DeployActivationNotRunning:
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("note:"), "the service is not running, it runs this release when started")
+	}
+	if result.Previous != "" {
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("previous:"), result.Previous)
+	}
+	if len(result.Pruned) > 0 {
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("pruned:"), fmt.Sprintf("%d old releases", len(result.Pruned)))
+	}
+	cmd.Printf("%s %s %s\n", ui.LabelInfo.Render("note:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintInfo, serviceName)), ui.TextMuted.Render("to view its releases"))
+	cmd.Printf("      %s %s\n\n", ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintRollback, serviceName)), ui.TextMuted.Render("to switch back"))
+}
+
+// printDeployFailedOutput reports a deploy or rollback that failed, and
+// whether the previous release was kept.
+func printDeployFailedOutput(cmd *cobra.Command, serviceName, doing string, result manager.DeployResult, err error) {
+	if errors.Is(err, manager.ErrCanaryFailed) && result.Reload.Canary != nil {
+		printReloadCanaryFailedOutput(cmd, serviceName, *result.Reload.Canary)
+		return
+	}
+	reason := err.Error()
+	if result.Release.Error != "" {
+		reason = result.Release.Error
+	}
+	cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("%s %s: %s", doing, serviceName, reason))
+	if result.Release.Name == "" {
+		return
+	}
+	cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), "release "+result.Release.Name, "was marked failed")
+	if result.Previous != "" {
+		cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), ui.TextBold.Render(serviceName), "kept release "+result.Previous)
+	}
+	cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintLogs, serviceName)), ui.TextMuted.Render("to see why it failed"))
+}
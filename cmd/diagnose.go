@@ -133,10 +133,13 @@ type diagnoseDaemonEnvInfo struct {
 // a running service actually received, read from its own live process
 // rather than inferred from service.yaml -- the two differ whenever
 // runtime.path is set, since buildEnvironment prepends it onto the daemon's
-// own PATH before launch.
+// own PATH before launch. WithheldVars names the daemon-env.json variables
+// the service didn't receive, which is what clean_env without a matching
+// pass_env entry does; names only, like daemon-env.json's withheld values.
 type diagnoseServiceEnvInfo struct {
-	Name string `json:"name"`
-	Path string `json:"path"`
+	Name         string   `json:"name"`
+	Path         string   `json:"path"`
+	WithheldVars []string `json:"withheld_vars,omitempty"`
 }
 
 func newDiagnoseCmd() *cobra.Command {
@@ -173,7 +176,9 @@ from the live processes themselves rather than inferred from config. Both
 are allowlist-redacted (PATH, HOME, USER, SHELL, LANG, PWD, and the
 variables systemd sets) -- a name outside that allowlist is listed with its
 value withheld, never dropped, so the bundle still shows the shape of the
-environment without risking a leaked secret. This is different from
+environment without risking a leaked secret. service-env.json also lists,
+by name, the daemon variables each service did not receive (clean_env
+withholds everything pass_env doesn't name). This is different from
 --include-env, which dumps each service's configured env_file unredacted.
 
 --include-env writes a raw, unredacted dump of each service's resolved
@@ -204,14 +209,14 @@ public issue.`,
 // does, collects every diagnostic step (never fail-fast), and writes the
 // resulting bundle. Only the final write is treated as a fatal error.
 func runDiagnose(cmd *cobra.Command, opts diagnoseOptions) error {
-	_, baseDir, sysCfg, _, err := newSystemConfig()
+	_, baseDir, sysCfg, identity, err := newSystemConfig()
 	if err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting system configuration: %v", err))
 		return helpers.ErrCommandFailed
 	}
 
 	verbose, _ := cmd.Flags().GetBool("verbose")
-	mgr, cleanup, err := newLocalManagerWithCleanup(cmd.Context(), baseDir, verbose, sysCfg.Sinks)
+	mgr, cleanup, err := newLocalManagerWithCleanup(cmd.Context(), baseDir, identity, verbose, sysCfg.Sinks)
 	if err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("opening local state: %v", err))
 		return helpers.ErrCommandFailed
@@ -287,7 +292,7 @@ func diagnoseCollect(ctx context.Context, mgr manager.ServiceManager, baseDir st
 	manifest.Steps = append(manifest.Steps, serviceSteps...)
 	files = append(files, diagnoseJSONFile("services.json", services))
 
-	serviceEnvFiles, serviceEnvSteps := diagnoseCollectServiceEnv(ctx, mgr, registeredServices)
+	serviceEnvFiles, serviceEnvSteps := diagnoseCollectServiceEnv(ctx, mgr, registeredServices, daemonEnvInfo)
 	manifest.Steps = append(manifest.Steps, serviceEnvSteps...)
 	files = append(files, serviceEnvFiles...)
 
@@ -593,11 +598,11 @@ func diagnoseCollectEnv(registeredServices []types.ServiceCatalogEntry) ([]diagn
 			steps = append(steps, diagnoseStepResult{Name: stepName, Captured: false, Error: err.Error()})
 			continue
 		}
-		if svcConfig.EnvFile == "" {
+		if len(svcConfig.EnvFile) == 0 {
 			steps = append(steps, diagnoseStepResult{Name: stepName, Captured: false, Error: "no env_file configured"})
 			continue
 		}
-		envVars, err := manager.ParseEnvFile(svcConfig, reg.DirectoryPath)
+		envVars, err := manager.ParseEnvFile(svcConfig, reg.DirectoryPath, os.Environ())
 		if err != nil {
 			steps = append(steps, diagnoseStepResult{Name: stepName, Captured: false, Error: err.Error()})
 			continue
@@ -687,7 +692,9 @@ func diagnoseCollectDaemonEnv(daemonPid *int) (diagnoseDaemonEnvInfo, diagnoseSt
 // live, matching process (stopped, never started, or its PGID has since been
 // recycled by an unrelated process) is skipped, not reported as a failure:
 // that's the expected state of a stopped service, not a collection error.
-func diagnoseCollectServiceEnv(ctx context.Context, mgr manager.ServiceManager, registeredServices []types.ServiceCatalogEntry) ([]diagnoseFile, []diagnoseStepResult) {
+// Each entry is also diffed against daemonEnv by name (empty when the
+// daemon's own environment couldn't be read) to report what was withheld.
+func diagnoseCollectServiceEnv(ctx context.Context, mgr manager.ServiceManager, registeredServices []types.ServiceCatalogEntry, daemonEnv diagnoseDaemonEnvInfo) ([]diagnoseFile, []diagnoseStepResult) {
 	var entries []diagnoseServiceEnvInfo
 	var steps []diagnoseStepResult
 
@@ -709,13 +716,30 @@ func diagnoseCollectServiceEnv(ctx context.Context, mgr manager.ServiceManager,
 			steps = append(steps, diagnoseStepResult{Name: stepName, Captured: false, Error: err.Error()})
 			continue
 		}
-		entries = append(entries, diagnoseServiceEnvInfo{Name: name, Path: diagnoseExtractPathVar(raw)})
+		entries = append(entries, diagnoseServiceEnvInfo{Name: name, Path: diagnoseExtractPathVar(raw), WithheldVars: diagnoseWithheldVars(daemonEnv, raw)})
 		steps = append(steps, diagnoseStepResult{Name: stepName, Captured: true})
 	}
 
 	return []diagnoseFile{diagnoseJSONFile("service-env.json", entries)}, steps
 }
 
+// diagnoseWithheldVars returns the names in daemonEnv missing from a
+// service's raw "KEY=VALUE" environment, in daemonEnv's (sorted) order.
+func diagnoseWithheldVars(daemonEnv diagnoseDaemonEnvInfo, serviceEnv []string) []string {
+	received := make(map[string]bool, len(serviceEnv))
+	for _, kv := range serviceEnv {
+		name, _, _ := strings.Cut(kv, "=")
+		received[name] = true
+	}
+	var withheld []string
+	for _, v := range daemonEnv.Vars {
+		if !received[v.Name] {
+			withheld = append(withheld, v.Name)
+		}
+	}
+	return withheld
+}
+
 // diagnoseExtractPathVar returns the PATH value from a raw "KEY=VALUE"
 // environment, or "" if unset.
 func diagnoseExtractPathVar(env []string) string {
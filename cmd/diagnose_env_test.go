@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"os/exec"
+	"slices"
 	"strings"
 	"syscall"
 	"testing"
@@ -122,7 +123,7 @@ func TestDiagnoseCollectServiceEnv(t *testing.T) {
 	t.Run("no process history", func(t *testing.T) {
 		mgr := &apiStatusFakeManager{processErr: errors.New("no process found")}
 		catalog := []types.ServiceCatalogEntry{{Name: "svc"}}
-		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog)
+		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog, diagnoseDaemonEnvInfo{})
 
 		if len(files) != 1 || files[0].Name != "service-env.json" {
 			t.Fatalf("expected a single service-env.json file, got: %+v", files)
@@ -135,7 +136,7 @@ func TestDiagnoseCollectServiceEnv(t *testing.T) {
 	t.Run("pgid not alive", func(t *testing.T) {
 		mgr := &apiStatusFakeManager{processEntry: &types.ProcessHistory{PGID: 999999999, StartedAtTicks: 0}}
 		catalog := []types.ServiceCatalogEntry{{Name: "svc"}}
-		_, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog)
+		_, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog, diagnoseDaemonEnvInfo{})
 
 		if len(steps) != 1 || steps[0].Captured || !strings.Contains(steps[0].Error, "not running") {
 			t.Errorf("expected a failed 'not running' step, got: %+v", steps)
@@ -155,7 +156,7 @@ func TestDiagnoseCollectServiceEnv(t *testing.T) {
 
 		mgr := &apiStatusFakeManager{processEntry: &types.ProcessHistory{PGID: cmd.Process.Pid, StartedAtTicks: startedAtTicks}}
 		catalog := []types.ServiceCatalogEntry{{Name: "svc"}}
-		_, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog)
+		_, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog, diagnoseDaemonEnvInfo{})
 
 		if len(steps) != 1 || steps[0].Captured || steps[0].Error != "read boom" {
 			t.Errorf("expected the read error surfaced, got: %+v", steps)
@@ -180,7 +181,7 @@ func TestDiagnoseCollectServiceEnv(t *testing.T) {
 
 		mgr := &apiStatusFakeManager{processEntry: &types.ProcessHistory{PGID: cmd.Process.Pid, StartedAtTicks: startedAtTicks}}
 		catalog := []types.ServiceCatalogEntry{{Name: "svc"}}
-		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog)
+		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, catalog, diagnoseDaemonEnvInfo{})
 
 		if len(steps) != 1 || !steps[0].Captured {
 			t.Fatalf("expected an ok step, got: %+v", steps)
@@ -191,9 +192,32 @@ func TestDiagnoseCollectServiceEnv(t *testing.T) {
 		}
 	})
 
+	t.Run("reports daemon variables the service was not given", func(t *testing.T) {
+		orig := diagnoseReadEnviron
+		defer func() { diagnoseReadEnviron = orig }()
+
+		cmd := startRealSleepProcess(t)
+		startedAtTicks, err := procutil.StartTime(cmd.Process.Pid)
+		if err != nil {
+			t.Fatalf("StartTime: %v", err)
+		}
+		diagnoseReadEnviron = func(int) ([]string, error) {
+			return []string{"PATH=/usr/bin", "LANG=C.UTF-8"}, nil
+		}
+		daemonEnv := diagnoseDaemonEnvInfo{Vars: diagnoseRedactEnviron([]string{"PATH=/usr/bin", "LANG=C.UTF-8", "AWS_SECRET_ACCESS_KEY=x", "SSH_AUTH_SOCK=/tmp/agent"})}
+
+		mgr := &apiStatusFakeManager{processEntry: &types.ProcessHistory{PGID: cmd.Process.Pid, StartedAtTicks: startedAtTicks}}
+		files, _ := diagnoseCollectServiceEnv(t.Context(), mgr, []types.ServiceCatalogEntry{{Name: "svc"}}, daemonEnv)
+
+		entries := unmarshalOrFatal[[]diagnoseServiceEnvInfo](t, files[0].Data)
+		if len(entries) != 1 || !slices.Equal(entries[0].WithheldVars, []string{"AWS_SECRET_ACCESS_KEY", "SSH_AUTH_SOCK"}) {
+			t.Errorf("expected AWS_SECRET_ACCESS_KEY and SSH_AUTH_SOCK reported withheld, got: %+v", entries)
+		}
+	})
+
 	t.Run("no services", func(t *testing.T) {
 		mgr := &apiStatusFakeManager{}
-		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, nil)
+		files, steps := diagnoseCollectServiceEnv(t.Context(), mgr, nil, diagnoseDaemonEnvInfo{})
 		if len(files) != 1 {
 			t.Fatalf("expected service-env.json even with no services, got: %+v", files)
 		}
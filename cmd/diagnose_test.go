@@ -709,7 +709,7 @@ func TestDiagnoseCmd_NoServiceLogsSkipsLogFiles(t *testing.T) {
 // disk exactly where the diagnose command itself will look for them.
 func diagnoseTestManager(t *testing.T, baseDir string) manager.ServiceManager {
 	t.Helper()
-	mgr, cleanup, err := newLocalManagerWithCleanup(t.Context(), baseDir, false, nil)
+	mgr, cleanup, err := newLocalManagerWithCleanup(t.Context(), baseDir, testutil.ResolveIdentity(t), false, nil)
 	if err != nil {
 		t.Fatalf("opening local manager: %v", err)
 	}
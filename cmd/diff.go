@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
+	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// configDriftReader is the optional manager capability behind eos diff and
+// eos status's restart pending flag, kept off manager.ServiceManager like
+// releaseLister.
+type configDriftReader interface {
+	GetConfigDrift(ctx context.Context, name string) (*types.ConfigDrift, error)
+}
+
+func newDiffCmd(getManager func() manager.ServiceManager) *cobra.Command {
+	return &cobra.Command{
+		Use:   cmdnames.UseDiff,
+		Short: "Show how a service's config changed since it was started",
+		Long: `Compare the config a running service was started with against its
+service.yaml and env files on disk now, field by field.
+
+eos records each launch's config as it starts it, so an edit that hasn't been
+applied yet shows up here, and as "restart pending" in eos status, until the
+service is restarted or reloaded.`,
+		Example:           `  eos diff cms`,
+		ValidArgsFunction: helpers.ServiceNameCompletions(getManager),
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serviceName := args[0]
+			mgr := getManager()
+			if _, err := infoFetchRegisteredService(cmd, cmd.Context(), mgr, serviceName); err != nil {
+				return err
+			}
+			reader, ok := mgr.(configDriftReader)
+			if !ok {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "config diffs are not supported by this manager")
+				return helpers.ErrCommandFailed
+			}
+			drift, err := reader.GetConfigDrift(cmd.Context(), serviceName)
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("comparing config: %v", err))
+				return helpers.ErrCommandFailed
+			}
+			printDiffOutput(cmd, serviceName, drift)
+			return nil
+		},
+	}
+}
+
+func printDiffOutput(cmd *cobra.Command, serviceName string, drift *types.ConfigDrift) {
+	if drift == nil {
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), ui.TextBold.Render(serviceName), "is not running a recorded launch, there is nothing to compare")
+		return
+	}
+	if !drift.Pending() {
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(serviceName), fmt.Sprintf("is running its config on disk (PGID %d)", drift.PGID))
+		return
+	}
+	cmd.Printf(fmtLabelTwoMsg, ui.LabelWarning.Render("warning"), ui.TextBold.Render(serviceName), fmt.Sprintf("config changed since PGID %d was started", drift.PGID))
+	for _, change := range drift.Changes {
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render(change.Field+":"), diffValue(change.Old)+" -> "+diffValue(change.New))
+	}
+	if drift.EnvChanged {
+		cmd.Printf(fmtIndentLabelMsgLn, ui.TextMuted.Render("env files:"), "contents changed")
+	}
+	cmd.Printf("\n%s %s %s\n\n", ui.LabelInfo.Render("note:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintRun, serviceName)), ui.TextMuted.Render("to restart it with this config"))
+}
+
+// diffValue renders one side of a change, naming an unset field.
+func diffValue(value string) string {
+	if value == "" {
+		return ui.TextMuted.Render("(unset)")
+	}
+	return value
+}
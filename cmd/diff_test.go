@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func TestPrintDiffOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	printDiffOutput(cmd, "cms", &types.ConfigDrift{
+		ServiceName: "cms",
+		PGID:        4242,
+		Changes: []types.ConfigChange{
+			{Field: "env.LEVEL", New: "debug"},
+			{Field: "port", Old: "8080", New: "9090"},
+		},
+		EnvChanged: true,
+	})
+	output := out.String()
+	for _, want := range []string{"config changed since PGID 4242", "env.LEVEL:", "(unset) -> debug", "port:", "8080 -> 9090", "env files:", "eos run cms"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	printDiffOutput(cmd, "cms", &types.ConfigDrift{ServiceName: "cms", PGID: 4242})
+	if !strings.Contains(out.String(), "running its config on disk") {
+		t.Errorf("expected an unchanged launch reported as such, got: %s", out.String())
+	}
+
+	out.Reset()
+	printDiffOutput(cmd, "cms", nil)
+	if !strings.Contains(out.String(), "nothing to compare") {
+		t.Errorf("expected a service with no recorded launch reported as such, got: %s", out.String())
+	}
+}
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
@@ -20,13 +22,21 @@ func newEnvCmd(getManager func() manager.ServiceManager) *cobra.Command {
 	return &cobra.Command{
 		Use:   envUse,
 		Short: "Inspect or edit a service's environment variables",
-		Long: `Prints the resolved environment variables for a registered service, sourced
-from its env_file. Reads directly from disk, so it reflects the current
-env_file contents without requiring the service to be running or restarted.
+		Long: `Prints the variables a registered service would launch with, each labelled
+with where it came from: daemon (inherited from the daemon's own environment,
+or under clean_env passed through by pass_env), eos (PORT, runtime.path,
+user:), env_file, or inline (service.yaml's env: map). Later sources override
+earlier ones in that order. The daemon resolves them from the current env_file
+and service.yaml on disk, over its own environment rather than your shell's,
+so the output needs no running or restarted service and matches what the next
+launch gets under systemd or launchd too.
 
 Use "set KEY=VALUE" to add or update a variable in the service's env_file, or
-"unset KEY" to remove one. Both require the service to have env_file configured.`,
-		Example: `  eos env cms                     # list resolved env vars
+"unset KEY" to remove one. Both require the service to have env_file configured.
+With a list of env files, set writes to the last one (which wins) and unset
+removes the variable from all of them. Values that need it are written
+double-quoted, and a multi-line entry is replaced or removed as a whole.`,
+		Example: `  eos env cms                     # list resolved env vars and their sources
   eos env cms set DEBUG=true      # write DEBUG=true to env_file
   eos env cms unset DEBUG         # remove DEBUG from env_file`,
 		ValidArgsFunction: helpers.ServiceNameCompletions(getManager),
@@ -63,7 +73,7 @@ Use "set KEY=VALUE" to add or update a variable in the service's env_file, or
 
 			switch {
 			case len(args) == 1:
-				return runEnvList(cmd, config, registeredService.DirectoryPath, serviceName)
+				return runEnvList(cmd, mgr, config, serviceName)
 			case len(args) == 3 && args[1] == "set":
 				return runEnvSet(cmd, config, registeredService.DirectoryPath, serviceName, args[2])
 			case len(args) == 3 && args[1] == "unset":
@@ -76,30 +86,34 @@ Use "set KEY=VALUE" to add or update a variable in the service's env_file, or
 	}
 }
 
-func runEnvList(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectoryPath, serviceName string) error {
-	envVars, err := manager.ParseEnvFile(config, serviceDirectoryPath)
+// serviceEnvironmentResolver is the manager capability behind eos env's
+// listing, kept off manager.ServiceManager like effectiveLimitsReader: both
+// the daemon-backed and the in-process manager resolve over the environment
+// of the process that would launch the service.
+type serviceEnvironmentResolver interface {
+	ResolveServiceEnvironment(ctx context.Context, name string) ([]manager.ResolvedEnvVar, error)
+}
+
+func runEnvList(cmd *cobra.Command, mgr manager.ServiceManager, config *types.ServiceConfig, serviceName string) error {
+	resolver, ok := mgr.(serviceEnvironmentResolver)
+	if !ok {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "resolving environment: not supported by this manager")
+		return helpers.ErrCommandFailed
+	}
+	envVars, err := resolver.ResolveServiceEnvironment(cmd.Context(), serviceName)
 	if err != nil {
-		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("reading env file: %v", err))
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("resolving environment: %v", err))
 		return helpers.ErrCommandFailed
 	}
 
 	cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "resolved env for", ui.TextBold.Render(serviceName))
 
-	if config.EnvFile == "" {
-		cmd.Println(ui.TextMuted.Render("  no env_file configured"))
-		cmd.Println("")
-		return nil
-	}
-
-	if len(envVars) == 0 {
-		cmd.Println(ui.TextMuted.Render("  env_file is empty"))
-		cmd.Println("")
-		return nil
+	if len(config.EnvFile) == 0 && len(config.Env) == 0 {
+		cmd.Println(ui.TextMuted.Render("  no env_file or env configured; inherited variables only"))
 	}
 
 	for _, envVar := range envVars {
-		key, value, _ := strings.Cut(envVar, "=")
-		helpers.PrintKV(cmd, key, value)
+		cmd.Printf("%s%s %s\n", ui.KeyStyle.Render(envVar.Name), ui.ValueStyle.Render(envVar.Value), ui.TextMuted.Render("("+string(envVar.Source)+")"))
 	}
 	cmd.Println("")
 	return nil
@@ -113,10 +127,12 @@ func runEnvSet(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectory
 		return helpers.ErrCommandFailed
 	}
 
-	envFilePath, err := requireEnvFilePath(cmd, config, serviceDirectoryPath, serviceName)
+	envFilePaths, err := requireEnvFilePaths(cmd, config, serviceDirectoryPath, serviceName)
 	if err != nil {
 		return err
 	}
+	// The last env_file wins, so that's where a new value takes effect.
+	envFilePath := envFilePaths[len(envFilePaths)-1]
 
 	lines, err := readEnvFileLines(envFilePath)
 	if err != nil {
@@ -124,35 +140,55 @@ func runEnvSet(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectory
 		return helpers.ErrCommandFailed
 	}
 
-	if err := writeEnvFileLines(envFilePath, setEnvFileLine(lines, key, value)); err != nil {
+	updatedLines, err := setEnvFileLine(lines, key, value)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("parsing env file: %v", err))
+		return helpers.ErrCommandFailed
+	}
+
+	if err := writeEnvFileLines(envFilePath, updatedLines); err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("writing env file: %v", err))
 		return helpers.ErrCommandFailed
 	}
 
 	cmd.Printf("%s %s %s %s\n\n", ui.LabelSuccess.Render("ok"), "set", ui.TextBold.Render(key), "in "+serviceName+"'s env_file")
+	if slices.ContainsFunc(config.Env, func(v types.EnvVar) bool { return v.Name == key }) {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelWarning.Render("warning"), fmt.Sprintf("%s is also set in service.yaml's env: map, which overrides env_file", key))
+	}
 	return nil
 }
 
 func runEnvUnset(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectoryPath, serviceName, key string) error {
-	envFilePath, err := requireEnvFilePath(cmd, config, serviceDirectoryPath, serviceName)
+	envFilePaths, err := requireEnvFilePaths(cmd, config, serviceDirectoryPath, serviceName)
 	if err != nil {
 		return err
 	}
 
-	lines, err := readEnvFileLines(envFilePath)
-	if err != nil {
-		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("reading env file: %v", err))
-		return helpers.ErrCommandFailed
-	}
+	removedAny := false
+	for _, envFilePath := range envFilePaths {
+		lines, err := readEnvFileLines(envFilePath)
+		if err != nil {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("reading env file: %v", err))
+			return helpers.ErrCommandFailed
+		}
 
-	updatedLines, removed := unsetEnvFileLine(lines, key)
-	if !removed {
-		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(key), "is not set in env_file")
-		return helpers.ErrCommandFailed
-	}
+		updatedLines, removed, err := unsetEnvFileLine(lines, key)
+		if err != nil {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("parsing env file %s: %v", filepath.Base(envFilePath), err))
+			return helpers.ErrCommandFailed
+		}
+		if !removed {
+			continue
+		}
+		removedAny = true
 
-	if err := writeEnvFileLines(envFilePath, updatedLines); err != nil {
-		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("writing env file: %v", err))
+		if err := writeEnvFileLines(envFilePath, updatedLines); err != nil {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("writing env file: %v", err))
+			return helpers.ErrCommandFailed
+		}
+	}
+	if !removedAny {
+		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(key), "is not set in env_file")
 		return helpers.ErrCommandFailed
 	}
 
@@ -160,82 +196,96 @@ func runEnvUnset(cmd *cobra.Command, config *types.ServiceConfig, serviceDirecto
 	return nil
 }
 
-func requireEnvFilePath(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectoryPath, serviceName string) (string, error) {
-	if config.EnvFile == "" {
+// requireEnvFilePaths resolves the service's env_file entries, in order,
+// reporting to the user when there are none or one escapes the service
+// directory.
+func requireEnvFilePaths(cmd *cobra.Command, config *types.ServiceConfig, serviceDirectoryPath, serviceName string) ([]string, error) {
+	if len(config.EnvFile) == 0 {
 		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(serviceName), "has no env_file configured")
 		cmd.PrintErrf("  %s\n\n", ui.TextMuted.Render("set env_file in the service config first"))
-		return "", helpers.ErrCommandFailed
+		return nil, helpers.ErrCommandFailed
 	}
 
-	envFilePath, err := manager.ResolveEnvFilePath(config, serviceDirectoryPath)
+	envFilePaths, err := manager.ResolveEnvFilePaths(config, serviceDirectoryPath)
 	if err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("resolving env file path: %v", err))
-		return "", helpers.ErrCommandFailed
+		return nil, helpers.ErrCommandFailed
 	}
-	return envFilePath, nil
+	return envFilePaths, nil
 }
 
-// envFileLineKey returns the KEY of a "KEY=VALUE" env_file line, or "" if the
-// line is blank, a comment, or has no "=".
-func envFileLineKey(line string) string {
-	trimmed := strings.TrimSpace(line)
-	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-		return ""
+// envFileKeyEntries returns the env_file entries in lines that assign key,
+// each with the (1-based) lines it spans: more than one for a multi-line
+// quoted value.
+func envFileKeyEntries(lines []string, key string) ([]manager.EnvFileEntry, error) {
+	entries, err := manager.ParseEnvFileEntries(strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
 	}
-	key, _, found := strings.Cut(trimmed, "=")
-	if !found {
-		return ""
+	var matching []manager.EnvFileEntry
+	for _, entry := range entries {
+		if entry.Name == key {
+			matching = append(matching, entry)
+		}
 	}
-	return key
+	return matching, nil
 }
 
-// setEnvFileLine replaces the last line assigning key (dropping any earlier
-// duplicates), or appends a new line if key isn't present. Comments, blank
-// lines, and unrelated assignments are left untouched.
-func setEnvFileLine(lines []string, key, value string) []string {
-	assignment := key + "=" + value
+// setEnvFileLine replaces the last entry assigning key (dropping any earlier
+// duplicates), or appends a new line if key isn't present. The value is
+// quoted when it needs to be (see manager.FormatEnvFileAssignment). Comments,
+// blank lines, and unrelated assignments are left untouched.
+func setEnvFileLine(lines []string, key, value string) ([]string, error) {
+	assignment := manager.FormatEnvFileAssignment(key, value)
 
-	lastIdx := -1
-	for i, line := range lines {
-		if envFileLineKey(line) == key {
-			lastIdx = i
-		}
+	matching, err := envFileKeyEntries(lines, key)
+	if err != nil {
+		return nil, err
 	}
-	if lastIdx == -1 {
-		return append(lines, assignment)
+	if len(matching) == 0 {
+		return append(lines, assignment), nil
 	}
 
+	last := matching[len(matching)-1]
 	updated := make([]string, 0, len(lines))
 	for i, line := range lines {
 		switch {
-		case i == lastIdx:
+		case i+1 == last.FirstLine:
 			updated = append(updated, assignment)
-		case envFileLineKey(line) == key:
+		case envFileEntriesSpan(matching, i+1):
 			continue
 		default:
 			updated = append(updated, line)
 		}
 	}
-	return updated
+	return updated, nil
 }
 
-// unsetEnvFileLine removes every line assigning key, leaving comments and
-// blank lines untouched. Reports whether any line was removed.
-func unsetEnvFileLine(lines []string, key string) ([]string, bool) {
+// unsetEnvFileLine removes every entry assigning key, leaving comments and
+// blank lines untouched. Reports whether any entry was removed.
+func unsetEnvFileLine(lines []string, key string) ([]string, bool, error) {
+	matching, err := envFileKeyEntries(lines, key)
+	if err != nil {
+		return nil, false, err
+	}
 	updated := make([]string, 0, len(lines))
-	removed := false
-	for _, line := range lines {
-		if envFileLineKey(line) == key {
-			removed = true
+	for i, line := range lines {
+		if envFileEntriesSpan(matching, i+1) {
 			continue
 		}
 		updated = append(updated, line)
 	}
-	return updated, removed
+	return updated, len(matching) > 0, nil
+}
+
+func envFileEntriesSpan(entries []manager.EnvFileEntry, line int) bool {
+	return slices.ContainsFunc(entries, func(entry manager.EnvFileEntry) bool {
+		return line >= entry.FirstLine && line <= entry.LastLine
+	})
 }
 
 func readEnvFileLines(path string) ([]string, error) {
-	// #nosec G304 - path resolved and validated by manager.ResolveEnvFilePath
+	// #nosec G304 - path resolved and validated by manager.ResolveEnvFilePaths
 	data, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return nil, err
@@ -193,7 +193,7 @@ func TestEnvUnsetWriteEnvFileError(t *testing.T) {
 
 // TestEnvRequireEnvFilePathResolveError checks requireEnvFilePath's own
 // "resolving env file path" error, hit when config.EnvFile is configured but
-// escapes the service directory (ResolveEnvFilePath's traversal guard).
+// escapes the service directory (ResolveEnvFilePaths' traversal guard).
 func TestEnvRequireEnvFilePathResolveError(t *testing.T) {
 	cmd, _, errBuf, tempDir := setupCmd(t)
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
 	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -91,8 +92,52 @@ func TestEnvListNoEnvFileConfigured(t *testing.T) {
 	}
 
 	output := outBuf.String()
-	if !strings.Contains(output, "no env_file configured") {
-		t.Errorf("expected 'no env_file configured', got: %s", output)
+	if !strings.Contains(output, "no env_file or env configured") {
+		t.Errorf("expected 'no env_file or env configured', got: %s", output)
+	}
+}
+
+func TestEnvListLabelsEachVariablesSource(t *testing.T) {
+	cmd, outBuf, errBuf, tempDir := setupCmd(t)
+	t.Setenv("EOS_TEST_INHERITED", "from-daemon")
+
+	testFile := testutil.NewTestServiceConfigFile(t, testutil.WithoutRuntime(), testutil.WithEnvFile(".env"))
+	testFile.Env = types.ServiceEnv{
+		{Name: "SHARED", Value: "inline"},
+		{Name: "GREETING", Value: "${FROM_FILE}-${MISSING:-fallback}-${EOS_TEST_INHERITED}"},
+	}
+	yamlData, err := yaml.Marshal(testFile)
+	if err != nil {
+		t.Fatalf("Failed to marshal test config: %v", err)
+	}
+	fullDirPath := filepath.Join(tempDir, "test-project")
+	if err := os.MkdirAll(fullDirPath, 0755); err != nil {
+		t.Fatalf("could not create test-project directory: %v", err)
+	}
+	fullPath := filepath.Join(fullDirPath, "service.yaml")
+	if err := os.WriteFile(fullPath, yamlData, 0644); err != nil {
+		t.Fatalf("Failed to write the service.yaml file, got: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(fullDirPath, ".env"), []byte("FROM_FILE=hello\nSHARED=file\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the .env file, got: %v", err)
+	}
+	cmd.SetArgs([]string{"add", fullPath})
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		t.Fatalf("add command should not return an error, got: %v\nerr output: %s", err, errBuf.String())
+	}
+
+	cmd.SetArgs([]string{"env", "cms"})
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		t.Fatalf("env command should not return an error, got: %v\nerr output: %s", err, errBuf.String())
+	}
+
+	output := outBuf.String()
+	// The in-process manager launches services itself, so this process's
+	// environment is the inherited layer here.
+	for _, want := range []string{"from-daemon (daemon)", "hello (env_file)", "inline (inline)", "hello-fallback-from-daemon (inline)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
 	}
 }
 
@@ -306,3 +351,33 @@ func TestEnvUnsetPreservesCommentsAndBlankLines(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvSetAndUnsetReplaceMultilineEntriesWhole(t *testing.T) {
+	cmd, _, errBuf, tempDir := setupCmd(t)
+	original := "export FOO=bar\nKEY=\"-----BEGIN KEY-----\nsecret\n-----END KEY-----\"\nBAZ=qux\n"
+	serviceDir := addServiceWithEnvFile(t, cmd, tempDir, original, errBuf)
+
+	cmd.SetArgs([]string{"env", "cms", "set", "KEY=rotated value"})
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		t.Fatalf("env set should not return an error, got: %v\nerr output: %s", err, errBuf.String())
+	}
+	envFileContents, err := os.ReadFile(filepath.Join(serviceDir, ".env"))
+	if err != nil {
+		t.Fatalf("could not read env file: %v", err)
+	}
+	if want := "export FOO=bar\nKEY=\"rotated value\"\nBAZ=qux\n"; string(envFileContents) != want {
+		t.Errorf("after set: got %q, want %q", envFileContents, want)
+	}
+
+	cmd.SetArgs([]string{"env", "cms", "unset", "FOO"})
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		t.Fatalf("env unset should not return an error, got: %v\nerr output: %s", err, errBuf.String())
+	}
+	envFileContents, err = os.ReadFile(filepath.Join(serviceDir, ".env"))
+	if err != nil {
+		t.Fatalf("could not read env file: %v", err)
+	}
+	if want := "KEY=\"rotated value\"\nBAZ=qux\n"; string(envFileContents) != want {
+		t.Errorf("after unset: got %q, want %q", envFileContents, want)
+	}
+}
@@ -72,9 +72,10 @@ func DetermineServiceStatus(mostRecentProcess *types.ProcessHistory, hasLiveOrph
 // its own backoff ceiling and collapse its own log output — the single
 // derivation site both status display and the monitor's retry behavior read,
 // so they can never silently disagree on what counts as a sustained failure
-// loop.
+// loop. A service the monitor has given up on (GaveUpReason) isn't looping
+// any more: it stays plain Failed, with the reason as its error.
 func InFailureLoop(instance *types.ServiceInstance) bool {
-	return instance != nil && instance.FailureLoopCount >= config.HealthCrashLoopThreshold
+	return instance != nil && instance.GaveUpReason == "" && instance.FailureLoopCount >= config.HealthCrashLoopThreshold
 }
 
 func determineStatusFromProcessState(mostRecentProcess *types.ProcessHistory) types.ServiceStatus {
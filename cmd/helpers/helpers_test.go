@@ -20,6 +20,7 @@ func TestInFailureLoop(t *testing.T) {
 		{name: "below threshold", instance: &types.ServiceInstance{FailureLoopCount: config.HealthCrashLoopThreshold - 1}, want: false},
 		{name: "at threshold", instance: &types.ServiceInstance{FailureLoopCount: config.HealthCrashLoopThreshold}, want: true},
 		{name: "above threshold", instance: &types.ServiceInstance{FailureLoopCount: config.HealthCrashLoopThreshold + 1}, want: true},
+		{name: "given up", instance: &types.ServiceInstance{FailureLoopCount: config.HealthCrashLoopThreshold, GaveUpReason: "[cms] gave up after 5 restarts (max_restarts)"}, want: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
 	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
@@ -40,6 +41,12 @@ func newInfoCmd(getManager func() manager.ServiceManager) *cobra.Command {
 			serviceInstance := infoFetchServiceInstance(cmd, cmd.Context(), mgr, serviceName)
 			processEntry := infoFetchProcessEntry(cmd, cmd.Context(), mgr, serviceName)
 			orphanGroups := infoFetchOrphanGroups(cmd, cmd.Context(), mgr, serviceName)
+			effectiveLimits := infoFetchEffectiveLimits(cmd, cmd.Context(), mgr, serviceName, config)
+			notifyStatus := infoFetchNotifyStatus(cmd, cmd.Context(), mgr, serviceName, config)
+			proxyStatus := infoFetchProxyStatus(cmd, cmd.Context(), mgr, serviceName, config)
+			instanceEntries := infoFetchInstanceEntries(cmd, cmd.Context(), mgr, serviceName, config)
+			canaryResult := infoFetchCanaryResult(cmd, cmd.Context(), mgr, serviceName)
+			releases := infoFetchReleases(cmd, cmd.Context(), mgr, serviceName)
 
 			// TODO: Is there a way to make the fact the log files only exist on services that have run once more explicit?
 			logPath := infoFetchLogPath(cmd, cmd.Context(), mgr, serviceName, false, serviceInstance)
@@ -47,10 +54,16 @@ func newInfoCmd(getManager func() manager.ServiceManager) *cobra.Command {
 			errorLogPath := infoFetchLogPath(cmd, cmd.Context(), mgr, serviceName, true, serviceInstance)
 
 			infoPrintProcessSection(cmd, processEntry, orphanGroups)
+			infoPrintInstancesSection(cmd, serviceName, config, instanceEntries)
 			infoPrintServiceSection(cmd, &registeredService)
 			infoPrintLoggingSection(cmd, logPath, errorLogPath, config)
 			infoPrintInstanceSection(cmd, serviceInstance)
 			infoPrintConfigSection(cmd, config)
+			infoPrintLimitsSection(cmd, config, effectiveLimits)
+			infoPrintNotifySection(cmd, config, notifyStatus)
+			infoPrintProxySection(cmd, config, proxyStatus)
+			infoPrintCanarySection(cmd, canaryResult)
+			infoPrintReleasesSection(cmd, releases)
 
 			cmd.Println("")
 			return nil
@@ -99,6 +112,69 @@ func infoFetchOrphanGroups(cmd *cobra.Command, ctx context.Context, mgr manager.
 	return orphanGroups
 }
 
+// effectiveLimitsReader is the optional manager capability behind eos info's
+// effective limit values: both the daemon-backed and the in-process manager
+// implement it, but it stays off manager.ServiceManager like the daemon's
+// own effectiveLimitsReader (internal/process/daemon.go).
+type effectiveLimitsReader interface {
+	GetEffectiveLimits(ctx context.Context, name string) (types.EffectiveLimits, error)
+}
+
+// infoFetchEffectiveLimits reads back what the kernel is enforcing on the
+// service's live launch, only when service.yaml configures limits at all. A
+// zero result (PGID 0) prints every effective value as N/A.
+func infoFetchEffectiveLimits(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string, config *types.ServiceConfig) types.EffectiveLimits {
+	reader, ok := mgr.(effectiveLimitsReader)
+	if !ok || config == nil || config.Limits == (types.ServiceLimits{}) {
+		return types.EffectiveLimits{}
+	}
+	effective, err := reader.GetEffectiveLimits(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting effective limits: %v", err))
+	}
+	return effective
+}
+
+// notifyStatusReader is the optional manager capability behind eos info's
+// Notify section, kept off manager.ServiceManager like effectiveLimitsReader.
+type notifyStatusReader interface {
+	GetNotifyStatus(ctx context.Context, name string) (types.NotifyStatus, error)
+}
+
+// infoFetchNotifyStatus reads what the service's live launch has reported
+// over its notify socket, only for a type: notify service.
+func infoFetchNotifyStatus(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string, config *types.ServiceConfig) types.NotifyStatus {
+	reader, ok := mgr.(notifyStatusReader)
+	if !ok || config == nil || manager.ServiceType(config.Type) != manager.ServiceTypeNotify {
+		return types.NotifyStatus{}
+	}
+	status, err := reader.GetNotifyStatus(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting notify status: %v", err))
+	}
+	return status
+}
+
+// proxyStatusReader is the optional manager capability behind eos info's
+// Proxy section, kept off manager.ServiceManager like effectiveLimitsReader.
+type proxyStatusReader interface {
+	GetProxyStatus(ctx context.Context, name string) (types.ProxyStatus, error)
+}
+
+// infoFetchProxyStatus reads the daemon's proxy for the service, only for
+// one with proxy: true.
+func infoFetchProxyStatus(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string, config *types.ServiceConfig) types.ProxyStatus {
+	reader, ok := mgr.(proxyStatusReader)
+	if !ok || config == nil || !config.Proxy {
+		return types.ProxyStatus{}
+	}
+	status, err := reader.GetProxyStatus(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting proxy status: %v", err))
+	}
+	return status
+}
+
 func infoFetchLogPath(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string, errorLog bool, serviceInstance *types.ServiceInstance) *string {
 	logPath, err := mgr.GetServiceLogFilePath(ctx, serviceName, errorLog)
 	if err != nil && serviceInstance != nil {
@@ -204,7 +280,7 @@ func infoPrintConfigSection(cmd *cobra.Command, config *types.ServiceConfig) {
 		cmd.PrintErr(ui.TextMuted.Render("  no config loaded\n"))
 		return
 	}
-	helpers.PrintKV(cmd, "command", config.Command)
+	helpers.PrintKV(cmd, "command", config.Command.String())
 	if config.Port != 0 {
 		helpers.PrintKV(cmd, "port", fmt.Sprintf("%d", config.Port))
 	} else {
@@ -220,4 +296,189 @@ func infoPrintConfigSection(cmd *cobra.Command, config *types.ServiceConfig) {
 	} else {
 		helpers.PrintKV(cmd, "runtime path", config.Runtime.Path)
 	}
+	for _, socket := range config.Sockets {
+		if socket.TCP != "" {
+			helpers.PrintKV(cmd, "socket "+socket.Name, "tcp "+socket.TCP)
+		} else {
+			helpers.PrintKV(cmd, "socket "+socket.Name, "unix "+socket.Unix)
+		}
+	}
+}
+
+// infoPrintLimitsSection shows each configured limit next to the value the
+// kernel reports for the running launch, and which mechanism that value came
+// from: the launch's cgroup file, or the rlimit planLimits falls back to
+// without one. A limit eos couldn't apply reads as "not enforced" rather than
+// silently matching the config.
+func infoPrintLimitsSection(cmd *cobra.Command, config *types.ServiceConfig, effective types.EffectiveLimits) {
+	if config == nil || config.Limits == (types.ServiceLimits{}) {
+		return
+	}
+	helpers.PrintSection(cmd, "Limits")
+	limits := config.Limits
+	if limits.MemoryMax != "" {
+		helpers.PrintKV(cmd, "memory max", infoLimitValue(limits.MemoryMax, effective, effective.MemoryMax, "memory.max", effective.AddressSpace, "RLIMIT_AS"))
+	}
+	if limits.CPUQuota != "" {
+		helpers.PrintKV(cmd, "cpu quota", infoLimitValue(limits.CPUQuota, effective, effective.CPUMax, "cpu.max", "", ""))
+	}
+	if limits.PidsMax != 0 {
+		helpers.PrintKV(cmd, "pids max", infoLimitValue(fmt.Sprintf("%d", limits.PidsMax), effective, effective.PidsMax, "pids.max", "", ""))
+	}
+	if limits.Nofile != 0 {
+		helpers.PrintKV(cmd, "nofile", infoLimitValue(fmt.Sprintf("%d", limits.Nofile), effective, "", "", effective.Nofile, "RLIMIT_NOFILE"))
+	}
+	if limits.Core != "" {
+		helpers.PrintKV(cmd, "core", infoLimitValue(limits.Core, effective, "", "", effective.Core, "RLIMIT_CORE"))
+	}
+}
+
+// infoLimitValue renders one limit row as "<configured>, effective <value>
+// (<source>)", preferring the cgroup file over the rlimit fallback the same
+// way planLimits does.
+func infoLimitValue(configured string, effective types.EffectiveLimits, cgroupValue, cgroupFile, rlimitValue, rlimitName string) string {
+	switch {
+	case effective.PGID == 0:
+		return fmt.Sprintf("%s, effective N/A", configured)
+	case cgroupValue != "":
+		return fmt.Sprintf("%s, effective %s (%s)", configured, cgroupValue, cgroupFile)
+	case rlimitValue != "":
+		return fmt.Sprintf("%s, effective %s (%s)", configured, rlimitValue, rlimitName)
+	}
+	return fmt.Sprintf("%s, not enforced", configured)
+}
+
+// infoPrintNotifySection shows what a type: notify service's live launch has
+// reported over its notify socket. A launch with no socket (PGID 0: stopped,
+// or started by an earlier daemon) prints every value as N/A.
+func infoPrintNotifySection(cmd *cobra.Command, config *types.ServiceConfig, status types.NotifyStatus) {
+	if config == nil || manager.ServiceType(config.Type) != manager.ServiceTypeNotify {
+		return
+	}
+	helpers.PrintSection(cmd, "Notify")
+	if status.PGID == 0 {
+		helpers.PrintKV(cmd, "ready", "N/A")
+		helpers.PrintKV(cmd, "status", "N/A")
+		helpers.PrintKV(cmd, "main pid", "N/A")
+		helpers.PrintKV(cmd, "last watchdog", "N/A")
+		return
+	}
+	helpers.PrintKV(cmd, "ready", fmt.Sprintf("%t", status.Ready))
+	if status.Status == "" {
+		helpers.PrintKV(cmd, "status", "N/A")
+	} else {
+		helpers.PrintKV(cmd, "status", status.Status)
+	}
+	if status.MainPID == 0 {
+		helpers.PrintKV(cmd, "main pid", fmt.Sprintf("%d", status.PGID))
+	} else {
+		helpers.PrintKV(cmd, "main pid", fmt.Sprintf("%d", status.MainPID))
+	}
+	switch {
+	case config.WatchdogSec == 0:
+		helpers.PrintKV(cmd, "last watchdog", "disabled")
+	case status.LastWatchdog == nil:
+		helpers.PrintKV(cmd, "last watchdog", "never")
+	default:
+		helpers.PrintKV(cmd, "last watchdog", status.LastWatchdog.String())
+	}
+}
+
+// infoPrintProxySection shows a proxy: true service's proxy and, per
+// instance behind it, its internal port and connection counts. A service
+// with no proxy running (Port 0: stopped) prints N/A.
+func infoPrintProxySection(cmd *cobra.Command, config *types.ServiceConfig, status types.ProxyStatus) {
+	if config == nil || !config.Proxy {
+		return
+	}
+	helpers.PrintSection(cmd, "Proxy")
+	if status.Port == 0 {
+		helpers.PrintKV(cmd, "listening", "N/A")
+		return
+	}
+	helpers.PrintKV(cmd, "listening", fmt.Sprintf("port %d", status.Port))
+	for _, backend := range status.Backends {
+		role := "draining"
+		if backend.Serving {
+			role = "serving"
+		}
+		helpers.PrintKV(cmd, fmt.Sprintf("pgid %d", backend.PGID), fmt.Sprintf("%s on port %d, %d open, %d total", role, backend.Port, backend.Connections, backend.TotalConnections))
+	}
+}
+
+// canaryResultReader is the optional manager capability behind eos info's
+// Last canary section, kept off manager.ServiceManager like
+// effectiveLimitsReader.
+type canaryResultReader interface {
+	GetCanaryResult(ctx context.Context, name string) (*types.CanaryResult, error)
+}
+
+func infoFetchCanaryResult(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string) *types.CanaryResult {
+	reader, ok := mgr.(canaryResultReader)
+	if !ok {
+		return nil
+	}
+	result, err := reader.GetCanaryResult(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting canary result: %v", err))
+	}
+	return result
+}
+
+// infoPrintCanarySection shows the service's most recent canary reload (eos
+// reload --canary) and the metrics it was decided on. A service never
+// reloaded with a canary has no section.
+func infoPrintCanarySection(cmd *cobra.Command, result *types.CanaryResult) {
+	if result == nil {
+		return
+	}
+	helpers.PrintSection(cmd, "Last canary")
+	outcome := string(result.Outcome)
+	if result.Reason != "" {
+		outcome += ": " + result.Reason
+	}
+	helpers.PrintKV(cmd, "outcome", outcome)
+	helpers.PrintKV(cmd, "started", result.StartedAt.Format(time.RFC3339))
+	helpers.PrintKV(cmd, "window", fmt.Sprintf("%s of %s", result.EndedAt.Sub(result.StartedAt).Round(time.Second), result.Window))
+	helpers.PrintKV(cmd, "pgid", fmt.Sprintf("%d to %d", result.OldPGID, result.NewPGID))
+	helpers.PrintKV(cmd, "health checks", fmt.Sprintf("%d passed, %d failed", result.HealthChecks-result.HealthFailures, result.HealthFailures))
+	helpers.PrintKV(cmd, "error lines", fmt.Sprintf("%d new, %d old", result.NewErrorLines, result.OldErrorLines))
+	helpers.PrintKV(cmd, "new exited", fmt.Sprintf("%t", result.NewExited))
+}
+
+// releaseLister is the optional manager capability behind eos info's
+// Releases section, kept off manager.ServiceManager like canaryResultReader.
+type releaseLister interface {
+	GetReleases(ctx context.Context, name string) ([]types.Release, error)
+}
+
+func infoFetchReleases(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string) []types.Release {
+	lister, ok := mgr.(releaseLister)
+	if !ok {
+		return nil
+	}
+	releases, err := lister.GetReleases(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting releases: %v", err))
+	}
+	return releases
+}
+
+// infoPrintReleasesSection lists the releases eos deploy has kept for the
+// service, newest first. A service never deployed has no section.
+func infoPrintReleasesSection(cmd *cobra.Command, releases []types.Release) {
+	if len(releases) == 0 {
+		return
+	}
+	helpers.PrintSection(cmd, "Releases")
+	for _, release := range releases {
+		detail := string(release.State)
+		if release.ActivatedAt != nil {
+			detail += ", activated " + release.ActivatedAt.Format(time.RFC3339)
+		}
+		if release.Error != "" {
+			detail += ": " + release.Error
+		}
+		helpers.PrintKV(cmd, release.Name, detail)
+	}
 }
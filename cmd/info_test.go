@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
 	"github.com/Elysium-Labs-EU/eos/internal/database"
@@ -86,7 +87,7 @@ func TestInfoOnlyRegisteredServiceIncompleteCommand(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "/home/user/start-script.sh",
+		Command: types.ServiceCommand{Shell: "/home/user/start-script.sh"},
 		Port:    1337,
 	}
 	yamlData, err := yaml.Marshal(testFile)
@@ -434,6 +435,122 @@ func TestInfoPrintProcessSectionWithLiveOrphan(t *testing.T) {
 	}
 }
 
+func TestInfoPrintLimitsSection(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	config := &types.ServiceConfig{Limits: types.ServiceLimits{MemoryMax: "512M", CPUQuota: "50%", Nofile: 4096}}
+	// A launch without the cpu controller: memory falls back to RLIMIT_AS and
+	// cpu_quota has nothing to read back.
+	effective := types.EffectiveLimits{PGID: 100, Cgroup: true, AddressSpace: "536870912", Nofile: "4096"}
+
+	infoPrintLimitsSection(cmd, config, effective)
+
+	output := out.String()
+	for _, want := range []string{"512M, effective 536870912 (RLIMIT_AS)", "50%, not enforced", "4096, effective 4096 (RLIMIT_NOFILE)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	infoPrintLimitsSection(cmd, config, types.EffectiveLimits{})
+	if !strings.Contains(out.String(), "512M, effective N/A") {
+		t.Errorf("expected N/A effective values for a stopped service, got: %s", out.String())
+	}
+
+	out.Reset()
+	infoPrintLimitsSection(cmd, &types.ServiceConfig{}, effective)
+	if out.Len() != 0 {
+		t.Errorf("expected no Limits section without configured limits, got: %s", out.String())
+	}
+}
+
+func TestInfoPrintNotifySection(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	config := &types.ServiceConfig{Type: "notify"}
+	infoPrintNotifySection(cmd, config, types.NotifyStatus{PGID: 100, Ready: true, Status: "accepting connections", MainPID: 104})
+
+	output := out.String()
+	for _, want := range []string{"Notify", "true", "accepting connections", "104", "disabled"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	infoPrintNotifySection(cmd, &types.ServiceConfig{}, types.NotifyStatus{PGID: 100, Ready: true})
+	if out.Len() != 0 {
+		t.Errorf("expected no Notify section for a type: simple service, got: %s", out.String())
+	}
+}
+
+func TestInfoPrintProxySection(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	config := &types.ServiceConfig{Port: 8080, Proxy: true}
+	infoPrintProxySection(cmd, config, types.ProxyStatus{Port: 8080, Backends: []types.ProxyBackendStatus{
+		{PGID: 100, Port: 41000, Connections: 2, TotalConnections: 40},
+		{PGID: 200, Port: 41001, Connections: 1, TotalConnections: 3, Serving: true},
+	}})
+
+	output := out.String()
+	for _, want := range []string{"Proxy", "port 8080", "draining on port 41000, 2 open, 40 total", "serving on port 41001, 1 open, 3 total"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	infoPrintProxySection(cmd, &types.ServiceConfig{Port: 8080}, types.ProxyStatus{Port: 8080})
+	if out.Len() != 0 {
+		t.Errorf("expected no Proxy section without proxy: true, got: %s", out.String())
+	}
+}
+
+func TestInfoPrintCanarySection(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	infoPrintCanarySection(cmd, &types.CanaryResult{
+		Outcome:        types.CanaryOutcomeRolledBack,
+		Reason:         "new instance failed 3 health checks in a row",
+		StartedAt:      started,
+		EndedAt:        started.Add(90 * time.Second),
+		Window:         5 * time.Minute,
+		OldPGID:        100,
+		NewPGID:        200,
+		NewErrorLines:  4,
+		OldErrorLines:  1,
+		HealthChecks:   10,
+		HealthFailures: 3,
+	})
+
+	output := out.String()
+	for _, want := range []string{"Last canary", "rolled_back: new instance failed 3 health checks in a row", "1m30s of 5m0s", "100 to 200", "7 passed, 3 failed", "4 new, 1 old"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	infoPrintCanarySection(cmd, nil)
+	if out.Len() != 0 {
+		t.Errorf("expected no Last canary section without a canary reload, got: %s", out.String())
+	}
+}
+
 func TestInfoWithRegistryLogSinkRef(t *testing.T) {
 	cmd, outBuf, errBuf, tempDir := setupCmd(t)
 
@@ -595,3 +712,28 @@ func TestInfoCommandInstanceAndHistoryLookupErrors(t *testing.T) {
 // database.ErrServiceNotFound this branch checks for. The generic err != nil
 // branch two lines below (already covered by TestInfoNonExistentServiceCommand
 // via "service not registered") is the one that actually fires.
+
+func TestInfoPrintReleasesSection(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	activated := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	infoPrintReleasesSection(cmd, []types.Release{
+		{Name: "20260103120000", State: types.ReleaseStateFailed, Error: "reload aborted: new instance not ready"},
+		{Name: "20260102120000", State: types.ReleaseStateCurrent, ActivatedAt: &activated},
+	})
+
+	output := out.String()
+	for _, want := range []string{"Releases", "20260103120000", "failed: reload aborted", "current, activated 2026-01-02T12:00:00Z"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	out.Reset()
+	infoPrintReleasesSection(cmd, nil)
+	if out.Len() != 0 {
+		t.Errorf("expected no section for a service never deployed, got: %s", out.String())
+	}
+}
@@ -25,12 +25,12 @@ const initLogSinkHint = "\n# Optional: route logs to a sink plugin.\n# log_sinks
 // initServiceConfig mirrors types.ServiceConfig but with Runtime as a pointer
 // so yaml omitempty works; an empty Runtime struct would otherwise marshal to "runtime: {}".
 type initServiceConfig struct {
-	Runtime       *types.Runtime `yaml:"runtime,omitempty"`
-	Name          string         `yaml:"name"`
-	Command       string         `yaml:"command"`
-	EnvFile       string         `yaml:"env_file,omitempty"`
-	Port          int            `yaml:"port,omitempty"`
-	MemoryLimitMb int            `yaml:"memory_limit_mb,omitempty"`
+	Runtime       *types.Runtime       `yaml:"runtime,omitempty"`
+	Name          string               `yaml:"name"`
+	Command       types.ServiceCommand `yaml:"command"`
+	EnvFile       string               `yaml:"env_file,omitempty"`
+	Port          int                  `yaml:"port,omitempty"`
+	MemoryLimitMb int                  `yaml:"memory_limit_mb,omitempty"`
 }
 
 type runtimeDetection struct {
@@ -96,7 +96,7 @@ func newInitCmd() *cobra.Command {
 
 			cfg := initServiceConfig{
 				Name:    name,
-				Command: command,
+				Command: initParseCommand(command),
 				Port:    port,
 			}
 
@@ -137,7 +137,7 @@ func initCmdConfirmOverwrite(cmd *cobra.Command, reader *bufio.Reader, outputPat
 
 func initCmdPromptBasics(cmd *cobra.Command, reader *bufio.Reader, absDir string) (name, command string, advanced bool, port int) {
 	name = promptLine(cmd, reader, "service name", filepath.Base(absDir))
-	command = promptLine(cmd, reader, "command (blank = skip)", "")
+	command = promptLine(cmd, reader, `command (blank = skip, ["prog", "arg"] = no shell)`, "")
 
 	mode := promptLine(cmd, reader, "mode (s=simple / a=advanced)", "s")
 	advanced = strings.TrimSpace(strings.ToLower(mode)) == "a"
@@ -148,6 +148,19 @@ func initCmdPromptBasics(cmd *cobra.Command, reader *bufio.Reader, absDir string
 	return name, command, advanced, port
 }
 
+// initParseCommand reads the command prompt's answer: a YAML flow list such as
+// ["node", "server.js"] is the exec form, anything else the shell form
+// exactly as typed.
+func initParseCommand(answer string) types.ServiceCommand {
+	if strings.HasPrefix(answer, "[") {
+		var argv []string
+		if err := yaml.Unmarshal([]byte(answer), &argv); err == nil && len(argv) > 0 {
+			return types.ServiceCommand{Argv: argv}
+		}
+	}
+	return types.ServiceCommand{Shell: answer}
+}
+
 func initCmdApplyAdvancedAnswers(cmd *cobra.Command, reader *bufio.Reader, detected runtimeDetection, cfg *initServiceConfig) {
 	runtimeType := promptLine(cmd, reader, "runtime type", detected.runtimeType)
 	runtimePath := promptLine(cmd, reader, "runtime path", detected.suggestedPath)
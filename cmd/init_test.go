@@ -49,7 +49,7 @@ func TestInitCmd_SimpleMode(t *testing.T) {
 	if cfg.Name != filepath.Base(dir) {
 		t.Errorf("name: got %q, want %q", cfg.Name, filepath.Base(dir))
 	}
-	if cfg.Command != "start.sh" {
+	if cfg.Command.Shell != "start.sh" {
 		t.Errorf("command: got %q, want %q", cfg.Command, "start.sh")
 	}
 	if cfg.Port != 3000 {
@@ -90,7 +90,7 @@ func TestInitCmd_AdvancedMode(t *testing.T) {
 	if cfg.Name != "api" {
 		t.Errorf("name: got %q, want api", cfg.Name)
 	}
-	if cfg.Command != "server.js" {
+	if cfg.Command.Shell != "server.js" {
 		t.Errorf("command: got %q", cfg.Command)
 	}
 	if cfg.Port != 3000 {
@@ -102,8 +102,8 @@ func TestInitCmd_AdvancedMode(t *testing.T) {
 	if cfg.Runtime.Path != "~/.nvm/bin" {
 		t.Errorf("runtime path: got %q, want ~/.nvm/bin", cfg.Runtime.Path)
 	}
-	if cfg.EnvFile != ".env" {
-		t.Errorf("env_file: got %q, want .env", cfg.EnvFile)
+	if len(cfg.EnvFile) != 1 || cfg.EnvFile[0] != ".env" {
+		t.Errorf("env_file: got %v, want [.env]", cfg.EnvFile)
 	}
 	if cfg.MemoryLimitMb != 512 {
 		t.Errorf("memory_limit_mb: got %d, want 512", cfg.MemoryLimitMb)
@@ -503,7 +503,7 @@ func TestInitCmd_SkippedCommand(t *testing.T) {
 		t.Fatalf("unmarshal: %v", err)
 	}
 
-	if cfg.Command != "" {
+	if !cfg.Command.IsZero() {
 		t.Errorf("skipped command: got %q, want empty", cfg.Command)
 	}
 }
@@ -603,3 +603,16 @@ func TestInitCmd_WriteFileError(t *testing.T) {
 // cannot fail marshaling it (no channels, funcs, or cyclic references are
 // possible here), so this branch is unreachable dead code from any real
 // input the command can construct.
+
+func TestInitParseCommand(t *testing.T) {
+	if got := initParseCommand(`["node", "dist/server.js"]`); !got.IsExec() || len(got.Argv) != 2 || got.Argv[1] != "dist/server.js" {
+		t.Errorf("list answer: got %+v, want the exec form [node dist/server.js]", got)
+	}
+	if got := initParseCommand("npm start"); got.IsExec() || got.Shell != "npm start" {
+		t.Errorf("plain answer: got %+v, want the shell form", got)
+	}
+	// Not a valid list: kept verbatim as a shell command rather than dropped.
+	if got := initParseCommand("[ -f x ] && ./start.sh"); got.IsExec() || got.Shell != "[ -f x ] && ./start.sh" {
+		t.Errorf("shell test answer: got %+v, want the shell form", got)
+	}
+}
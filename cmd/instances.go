@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// serviceInstanceController is the optional manager capability behind eos
+// run and eos stop given one instance ("name:index") of a service running
+// several, kept off manager.ServiceManager like effectiveLimitsReader.
+type serviceInstanceController interface {
+	StartServiceInstance(ctx context.Context, name string, index int) (int, error)
+	StopServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (manager.StopServiceResult, error)
+	RestartServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error)
+}
+
+// instanceHistoryReader is the optional manager capability behind the
+// per-instance rows of eos status and eos info's Instances section.
+type instanceHistoryReader interface {
+	GetInstanceProcessHistoryEntries(ctx context.Context, name string) ([]types.ProcessHistory, error)
+}
+
+// statusExpandInstances turns the row of a service running several
+// instances into one row per instance, named "name:index", each with its own
+// launch's status, PGID, usage and error. The restart count, start time and
+// cron schedule are the service's and repeat on every row; orphaned PGIDs
+// stay on the first. A manager that can't list instances keeps the one row.
+func statusExpandInstances(cmd *cobra.Command, mgr manager.ServiceManager, entry statusServiceEntry, checkInterval time.Duration, now time.Time) []statusServiceEntry {
+	reader, ok := mgr.(instanceHistoryReader)
+	if !ok {
+		return []statusServiceEntry{entry}
+	}
+	history, err := reader.GetInstanceProcessHistoryEntries(cmd.Context(), entry.Name)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelKeyMsg, ui.LabelError.Render("error"), ui.TextBold.Render(entry.Name), fmt.Sprintf("getting instance process history: %v", err))
+		return []statusServiceEntry{entry}
+	}
+	byIndex := make(map[int]*types.ProcessHistory, len(history))
+	for i := range history {
+		byIndex[history[i].Instance] = &history[i]
+	}
+
+	rows := make([]statusServiceEntry, entry.Instances)
+	for index := range rows {
+		row := entry
+		row.Name = manager.InstanceName(entry.Name, index)
+		if index > 0 {
+			row.OrphanedPGIDs = nil
+		}
+		process := byIndex[index]
+		row.Status = helpers.DetermineServiceStatus(process, len(row.OrphanedPGIDs) > 0)
+		row.Uptime = helpers.DetermineUptimeHuman(process)
+		row.MemoryMb = helpers.DetermineProcessMemoryInMbHuman(0, row.Status)
+		row.CPU = helpers.DetermineProcessCPUHuman(0, row.Status)
+		row.PGID, row.Error, row.Stale = 0, "", false
+		if process != nil {
+			row.PGID = process.PGID
+			row.Error = helpers.DetermineError(process.Error)
+			row.MemoryMb = helpers.DetermineProcessMemoryInMbHuman(process.RssMemoryKb, row.Status)
+			row.CPU = helpers.DetermineProcessCPUHuman(process.CPUPercent, row.Status)
+			row.Stale = helpers.IsProcessHistoryStale(process, checkInterval, now)
+		}
+		if row.Status == types.ServiceStatusFailed && entry.InFailureLoop {
+			row.Status = types.ServiceStatusCrashLoop
+		}
+		rows[index] = row
+	}
+	return rows
+}
+
+// infoFetchInstanceEntries reads the latest launch of each instance, only
+// for a service running several.
+func infoFetchInstanceEntries(cmd *cobra.Command, ctx context.Context, mgr manager.ServiceManager, serviceName string, config *types.ServiceConfig) []types.ProcessHistory {
+	reader, ok := mgr.(instanceHistoryReader)
+	if !ok || config == nil || manager.InstanceCount(config) < 2 {
+		return nil
+	}
+	entries, err := reader.GetInstanceProcessHistoryEntries(ctx, serviceName)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting instance process history: %v", err))
+	}
+	return entries
+}
+
+func infoPrintInstancesSection(cmd *cobra.Command, serviceName string, config *types.ServiceConfig, entries []types.ProcessHistory) {
+	if config == nil || manager.InstanceCount(config) < 2 {
+		return
+	}
+	byIndex := make(map[int]*types.ProcessHistory, len(entries))
+	for i := range entries {
+		byIndex[entries[i].Instance] = &entries[i]
+	}
+	helpers.PrintSection(cmd, "Instances")
+	for index := range manager.InstanceCount(config) {
+		process := byIndex[index]
+		value := helpers.PrintStatus(helpers.DetermineServiceStatus(process, false))
+		if process != nil {
+			value += fmt.Sprintf(", pgid %d", process.PGID)
+		}
+		if port := manager.InstancePort(config, index); port != 0 {
+			value += fmt.Sprintf(", port %d", port)
+		}
+		helpers.PrintKV(cmd, manager.InstanceName(serviceName, index), value)
+	}
+}
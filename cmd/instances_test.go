@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// instanceHistoryMgr is a mockMgr that also lists per-instance history.
+type instanceHistoryMgr struct {
+	*mockMgr
+	entries []types.ProcessHistory
+}
+
+func (m *instanceHistoryMgr) GetInstanceProcessHistoryEntries(context.Context, string) ([]types.ProcessHistory, error) {
+	return m.entries, nil
+}
+
+func TestStatusExpandInstances(t *testing.T) {
+	mgr := &instanceHistoryMgr{mockMgr: &mockMgr{}, entries: []types.ProcessHistory{
+		{Instance: 0, PGID: 100, State: types.ProcessStateRunning},
+		{Instance: 2, PGID: 300, State: types.ProcessStateFailed},
+	}}
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+	entry := statusServiceEntry{Name: "svc", Instances: 3, RestartCount: 4, OrphanedPGIDs: []int{77}, InFailureLoop: true}
+
+	rows := statusExpandInstances(cmd, mgr, entry, time.Minute, time.Now())
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want one per instance: %+v", len(rows), rows)
+	}
+	wants := []struct {
+		name   string
+		pgid   int
+		status types.ServiceStatus
+	}{
+		{"svc:0", 100, types.ServiceStatusRunning},
+		{"svc:1", 0, types.ServiceStatusStopped},
+		{"svc:2", 300, types.ServiceStatusCrashLoop},
+	}
+	for i, want := range wants {
+		row := rows[i]
+		if row.Name != want.name || row.PGID != want.pgid || row.RestartCount != 4 {
+			t.Errorf("row %d = %+v, want %s with pgid %d and the service's restart count", i, row, want.name, want.pgid)
+		}
+		if row.Status != want.status {
+			t.Errorf("row %d status = %s, want %s", i, row.Status, want.status)
+		}
+	}
+	if len(rows[1].OrphanedPGIDs) != 0 || len(rows[0].OrphanedPGIDs) != 1 {
+		t.Errorf("orphaned pgids = %v / %v, want them on the first row only", rows[0].OrphanedPGIDs, rows[1].OrphanedPGIDs)
+	}
+}
+
+func TestInfoPrintInstancesSection(t *testing.T) {
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	config := &types.ServiceConfig{Port: 8080, Instances: 2, IncrementPort: true}
+
+	infoPrintInstancesSection(cmd, "svc", config, []types.ProcessHistory{{Instance: 1, PGID: 200, State: types.ProcessStateRunning}})
+	got := out.String()
+	for _, want := range []string{"Instances", "svc:0", "port 8080", "svc:1", "pgid 200", "port 8081"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Instances section missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -52,7 +52,7 @@ func TestLogsProcessHistoryGenericError(t *testing.T) {
 // alive, which this setup helper must not do.
 func addAndRunLogsService(t *testing.T, cmd *cobra.Command, mgr *manager.LocalManager, tempDir string) *types.ServiceConfig {
 	t.Helper()
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 	scriptPath := filepath.Join(filepath.Dir(path), "start-script.sh")
 	if err := os.WriteFile(scriptPath, []byte("#!/bin/bash\necho a-line\n"), 0755); err != nil {
@@ -62,7 +62,7 @@ func writeServiceYAML(t *testing.T, tempDir string, cfg *types.ServiceConfig) st
 func TestLogsCommand(t *testing.T) {
 	cmd, outBuf, _, tempDir, mgr := setupCmdWithManager(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 
 	cmd.SetArgs([]string{"add", path})
@@ -93,7 +93,7 @@ func TestLogsCommand(t *testing.T) {
 func TestLogsCommandUnresolvableTail(t *testing.T) {
 	cmd, _, errBuf, tempDir, mgr := setupCmdWithManager(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 
 	cmd.SetArgs([]string{"add", path})
@@ -123,7 +123,7 @@ func TestLogsCommandUnresolvableTail(t *testing.T) {
 func TestLogsNeverRanServiceCommand(t *testing.T) {
 	cmd, _, errBuf, tempDir := setupCmd(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 
 	cmd.SetArgs([]string{"add", path})
@@ -166,7 +166,7 @@ func TestLogsNonExistingServiceCommand(t *testing.T) {
 func TestLogsCommandLinesOutOfRange(t *testing.T) {
 	cmd, mgr, _, errBuf, tempDir := setupLogsTestCmd(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 	scriptPath := filepath.Join(filepath.Dir(path), "start-script.sh")
 	if err := os.WriteFile(scriptPath, []byte("#!/bin/bash\necho a-line\n"), 0755); err != nil {
@@ -201,7 +201,7 @@ func TestLogsCommandLinesOutOfRange(t *testing.T) {
 func TestLogsCommandMutuallyExclusiveFlags(t *testing.T) {
 	cmd, _, _, _, tempDir := setupLogsTestCmd(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 
 	cmd.SetArgs([]string{"add", path})
@@ -219,7 +219,7 @@ func TestLogsCommandMutuallyExclusiveFlags(t *testing.T) {
 func TestLogsCommandSingleStreamModes(t *testing.T) {
 	cmd, mgr, outBuf, _, tempDir := setupLogsTestCmd(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 	scriptPath := filepath.Join(filepath.Dir(path), "start-script.sh")
 	if err := os.WriteFile(scriptPath, []byte("#!/bin/bash\necho stdout-line\necho stderr-line >&2\n"), 0755); err != nil {
@@ -287,7 +287,7 @@ func (s *syncBuffer) String() string {
 func TestLogsCommandFollow(t *testing.T) {
 	cmd, _, _, tempDir, mgr := setupCmdWithManager(t)
 
-	cfg := &types.ServiceConfig{Name: "cms", Command: "./start-script.sh", Port: 1337}
+	cfg := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "./start-script.sh"}, Port: 1337}
 	path := writeServiceYAML(t, tempDir, cfg)
 	scriptPath := filepath.Join(filepath.Dir(path), "start-script.sh")
 	if err := os.WriteFile(scriptPath, []byte("#!/bin/bash\nsleep 0.2\necho followed-line\nsleep 5\n"), 0755); err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
 	"github.com/Elysium-Labs-EU/eos/internal/config"
 	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
 	"github.com/Elysium-Labs-EU/eos/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -36,6 +37,7 @@ const (
 )
 
 func newReloadCmd(getManager func() manager.ServiceManager, getConfig func() *config.SystemConfig) *cobra.Command {
+	var canary time.Duration
 	cmd := &cobra.Command{
 		Use:   cmdnames.UseReload,
 		Short: "Zero-downtime reload of a service",
@@ -54,8 +56,23 @@ promptly on startup so the new instance is accepting before the old one is
 drained. eos does not own the socket or proxy traffic; it only sequences the
 cutover. A service that binds without SO_REUSEPORT will fail to start its second
 instance (address already in use) and the reload will abort with the old
-instance untouched.`,
-		Example:           `  eos reload cms    # start a new instance, health-check it, then drain the old one`,
+instance untouched.
+
+A service running several instances (instances: N) is reloaded as a rollout:
+instances are replaced in steps of max_surge plus max_unavailable (one at a
+time if neither is set), each step waiting for its replacements to pass the
+health check before the next begins. The outgoing instances are only drained
+once every step has passed; if a step fails, every replacement is stopped and
+the outgoing instances keep serving.
+
+With --canary, a new instance that passes its health check serves beside the old
+one for the given window before the old one is drained. The new instance is
+stopped and the old one kept if, during the window, the new one exits, fails
+three health checks in a row, or writes more than twice as many error lines to
+stderr as the old one, plus five. The outcome and the numbers behind it show in
+eos info.`,
+		Example: `  eos reload cms                # start a new instance, health-check it, then drain the old one
+  eos reload cms --canary 5m    # serve both for five minutes before draining the old one`,
 		Args:              cobra.ExactArgs(1),
 		SilenceUsage:      true,
 		SilenceErrors:     true,
@@ -90,6 +107,7 @@ instance untouched.`,
 				TickerPeriod:     reloadTickerPeriod,
 				ReadinessTimeout: reloadReadinessTimeout,
 				ProbeInterval:    reloadProbeInterval,
+				Canary:           canary,
 			})
 			if err != nil {
 				if errors.Is(err, manager.ErrServiceNotRunning) {
@@ -97,6 +115,14 @@ instance untouched.`,
 					cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintRun, serviceName)), ui.TextMuted.Render("to start it"))
 					return helpers.ErrCommandFailed
 				}
+				if errors.Is(err, manager.ErrCanaryFailed) && result.Canary != nil {
+					printReloadCanaryFailedOutput(cmd, serviceName, *result.Canary)
+					return helpers.ErrCommandFailed
+				}
+				if errors.Is(err, manager.ErrReloadNotReady) && result.FailedStep > 0 {
+					printReloadRolledBackOutput(cmd, serviceName, result)
+					return helpers.ErrCommandFailed
+				}
 				if errors.Is(err, manager.ErrReloadNotReady) {
 					cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("new instance never became healthy: %v", err))
 					cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), ui.TextBold.Render(serviceName), "kept the old instance running")
@@ -111,13 +137,59 @@ instance untouched.`,
 			return nil
 		},
 	}
+	cmd.Flags().DurationVar(&canary, "canary", 0, "serve the old and new instance side by side this long before finishing the cutover")
 
 	return cmd
 }
 
 func printReloadSuccessOutput(cmd *cobra.Command, serviceName string, result manager.ReloadResult) {
-	cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(serviceName), fmt.Sprintf("reloaded (PGID %d to %d)", result.OldPGID, result.NewPGID))
+	if len(result.Instances) > 0 {
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(serviceName), fmt.Sprintf("reloaded %d instances in %d steps", len(result.Instances), result.Steps))
+		for _, instance := range result.Instances {
+			cmd.Printf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render(manager.InstanceName(serviceName, instance.Instance)), fmt.Sprintf("PGID %d to %d", instance.OldPGID, instance.NewPGID), ui.TextMuted.Render(fmt.Sprintf("(step %d)", instance.Step)))
+		}
+	} else {
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(serviceName), fmt.Sprintf("reloaded (PGID %d to %d)", result.OldPGID, result.NewPGID))
+	}
+	if result.Canary != nil {
+		cmd.Printf(fmtIndentLabelMsg, ui.TextMuted.Render("canary:"), reloadCanaryMetrics(*result.Canary))
+	}
 	cmd.Printf("%s %s %s\n", ui.LabelInfo.Render("note:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintInfo, serviceName)), ui.TextMuted.Render("to view service info"))
 	cmd.Printf("      %s %s\n", ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintLogs, serviceName)), ui.TextMuted.Render("to view logs"))
 	cmd.Printf("      %s\n\n", ui.TextCommand.Render(cmdnames.HintStatus))
 }
+
+// printReloadRolledBackOutput reports a rolling reload whose step FailedStep
+// never became healthy, and what the rollback did to each instance.
+func printReloadRolledBackOutput(cmd *cobra.Command, serviceName string, result manager.ReloadResult) {
+	cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("step %d of %d never became healthy, rolled back", result.FailedStep, result.Steps))
+	for _, instance := range result.Instances {
+		var outcome string
+		switch {
+		case instance.Relaunched:
+			outcome = fmt.Sprintf("relaunched as PGID %d", instance.NewPGID)
+		case instance.RolledBack:
+			outcome = fmt.Sprintf("replacement stopped, PGID %d kept", instance.OldPGID)
+		default:
+			outcome = fmt.Sprintf("untouched, PGID %d", instance.OldPGID)
+		}
+		cmd.PrintErrf(fmtIndentLabelMsgLn, ui.TextMuted.Render(manager.InstanceName(serviceName, instance.Instance)), outcome)
+	}
+	cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), ui.TextBold.Render(serviceName), "kept its old instances running")
+	cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintLogs, serviceName)), ui.TextMuted.Render("to see why it failed"))
+}
+
+// printReloadCanaryFailedOutput reports a canary reload whose new instance
+// didn't hold up over the window, and the metrics it was rolled back on.
+func printReloadCanaryFailedOutput(cmd *cobra.Command, serviceName string, canary types.CanaryResult) {
+	cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("canary failed after %s: %s", canary.EndedAt.Sub(canary.StartedAt).Round(time.Second), canary.Reason))
+	cmd.PrintErrf(fmtIndentLabelMsgLn, ui.TextMuted.Render("canary:"), reloadCanaryMetrics(canary))
+	cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), ui.TextBold.Render(serviceName), fmt.Sprintf("stopped the new instance (PGID %d) and kept the old one (PGID %d)", canary.NewPGID, canary.OldPGID))
+	cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf(cmdnames.FmtHintLogs, serviceName)), ui.TextMuted.Render("to see why it failed"))
+}
+
+// reloadCanaryMetrics summarizes what a canary window measured.
+func reloadCanaryMetrics(canary types.CanaryResult) string {
+	return fmt.Sprintf("%d/%d health checks passed, %d error lines (old instance %d)",
+		canary.HealthChecks-canary.HealthFailures, canary.HealthChecks, canary.NewErrorLines, canary.OldErrorLines)
+}
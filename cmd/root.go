@@ -96,6 +96,10 @@ eos is a service supervisor.
 	rootCmd.AddCommand(newLogsCmd(getManager, noopWarnDaemonDown))
 	rootCmd.AddCommand(newRemoveCmd(getManager, noLocalMode))
 	rootCmd.AddCommand(newReloadCmd(getManager, getConfig))
+	rootCmd.AddCommand(newDeployCmd(getManager, getConfig))
+	rootCmd.AddCommand(newRollbackCmd(getManager, getConfig))
+	rootCmd.AddCommand(newDiffCmd(getManager))
+	rootCmd.AddCommand(newApplyCmd(getManager, getConfig))
 	rootCmd.AddCommand(newRunCmd(getManager, getConfig, noLocalMode))
 	rootCmd.AddCommand(newStatusCmd(getManager, noopWarnDaemonDown, getConfig))
 	rootCmd.AddCommand(newStopCmd(getManager, getConfig, noLocalMode))
@@ -136,6 +140,7 @@ eos is a service supervisor.
 		}, identity, nil
 	}
 	rootCmd.AddCommand(newDaemonCmd(testDaemonConfig))
+	rootCmd.AddCommand(newUpCmd(testDaemonConfig))
 	rootCmd.AddCommand(newSystemCmd(getManager, getConfig, noLocalMode))
 	rootCmd.AddCommand(newAPICmd(getManager, getConfig, testDaemonConfig, noLocalMode))
 	rootCmd.AddCommand(newCompletionCmd(rootCmd))
@@ -156,12 +161,12 @@ func newRootCmd() *cobra.Command {
 
 	lazyInit := func() {
 		once.Do(func() {
-			_, baseDir, c, _, err := newSystemConfig()
+			_, baseDir, c, identity, err := newSystemConfig()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "%s %s\n\n", ui.LabelError.Render("error"), fmt.Sprintf("getting system configuration: %v", err))
 				os.Exit(1)
 			}
-			m, cl, mode, err := newManager(rootCmd, baseDir, c.Daemon, c.Sinks)
+			m, cl, mode, err := newManager(rootCmd, baseDir, identity, c.Daemon, c.Sinks)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "%s %s\n\n", ui.LabelError.Render("error"), fmt.Sprintf("getting manager: %v", err))
 				os.Exit(1)
@@ -217,6 +222,10 @@ eos is a service supervisor.
 	rootCmd.AddCommand(newLogsCmd(getManager, warnIfDaemonDown))
 	rootCmd.AddCommand(newRemoveCmd(getManager, managerModeFn))
 	rootCmd.AddCommand(newReloadCmd(getManager, getConfig))
+	rootCmd.AddCommand(newDeployCmd(getManager, getConfig))
+	rootCmd.AddCommand(newRollbackCmd(getManager, getConfig))
+	rootCmd.AddCommand(newDiffCmd(getManager))
+	rootCmd.AddCommand(newApplyCmd(getManager, getConfig))
 	rootCmd.AddCommand(newRunCmd(getManager, getConfig, managerModeFn))
 	rootCmd.AddCommand(newStatusCmd(getManager, warnIfDaemonDown, getConfig))
 	rootCmd.AddCommand(newStopCmd(getManager, getConfig, managerModeFn))
@@ -231,6 +240,7 @@ eos is a service supervisor.
 		return baseDir, c, identity, err
 	}
 	rootCmd.AddCommand(newDaemonCmd(getDaemonConfig))
+	rootCmd.AddCommand(newUpCmd(getDaemonConfig))
 	rootCmd.AddCommand(newSystemCmd(getManager, getConfig, managerModeFn))
 
 	rootCmd.AddCommand(newCompletionCmd(rootCmd))
@@ -425,6 +435,7 @@ func newSystemConfig() (installDir string, baseDir string, systemConfig *config.
 		Shutdown:     shutdownConfig,
 		Telemetry:    telemetryConfig,
 		BaseDir:      baseDir,
+		ServicesDir:  overrideStringConfigValue("EOS_SERVICES_DIR", eosCfg.ServicesDir),
 		UnderSystemd: config.IsUnderSystemd(),
 		Verbose:      overrideBoolConfigValue("EOS_VERBOSE", false),
 	}
@@ -500,12 +511,12 @@ func safeParseDuration(durationAsString string, fallback time.Duration) time.Dur
 // only manager that can serve a request with no daemon answering, so it backs
 // every case where IPC is unavailable or unwanted: --no-daemon, a config that
 // names no supervisor at all, and a supervised unit that is currently stopped.
-func newLocalManagerWithCleanup(ctx context.Context, baseDir string, verbose bool, sinkRegistry map[string]types.LogSink) (manager.ServiceManager, func(), error) {
+func newLocalManagerWithCleanup(ctx context.Context, baseDir string, identity userutil.Identity, verbose bool, sinkRegistry map[string]types.LogSink) (manager.ServiceManager, func(), error) {
 	db, err := database.NewDB(ctx, baseDir)
 	if err != nil {
 		return nil, nil, fmt.Errorf("connecting to database: %w", err)
 	}
-	mgr := manager.NewLocalManager(db, baseDir, ctx, logutil.NewTextLogger(os.Stderr, verbose), manager.WithSinkRegistry(sinkRegistry))
+	mgr := manager.NewLocalManager(db, baseDir, ctx, logutil.NewTextLogger(os.Stderr, verbose), manager.WithSinkRegistry(sinkRegistry), manager.WithIdentity(identity))
 	cleanup := func() {
 		if closeErr := db.CloseDBConnection(); closeErr != nil {
 			fmt.Printf("closing database connection on cleanup: %v\n", closeErr)
@@ -521,7 +532,7 @@ func newLocalManagerWithCleanup(ctx context.Context, baseDir string, verbose boo
 //
 // The daemon socket is probed at most once per invocation, and only when a
 // daemon is configured at all.
-func newManager(rootCmd *cobra.Command, baseDir string, daemonConfig config.DaemonConfig, sinkRegistry map[string]types.LogSink) (mgr manager.ServiceManager, cleanUp func(), mode localMode, err error) {
+func newManager(rootCmd *cobra.Command, baseDir string, identity userutil.Identity, daemonConfig config.DaemonConfig, sinkRegistry map[string]types.LogSink) (mgr manager.ServiceManager, cleanUp func(), mode localMode, err error) {
 	ctx := rootCmd.Context()
 	noDaemon, err := rootCmd.Flags().GetBool("no-daemon")
 	if err != nil {
@@ -562,7 +573,7 @@ func newManager(rootCmd *cobra.Command, baseDir string, daemonConfig config.Daem
 	// about: read commands must keep serving last-known state (warnIfDaemonDown
 	// says as much), and refuseLocalStart is what stops a write path from
 	// spawning an orphan in that window.
-	mgr, cleanUp, err = newLocalManagerWithCleanup(ctx, baseDir, verbose, sinkRegistry)
+	mgr, cleanUp, err = newLocalManagerWithCleanup(ctx, baseDir, identity, verbose, sinkRegistry)
 	if err != nil {
 		return nil, nil, localMode{}, err
 	}
@@ -184,7 +184,7 @@ func TestNewManagerLocalMode(t *testing.T) {
 		t.Fatalf("setting no-daemon flag: %v", err)
 	}
 
-	mgr, cleanup, mode, err := newManager(rootCmd, td, config.DaemonConfig{Standalone: nil}, nil)
+	mgr, cleanup, mode, err := newManager(rootCmd, td, testutil.ResolveIdentity(t), config.DaemonConfig{Standalone: nil}, nil)
 	if err != nil {
 		t.Fatalf("newManager should not error in local mode: %v", err)
 	}
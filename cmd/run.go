@@ -211,7 +211,7 @@ func runResolveServiceNameFromFile(cmd *cobra.Command, mgr manager.ServiceManage
 
 	cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "starting", ui.TextBold.Render(parsedService.Config.Name))
 
-	printSelfDetachWarnings(cmd, parsedService.Config.Command)
+	printSelfDetachWarnings(cmd, &parsedService.Config)
 
 	registerResult, registerErr := registerServiceIfNeeded(cmd.Context(), mgr, parsedService.YamlFile, parsedService.Config.Name)
 	if registerErr != nil {
@@ -351,10 +351,21 @@ func runResolveStartedAtTicks(ctx context.Context, mgr manager.ServiceManager, s
 	if err != nil {
 		return 0, fmt.Errorf("getting process history: %w", err)
 	}
-	if entry.PGID != pgid {
-		return 0, fmt.Errorf("most recent process history entry is for pgid %d, not the just-started %d", entry.PGID, pgid)
+	if entry.PGID == pgid {
+		return entry.StartedAtTicks, nil
+	}
+	// A service running several instances launches instance 0, whose pgid
+	// StartService returns, before the others.
+	if reader, ok := mgr.(instanceHistoryReader); ok {
+		if entries, err := reader.GetInstanceProcessHistoryEntries(ctx, serviceName); err == nil {
+			for _, instanceEntry := range entries {
+				if instanceEntry.PGID == pgid {
+					return instanceEntry.StartedAtTicks, nil
+				}
+			}
+		}
 	}
-	return entry.StartedAtTicks, nil
+	return 0, fmt.Errorf("most recent process history entry is for pgid %d, not the just-started %d", entry.PGID, pgid)
 }
 
 // runSupervisePollInterval is how often runBlockAndSupervise checks whether
@@ -462,6 +473,57 @@ func runResolveAndStart(cmd *cobra.Command, mgr manager.ServiceManager, cfg *con
 	return result, serviceName, false, err
 }
 
+// runStartInstance starts, or restarts when it is still running, one
+// instance of a service running several, as after "eos stop name:index".
+// The daemon supervises the instance like the rest of the service; in local
+// mode there would be no one to, so it is refused there.
+func runStartInstance(cmd *cobra.Command, mgr manager.ServiceManager, cfg *config.SystemConfig, serviceName string, index int, once bool) error {
+	instanceName := manager.InstanceName(serviceName, index)
+	cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "starting", ui.TextBold.Render(instanceName))
+
+	controller, ok := mgr.(serviceInstanceController)
+	if _, local := mgr.(*manager.LocalManager); !ok || local {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "starting one instance needs the eos daemon")
+		cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf("eos run %s", serviceName)), ui.TextMuted.Render("to run every instance"))
+		return helpers.ErrCommandFailed
+	}
+	if _, err := isServiceRegistered(cmd.Context(), mgr, serviceName); errors.Is(err, ErrServiceNonExistent) {
+		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(serviceName), "is not registered")
+		cmd.PrintErrf(fmtIndentLabelMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(cmdnames.HintRunFlagPath))
+		return helpers.ErrCommandFailed
+	} else if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("handling service name: %v", err))
+		return helpers.ErrCommandFailed
+	}
+	if err := mgr.SetServiceEnabled(cmd.Context(), serviceName, true); err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("persisting run state: %v", err))
+		return helpers.ErrCommandFailed
+	}
+
+	pgid, err := controller.StartServiceInstance(cmd.Context(), serviceName, index)
+	if errors.Is(err, manager.ErrAlreadyRunning) {
+		if once {
+			cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), ui.TextBold.Render(instanceName), "instance is already running")
+			return nil
+		}
+		pgid, err = controller.RestartServiceInstance(cmd.Context(), serviceName, index, cfg.Shutdown.GracePeriod, 200*time.Millisecond)
+		if err != nil {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("restarting instance: %v", err))
+			return helpers.ErrCommandFailed
+		}
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(instanceName), fmt.Sprintf("restarted with PGID: %d", pgid))
+		cmd.Printf("%s %s\n\n", ui.LabelInfo.Render("note:"), ui.TextCommand.Render(cmdnames.HintStatus))
+		return nil
+	}
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("starting instance: %v", err))
+		return helpers.ErrCommandFailed
+	}
+	cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(instanceName), fmt.Sprintf("started with PGID: %d", pgid))
+	cmd.Printf("%s %s\n\n", ui.LabelInfo.Render("note:"), ui.TextCommand.Render(cmdnames.HintStatus))
+	return nil
+}
+
 // --wait, optional flag will be added later.
 func newRunCmd(getManager func() manager.ServiceManager, getConfig func() *config.SystemConfig, managerMode localModeFn) *cobra.Command {
 	runCmd := &cobra.Command{
@@ -482,7 +544,8 @@ func newRunCmd(getManager func() manager.ServiceManager, getConfig func() *confi
 		Examples:
 		eos run myservice              start or restart a registered service
 		eos run -f ./myservice.yaml    register and start from a service file
-		eos run --once myservice       start only if not already running`,
+		eos run --once myservice       start only if not already running
+		eos run myservice:2            start or restart one instance of a service`,
 
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return runValidArgs(cmd, args, toComplete, getManager)
@@ -506,6 +569,12 @@ func newRunCmd(getManager func() manager.ServiceManager, getConfig func() *confi
 				return helpers.ErrCommandFailed
 			}
 
+			if len(args) > 0 && serviceFile == "" {
+				if name, index, ok := manager.ParseInstanceName(args[0]); ok {
+					return runStartInstance(cmd, mgr, cfg, name, index, once)
+				}
+			}
+
 			startResult, serviceName, skip, err := runResolveAndStart(cmd, mgr, cfg, args, serviceFile, once)
 			if err != nil {
 				return err
@@ -174,7 +174,7 @@ func TestRunWithNameCatalogEntryGenuineError(t *testing.T) {
 // startOrRestartService is ever reached.
 func TestRunWithNameStartServiceGenuineError(t *testing.T) {
 	tempDir := t.TempDir()
-	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{Name: "web", Command: "/bin/true"})
+	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}})
 
 	mgr := &fakeRunMgr{isRegistered: true, catalogEntry: entry, startErr: errors.New("fork/exec: resource temporarily unavailable")}
 	cmd, errBuf := newRunCmdWithFakeMgr(t, mgr)
@@ -47,7 +47,7 @@ func TestGateDependencies_NoDeps(t *testing.T) {
 	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
-	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{Name: "solo", Command: "/bin/true"})
+	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{Name: "solo", Command: types.ServiceCommand{Shell: "/bin/true"}})
 
 	if err := gateDependencies(t.Context(), newGateTestCmd(), mgr, &entry); err != nil {
 		t.Fatalf("expected no error for a service with no depends_on, got %v", err)
@@ -63,7 +63,7 @@ func TestGateDependencies_DependencyReady(t *testing.T) {
 	}
 
 	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/true", DependsOn: []string{"proxy"}, MaxWait: "2s",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"proxy"}, MaxWait: "2s",
 	})
 
 	var outBuf bytes.Buffer
@@ -89,7 +89,7 @@ func TestGateDependencies_MaxWaitFailsLoud(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/true", DependsOn: []string{"never-started"}, MaxWait: "150ms",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"never-started"}, MaxWait: "150ms",
 	})
 
 	err := gateDependencies(t.Context(), newGateTestCmd(), mgr, &entry)
@@ -115,7 +115,7 @@ func TestGateDependencies_SelfDependencyFailsLoud(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "loop", Command: "/bin/true", DependsOn: []string{"loop"}, MaxWait: "150ms",
+		Name: "loop", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"loop"}, MaxWait: "150ms",
 	})
 
 	// Run in a goroutine so a regression that reintroduces an unbounded wait
@@ -151,10 +151,10 @@ func TestGateDependencies_CycleFailsLoudNoHang(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "api", Command: "/bin/true", DependsOn: []string{"web"}, MaxWait: "150ms",
+		Name: "api", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"web"}, MaxWait: "150ms",
 	})
 	web := writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/true", DependsOn: []string{"api"}, MaxWait: "150ms",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"api"}, MaxWait: "150ms",
 	})
 
 	done := make(chan error, 1)
@@ -196,7 +196,7 @@ func TestGateDependencies_MaxWaitParseError(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	entry := writeGateTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/true", DependsOn: []string{"proxy"}, MaxWait: "not-a-duration",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"proxy"}, MaxWait: "not-a-duration",
 	})
 
 	if err := gateDependencies(t.Context(), newGateTestCmd(), mgr, &entry); err == nil {
@@ -955,7 +955,7 @@ func TestRunWithFileInvalidConfigRejected(t *testing.T) {
 	cmd, _, errBuf, tempDir := setupCmd(t)
 
 	testFile := testutil.NewTestServiceConfigFile(t, testutil.WithoutRuntime())
-	testFile.Command = ""
+	testFile.Command = types.ServiceCommand{}
 
 	yamlData, err := yaml.Marshal(testFile)
 	if err != nil {
@@ -1081,7 +1081,7 @@ func TestRunWithDependsOnMaxWaitFailsLoud(t *testing.T) {
 	}
 
 	cfg := &types.ServiceConfig{
-		Name: "web", Command: "/bin/true", DependsOn: []string{"never-started"}, MaxWait: "150ms",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/true"}, DependsOn: []string{"never-started"}, MaxWait: "150ms",
 	}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
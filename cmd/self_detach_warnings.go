@@ -2,16 +2,22 @@ package cmd
 
 import (
 	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
 	"github.com/Elysium-Labs-EU/eos/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// printSelfDetachWarnings prints each self-detach-risk warning for command in
-// the shared CLI format. Single choke point so cmd/run.go, cmd/add.go, and
-// cmd/validate.go can't drift out of sync (see issue #94's OpenForkStderrLog
-// lesson: duplicated follow-up logic at N call sites is how one gets missed).
-func printSelfDetachWarnings(cmd *cobra.Command, command string) {
-	for _, w := range manager.DetectSelfDetachRisk(command) {
+// printSelfDetachWarnings prints each self-detach-risk warning for config's
+// command in the shared CLI format. Single choke point so cmd/run.go,
+// cmd/add.go, and cmd/validate.go can't drift out of sync (see issue #94's
+// OpenForkStderrLog lesson: duplicated follow-up logic at N call sites is how
+// one gets missed). A type: forking service is expected to detach, and eos
+// follows it through pid_file, so it gets no warning.
+func printSelfDetachWarnings(cmd *cobra.Command, config *types.ServiceConfig) {
+	if manager.ServiceType(config.Type) == manager.ServiceTypeForking {
+		return
+	}
+	for _, w := range manager.DetectSelfDetachRisk(config.Command) {
 		cmd.PrintErrf("%s %s\n", ui.LabelWarning.Render("warning"), w)
 	}
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -131,6 +132,14 @@ type statusServiceEntry struct {
 	PGID          int
 	RestartCount  int
 	Stale         bool
+	// RestartPending is set when the running launch's config differs from
+	// the service's config on disk (see printDiffOutput).
+	RestartPending bool
+	// Instances is the service's instances: count, 1 when unset.
+	Instances int
+	// InFailureLoop carries the service's crash-loop overlay (see
+	// helpers.InFailureLoop) to the per-instance rows.
+	InFailureLoop bool
 }
 
 // buildStatusServiceEntry resolves a single registered service's display row.
@@ -179,6 +188,8 @@ func buildStatusServiceEntry(cmd *cobra.Command, mgr manager.ServiceManager, reg
 		MemoryMb:      helpers.DetermineProcessMemoryInMbHuman(0, status),
 		CPU:           helpers.DetermineProcessCPUHuman(0, status),
 		OrphanedPGIDs: helpers.ExtractPGIDs(orphans),
+		Instances:     manager.InstanceCount(config),
+		InFailureLoop: helpers.InFailureLoop(serviceInstance),
 	}
 	if mostRecentProcess != nil {
 		entry.PGID = mostRecentProcess.PGID
@@ -194,7 +205,7 @@ func buildStatusServiceEntry(cmd *cobra.Command, mgr manager.ServiceManager, reg
 	// Overlays a Failed status the same way ServiceStatusWaitingForDeps does
 	// below: FailureLoopCount lives on ServiceInstance, not ProcessHistory, so
 	// helpers.DetermineServiceStatus above can't see it.
-	if entry.Status == types.ServiceStatusFailed && helpers.InFailureLoop(serviceInstance) {
+	if entry.Status == types.ServiceStatusFailed && entry.InFailureLoop {
 		entry.Status = types.ServiceStatusCrashLoop
 	}
 	switch {
@@ -213,9 +224,24 @@ func buildStatusServiceEntry(cmd *cobra.Command, mgr manager.ServiceManager, reg
 		entry.Status = types.ServiceStatusWaitingForDeps
 		entry.Error = "waiting for: " + strings.Join(pending, ", ")
 	}
+	if entry.Status == types.ServiceStatusRunning {
+		entry.RestartPending = statusRestartPending(cmd.Context(), mgr, regServiceName)
+	}
 	return entry, true
 }
 
+// statusRestartPending reports whether name's running launch has config
+// changes it hasn't picked up. A manager that can't tell, or a comparison
+// that fails, leaves the flag off rather than holding up the table.
+func statusRestartPending(ctx context.Context, mgr manager.ServiceManager, name string) bool {
+	reader, ok := mgr.(configDriftReader)
+	if !ok {
+		return false
+	}
+	drift, err := reader.GetConfigDrift(ctx, name)
+	return err == nil && drift != nil && drift.Pending()
+}
+
 // buildStatusRows renders resolved service entries into table cells.
 // staleRows[i] tracks whether data row i has a stale process_history row, so
 // the table's StyleFunc (which only sees row/col indices) can dim it. A stale
@@ -232,6 +258,9 @@ func buildStatusRows(activeServices []statusServiceEntry) (rows [][]string, stal
 		if svc.Stale {
 			status += " " + ui.TextMuted.Render("(stale)")
 		}
+		if svc.RestartPending {
+			status += " " + ui.TextMuted.Render("(restart pending)")
+		}
 		rows = append(rows, []string{
 			svc.Name,
 			status,
@@ -287,6 +316,10 @@ func printStatusTable(cmd *cobra.Command, mgr manager.ServiceManager, checkInter
 		if !ok {
 			continue
 		}
+		if entry.Instances > 1 && entry.Status != types.ServiceStatusWaitingForDeps {
+			activeServices = append(activeServices, statusExpandInstances(cmd, mgr, entry, checkInterval, now)...)
+			continue
+		}
 		activeServices = append(activeServices, entry)
 	}
 
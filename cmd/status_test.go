@@ -588,7 +588,7 @@ func TestPrintStatusTable_GetCatalogError(t *testing.T) {
 
 func writeStatusTestService(t *testing.T, dir string) {
 	t.Helper()
-	cfg := &types.ServiceConfig{Name: "svc", Command: "./start.sh"}
+	cfg := &types.ServiceConfig{Name: "svc", Command: types.ServiceCommand{Shell: "./start.sh"}}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal service config: %v", err)
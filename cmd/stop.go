@@ -23,7 +23,8 @@ func newStopCmd(getManager func() manager.ServiceManager, getConfig func() *conf
 This persists across a daemon restart, reboot, or "eos system update": the
 service stays down until you bring it back with "eos run".`,
 		Example: `  eos stop cms              # graceful stop with configurable grace period
-  eos stop cms --force      # immediate kill`,
+  eos stop cms --force      # immediate kill
+  eos stop cms:2            # stop one instance, leaving the others running`,
 		Args:              cobra.ExactArgs(1),
 		SilenceUsage:      true,
 		SilenceErrors:     true,
@@ -39,6 +40,10 @@ service stays down until you bring it back with "eos run".`,
 				return err
 			}
 
+			if name, index, ok := manager.ParseInstanceName(serviceName); ok {
+				return stopCmdStopInstance(cmd, mgr, name, index, forceQuit, cfg.Shutdown.GracePeriod)
+			}
+
 			stopCmdPrintStarting(cmd, serviceName, forceQuit)
 
 			if err := stopCmdEnsureRegistered(cmd, mgr, serviceName); err != nil {
@@ -64,6 +69,49 @@ service stays down until you bring it back with "eos run".`,
 	return cmd
 }
 
+// stopCmdStopInstance stops one instance of a service running several. It
+// neither persists the service as stopped nor removes its instance row: the
+// service's other instances keep running, and "eos run name:index" brings
+// this one back.
+func stopCmdStopInstance(cmd *cobra.Command, mgr manager.ServiceManager, serviceName string, index int, forceQuit bool, gracePeriod time.Duration) error {
+	instanceName := manager.InstanceName(serviceName, index)
+	if forceQuit {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "--force stops a whole service, not one instance")
+		cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(fmt.Sprintf("eos stop %s --force", serviceName)), ui.TextMuted.Render("to force stop every instance"))
+		return helpers.ErrCommandFailed
+	}
+	stopCmdPrintStarting(cmd, instanceName, false)
+
+	if err := stopCmdEnsureRegistered(cmd, mgr, serviceName); err != nil {
+		return err
+	}
+	controller, ok := mgr.(serviceInstanceController)
+	if !ok {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "stopping one instance is not supported by this manager")
+		return helpers.ErrCommandFailed
+	}
+
+	stopResult, err := controller.StopServiceInstance(cmd.Context(), serviceName, index, gracePeriod, 200*time.Millisecond)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("stopping instance: %v", err))
+		return helpers.ErrCommandFailed
+	}
+	if len(stopResult.Errored) > 0 {
+		cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), "failed to gracefully stop", ui.TextBold.Render(instanceName))
+		for erroredPGID, erroredMsg := range stopResult.Errored {
+			cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), ui.TextBold.Render(fmt.Sprintf("PGID %d:", erroredPGID)), erroredMsg)
+		}
+		return helpers.ErrCommandFailed
+	}
+	if len(stopResult.Stopped) == 0 {
+		cmd.Printf(fmtLabelMsg, ui.LabelWarning.Render("warning"), "no running processes found")
+		return nil
+	}
+	stopCmdPrintStoppedCount(cmd, len(stopResult.Stopped))
+	stopCmdPrintStaleDataWarning(cmd, len(stopResult.StaleData))
+	return nil
+}
+
 func stopCmdPrintStarting(cmd *cobra.Command, serviceName string, forceQuit bool) {
 	if forceQuit {
 		cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "forcefully stopping", ui.TextBold.Render(serviceName))
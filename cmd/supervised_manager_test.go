@@ -80,7 +80,7 @@ func TestNewManagerSupervisedLive(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			sock := listenSocket(t)
 
-			mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), t.TempDir(), tt.daemon(sock), nil)
+			mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), t.TempDir(), testutil.ResolveIdentity(t), tt.daemon(sock), nil)
 			if err != nil {
 				t.Fatalf("newManager: %v", err)
 			}
@@ -109,7 +109,7 @@ func TestNewManagerSupervisedDownFallsBackLocal(t *testing.T) {
 	_, _, td := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	deadSock := filepath.Join(shortTempSocketDir(t), "eos.sock")
 
-	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), td,
+	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), td, testutil.ResolveIdentity(t),
 		config.DaemonConfig{Systemd: &config.SystemdConfig{SocketPath: deadSock}}, nil)
 	if err != nil {
 		t.Fatalf("newManager: %v", err)
@@ -136,7 +136,7 @@ func TestNewManagerSupervisedDownWithNoDaemonFlag(t *testing.T) {
 	_, _, td := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	deadSock := filepath.Join(shortTempSocketDir(t), "eos.sock")
 
-	_, cleanup, mode, err := newManager(newManagerCmd(t, true), td,
+	_, cleanup, mode, err := newManager(newManagerCmd(t, true), td, testutil.ResolveIdentity(t),
 		config.DaemonConfig{Systemd: &config.SystemdConfig{SocketPath: deadSock}}, nil)
 	if err != nil {
 		t.Fatalf("newManager: %v", err)
@@ -176,7 +176,7 @@ func TestNewManagerNoDaemonFlagWinsOverLiveDaemon(t *testing.T) {
 			_, _, td := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 			sock := listenSocket(t)
 
-			mgr, cleanup, mode, err := newManager(newManagerCmd(t, true), td, tt.daemon(sock), nil)
+			mgr, cleanup, mode, err := newManager(newManagerCmd(t, true), td, testutil.ResolveIdentity(t), tt.daemon(sock), nil)
 			if err != nil {
 				t.Fatalf("newManager: %v", err)
 			}
@@ -210,7 +210,7 @@ func TestNewManagerStandaloneLiveUsesDaemonManager(t *testing.T) {
 		t.Fatalf("writing pid file: %v", err)
 	}
 
-	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), t.TempDir(),
+	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), t.TempDir(), testutil.ResolveIdentity(t),
 		config.DaemonConfig{Standalone: &config.StandaloneDaemonConfig{
 			SocketPath:    sock,
 			PIDFile:       pidFile,
@@ -235,7 +235,7 @@ func TestNewManagerStandaloneLiveUsesDaemonManager(t *testing.T) {
 // preparing the fork fails before anything is spawned.
 func TestNewManagerStandaloneStartFailurePropagates(t *testing.T) {
 	dir := shortTempSocketDir(t)
-	_, _, _, err := newManager(newManagerCmd(t, false), t.TempDir(),
+	_, _, _, err := newManager(newManagerCmd(t, false), t.TempDir(), testutil.ResolveIdentity(t),
 		config.DaemonConfig{Standalone: &config.StandaloneDaemonConfig{
 			SocketPath:    filepath.Join(dir, "eos.sock"),
 			PIDFile:       filepath.Join(dir, "no-such-dir", "eos.pid"),
@@ -253,7 +253,7 @@ func TestNewManagerFlagLookupError(t *testing.T) {
 	rootCmd := &cobra.Command{Use: "eos"}
 	rootCmd.SetContext(t.Context())
 
-	if _, _, _, err := newManager(rootCmd, t.TempDir(), config.DaemonConfig{}, nil); err == nil {
+	if _, _, _, err := newManager(rootCmd, t.TempDir(), testutil.ResolveIdentity(t), config.DaemonConfig{}, nil); err == nil {
 		t.Fatal("expected an error when the no-daemon flag is not registered")
 	}
 }
@@ -267,7 +267,7 @@ func TestNewManagerLocalManagerErrorPropagates(t *testing.T) {
 		t.Fatalf("writing fixture: %v", err)
 	}
 
-	if _, _, _, err := newManager(newManagerCmd(t, true), notADir, config.DaemonConfig{}, nil); err == nil {
+	if _, _, _, err := newManager(newManagerCmd(t, true), notADir, testutil.ResolveIdentity(t), config.DaemonConfig{}, nil); err == nil {
 		t.Fatal("expected newManager to surface the database open failure")
 	}
 }
@@ -279,7 +279,7 @@ func TestNewManagerLocalManagerErrorPropagates(t *testing.T) {
 func TestNewManagerUnconfiguredWithoutNoDaemonFlag(t *testing.T) {
 	_, _, td := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 
-	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), td, config.DaemonConfig{}, nil)
+	mgr, cleanup, mode, err := newManager(newManagerCmd(t, false), td, testutil.ResolveIdentity(t), config.DaemonConfig{}, nil)
 	if err != nil {
 		t.Fatalf("newManager: %v", err)
 	}
@@ -302,7 +302,7 @@ func TestNewManagerStandaloneDownIsNotAnOutage(t *testing.T) {
 	_, _, td := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	deadSock := filepath.Join(shortTempSocketDir(t), "eos.sock")
 
-	_, cleanup, mode, err := newManager(newManagerCmd(t, true), td,
+	_, cleanup, mode, err := newManager(newManagerCmd(t, true), td, testutil.ResolveIdentity(t),
 		config.DaemonConfig{Standalone: &config.StandaloneDaemonConfig{SocketPath: deadSock}}, nil)
 	if err != nil {
 		t.Fatalf("newManager: %v", err)
@@ -338,7 +338,7 @@ func sysResolveDaemonController(cmd *cobra.Command) DaemonController {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting config: %v", err))
 		os.Exit(1)
 	}
-	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
+	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, systemConfig.ServicesDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
 	if err != nil {
 		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("resolving daemon mode: %v", err))
 		os.Exit(1)
@@ -851,6 +851,11 @@ func renderUnitFile(installDir string, user string, userUnit bool) (string, erro
 	// every RestartSec=5s. Widening the window to 60s lets 5 restarts land
 	// inside it, so systemd gives up and enters "failed" instead of looping
 	// indefinitely. This mirrors OpenRC's supervise-daemon --respawn-max 5.
+	//
+	// Delegate=yes hands the unit's cgroup subtree to the daemon, which is what
+	// lets it create eos.slice/<service> beneath itself and track each service
+	// by cgroup rather than process group (see internal/cgroup). Without it
+	// systemd owns the subtree and the daemon falls back to PGID tracking.
 	const systemUnitTemplate = `[Unit]
 Description=eos deployment daemon
 After=network.target
@@ -862,6 +867,7 @@ Type=simple
 ExecStart={{.ExecStart}} daemon start --foreground
 Restart=always
 RestartSec=5s
+Delegate=yes
 User={{.User}}
 
 [Install]
@@ -878,6 +884,7 @@ Type=simple
 ExecStart={{.ExecStart}} daemon start --foreground
 Restart=always
 RestartSec=5s
+Delegate=yes
 
 [Install]
 WantedBy=default.target`
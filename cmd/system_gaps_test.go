@@ -1128,7 +1128,7 @@ func TestUpdateCmd_DeclineUpgrade(t *testing.T) {
 	if err != nil {
 		t.Fatalf("newSystemConfig: %v", err)
 	}
-	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
+	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, systemConfig.ServicesDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
 	if err != nil {
 		t.Fatalf("newDaemonController: %v", err)
 	}
@@ -537,6 +537,20 @@ func TestRenderUnitFile_CapsCrashLoop(t *testing.T) {
 	}
 }
 
+func TestRenderUnitFile_DelegatesCgroupSubtree(t *testing.T) {
+	// Without Delegate=yes the daemon can't create eos.slice under its own
+	// cgroup and silently falls back to process-group tracking.
+	for _, userUnit := range []bool{false, true} {
+		unit, err := renderUnitFile("/usr/local/bin", "eos", userUnit)
+		if err != nil {
+			t.Fatalf("renderUnitFile returned error: %v", err)
+		}
+		if !strings.Contains(unit, "Delegate=yes") {
+			t.Errorf("expected unit (userUnit=%v) to contain Delegate=yes, got:\n%s", userUnit, unit)
+		}
+	}
+}
+
 func TestUnstartupCmdNonSystemdRuntime(t *testing.T) {
 	c, _, errBuf := makeTestCmd(t)
 	var calls []string
@@ -1789,7 +1803,7 @@ func TestSystemUpdateWithInvalidOSArchCombinationCommand(t *testing.T) {
 		t.Fatalf("preparing update test - newSystemConfig should not return an error: %v\n", err)
 	}
 
-	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
+	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, systemConfig.ServicesDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
 	if err != nil {
 		t.Fatalf("preparing update test - newDaemonController should not return an error: %v\n", err)
 	}
@@ -1834,7 +1848,7 @@ func TestSystemUpdateWithLowerVersionCommand(t *testing.T) {
 		t.Fatalf("preparing update test - newSystemConfig should not return an error: %v\n", err)
 	}
 
-	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
+	ctrl, err := newDaemonController(systemConfig.Daemon, baseDir, systemConfig.ServicesDir, &systemConfig.Health, systemConfig.Shutdown, systemConfig.Telemetry, systemConfig.UnderSystemd, identity)
 	if err != nil {
 		t.Fatalf("preparing update test - newDaemonController should not return an error: %v\n", err)
 	}
@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/cmd/helpers"
+	"github.com/Elysium-Labs-EU/eos/internal/cmdnames"
+	"github.com/Elysium-Labs-EU/eos/internal/config"
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/logutil"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/monitor"
+	"github.com/Elysium-Labs-EU/eos/internal/otelx"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/ui"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// upPrefixColors are cycled through across the services eos up runs, so
+// neighbouring services' lines can be told apart at a glance.
+var upPrefixColors = []lipgloss.Color{
+	ui.ColorAccent,
+	ui.ColorSuccess,
+	ui.ColorWarning,
+	ui.ColorInfo,
+	lipgloss.Color("13"), // bright magenta
+	lipgloss.Color("6"),  // cyan
+}
+
+func newUpCmd(getDaemonConfig func() (string, *config.SystemConfig, userutil.Identity, error)) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   cmdnames.Up + " [flags] [name...]",
+		Short: "Run several services in the foreground",
+		Long: `Run several services in this process, in the foreground, for local
+development and containers.
+
+Given names, eos up starts those registered services and everything they
+depends_on. Given a stack manifest with -f, or with neither when
+eos.stack.yaml is in the current directory, it registers the manifest's
+services as eos apply would and starts them, or only the named ones and
+their dependencies. Services start after the ones they depend on.
+
+Every service's output is printed here, each line prefixed with its
+service's name, as well as written to its usual log files. The health
+monitor runs in this process too, so a crashed service is restarted exactly
+as the daemon would restart it. Ctrl-C (SIGINT/SIGTERM) stops the services
+in reverse dependency order and exits.
+
+eos up refuses to run beside a live eos daemon for the same base dir, which
+is already supervising the catalog.`,
+		Example: `  eos up                         # run ./eos.stack.yaml
+  eos up api worker              # run two registered services
+  eos up -f stacks/dev.yaml api  # run api from a manifest`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			baseDir, cfg, identity, err := getDaemonConfig()
+			if err != nil {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("getting system configuration: %v", err))
+				return helpers.ErrCommandFailed
+			}
+			if endpoint, ok := config.ResolveDaemonEndpoint(cfg.Daemon); ok && socketResponds(cmd.Context(), endpoint.SocketPath) {
+				cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), "refusing to run in the foreground: an eos daemon is live on this base dir")
+				cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("note:"), ui.TextBold.Render(endpoint.SocketPath), ui.TextMuted.Render("is answering"))
+				cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("use:"), ui.TextCommand.Render(cmdnames.Root+" "+cmdnames.Apply), ui.TextMuted.Render("to have the daemon run a stack"))
+				return helpers.ErrCommandFailed
+			}
+			return runUp(cmd, baseDir, cfg, identity, file, args)
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the stack manifest to run (default ./"+manager.StackManifestFileName+" when no names are given)")
+
+	return cmd
+}
+
+// runUp opens its own LocalManager and health monitor on baseDir, starts the
+// services up resolves, and supervises them until interrupted. The manager's
+// context is kept apart from the command's, which the interrupt cancels:
+// stopping the services afterwards goes through that manager, and a manager
+// whose context is already canceled can't.
+func runUp(cmd *cobra.Command, baseDir string, cfg *config.SystemConfig, identity userutil.Identity, file string, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	logger := logutil.NewTextLogger(cmd.ErrOrStderr(), verbose)
+	out := &upOutput{w: cmd.OutOrStdout()}
+
+	db, err := database.NewDB(cmd.Context(), baseDir)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("connecting to database: %v", err))
+		return helpers.ErrCommandFailed
+	}
+	mgrCtx, cancelMgr := context.WithCancel(context.WithoutCancel(cmd.Context()))
+	mgr := manager.NewLocalManager(db, baseDir, mgrCtx, logger,
+		manager.WithSinkRegistry(cfg.Sinks),
+		manager.WithShutdownGracePeriod(cfg.Shutdown.GracePeriod),
+		manager.WithOutputTee(out.line),
+		manager.WithIdentity(identity),
+	)
+	defer func() {
+		cancelMgr()
+		mgr.WaitServices()
+		mgr.WaitPipes()
+		if closeErr := db.CloseDBConnection(); closeErr != nil {
+			cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), fmt.Sprintf("closing database connection: %v", closeErr))
+		}
+	}()
+
+	entries, err := upResolveServices(mgrCtx, mgr, file, args)
+	if err != nil {
+		cmd.PrintErrf(fmtLabelMsg, ui.LabelError.Render("error"), err.Error())
+		if errors.Is(err, errUpNothingToRun) {
+			cmd.PrintErrf(fmtIndentLabelTwoMsgLn, ui.TextMuted.Render("run:"), ui.TextCommand.Render(cmdnames.Root+" "+cmdnames.Up+" <name>..."), ui.TextMuted.Render("to run registered services"))
+			cmd.PrintErrf(fmtIndentLabelTwoMsg, ui.TextMuted.Render("run:"), ui.TextCommand.Render(cmdnames.Root+" "+cmdnames.Up+" -f "+cmdnames.ArgPath), ui.TextMuted.Render("to run a stack manifest"))
+		}
+		return helpers.ErrCommandFailed
+	}
+	names := make([]string, len(entries))
+	for i := range entries {
+		names[i] = entries[i].Name
+	}
+	out.setServices(names)
+
+	sigCtx, stopNotify := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopNotify()
+
+	// The monitor runs from the start: it is what marks a starting service
+	// running, which the dependency gate of the services after it waits on.
+	healthMonitor := monitor.NewHealthMonitor(mgr, db, logger, &cfg.Health, cfg.Shutdown, otelx.NoopHandles())
+	healthMonitor.Scope(names)
+	monitorCtx, stopMonitor := context.WithCancel(mgrCtx)
+	monitorDone := make(chan struct{})
+	go func() {
+		defer close(monitorDone)
+		healthMonitor.Start(monitorCtx)
+	}()
+
+	started, startErr := upStart(sigCtx, cmd, mgr, entries, cfg.Shutdown.GracePeriod)
+	if startErr == nil {
+		cmd.PrintErrf(fmtLabelMsgLn, ui.LabelInfo.Render("note:"), fmt.Sprintf("running %d services in the foreground; press Ctrl-C to stop them", len(started)))
+		<-sigCtx.Done()
+	}
+
+	// Stopped before the services are, so it can't restart one on its way
+	// down.
+	stopMonitor()
+	<-monitorDone
+	stopErr := upStop(mgrCtx, cmd, mgr, started, cfg.Shutdown.GracePeriod)
+	if startErr != nil {
+		return startErr
+	}
+	return stopErr
+}
+
+// errUpNothingToRun is upResolveServices' error when given no names, no
+// manifest, and there is no eos.stack.yaml to fall back to.
+var errUpNothingToRun = errors.New("no services specified and no " + manager.StackManifestFileName + " in the current directory")
+
+// upResolveServices returns the services eos up runs, in start order. From a
+// manifest, they are registered, or their catalog entries repointed, as eos
+// apply would; names then pick out some of them. Without a manifest, names
+// are looked up in the catalog.
+func upResolveServices(ctx context.Context, mgr manager.ServiceManager, file string, names []string) ([]manager.StackEntry, error) {
+	if file == "" && len(names) == 0 {
+		if _, err := os.Stat(manager.StackManifestFileName); err != nil {
+			return nil, errUpNothingToRun
+		}
+		file = manager.StackManifestFileName
+	}
+
+	var entries []manager.StackEntry
+	var err error
+	if file == "" {
+		entries, err = upCatalogServices(ctx, mgr, names)
+	} else {
+		entries, err = upManifestServices(file, names)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ordered, err := manager.StackStartOrder(entries)
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		for i := range ordered {
+			if err := upRegister(ctx, mgr, &ordered[i]); err != nil {
+				return nil, fmt.Errorf("registering %s: %w", ordered[i].Name, err)
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// upCatalogServices looks names up in the catalog, along with every service
+// they depends_on, directly or not.
+func upCatalogServices(ctx context.Context, mgr manager.ServiceManager, names []string) ([]manager.StackEntry, error) {
+	var entries []manager.StackEntry
+	seen := make(map[string]bool)
+	dependents := make(map[string]string)
+	queue := slices.Clone(names)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		service, err := mgr.GetServiceCatalogEntry(ctx, name)
+		if errors.Is(err, manager.ErrServiceNotRegistered) {
+			if dependent, ok := dependents[name]; ok {
+				return nil, fmt.Errorf("%s depends on %s, which is not registered", dependent, name)
+			}
+			return nil, fmt.Errorf("%s is not registered", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting %s: %w", name, err)
+		}
+		serviceConfig, err := manager.LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+		if err != nil {
+			return nil, fmt.Errorf("loading %s's config: %w", name, err)
+		}
+		for _, dep := range serviceConfig.DependsOn {
+			if _, ok := dependents[dep]; !ok {
+				dependents[dep] = name
+			}
+			queue = append(queue, dep)
+		}
+		entries = append(entries, manager.StackEntry{
+			Config:         serviceConfig,
+			Name:           name,
+			DirectoryPath:  service.DirectoryPath,
+			ConfigFileName: service.ConfigFileName,
+		})
+	}
+	return entries, nil
+}
+
+// upManifestServices loads the manifest at file. Given names, it keeps only
+// those services and the ones in the manifest they depends_on; a dependency
+// outside it is left to the dependency gate, as with eos apply.
+func upManifestServices(file string, names []string) ([]manager.StackEntry, error) {
+	entries, err := manager.LoadStackManifest(file)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", file, err)
+	}
+	if len(names) == 0 {
+		return entries, nil
+	}
+
+	index := make(map[string]int, len(entries))
+	for i := range entries {
+		index[entries[i].Name] = i
+	}
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%s is not in %s", name, file)
+		}
+	}
+	keep := make(map[string]bool)
+	queue := slices.Clone(names)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		i, ok := index[name]
+		if !ok || keep[name] {
+			continue
+		}
+		keep[name] = true
+		queue = append(queue, entries[i].Config.DependsOn...)
+	}
+	return slices.DeleteFunc(entries, func(entry manager.StackEntry) bool {
+		return !keep[entry.Name]
+	}), nil
+}
+
+// upRegister writes entry's inline config, if it has one, and registers it,
+// or repoints its catalog entry when it is registered from elsewhere.
+func upRegister(ctx context.Context, mgr manager.ServiceManager, entry *manager.StackEntry) error {
+	if err := manager.WriteStackConfig(entry); err != nil {
+		return err
+	}
+	current, err := mgr.GetServiceCatalogEntry(ctx, entry.Name)
+	if errors.Is(err, manager.ErrServiceNotRegistered) {
+		catalogEntry, newErr := manager.NewServiceCatalogEntry(entry.Name, entry.DirectoryPath, entry.ConfigFileName)
+		if newErr != nil {
+			return fmt.Errorf("creating service catalog entry: %w", newErr)
+		}
+		if addErr := mgr.AddServiceCatalogEntry(ctx, catalogEntry); addErr != nil {
+			return fmt.Errorf("adding service catalog entry: %w", addErr)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting service catalog entry: %w", err)
+	}
+	if current.DirectoryPath != entry.DirectoryPath || current.ConfigFileName != entry.ConfigFileName {
+		if err := mgr.UpdateServiceCatalogEntry(ctx, entry.Name, entry.DirectoryPath, entry.ConfigFileName); err != nil {
+			return fmt.Errorf("updating service: %w", err)
+		}
+	}
+	return nil
+}
+
+// upStart starts entries in order, each once its dependencies are ready,
+// restarting one already running so it runs under this process. It returns
+// the services it started, for upStop, and stops early once ctx is done.
+func upStart(ctx context.Context, cmd *cobra.Command, mgr manager.ServiceManager, entries []manager.StackEntry, gracePeriod time.Duration) ([]string, error) {
+	var started []string
+	for i := range entries {
+		service := types.ServiceCatalogEntry{Name: entries[i].Name, DirectoryPath: entries[i].DirectoryPath, ConfigFileName: entries[i].ConfigFileName}
+		if err := gateDependencies(ctx, cmd, mgr, &service); err != nil {
+			if ctx.Err() != nil {
+				return started, nil
+			}
+			cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(service.Name), err.Error())
+			return started, helpers.ErrCommandFailed
+		}
+		if ctx.Err() != nil {
+			return started, nil
+		}
+		result, err := startOrRestartService(context.WithoutCancel(ctx), mgr, gracePeriod, &service)
+		if err != nil {
+			cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(service.Name), err.Error())
+			return started, helpers.ErrCommandFailed
+		}
+		started = append(started, service.Name)
+		verb := "started"
+		if result.Restarted {
+			verb = "restarted"
+		}
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelSuccess.Render("success"), ui.TextBold.Render(service.Name), fmt.Sprintf("%s with PGID: %d", verb, result.PGID))
+	}
+	return started, nil
+}
+
+// upStop stops started in reverse, so each service stops before the ones it
+// depends on. As with an interrupted eos run, the services are only stopped,
+// not disabled.
+func upStop(ctx context.Context, cmd *cobra.Command, mgr manager.ServiceManager, started []string, gracePeriod time.Duration) error {
+	var failed bool
+	for _, name := range slices.Backward(started) {
+		cmd.Printf(fmtLabelTwoMsg, ui.LabelInfo.Render("info"), "stopping", ui.TextBold.Render(name))
+		if _, err := mgr.StopService(ctx, name, gracePeriod, 200*time.Millisecond); err != nil && !errors.Is(err, manager.ErrServiceNotRunning) {
+			cmd.PrintErrf(fmtLabelTwoMsg, ui.LabelError.Render("error"), ui.TextBold.Render(name), fmt.Sprintf("stopping service: %v", err))
+			failed = true
+		}
+	}
+	if failed {
+		return helpers.ErrCommandFailed
+	}
+	if len(started) > 0 {
+		cmd.Printf(fmtLabelMsg, ui.LabelSuccess.Render("success"), "stopped every service")
+	}
+	return nil
+}
+
+// upOutput prints the services' output lines, each prefixed with its
+// service's name in that service's color. It is the LocalManager's
+// OutputTee, called from every service's log goroutines at once.
+type upOutput struct {
+	w      io.Writer
+	styles map[string]lipgloss.Style
+	mu     sync.Mutex
+	width  int
+}
+
+// setServices assigns each of names a color and pads the prefixes to the
+// longest. It must be called before any of them starts.
+func (o *upOutput) setServices(names []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.styles = make(map[string]lipgloss.Style, len(names))
+	for i, name := range names {
+		o.styles[name] = lipgloss.NewStyle().Bold(true).Foreground(upPrefixColors[i%len(upPrefixColors)])
+		o.width = max(o.width, len(name))
+	}
+}
+
+func (o *upOutput) line(service, source, line string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	style, ok := o.styles[service]
+	if !ok {
+		style = ui.TextBold
+	}
+	if source == logutil.HealthBreadcrumbSource {
+		line = ui.TextMuted.Render(line)
+	}
+	_, _ = fmt.Fprintf(o.w, "%s %s\n", style.Render(fmt.Sprintf("%-*s |", o.width, service)), line)
+}
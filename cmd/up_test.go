@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+)
+
+// TestUpCommandRunsManifestAndStopsInReverseOrder runs a two-service
+// manifest in the foreground: both services' output comes through prefixed
+// with their names, and canceling the command, as Ctrl-C would, stops the
+// dependent service before the one it depends on.
+func TestUpCommandRunsManifestAndStopsInReverseOrder(t *testing.T) {
+	// eos up opens its own in-process manager on the test daemon config's
+	// base dir, so there is none to inject.
+	cmd := newTestRootCmd(nil)
+	tempDir := t.TempDir()
+
+	manifest := filepath.Join(tempDir, manager.StackManifestFileName)
+	data := `services:
+  - directory: .
+    config:
+      name: api
+      command: "echo api says hi; exec sleep 30"
+      depends_on: [store]
+  - directory: .
+    config:
+      name: store
+      command: "echo store says hi; exec sleep 30"
+`
+	if err := os.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("could not write manifest: %v", err)
+	}
+
+	var outBuf, errBuf syncBuffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	cmd.SetArgs([]string{"up", "-f", manifest})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	deadline := time.Now().Add(15 * time.Second)
+	for !strings.Contains(outBuf.String(), "api   | api says hi") {
+		if time.Now().After(deadline) {
+			cancel()
+			<-done
+			t.Fatalf("expected api's prefixed output, got: %s (stderr: %s)", outBuf.String(), errBuf.String())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("up should exit cleanly once interrupted, got: %v (stderr: %s)", err, errBuf.String())
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("up did not return after its context was canceled")
+	}
+
+	output := outBuf.String()
+	if !strings.Contains(output, "store | store says hi") {
+		t.Errorf("expected store's prefixed output, got: %s", output)
+	}
+	stopAPI, stopStore := strings.Index(output, "stopping api"), strings.Index(output, "stopping store")
+	if stopAPI < 0 || stopStore < 0 || stopAPI > stopStore {
+		t.Errorf("expected api to be stopped before store, got: %s", output)
+	}
+}
@@ -42,7 +42,7 @@ func newValidateCmd() *cobra.Command {
 			cmd.Printf("%s %s %s\n\n", ui.LabelSuccess.Render("valid"), ui.TextBold.Render(config.Name), "configuration is valid")
 			cmd.Printf("  %s %s\n\n", ui.TextMuted.Render("file:"), yamlFile)
 
-			printSelfDetachWarnings(cmd, config.Command)
+			printSelfDetachWarnings(cmd, config)
 
 			return nil
 		},
@@ -0,0 +1,363 @@
+// Package cgroup places each eos-launched service instance in its own cgroup
+// v2 leaf, so liveness, stop, and orphan detection can follow every process
+// the service spawned — including one that called setsid(2) and left the
+// process group eos would otherwise track it by.
+//
+// The layout under the daemon's own (delegated) cgroup is:
+//
+//	eos.slice/<service>/<pgid>
+//
+// One leaf per launch rather than one per service: a reload runs the incoming
+// and outgoing instances side by side, and each must be signaled on its own.
+// Naming the leaf after the launch's PGID ties it to the process_history row
+// already keyed by PGID, so no extra state needs persisting to find it again
+// after a daemon restart.
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// SliceName is the directory created under the daemon's own cgroup to hold
+// every service's cgroup.
+const SliceName = "eos.slice"
+
+// stagingPrefix names the leaf a launch is cloned into before its PGID is
+// known (see Stage and Commit).
+const stagingPrefix = "starting-"
+
+// Hierarchy is a resolved, writable eos.slice directory on the cgroup v2
+// mount. Detect is the only place that derives it; everything else takes it
+// as a value.
+type Hierarchy struct {
+	// Slice is the absolute path to eos.slice, e.g.
+	// /sys/fs/cgroup/system.slice/eos.service/eos.slice.
+	Slice string
+	// Controllers lists the resource controllers (of "memory", "cpu",
+	// "pids") enabled for eos.slice's children, and so available to every
+	// launch leaf for WriteLimits. Empty still tracks launches; their limits
+	// just fall back to setrlimit.
+	Controllers []string
+}
+
+// Limits holds a launch leaf's resource-control files in the kernel's own
+// formats: MemoryMax and PidsMax are a number or "max", CPUMax is
+// "<quota> <period>" in microseconds. WriteLimits leaves an empty field's
+// file untouched; ReadLimits returns one empty when the file isn't there,
+// i.e. its controller isn't enabled for the leaf.
+type Limits struct {
+	MemoryMax string
+	CPUMax    string
+	PidsMax   string
+}
+
+// Staged is a launch leaf created before the process exists, held open so
+// its descriptor can be handed to clone3(CLONE_INTO_CGROUP) via SetCgroupFD.
+// The child is born inside the leaf, so even a fork in its first instruction
+// can't land outside it.
+type Staged struct {
+	Dir  *os.File
+	Path string
+}
+
+// ServiceDir returns the cgroup directory holding every launch leaf of
+// service.
+func ServiceDir(h Hierarchy, service string) string {
+	return filepath.Join(h.Slice, service)
+}
+
+// LaunchDir returns the leaf a launch with the given PGID is committed to.
+func LaunchDir(h Hierarchy, service string, pgid int) string {
+	return filepath.Join(ServiceDir(h, service), strconv.Itoa(pgid))
+}
+
+// Find locates the launch leaf for pgid under any service, for callers that
+// only have a PGID (the health monitor, the stop path's history walk). ok is
+// false when no leaf exists: the launch predates cgroup tracking, its
+// placement failed, or it was pruned after exiting.
+//
+// A reused PGID can match more than one leaf: the live launch's, and an
+// empty one another service's earlier launch left behind before it was
+// pruned. The populated leaf wins, so a stale one can never hide the launch
+// that is actually running.
+func Find(h Hierarchy, pgid int) (dir string, ok bool) {
+	matches, err := filepath.Glob(filepath.Join(h.Slice, "*", strconv.Itoa(pgid)))
+	if err != nil || len(matches) == 0 {
+		return "", false
+	}
+	for _, match := range matches {
+		if populated, popErr := Populated(match); popErr == nil && populated {
+			return match, true
+		}
+	}
+	return matches[0], true
+}
+
+// Stage creates a fresh staging leaf under service's directory and opens it
+// for SetCgroupFD. The caller must follow up with exactly one of Commit
+// (after a successful start) or Abort.
+//
+// The service directory passes h.Controllers on to its leaves. It holds no
+// processes itself, so cgroup v2's no-internal-processes rule never stands in
+// the way; re-enabling on every launch is a no-op write.
+func Stage(h Hierarchy, service string) (Staged, error) {
+	serviceDir := ServiceDir(h, service)
+	if err := os.Mkdir(serviceDir, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return Staged{}, fmt.Errorf("creating cgroup %s: %w", serviceDir, err)
+	}
+	if err := enableControllers(serviceDir, h.Controllers); err != nil {
+		return Staged{}, err
+	}
+	path, err := os.MkdirTemp(serviceDir, stagingPrefix)
+	if err != nil {
+		return Staged{}, fmt.Errorf("creating staging cgroup under %s: %w", serviceDir, err)
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return Staged{}, fmt.Errorf("opening staging cgroup %s: %w", path, err)
+	}
+	return Staged{Dir: dir, Path: path}, nil
+}
+
+// Commit renames a staged leaf to its launch's PGID once the process exists.
+// cgroup v2 allows renaming a cgroup within its parent, and the rename
+// doesn't move any process, so nothing the launch has already forked is
+// lost. On failure the process stays in the staging leaf, where Find won't
+// see it; callers fall back to PGID tracking for that launch.
+func Commit(h Hierarchy, service string, pgid int, s Staged) (string, error) {
+	_ = s.Dir.Close()
+	dir := LaunchDir(h, service, pgid)
+	if err := os.Rename(s.Path, dir); err != nil {
+		return "", fmt.Errorf("renaming cgroup %s to %s: %w", s.Path, dir, err)
+	}
+	return dir, nil
+}
+
+// Abort releases a staged leaf whose launch never started.
+func Abort(s Staged) {
+	_ = s.Dir.Close()
+	_ = os.Remove(s.Path)
+}
+
+// WriteLimits writes l's non-empty fields into dir's resource-control files.
+// It's meant for a staged leaf, before anything is cloned into it, so the
+// launch never runs a single instruction uncapped.
+func WriteLimits(dir string, l Limits) error {
+	for _, f := range []struct{ name, value string }{
+		{"memory.max", l.MemoryMax},
+		{"cpu.max", l.CPUMax},
+		{"pids.max", l.PidsMax},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, f.name), []byte(f.value), 0o644); err != nil {
+			return fmt.Errorf("writing %s/%s: %w", dir, f.name, err)
+		}
+	}
+	return nil
+}
+
+// ReadLimits reads dir's resource-control files back as the kernel reports
+// them, which for memory.max is the requested value rounded down to a whole
+// page.
+func ReadLimits(dir string) Limits {
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	return Limits{MemoryMax: read("memory.max"), CPUMax: read("cpu.max"), PidsMax: read("pids.max")}
+}
+
+// OOMKills returns how many processes in dir the kernel's OOM killer has
+// killed for breaching memory.max (memory.events' oom_kill counter).
+func OOMKills(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "memory.events"))
+	if err != nil {
+		return 0, fmt.Errorf("reading %s/memory.events: %w", dir, err)
+	}
+	return parseOOMKills(string(data))
+}
+
+// Populated reports whether any process, in any descendant, is still in dir.
+// The kernel drops a task from its cgroup the moment it exits — before its
+// parent reaps it — so unlike a kill(-pgid, 0) probe, a zombie never reads as
+// alive here.
+func Populated(dir string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.events"))
+	if err != nil {
+		return false, fmt.Errorf("reading %s/cgroup.events: %w", dir, err)
+	}
+	return parsePopulated(string(data))
+}
+
+// Procs returns the PIDs currently in dir itself (not its descendants).
+func Procs(dir string) ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s/cgroup.procs: %w", dir, err)
+	}
+	return parseProcs(string(data))
+}
+
+// Signal delivers sig to every process in dir. SIGKILL goes through
+// cgroup.kill, which the kernel applies atomically to the whole subtree —
+// nothing can fork its way out between reading cgroup.procs and signaling.
+// Other signals are sent per PID. An empty cgroup returns syscall.ESRCH, the
+// same error kill(-pgid, sig) gives for a vanished group, so callers written
+// against the PGID path keep working unchanged.
+func Signal(dir string, sig syscall.Signal) error {
+	if sig == syscall.SIGKILL {
+		if err := os.WriteFile(filepath.Join(dir, "cgroup.kill"), []byte("1"), 0o644); err != nil {
+			return fmt.Errorf("writing %s/cgroup.kill: %w", dir, err)
+		}
+		return nil
+	}
+	pids, err := Procs(dir)
+	if err != nil {
+		return err
+	}
+	delivered := 0
+	var errs []error
+	for _, pid := range pids {
+		killErr := syscall.Kill(pid, sig)
+		switch {
+		case killErr == nil:
+			delivered++
+		case errors.Is(killErr, syscall.ESRCH):
+			// Exited between the cgroup.procs read and the signal.
+		default:
+			errs = append(errs, fmt.Errorf("signaling pid %d: %w", pid, killErr))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if delivered == 0 {
+		return syscall.ESRCH
+	}
+	return nil
+}
+
+// LiveLaunches returns the PGIDs of every launch leaf under service that
+// still has a process in it. Staging leaves are skipped: their launch either
+// never started or failed to commit, and has no PGID to report.
+func LiveLaunches(h Hierarchy, service string) []int {
+	entries, err := os.ReadDir(ServiceDir(h, service))
+	if err != nil {
+		return nil
+	}
+	var pgids []int
+	for _, e := range entries {
+		pgid, convErr := strconv.Atoi(e.Name())
+		if !e.IsDir() || convErr != nil {
+			continue
+		}
+		if populated, popErr := Populated(filepath.Join(ServiceDir(h, service), e.Name())); popErr == nil && populated {
+			pgids = append(pgids, pgid)
+		}
+	}
+	return pgids
+}
+
+// Prune removes every empty launch leaf under service's directory. rmdir on
+// a populated cgroup fails with EBUSY, so a live launch is never touched; the
+// error is deliberately ignored for that reason. Staging leaves are skipped:
+// an empty one may belong to another launch of the service still on its way
+// to clone3, a concurrent instance or a reload's incoming one, and removing
+// it would leave that launch's CLONE_INTO_CGROUP target gone.
+func Prune(h Hierarchy, service string) {
+	entries, err := os.ReadDir(ServiceDir(h, service))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), stagingPrefix) {
+			_ = os.Remove(filepath.Join(ServiceDir(h, service), e.Name()))
+		}
+	}
+}
+
+// Release removes the leaf of service's launch pgid once the launch has
+// exited, so it can't linger to be matched by a later launch reusing the
+// PGID. Like Prune, it leaves a still-populated leaf alone.
+func Release(h Hierarchy, service string, pgid int) {
+	_ = os.Remove(LaunchDir(h, service, pgid))
+}
+
+// enableControllers turns controllers on for dir's children by writing
+// "+<name>" for each to cgroup.subtree_control.
+func enableControllers(dir string, controllers []string) error {
+	if len(controllers) == 0 {
+		return nil
+	}
+	words := make([]string, 0, len(controllers))
+	for _, c := range controllers {
+		words = append(words, "+"+c)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(strings.Join(words, " ")), 0o644); err != nil {
+		return fmt.Errorf("enabling %s controllers in %s: %w", strings.Join(controllers, ","), dir, err)
+	}
+	return nil
+}
+
+// parseUnifiedPath extracts the cgroup v2 path from /proc/<pid>/cgroup
+// contents: the single "0::<path>" line. A hybrid host lists v1 hierarchies
+// on other lines, which are ignored.
+func parseUnifiedPath(contents string) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// parsePopulated reads the "populated" key from cgroup.events contents.
+func parsePopulated(contents string) (bool, error) {
+	for line := range strings.SplitSeq(contents, "\n") {
+		if value, ok := strings.CutPrefix(line, "populated "); ok {
+			return strings.TrimSpace(value) == "1", nil
+		}
+	}
+	return false, errors.New("cgroup.events has no populated key")
+}
+
+// parseOOMKills reads the "oom_kill" key from memory.events contents.
+func parseOOMKills(contents string) (int, error) {
+	for line := range strings.SplitSeq(contents, "\n") {
+		if value, ok := strings.CutPrefix(line, "oom_kill "); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return 0, fmt.Errorf("parsing memory.events oom_kill %q: %w", value, err)
+			}
+			return n, nil
+		}
+	}
+	return 0, errors.New("memory.events has no oom_kill key")
+}
+
+// parseProcs parses cgroup.procs contents: one PID per line.
+func parseProcs(contents string) ([]int, error) {
+	var pids []int
+	for field := range strings.FieldsSeq(contents) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cgroup.procs entry %q: %w", field, err)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
@@ -0,0 +1,106 @@
+//go:build linux
+
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// mountPoint is where every mainstream distro (and systemd itself) mounts the
+// unified cgroup v2 hierarchy.
+const mountPoint = "/sys/fs/cgroup"
+
+// daemonLeaf is the sibling of eos.slice the daemon moves itself into so
+// controllers can be enabled below its own cgroup (see delegateControllers).
+const daemonLeaf = "eos-daemon"
+
+// Detect resolves eos.slice under the calling process's own cgroup, creating
+// it if needed. It fails — and the caller falls back to PGID tracking — when:
+//
+//   - the host has no unified cgroup v2 mount (v1-only or hybrid without
+//     cgroup2 at /sys/fs/cgroup);
+//   - the daemon's cgroup isn't delegated to it, so mkdir is refused (a
+//     non-root daemon outside a Delegate=yes unit);
+//   - the kernel predates cgroup.kill (Linux 5.14), which also rules out
+//     kernels without clone3(CLONE_INTO_CGROUP) (5.7), the two primitives
+//     Stage and Signal depend on.
+//
+// A daemon already sitting in daemonLeaf (moved there by an earlier Detect in
+// the same process) resolves against the leaf's parent, so eos.slice never
+// ends up nested inside it.
+func Detect() (*Hierarchy, error) {
+	if _, err := os.Stat(filepath.Join(mountPoint, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("no cgroup v2 hierarchy at %s: %w", mountPoint, err)
+	}
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil, fmt.Errorf("reading /proc/self/cgroup: %w", err)
+	}
+	own, ok := parseUnifiedPath(string(data))
+	if !ok {
+		return nil, errors.New("no cgroup v2 entry in /proc/self/cgroup")
+	}
+	if filepath.Base(own) == daemonLeaf {
+		own = filepath.Dir(own)
+	}
+	ownDir := filepath.Join(mountPoint, own)
+	slice := filepath.Join(ownDir, SliceName)
+	if err := os.Mkdir(slice, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("creating %s (is the cgroup subtree delegated to eos?): %w", slice, err)
+	}
+	if _, err := os.Stat(filepath.Join(slice, "cgroup.kill")); err != nil {
+		return nil, fmt.Errorf("kernel lacks cgroup.kill (needs Linux 5.14+): %w", err)
+	}
+	return &Hierarchy{Slice: slice, Controllers: delegateControllers(ownDir, slice)}, nil
+}
+
+// delegateControllers enables the memory, cpu and pids controllers from the
+// daemon's own cgroup down to eos.slice's children, returning the ones that
+// took. cgroup v2 refuses to enable controllers for the children of a cgroup
+// that itself holds processes, so unless ownDir is the root cgroup (exempt
+// from that rule) the daemon first moves itself into a daemonLeaf sibling of
+// eos.slice — under a Delegate=yes unit the whole subtree is the daemon's to
+// arrange. Nothing here is fatal: a controller that can't be enabled (not
+// offered by the parent, or the move refused because something else shares
+// the daemon's cgroup) is simply left out, and limits it would have enforced
+// fall back to setrlimit.
+func delegateControllers(ownDir, slice string) []string {
+	offered, err := os.ReadFile(filepath.Join(ownDir, "cgroup.controllers"))
+	if err != nil {
+		return nil
+	}
+	if ownDir != mountPoint {
+		leaf := filepath.Join(ownDir, daemonLeaf)
+		if err := os.Mkdir(leaf, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+			return nil
+		}
+		if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+			return nil
+		}
+	}
+	var enabled []string
+	for _, c := range []string{"memory", "cpu", "pids"} {
+		if !slices.Contains(strings.Fields(string(offered)), c) {
+			continue
+		}
+		if enableControllers(ownDir, []string{c}) != nil || enableControllers(slice, []string{c}) != nil {
+			continue
+		}
+		enabled = append(enabled, c)
+	}
+	return enabled
+}
+
+// SetCgroupFD makes the process started with attr clone directly into the
+// cgroup open as fd (see Stage).
+func SetCgroupFD(attr *syscall.SysProcAttr, fd int) {
+	attr.UseCgroupFD = true
+	attr.CgroupFD = fd
+}
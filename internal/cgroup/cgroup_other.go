@@ -0,0 +1,19 @@
+//go:build !linux
+
+package cgroup
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+)
+
+// Detect always fails outside Linux, the only platform with cgroups; the
+// caller falls back to PGID tracking.
+func Detect() (*Hierarchy, error) {
+	return nil, fmt.Errorf("cgroup v2 not supported on %s", runtime.GOOS)
+}
+
+// SetCgroupFD is a no-op outside Linux. Detect never succeeds there, so no
+// caller ever has a staged cgroup to pass.
+func SetCgroupFD(_ *syscall.SysProcAttr, _ int) {}
@@ -0,0 +1,209 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestParseUnifiedPath(t *testing.T) {
+	if path, ok := parseUnifiedPath("0::/system.slice/eos.service\n"); !ok || path != "/system.slice/eos.service" {
+		t.Errorf("parseUnifiedPath(unified) = (%q, %v), want (/system.slice/eos.service, true)", path, ok)
+	}
+	// Hybrid hosts list v1 hierarchies alongside the unified one.
+	hybrid := "12:memory:/user.slice\n1:name=systemd:/user.slice/session-2.scope\n0::/user.slice/session-2.scope\n"
+	if path, ok := parseUnifiedPath(hybrid); !ok || path != "/user.slice/session-2.scope" {
+		t.Errorf("parseUnifiedPath(hybrid) = (%q, %v), want (/user.slice/session-2.scope, true)", path, ok)
+	}
+	if _, ok := parseUnifiedPath("12:memory:/user.slice\n"); ok {
+		t.Error("parseUnifiedPath(v1 only) ok=true, want false")
+	}
+}
+
+func TestParsePopulated(t *testing.T) {
+	if populated, err := parsePopulated("populated 1\nfrozen 0\n"); err != nil || !populated {
+		t.Errorf("parsePopulated(populated 1) = (%v, %v), want (true, nil)", populated, err)
+	}
+	if populated, err := parsePopulated("populated 0\nfrozen 0\n"); err != nil || populated {
+		t.Errorf("parsePopulated(populated 0) = (%v, %v), want (false, nil)", populated, err)
+	}
+	if _, err := parsePopulated("frozen 0\n"); err == nil {
+		t.Error("parsePopulated(no populated key) err=nil, want an error")
+	}
+}
+
+func TestParseProcs(t *testing.T) {
+	pids, err := parseProcs("123\n456\n")
+	if err != nil || !slices.Equal(pids, []int{123, 456}) {
+		t.Errorf("parseProcs = (%v, %v), want ([123 456], nil)", pids, err)
+	}
+	if pids, err := parseProcs(""); err != nil || len(pids) != 0 {
+		t.Errorf("parseProcs(empty) = (%v, %v), want ([], nil)", pids, err)
+	}
+	if _, err := parseProcs("123\nabc\n"); err == nil {
+		t.Error("parseProcs(non-numeric) err=nil, want an error")
+	}
+}
+
+// writeLeaf fakes a launch leaf on a plain filesystem: only the files this
+// package reads, not a real cgroupfs.
+func writeLeaf(t *testing.T, dir, events, procs string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("creating %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.events"), []byte(events), 0o644); err != nil {
+		t.Fatalf("writing cgroup.events: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(procs), 0o644); err != nil {
+		t.Fatalf("writing cgroup.procs: %v", err)
+	}
+}
+
+func TestFindAndLiveLaunches(t *testing.T) {
+	h := Hierarchy{Slice: t.TempDir()}
+	writeLeaf(t, LaunchDir(h, "api", 100), "populated 1\n", "100\n")
+	writeLeaf(t, LaunchDir(h, "api", 200), "populated 0\n", "")
+	writeLeaf(t, filepath.Join(ServiceDir(h, "api"), stagingPrefix+"x"), "populated 1\n", "300\n")
+
+	dir, ok := Find(h, 100)
+	if !ok || dir != LaunchDir(h, "api", 100) {
+		t.Errorf("Find(100) = (%q, %v), want (%q, true)", dir, ok, LaunchDir(h, "api", 100))
+	}
+	if _, ok := Find(h, 999); ok {
+		t.Error("Find(999) ok=true for a PGID with no leaf, want false")
+	}
+
+	if live := LiveLaunches(h, "api"); !slices.Equal(live, []int{100}) {
+		t.Errorf("LiveLaunches = %v, want [100] (empty and staging leaves skipped)", live)
+	}
+	if live := LiveLaunches(h, "missing"); len(live) != 0 {
+		t.Errorf("LiveLaunches(unknown service) = %v, want none", live)
+	}
+}
+
+// TestFind_prefersPopulatedLeaf proves a reused PGID finds the live launch,
+// not an empty leaf another service left behind.
+func TestFind_prefersPopulatedLeaf(t *testing.T) {
+	h := Hierarchy{Slice: t.TempDir()}
+	writeLeaf(t, LaunchDir(h, "api", 100), "populated 0\n", "")
+	writeLeaf(t, LaunchDir(h, "worker", 100), "populated 1\n", "100\n")
+
+	if dir, ok := Find(h, 100); !ok || dir != LaunchDir(h, "worker", 100) {
+		t.Errorf("Find(100) = (%q, %v), want the populated leaf %q", dir, ok, LaunchDir(h, "worker", 100))
+	}
+}
+
+// TestPruneAndRelease proves Prune removes empty launch leaves but not an
+// empty staging leaf, which may be a launch still about to be cloned into
+// it, and that Release removes a single launch's leaf.
+func TestPruneAndRelease(t *testing.T) {
+	h := Hierarchy{Slice: t.TempDir()}
+	launch := LaunchDir(h, "api", 100)
+	staging := filepath.Join(ServiceDir(h, "api"), stagingPrefix+"x")
+	for _, dir := range []string{launch, staging, LaunchDir(h, "api", 200)} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("creating %s: %v", dir, err)
+		}
+	}
+
+	Release(h, "api", 200)
+	if _, err := os.Stat(LaunchDir(h, "api", 200)); !os.IsNotExist(err) {
+		t.Error("Release left the launch's empty leaf behind")
+	}
+	Prune(h, "api")
+	if _, err := os.Stat(launch); !os.IsNotExist(err) {
+		t.Error("Prune left an empty launch leaf behind")
+	}
+	if _, err := os.Stat(staging); err != nil {
+		t.Errorf("Prune removed a staging leaf: %v", err)
+	}
+}
+
+func TestSignal_emptyCgroupIsESRCH(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "1")
+	writeLeaf(t, dir, "populated 0\n", "")
+
+	if err := Signal(dir, syscall.SIGTERM); err != syscall.ESRCH {
+		t.Errorf("Signal(empty cgroup) = %v, want ESRCH so PGID-path callers keep working", err)
+	}
+}
+
+func TestStageCommitAbort(t *testing.T) {
+	h := Hierarchy{Slice: t.TempDir()}
+
+	staged, err := Stage(h, "api")
+	if err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	dir, err := Commit(h, "api", 4242, staged)
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if dir != LaunchDir(h, "api", 4242) {
+		t.Errorf("Commit dir = %q, want %q", dir, LaunchDir(h, "api", 4242))
+	}
+	if _, err := os.Stat(staged.Path); !os.IsNotExist(err) {
+		t.Errorf("staging leaf %s still exists after Commit", staged.Path)
+	}
+
+	aborted, err := Stage(h, "api")
+	if err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	Abort(aborted)
+	if _, err := os.Stat(aborted.Path); !os.IsNotExist(err) {
+		t.Errorf("staging leaf %s still exists after Abort", aborted.Path)
+	}
+}
+
+func TestParseOOMKills(t *testing.T) {
+	if n, err := parseOOMKills("low 0\nhigh 0\nmax 3\noom 2\noom_kill 2\noom_group_kill 0\n"); err != nil || n != 2 {
+		t.Errorf("parseOOMKills = (%d, %v), want (2, nil)", n, err)
+	}
+	if _, err := parseOOMKills("low 0\n"); err == nil {
+		t.Error("parseOOMKills(no oom_kill key) err=nil, want an error")
+	}
+}
+
+func TestWriteAndReadLimits(t *testing.T) {
+	dir := t.TempDir()
+	// pids.max exists on a real leaf with the pids controller enabled; the
+	// empty PidsMax below must leave it as the kernel default.
+	if err := os.WriteFile(filepath.Join(dir, "pids.max"), []byte("max\n"), 0o644); err != nil {
+		t.Fatalf("writing pids.max: %v", err)
+	}
+
+	if err := WriteLimits(dir, Limits{MemoryMax: "536870912", CPUMax: "150000 100000"}); err != nil {
+		t.Fatalf("WriteLimits: %v", err)
+	}
+	got := ReadLimits(dir)
+	want := Limits{MemoryMax: "536870912", CPUMax: "150000 100000", PidsMax: "max"}
+	if got != want {
+		t.Errorf("ReadLimits = %+v, want %+v", got, want)
+	}
+
+	if got := ReadLimits(t.TempDir()); got != (Limits{}) {
+		t.Errorf("ReadLimits(no controllers) = %+v, want all empty", got)
+	}
+}
+
+func TestStage_enablesControllersForLeaves(t *testing.T) {
+	h := Hierarchy{Slice: t.TempDir(), Controllers: []string{"memory", "pids"}}
+
+	staged, err := Stage(h, "api")
+	if err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	defer Abort(staged)
+
+	data, err := os.ReadFile(filepath.Join(ServiceDir(h, "api"), "cgroup.subtree_control"))
+	if err != nil {
+		t.Fatalf("reading cgroup.subtree_control: %v", err)
+	}
+	if string(data) != "+memory +pids" {
+		t.Errorf("cgroup.subtree_control = %q, want %q", data, "+memory +pids")
+	}
+}
@@ -24,6 +24,11 @@ const (
 	System     = "system"
 	Daemon     = "daemon"
 	Reload     = "reload"
+	Deploy     = "deploy"
+	Rollback   = "rollback"
+	Diff       = "diff"
+	Apply      = "apply"
+	Up         = "up"
 	Env        = "env"
 	Completion = "completion"
 	Init       = "init"
@@ -63,6 +68,8 @@ const (
 	ArgPath        = "<path>"
 	ArgServiceName = "<service-name>"
 	ArgNewPath     = "<new-path>"
+	ArgArtifact    = "<artifact>"
+	ArgRelease     = "[release]"
 )
 
 // UseAdd, UseRemove, etc. are the Use: field values shared by each command's
@@ -76,6 +83,9 @@ const (
 	UseLogs     = Logs + " " + ArgServiceName
 	UseValidate = Validate + " " + ArgPath
 	UseReload   = Reload + " " + ArgServiceName
+	UseDeploy   = Deploy + " " + ArgServiceName + " " + ArgArtifact
+	UseRollback = Rollback + " " + ArgServiceName + " " + ArgRelease
+	UseDiff     = Diff + " " + ArgServiceName
 )
 
 // Hint* constants are full, ready-to-render "eos ..." invocations with no
@@ -98,11 +108,13 @@ const (
 // FmtHint* constants are "eos ..." invocation templates taking one %s
 // argument (typically a service name), for use with fmt.Sprintf.
 const (
-	FmtHintRun     = Root + " " + Run + " %s"
-	FmtHintRunFile = Root + " " + Run + " -f %s"
-	FmtHintLogs    = Root + " " + Logs + " %s"
-	FmtHintInfo    = Root + " " + Info + " %s"
-	FmtHintRemove  = Root + " " + Remove + " %s"
-	FmtHintStop    = Root + " " + Stop + " %s"
-	FmtHintUpdate  = Root + " " + Update + " %s"
+	FmtHintRun      = Root + " " + Run + " %s"
+	FmtHintRunFile  = Root + " " + Run + " -f %s"
+	FmtHintLogs     = Root + " " + Logs + " %s"
+	FmtHintInfo     = Root + " " + Info + " %s"
+	FmtHintRemove   = Root + " " + Remove + " %s"
+	FmtHintStop     = Root + " " + Stop + " %s"
+	FmtHintUpdate   = Root + " " + Update + " %s"
+	FmtHintRollback = Root + " " + Rollback + " %s"
+	FmtHintDiff     = Root + " " + Diff + " %s"
 )
@@ -24,6 +24,9 @@ func TestUseFieldsCarryTheirArgPlaceholder(t *testing.T) {
 		{"logs", UseLogs, ArgServiceName},
 		{"validate", UseValidate, ArgPath},
 		{"reload", UseReload, ArgServiceName},
+		{"deploy", UseDeploy, ArgArtifact},
+		{"rollback", UseRollback, ArgRelease},
+		{"diff", UseDiff, ArgServiceName},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -73,6 +76,7 @@ func TestFmtHintsProduceRootPrefixedInvocation(t *testing.T) {
 		"FmtHintRemove":  FmtHintRemove,
 		"FmtHintStop":    FmtHintStop,
 		"FmtHintUpdate":  FmtHintUpdate,
+		"FmtHintDiff":    FmtHintDiff,
 	}
 	for name, tmpl := range templates {
 		if strings.Count(tmpl, "%s") != 1 {
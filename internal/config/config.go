@@ -217,6 +217,9 @@ type TelemetryConfig struct {
 type SystemConfig struct {
 	Daemon DaemonConfig             `json:"daemon" yaml:"daemon"`
 	Sinks  map[string]types.LogSink `json:"sinks" yaml:"sinks"`
+	// ServicesDir is the drop-in directory of service files the daemon
+	// registers and watches; empty when none is configured.
+	ServicesDir string `json:"services_dir" yaml:"services_dir"`
 	// BaseDir is the resolved eos data directory (see GetBaseDir), the single
 	// derivation site for this fact. Commands that need it (e.g. the snapshot
 	// file location) read it here instead of re-resolving identity/overrides
@@ -453,6 +456,9 @@ type EosConfig struct {
 	Telemetry EosTelemetryConfig       `yaml:"telemetry"`
 	Health    EosHealthConfig          `yaml:"health"`
 	Log       EosLogConfig             `yaml:"log"`
+	// ServicesDir is a directory of service files, each registered and kept
+	// in line with its file by the daemon. Empty, the default, disables it.
+	ServicesDir string `yaml:"services_dir"`
 }
 
 // EosTelemetryConfig is the config.yaml shape of TelemetryConfig.
@@ -542,6 +548,9 @@ func (c *EosConfig) Validate() error {
 	if c.Telemetry.Enable && c.Telemetry.Endpoint == "" {
 		return fmt.Errorf("telemetry.enable is true but telemetry.endpoint is empty")
 	}
+	if c.ServicesDir != "" && !filepath.IsAbs(c.ServicesDir) {
+		return fmt.Errorf("services_dir must be an absolute path, got %q", c.ServicesDir)
+	}
 	return nil
 }
 
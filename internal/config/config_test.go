@@ -448,6 +448,22 @@ func TestEosConfig_Validate_TelemetryEnabledWithoutEndpoint(t *testing.T) {
 	}
 }
 
+func TestEosConfig_Validate_ServicesDirRelative(t *testing.T) {
+	cfg := DefaultEosConfig()
+	cfg.ServicesDir = "services.d"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error for a relative services_dir, got nil")
+	}
+	if !strings.Contains(err.Error(), "services_dir") {
+		t.Errorf("expected error to mention services_dir, got: %v", err)
+	}
+	cfg.ServicesDir = "/etc/eos/services.d"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected an absolute services_dir to validate, got: %v", err)
+	}
+}
+
 func TestLoadEosConfig_Sinks(t *testing.T) {
 	dir := t.TempDir()
 	yaml := `sinks:
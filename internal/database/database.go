@@ -45,6 +45,7 @@ type Database interface {
 	GetMostRecentProcessHistoryEntryByName(ctx context.Context, serviceName string) (types.ProcessHistory, error)
 	GetProcessHistoryEntryByPGID(ctx context.Context, pgid int) (types.ProcessHistory, error)
 	RegisterProcessHistoryEntry(ctx context.Context, pgid int, startedAtTicks int64, serviceName string, state types.ProcessState) (types.ProcessHistory, error)
+	RegisterInstanceProcessHistoryEntry(ctx context.Context, pgid int, startedAtTicks int64, serviceName string, instance int, state types.ProcessState) (types.ProcessHistory, error)
 	RemoveProcessHistoryEntryViaPGID(ctx context.Context, pgid int) (bool, error)
 	UpdateProcessHistoryEntry(ctx context.Context, pgid int, updates ProcessHistoryUpdate) error
 
@@ -65,6 +66,27 @@ type Database interface {
 	// waiting on it no longer exists.
 	ClearAllDependencyWaits(ctx context.Context) error
 
+	// RecordCanaryResult and GetCanaryResult keep each service's most recent
+	// canary reload, a standalone table keyed on service_name like
+	// dependency_waits.
+	RecordCanaryResult(ctx context.Context, result types.CanaryResult) error
+	GetCanaryResult(ctx context.Context, serviceName string) (result types.CanaryResult, found bool, err error)
+
+	// RecordRelease, ActivateRelease, FailRelease, GetReleases and
+	// RemoveRelease keep the history of the releases eos deploy unpacked for
+	// a service, keyed on service_name and the release's name.
+	RecordRelease(ctx context.Context, release types.Release) error
+	ActivateRelease(ctx context.Context, serviceName string, name string) error
+	FailRelease(ctx context.Context, serviceName string, name string, reason string) error
+	GetReleases(ctx context.Context, serviceName string) ([]types.Release, error)
+	RemoveRelease(ctx context.Context, serviceName string, name string) (bool, error)
+
+	// RecordLaunchConfig and GetLaunchConfig keep the config each launch was
+	// started with, keyed on its pgid like process_history; removing the
+	// launch's process_history row removes it too.
+	RecordLaunchConfig(ctx context.Context, launch types.LaunchConfig) error
+	GetLaunchConfig(ctx context.Context, pgid int) (launch types.LaunchConfig, found bool, err error)
+
 	RunMigrations(migrationsFS embed.FS, migrationsPath string) error
 	GetCurrentMigrationVersion(migrationsFS embed.FS, migrationsPath string) (uint, bool, error)
 	RunDownMigration(migrationsFS embed.FS, migrationsPath string) error
@@ -201,13 +223,20 @@ func (db *DB) RegisterServiceInstance(ctx context.Context, name string) error {
 }
 
 func (db *DB) RegisterProcessHistoryEntry(ctx context.Context, pgid int, startedAtTicks int64, serviceName string, state types.ProcessState) (types.ProcessHistory, error) {
+	return db.RegisterInstanceProcessHistoryEntry(ctx, pgid, startedAtTicks, serviceName, 0, state)
+}
+
+// RegisterInstanceProcessHistoryEntry is RegisterProcessHistoryEntry for one
+// of the instances of a service with instances: set (see
+// types.ProcessHistory.Instance).
+func (db *DB) RegisterInstanceProcessHistoryEntry(ctx context.Context, pgid int, startedAtTicks int64, serviceName string, instance int, state types.ProcessState) (types.ProcessHistory, error) {
 	createdAt := time.Now()
 	instanceQuery := `
-	INSERT INTO process_history (pgid, started_at_ticks, service_name, state, created_at, started_at)
-	VALUES (?, ?, ?, ?, ?, ?)
+	INSERT INTO process_history (pgid, started_at_ticks, service_name, instance, state, created_at, started_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := db.conn.ExecContext(ctx, instanceQuery, pgid, startedAtTicks, serviceName, state, createdAt, createdAt)
+	_, err := db.conn.ExecContext(ctx, instanceQuery, pgid, startedAtTicks, serviceName, instance, state, createdAt, createdAt)
 	if err != nil {
 		return types.ProcessHistory{}, fmt.Errorf("could not create process history entry: %w", err)
 	}
@@ -216,6 +245,7 @@ func (db *DB) RegisterProcessHistoryEntry(ctx context.Context, pgid int, started
 		PGID:           pgid,
 		StartedAtTicks: startedAtTicks,
 		ServiceName:    serviceName,
+		Instance:       instance,
 		State:          state,
 		CreatedAt:      createdAt,
 		StartedAt:      &createdAt,
@@ -276,7 +306,7 @@ func (db *DB) GetServiceCatalogEntry(ctx context.Context, name string) (types.Se
 
 func (db *DB) GetAllServiceInstances(ctx context.Context) ([]types.ServiceInstance, error) {
 	query := `
-	SELECT name, restart_count, last_health_check, created_at, started_at, updated_at, next_restart_at, failure_loop_count, failure_signature
+	SELECT name, restart_count, last_health_check, created_at, started_at, updated_at, next_restart_at, failure_loop_count, failure_signature, restart_window_start, restart_window_count, gave_up_reason
 	FROM service_instances
 	ORDER BY name
 	`
@@ -290,7 +320,7 @@ func (db *DB) GetAllServiceInstances(ctx context.Context) ([]types.ServiceInstan
 	var serviceInstances []types.ServiceInstance
 	for rows.Next() {
 		var serviceInstance types.ServiceInstance
-		err := rows.Scan(&serviceInstance.Name, &serviceInstance.RestartCount, &serviceInstance.LastHealthCheck, &serviceInstance.CreatedAt, &serviceInstance.StartedAt, &serviceInstance.UpdatedAt, &serviceInstance.NextRestartAt, &serviceInstance.FailureLoopCount, &serviceInstance.FailureSignature)
+		err := rows.Scan(&serviceInstance.Name, &serviceInstance.RestartCount, &serviceInstance.LastHealthCheck, &serviceInstance.CreatedAt, &serviceInstance.StartedAt, &serviceInstance.UpdatedAt, &serviceInstance.NextRestartAt, &serviceInstance.FailureLoopCount, &serviceInstance.FailureSignature, &serviceInstance.RestartWindowStart, &serviceInstance.RestartWindowCount, &serviceInstance.GaveUpReason)
 		if err != nil {
 			return nil, fmt.Errorf("could not scan service row: %w", err)
 		}
@@ -306,7 +336,7 @@ func (db *DB) GetAllServiceInstances(ctx context.Context) ([]types.ServiceInstan
 
 func (db *DB) GetServiceInstance(ctx context.Context, name string) (types.ServiceInstance, error) {
 	query := `
-	SELECT name, restart_count, last_health_check, created_at, started_at, updated_at, next_restart_at, failure_loop_count, failure_signature
+	SELECT name, restart_count, last_health_check, created_at, started_at, updated_at, next_restart_at, failure_loop_count, failure_signature, restart_window_start, restart_window_count, gave_up_reason
 	FROM service_instances
 	WHERE name = ?
 	`
@@ -314,7 +344,7 @@ func (db *DB) GetServiceInstance(ctx context.Context, name string) (types.Servic
 	row := db.conn.QueryRowContext(ctx, query, name)
 	var svc types.ServiceInstance
 
-	err := row.Scan(&svc.Name, &svc.RestartCount, &svc.LastHealthCheck, &svc.CreatedAt, &svc.StartedAt, &svc.UpdatedAt, &svc.NextRestartAt, &svc.FailureLoopCount, &svc.FailureSignature)
+	err := row.Scan(&svc.Name, &svc.RestartCount, &svc.LastHealthCheck, &svc.CreatedAt, &svc.StartedAt, &svc.UpdatedAt, &svc.NextRestartAt, &svc.FailureLoopCount, &svc.FailureSignature, &svc.RestartWindowStart, &svc.RestartWindowCount, &svc.GaveUpReason)
 	if err == sql.ErrNoRows {
 		return types.ServiceInstance{}, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
 	}
@@ -328,7 +358,7 @@ var ErrProcessHistoryNotFound = errors.New("process history not found")
 
 func (db *DB) GetProcessHistoryEntryByPGID(ctx context.Context, pgid int) (types.ProcessHistory, error) {
 	query := `
-	SELECT pgid, started_at_ticks, service_name, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
+	SELECT pgid, started_at_ticks, service_name, instance, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
 	FROM process_history
 	WHERE pgid = ?
 	`
@@ -339,6 +369,7 @@ func (db *DB) GetProcessHistoryEntryByPGID(ctx context.Context, pgid int) (types
 	err := row.Scan(&processHistory.PGID,
 		&processHistory.StartedAtTicks,
 		&processHistory.ServiceName,
+		&processHistory.Instance,
 		&processHistory.State,
 		&processHistory.RssMemoryKb,
 		&processHistory.PeakRssMemoryKb,
@@ -359,7 +390,7 @@ func (db *DB) GetProcessHistoryEntryByPGID(ctx context.Context, pgid int) (types
 
 func (db *DB) GetProcessHistoryEntriesByServiceName(ctx context.Context, serviceName string) ([]types.ProcessHistory, error) {
 	query := `
-	SELECT pgid, started_at_ticks, service_name, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
+	SELECT pgid, started_at_ticks, service_name, instance, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
 	FROM process_history
 	WHERE service_name = ?
 	ORDER BY pgid
@@ -377,6 +408,7 @@ func (db *DB) GetProcessHistoryEntriesByServiceName(ctx context.Context, service
 		err := rows.Scan(&processHistory.PGID,
 			&processHistory.StartedAtTicks,
 			&processHistory.ServiceName,
+			&processHistory.Instance,
 			&processHistory.State,
 			&processHistory.RssMemoryKb,
 			&processHistory.PeakRssMemoryKb,
@@ -401,7 +433,7 @@ func (db *DB) GetProcessHistoryEntriesByServiceName(ctx context.Context, service
 
 func (db *DB) GetMostRecentProcessHistoryEntryByName(ctx context.Context, serviceName string) (types.ProcessHistory, error) {
 	query := `
-	SELECT pgid, started_at_ticks, service_name, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
+	SELECT pgid, started_at_ticks, service_name, instance, state, rss_memory_kb, peak_rss_memory_kb, cpu_percent, error, created_at, started_at, stopped_at, updated_at
 	FROM process_history
 	WHERE service_name = ?
 	ORDER BY started_at DESC NULLS LAST
@@ -412,6 +444,7 @@ func (db *DB) GetMostRecentProcessHistoryEntryByName(ctx context.Context, servic
 		&entry.PGID,
 		&entry.StartedAtTicks,
 		&entry.ServiceName,
+		&entry.Instance,
 		&entry.State,
 		&entry.RssMemoryKb,
 		&entry.PeakRssMemoryKb,
@@ -513,6 +546,9 @@ func (db *DB) RemoveProcessHistoryEntryViaPGID(ctx context.Context, pgid int) (b
 	if err != nil {
 		return false, fmt.Errorf("could not remove from process_history: %w", err)
 	}
+	if _, err := db.conn.ExecContext(ctx, "DELETE FROM launch_configs WHERE pgid = ?", pgid); err != nil {
+		return false, fmt.Errorf("could not remove from launch_configs: %w", err)
+	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -693,6 +729,145 @@ func (db *DB) ClearAllDependencyWaits(ctx context.Context) error {
 	return nil
 }
 
+// RecordCanaryResult upserts result as its service's most recent canary
+// reload, replacing the one before.
+func (db *DB) RecordCanaryResult(ctx context.Context, result types.CanaryResult) error {
+	query := `
+	INSERT INTO canary_results (service_name, outcome, reason, started_at, ended_at, window_ms, old_pgid, new_pgid,
+		new_exited, new_error_lines, old_error_lines, health_checks, health_failures)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(service_name) DO UPDATE SET outcome = excluded.outcome, reason = excluded.reason,
+		started_at = excluded.started_at, ended_at = excluded.ended_at, window_ms = excluded.window_ms,
+		old_pgid = excluded.old_pgid, new_pgid = excluded.new_pgid, new_exited = excluded.new_exited,
+		new_error_lines = excluded.new_error_lines, old_error_lines = excluded.old_error_lines,
+		health_checks = excluded.health_checks, health_failures = excluded.health_failures
+	`
+	if _, err := db.conn.ExecContext(ctx, query, result.ServiceName, string(result.Outcome), result.Reason,
+		result.StartedAt, result.EndedAt, result.Window.Milliseconds(), result.OldPGID, result.NewPGID,
+		result.NewExited, result.NewErrorLines, result.OldErrorLines, result.HealthChecks, result.HealthFailures); err != nil {
+		return fmt.Errorf("could not record canary result: %w", err)
+	}
+	return nil
+}
+
+// GetCanaryResult returns serviceName's most recent canary reload. found is
+// false, with no error, for a service never reloaded with a canary.
+func (db *DB) GetCanaryResult(ctx context.Context, serviceName string) (types.CanaryResult, bool, error) {
+	query := `
+	SELECT service_name, outcome, reason, started_at, ended_at, window_ms, old_pgid, new_pgid,
+		new_exited, new_error_lines, old_error_lines, health_checks, health_failures
+	FROM canary_results WHERE service_name = ?
+	`
+	var result types.CanaryResult
+	var outcome string
+	var windowMs int64
+	err := db.conn.QueryRowContext(ctx, query, serviceName).Scan(&result.ServiceName, &outcome, &result.Reason,
+		&result.StartedAt, &result.EndedAt, &windowMs, &result.OldPGID, &result.NewPGID,
+		&result.NewExited, &result.NewErrorLines, &result.OldErrorLines, &result.HealthChecks, &result.HealthFailures)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.CanaryResult{}, false, nil
+	}
+	if err != nil {
+		return types.CanaryResult{}, false, fmt.Errorf("could not get canary result: %w", err)
+	}
+	result.Outcome = types.CanaryOutcome(outcome)
+	result.Window = time.Duration(windowMs) * time.Millisecond
+	return result, true, nil
+}
+
+// RecordRelease inserts a newly unpacked release.
+func (db *DB) RecordRelease(ctx context.Context, release types.Release) error {
+	query := `
+	INSERT INTO releases (service_name, name, path, source, state, created_at, activated_at, error)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.ExecContext(ctx, query, release.ServiceName, release.Name, release.Path, release.Source,
+		string(release.State), release.CreatedAt, release.ActivatedAt, release.Error); err != nil {
+		return fmt.Errorf("could not record release: %w", err)
+	}
+	return nil
+}
+
+// ActivateRelease makes name serviceName's current release, stamping when,
+// and retires the one that was current before. It is one statement, so no
+// reader ever sees two current releases or none. A name that isn't recorded
+// changes nothing and is an error.
+func (db *DB) ActivateRelease(ctx context.Context, serviceName string, name string) error {
+	query := `
+	UPDATE releases
+	SET state = CASE WHEN name = ? THEN 'current' ELSE 'retired' END,
+		activated_at = CASE WHEN name = ? THEN ? ELSE activated_at END,
+		error = CASE WHEN name = ? THEN '' ELSE error END
+	WHERE service_name = ? AND (name = ? OR state = 'current')
+		AND EXISTS (SELECT 1 FROM releases WHERE service_name = ? AND name = ?)
+	`
+	result, err := db.conn.ExecContext(ctx, query, name, name, time.Now(), name, serviceName, name, serviceName, name)
+	if err != nil {
+		return fmt.Errorf("could not activate release: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not check update result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("release %s of %s not found", name, serviceName)
+	}
+	return nil
+}
+
+// FailRelease marks name failed with reason. A release that was current
+// stays current only in name: the caller has already switched away from it.
+func (db *DB) FailRelease(ctx context.Context, serviceName string, name string, reason string) error {
+	if _, err := db.conn.ExecContext(ctx, `UPDATE releases SET state = 'failed', error = ? WHERE service_name = ? AND name = ?`,
+		reason, serviceName, name); err != nil {
+		return fmt.Errorf("could not mark release failed: %w", err)
+	}
+	return nil
+}
+
+// GetReleases returns serviceName's recorded releases, newest first.
+func (db *DB) GetReleases(ctx context.Context, serviceName string) ([]types.Release, error) {
+	query := `
+	SELECT service_name, name, path, source, state, created_at, activated_at, error
+	FROM releases
+	WHERE service_name = ?
+	ORDER BY name DESC
+	`
+	rows, err := db.conn.QueryContext(ctx, query, serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("could not query releases: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck // rows.Close error is not actionable here
+
+	var releases []types.Release
+	for rows.Next() {
+		var release types.Release
+		if err := rows.Scan(&release.ServiceName, &release.Name, &release.Path, &release.Source, &release.State,
+			&release.CreatedAt, &release.ActivatedAt, &release.Error); err != nil {
+			return nil, fmt.Errorf("could not scan release row: %w", err)
+		}
+		releases = append(releases, release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate release rows: %w", err)
+	}
+	return releases, nil
+}
+
+// RemoveRelease deletes name's row once its directory has been pruned,
+// reporting whether there was one.
+func (db *DB) RemoveRelease(ctx context.Context, serviceName string, name string) (bool, error) {
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM releases WHERE service_name = ? AND name = ?`, serviceName, name)
+	if err != nil {
+		return false, fmt.Errorf("could not remove release: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not check delete result: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
 // SetServiceCatalogEnabled updates a service's persisted desired boot state.
 // See the Database interface doc for why this exists.
 func (db *DB) SetServiceCatalogEnabled(ctx context.Context, name string, enabled bool) error {
@@ -740,25 +915,45 @@ type ServiceInstanceUpdate struct {
 	NextRestartAt    *time.Time
 	FailureLoopCount *int
 	FailureSignature *string
+	// RestartWindowStart and RestartWindowCount track the monitor's restarts
+	// against a service's max_restarts; GaveUpReason, once set, stops it
+	// restarting the service at all. RegisterServiceInstance resets all three.
+	RestartWindowStart *time.Time
+	RestartWindowCount *int
+	GaveUpReason       *string
+	// RestartCountDelta adds to the stored restart count in the UPDATE itself,
+	// never taking it below zero, for a caller that must not overwrite counts
+	// others added since it read the row. It can't be set with RestartCount.
+	RestartCountDelta *int
 }
 
 var serviceInstanceValidColumns = map[string]bool{
 	"restart_count": true, "last_health_check": true,
 	"started_at": true, "updated_at": true, "next_restart_at": true,
 	"failure_loop_count": true, "failure_signature": true,
+	"restart_window_start": true, "restart_window_count": true, "gave_up_reason": true,
 }
 
 func (db *DB) UpdateServiceInstance(ctx context.Context, name string, updates ServiceInstanceUpdate) error {
-	setParts := make([]string, 0, 7)
-	args := make([]any, 0, 7)
-	requestedColumns := make([]string, 0, 7)
+	setParts := make([]string, 0, 10)
+	args := make([]any, 0, 10)
+	requestedColumns := make([]string, 0, 10)
 
+	if updates.RestartCount != nil && updates.RestartCountDelta != nil {
+		return fmt.Errorf("restart count and restart count delta both set")
+	}
 	if updates.RestartCount != nil {
 		requestedColumns = append(requestedColumns, "restart_count")
 		setParts = append(setParts, "restart_count = ?")
 		args = append(args, *updates.RestartCount)
 	}
 
+	if updates.RestartCountDelta != nil {
+		requestedColumns = append(requestedColumns, "restart_count")
+		setParts = append(setParts, "restart_count = MAX(restart_count + ?, 0)")
+		args = append(args, *updates.RestartCountDelta)
+	}
+
 	if updates.LastHealthCheck != nil {
 		requestedColumns = append(requestedColumns, "last_health_check")
 		setParts = append(setParts, "last_health_check = ?")
@@ -789,6 +984,24 @@ func (db *DB) UpdateServiceInstance(ctx context.Context, name string, updates Se
 		args = append(args, *updates.FailureSignature)
 	}
 
+	if updates.RestartWindowStart != nil {
+		requestedColumns = append(requestedColumns, "restart_window_start")
+		setParts = append(setParts, "restart_window_start = ?")
+		args = append(args, *updates.RestartWindowStart)
+	}
+
+	if updates.RestartWindowCount != nil {
+		requestedColumns = append(requestedColumns, "restart_window_count")
+		setParts = append(setParts, "restart_window_count = ?")
+		args = append(args, *updates.RestartWindowCount)
+	}
+
+	if updates.GaveUpReason != nil {
+		requestedColumns = append(requestedColumns, "gave_up_reason")
+		setParts = append(setParts, "gave_up_reason = ?")
+		args = append(args, *updates.GaveUpReason)
+	}
+
 	if len(setParts) == 0 {
 		return fmt.Errorf("no fields to update")
 	}
@@ -829,3 +1042,45 @@ func (db *DB) UpdateServiceInstance(ctx context.Context, name string, updates Se
 	}
 	return nil
 }
+
+// RecordLaunchConfig stores the config launch.PGID was started with,
+// replacing any left by an earlier process that had the same pgid.
+func (db *DB) RecordLaunchConfig(ctx context.Context, launch types.LaunchConfig) error {
+	encoded, err := json.Marshal(launch.Config)
+	if err != nil {
+		return fmt.Errorf("encoding launch config: %w", err)
+	}
+	query := `
+	INSERT OR REPLACE INTO launch_configs (pgid, service_name, config_hash, env_file_hash, config, recorded_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.conn.ExecContext(ctx, query, launch.PGID, launch.ServiceName, launch.ConfigHash,
+		launch.EnvFileHash, string(encoded), launch.RecordedAt); err != nil {
+		return fmt.Errorf("could not record launch config: %w", err)
+	}
+	return nil
+}
+
+// GetLaunchConfig returns the config pgid was started with, if one was
+// recorded.
+func (db *DB) GetLaunchConfig(ctx context.Context, pgid int) (types.LaunchConfig, bool, error) {
+	query := `
+	SELECT pgid, service_name, config_hash, env_file_hash, config, recorded_at
+	FROM launch_configs
+	WHERE pgid = ?
+	`
+	var launch types.LaunchConfig
+	var encoded string
+	err := db.conn.QueryRowContext(ctx, query, pgid).Scan(&launch.PGID, &launch.ServiceName, &launch.ConfigHash,
+		&launch.EnvFileHash, &encoded, &launch.RecordedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.LaunchConfig{}, false, nil
+	}
+	if err != nil {
+		return types.LaunchConfig{}, false, fmt.Errorf("could not get launch config: %w", err)
+	}
+	if err := json.Unmarshal([]byte(encoded), &launch.Config); err != nil {
+		return types.LaunchConfig{}, false, fmt.Errorf("decoding launch config: %w", err)
+	}
+	return launch, true, nil
+}
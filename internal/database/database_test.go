@@ -361,6 +361,45 @@ func TestUpdateServiceInstance(t *testing.T) {
 	}
 }
 
+// TestUpdateServiceInstance_RestartCountDelta proves a delta adjusts the
+// stored count rather than a caller's stale copy, and stops at zero.
+func TestUpdateServiceInstance_RestartCountDelta(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+
+	if err := db.RegisterServiceInstance(t.Context(), "cms"); err != nil {
+		t.Fatalf("RegisterServiceInstance failed: %v", err)
+	}
+	if err := db.UpdateServiceInstance(t.Context(), "cms", database.ServiceInstanceUpdate{RestartCount: new(3)}); err != nil {
+		t.Fatalf("UpdateServiceInstance failed: %v", err)
+	}
+	if err := db.UpdateServiceInstance(t.Context(), "cms", database.ServiceInstanceUpdate{RestartCountDelta: new(-1)}); err != nil {
+		t.Fatalf("UpdateServiceInstance(delta -1) failed: %v", err)
+	}
+	instance, err := db.GetServiceInstance(t.Context(), "cms")
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+	if instance.RestartCount != 2 {
+		t.Errorf("expected restart count 2 after a -1 delta, got %d", instance.RestartCount)
+	}
+
+	if err := db.UpdateServiceInstance(t.Context(), "cms", database.ServiceInstanceUpdate{RestartCountDelta: new(-5)}); err != nil {
+		t.Fatalf("UpdateServiceInstance(delta -5) failed: %v", err)
+	}
+	instance, err = db.GetServiceInstance(t.Context(), "cms")
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+	if instance.RestartCount != 0 {
+		t.Errorf("expected restart count to stop at 0, got %d", instance.RestartCount)
+	}
+
+	err = db.UpdateServiceInstance(t.Context(), "cms", database.ServiceInstanceUpdate{RestartCount: new(1), RestartCountDelta: new(1)})
+	if err == nil {
+		t.Error("expected an error when both RestartCount and RestartCountDelta are set")
+	}
+}
+
 func TestUpdateServiceInstance_NextRestartAt(t *testing.T) {
 	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 
@@ -998,3 +1037,126 @@ func TestDependencyWaitStatus_MalformedPendingErrors(t *testing.T) {
 		t.Error("expected a decode error for a malformed pending column")
 	}
 }
+
+func TestCanaryResult_RecordGetReplace(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+
+	if _, found, err := db.GetCanaryResult(t.Context(), "web"); err != nil || found {
+		t.Fatalf("expected no canary result initially, found=%v err=%v", found, err)
+	}
+
+	started := time.Now().Add(-5 * time.Minute)
+	first := types.CanaryResult{
+		ServiceName:    "web",
+		Outcome:        types.CanaryOutcomeRolledBack,
+		Reason:         "new instance exited",
+		StartedAt:      started,
+		EndedAt:        started.Add(time.Minute),
+		Window:         5 * time.Minute,
+		OldPGID:        100,
+		NewPGID:        200,
+		NewExited:      true,
+		NewErrorLines:  7,
+		OldErrorLines:  1,
+		HealthChecks:   12,
+		HealthFailures: 2,
+	}
+	if err := db.RecordCanaryResult(t.Context(), first); err != nil {
+		t.Fatalf("RecordCanaryResult: %v", err)
+	}
+	got, found, err := db.GetCanaryResult(t.Context(), "web")
+	if err != nil || !found {
+		t.Fatalf("GetCanaryResult: found=%v err=%v", found, err)
+	}
+	if got.Outcome != first.Outcome || got.Reason != first.Reason || got.Window != first.Window ||
+		got.NewPGID != 200 || !got.NewExited || got.NewErrorLines != 7 || got.HealthFailures != 2 {
+		t.Errorf("GetCanaryResult = %+v, want %+v", got, first)
+	}
+	if !got.StartedAt.Equal(first.StartedAt) {
+		t.Errorf("StartedAt = %v, want %v to round-trip", got.StartedAt, first.StartedAt)
+	}
+
+	if err := db.RecordCanaryResult(t.Context(), types.CanaryResult{ServiceName: "web", Outcome: types.CanaryOutcomePromoted, StartedAt: started, EndedAt: started, NewPGID: 300}); err != nil {
+		t.Fatalf("second RecordCanaryResult: %v", err)
+	}
+	if got, _, _ := db.GetCanaryResult(t.Context(), "web"); got.Outcome != types.CanaryOutcomePromoted || got.NewPGID != 300 || got.NewExited {
+		t.Errorf("after a second record got %+v, want it to replace the first", got)
+	}
+}
+
+func TestReleases_RecordActivateFailRemove(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+
+	for _, name := range []string{"20260101120000", "20260102120000"} {
+		if err := db.RecordRelease(t.Context(), types.Release{
+			ServiceName: "web", Name: name, Path: "/srv/web/releases/" + name, Source: "/tmp/build",
+			State: types.ReleaseStateRetired, CreatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("RecordRelease(%s): %v", name, err)
+		}
+	}
+	if err := db.ActivateRelease(t.Context(), "web", "20260101120000"); err != nil {
+		t.Fatalf("ActivateRelease: %v", err)
+	}
+	if err := db.ActivateRelease(t.Context(), "web", "20260102120000"); err != nil {
+		t.Fatalf("second ActivateRelease: %v", err)
+	}
+	if err := db.ActivateRelease(t.Context(), "web", "missing"); err == nil {
+		t.Error("expected activating an unrecorded release to fail")
+	}
+
+	releases, err := db.GetReleases(t.Context(), "web")
+	if err != nil {
+		t.Fatalf("GetReleases: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Name != "20260102120000" {
+		t.Fatalf("GetReleases = %+v, want both, newest first", releases)
+	}
+	if releases[0].State != types.ReleaseStateCurrent || releases[1].State != types.ReleaseStateRetired {
+		t.Errorf("states = %s, %s; want the second activation current and the first retired", releases[0].State, releases[1].State)
+	}
+	if releases[0].ActivatedAt == nil || releases[1].ActivatedAt == nil {
+		t.Errorf("expected both releases to carry an activation time, got %v and %v", releases[0].ActivatedAt, releases[1].ActivatedAt)
+	}
+
+	if err := db.FailRelease(t.Context(), "web", "20260102120000", "not ready"); err != nil {
+		t.Fatalf("FailRelease: %v", err)
+	}
+	removed, err := db.RemoveRelease(t.Context(), "web", "20260101120000")
+	if err != nil || !removed {
+		t.Fatalf("RemoveRelease = %v, %v; want the row removed", removed, err)
+	}
+	releases, _ = db.GetReleases(t.Context(), "web")
+	if len(releases) != 1 || releases[0].State != types.ReleaseStateFailed || releases[0].Error != "not ready" {
+		t.Errorf("GetReleases = %+v, want only the failed release left", releases)
+	}
+}
+
+func TestLaunchConfigs_RecordGetAndRemoveWithHistory(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+
+	if _, found, err := db.GetLaunchConfig(t.Context(), 4242); err != nil || found {
+		t.Fatalf("GetLaunchConfig before recording = %v, %v; want nothing found", found, err)
+	}
+	launch := types.LaunchConfig{
+		PGID: 4242, ServiceName: "web", ConfigHash: "abc", EnvFileHash: "def", RecordedAt: time.Now(),
+		Config: types.ServiceConfig{Name: "web", Port: 8080, Env: types.ServiceEnv{{Name: "MODE", Value: "prod"}}},
+	}
+	if err := db.RecordLaunchConfig(t.Context(), launch); err != nil {
+		t.Fatalf("RecordLaunchConfig: %v", err)
+	}
+	got, found, err := db.GetLaunchConfig(t.Context(), 4242)
+	if err != nil || !found {
+		t.Fatalf("GetLaunchConfig = %v, %v; want it found", found, err)
+	}
+	if got.ConfigHash != "abc" || got.EnvFileHash != "def" || got.Config.Port != 8080 || len(got.Config.Env) != 1 {
+		t.Errorf("GetLaunchConfig = %+v, want the recorded launch back", got)
+	}
+
+	if _, err := db.RemoveProcessHistoryEntryViaPGID(t.Context(), 4242); err != nil {
+		t.Fatalf("RemoveProcessHistoryEntryViaPGID: %v", err)
+	}
+	if _, found, _ := db.GetLaunchConfig(t.Context(), 4242); found {
+		t.Error("expected removing the launch's history row to remove its config")
+	}
+}
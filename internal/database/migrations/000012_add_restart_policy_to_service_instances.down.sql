@@ -0,0 +1,3 @@
+ALTER TABLE service_instances DROP COLUMN gave_up_reason;
+ALTER TABLE service_instances DROP COLUMN restart_window_count;
+ALTER TABLE service_instances DROP COLUMN restart_window_start;
@@ -0,0 +1,3 @@
+ALTER TABLE service_instances ADD COLUMN restart_window_start DATETIME;
+ALTER TABLE service_instances ADD COLUMN restart_window_count INTEGER default 0;
+ALTER TABLE service_instances ADD COLUMN gave_up_reason TEXT default '';
@@ -0,0 +1 @@
+ALTER TABLE process_history DROP COLUMN instance;
@@ -0,0 +1 @@
+ALTER TABLE process_history ADD COLUMN instance INTEGER NOT NULL DEFAULT 0 CHECK (instance >= 0);
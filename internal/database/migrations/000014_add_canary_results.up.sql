@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS canary_results (
+	service_name TEXT PRIMARY KEY,
+	outcome TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	started_at DATETIME NOT NULL,
+	ended_at DATETIME NOT NULL,
+	window_ms INTEGER NOT NULL,
+	old_pgid INTEGER NOT NULL,
+	new_pgid INTEGER NOT NULL,
+	new_exited INTEGER NOT NULL DEFAULT 0,
+	new_error_lines INTEGER NOT NULL DEFAULT 0,
+	old_error_lines INTEGER NOT NULL DEFAULT 0,
+	health_checks INTEGER NOT NULL DEFAULT 0,
+	health_failures INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS releases (
+	service_name TEXT NOT NULL,
+	name TEXT NOT NULL,
+	path TEXT NOT NULL,
+	source TEXT NOT NULL,
+	state TEXT NOT NULL CHECK (state IN ('current', 'retired', 'failed')),
+	created_at DATETIME NOT NULL,
+	activated_at DATETIME,
+	error TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (service_name, name)
+);
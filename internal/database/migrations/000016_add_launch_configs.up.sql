@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS launch_configs (
+	pgid INTEGER PRIMARY KEY,
+	service_name TEXT NOT NULL,
+	config_hash TEXT NOT NULL,
+	env_file_hash TEXT NOT NULL,
+	config TEXT NOT NULL,
+	recorded_at DATETIME NOT NULL
+);
@@ -0,0 +1,28 @@
+// Package fswatch reports changes to the files under a directory, through
+// inotify on Linux and by polling elsewhere.
+package fswatch
+
+import "time"
+
+// Overflow is reported in place of a path when the watch may have missed
+// changes, its inotify queue having overflowed say: anything under the root
+// may have changed.
+const Overflow = "."
+
+// PollInterval is how often a watched tree is rescanned where there is no
+// inotify.
+const PollInterval = 2 * time.Second
+
+// Options shapes a Watch.
+type Options struct {
+	// SkipDir reports whether to leave a subdirectory, by its slash-separated
+	// path relative to the root, and everything under it unwatched. Nil
+	// watches every one.
+	SkipDir func(rel string) bool
+	// Recursive watches the root's subdirectories as well as the root.
+	Recursive bool
+}
+
+func (o Options) skip(rel string) bool {
+	return rel != "." && o.SkipDir != nil && o.SkipDir(rel)
+}
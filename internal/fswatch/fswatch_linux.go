@@ -0,0 +1,159 @@
+//go:build linux
+
+package fswatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_MOVED_FROM | unix.IN_DELETE | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+
+type inotifyWatch struct {
+	// dirs maps each watch descriptor to its directory relative to root.
+	dirs map[int]string
+	root string
+	opts Options
+	fd   int
+}
+
+// Watch reports the slash-separated path, relative to root, of each file
+// created, written and closed, moved in or out, or deleted under root, until
+// ctx is done or root itself is removed or moved away, when the channel is
+// closed.
+func Watch(ctx context.Context, root string, opts Options) (<-chan string, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+	w := &inotifyWatch{dirs: make(map[int]string), root: filepath.Clean(root), opts: opts, fd: fd}
+	if err := w.addTree(".", nil); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	// A non-blocking fd wrapped by os.NewFile goes through the runtime
+	// poller, so Close below unblocks the Read in run.
+	inotify := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		<-ctx.Done()
+		_ = inotify.Close()
+	}()
+
+	changes := make(chan string, 64)
+	go w.run(ctx, inotify, changes)
+	return changes, nil
+}
+
+// addTree watches the directory rel and, for a recursive watch, every
+// subdirectory under it SkipDir doesn't skip. With found set, it is called
+// with each file already there: a directory moved or created under the root
+// can be filled before its watch is added.
+func (w *inotifyWatch) addTree(rel string, found func(string)) error {
+	return filepath.WalkDir(filepath.Join(w.root, rel), func(path string, d fs.DirEntry, err error) error {
+		sub, relErr := filepath.Rel(w.root, path)
+		if relErr != nil {
+			return relErr
+		}
+		sub = filepath.ToSlash(sub)
+		if err != nil {
+			if sub == rel {
+				return err
+			}
+			return nil // removed while being walked
+		}
+		if !d.IsDir() {
+			if found != nil {
+				found(sub)
+			}
+			return nil
+		}
+		if w.opts.skip(sub) {
+			return filepath.SkipDir
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			if sub == rel {
+				return fmt.Errorf("watching %s: %w", path, err)
+			}
+			return nil
+		}
+		w.dirs[wd] = sub
+		if !w.opts.Recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+func (w *inotifyWatch) run(ctx context.Context, inotify *os.File, changes chan<- string) {
+	defer close(changes)
+	send := func(path string) bool {
+		select {
+		case changes <- path:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := inotify.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				_ = inotify.Close()
+			}
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[offset:])))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			name := string(bytes.TrimRight(buf[offset+unix.SizeofInotifyEvent:offset+unix.SizeofInotifyEvent+nameLen], "\x00"))
+			offset += unix.SizeofInotifyEvent + nameLen
+
+			if mask&unix.IN_Q_OVERFLOW != 0 {
+				if !send(Overflow) {
+					return
+				}
+				continue
+			}
+			dir, known := w.dirs[wd]
+			if !known {
+				continue
+			}
+			if mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF|unix.IN_IGNORED) != 0 {
+				if dir == "." {
+					return
+				}
+				delete(w.dirs, wd)
+				continue
+			}
+			path := name
+			if dir != "." {
+				path = dir + "/" + name
+			}
+			if mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 && w.opts.Recursive && !w.opts.skip(path) {
+				var found []string
+				_ = w.addTree(path, func(file string) { found = append(found, file) })
+				for _, file := range found {
+					if !send(file) {
+						return
+					}
+				}
+				continue
+			}
+			if !send(path) {
+				return
+			}
+		}
+	}
+}
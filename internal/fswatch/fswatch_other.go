@@ -0,0 +1,98 @@
+//go:build !linux
+
+package fswatch
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+type pollStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch reports the slash-separated path, relative to root, of each file
+// created, changed or removed under root, comparing a scan every
+// PollInterval with the one before, until ctx is done or root can no longer
+// be read, when the channel is closed.
+func Watch(ctx context.Context, root string, opts Options) (<-chan string, error) {
+	root = filepath.Clean(root)
+	last, err := pollScan(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan string, 64)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current, err := pollScan(root, opts)
+			if err != nil {
+				return
+			}
+			for _, path := range pollDiff(last, current) {
+				select {
+				case changes <- path:
+				case <-ctx.Done():
+					return
+				}
+			}
+			last = current
+		}
+	}()
+	return changes, nil
+}
+
+func pollScan(root string, opts Options) (map[string]pollStamp, error) {
+	stamps := make(map[string]pollStamp)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if opts.skip(rel) || (!opts.Recursive && rel != ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		stamps[rel] = pollStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return stamps, err
+}
+
+func pollDiff(last, current map[string]pollStamp) []string {
+	var changed []string
+	for path, stamp := range current {
+		if old, ok := last[path]; !ok || old != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range last {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
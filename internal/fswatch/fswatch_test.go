@@ -0,0 +1,81 @@
+package fswatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitForPath reads changes until want arrives, failing on anything in
+// unwanted or after five seconds.
+func waitForPath(t *testing.T, changes <-chan string, want string, unwanted ...string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case path, ok := <-changes:
+			if !ok {
+				t.Fatalf("watch closed before %s was reported", want)
+			}
+			for _, skip := range unwanted {
+				if path == skip {
+					t.Fatalf("%s should not have been reported", path)
+				}
+			}
+			if path == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected %s to be reported", want)
+		}
+	}
+}
+
+func TestWatchReportsChangedFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "node_modules"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	changes, err := Watch(t.Context(), root, Options{
+		Recursive: true,
+		SkipDir:   func(rel string) bool { return rel == "node_modules" },
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "node_modules", "dep.js"), []byte("x"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "app.js"), []byte("x"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	waitForPath(t, changes, "app.js", "node_modules/dep.js")
+
+	if err := os.MkdirAll(filepath.Join(root, "src", "lib"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "lib", "util.js"), []byte("x"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	waitForPath(t, changes, "src/lib/util.js", "node_modules/dep.js")
+}
+
+func TestWatchClosesWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	changes, err := Watch(ctx, t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	cancel()
+	select {
+	case _, ok := <-changes:
+		for ok {
+			_, ok = <-changes
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the channel to close once ctx is done")
+	}
+}
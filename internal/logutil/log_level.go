@@ -39,6 +39,12 @@ func slogLevel(verbose bool) slog.Level {
 // child output.
 const HealthBreadcrumbSource = "health"
 
+// HookSource tags the "source" field of lines a service's lifecycle hook
+// (pre_start, post_start, pre_stop, post_stop) wrote, so eos logs can tell
+// them apart from the service's own output. Each line also names its hook
+// in a "hook" field.
+const HookSource = "hook"
+
 // errorLineMarkers are case-insensitive substrings that mark a line as
 // plausibly naming a genuine crash reason, as opposed to a runtime's own
 // startup/version banner (e.g. "Bun v1.3.14 (Linux arm64)", "Node.js
@@ -116,3 +122,41 @@ func LastLogMessage(path string, pgid int) (string, bool) {
 	}
 	return fallback, haveFallback
 }
+
+// CountErrorLines tallies, per the "pgid" they're tagged with, the lines of
+// the JSON log file at path that looksLikeErrorLine, reading from byte offset
+// from onward so a caller polling a growing file only pays for what's new. It
+// returns the offset to pass next time: the end of the last complete line
+// read, so a line still being written is counted once it's finished. A file
+// shorter than from has been rotated since, and is read from the start.
+// Health-monitor breadcrumbs and hook output are skipped, as in
+// LastLogMessage; they aren't the process group's own.
+func CountErrorLines(path string, from int64) (counts map[int]int, next int64, err error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is caller-controlled, not user input
+	if err != nil {
+		return nil, from, err
+	}
+	if int64(len(data)) < from {
+		from = 0
+	}
+	data = data[from:]
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	counts = make(map[int]int)
+	for raw := range bytes.SplitSeq(data[:complete], []byte("\n")) {
+		var entry struct {
+			Msg    string `json:"msg"`
+			Source string `json:"source"`
+			PGID   int    `json:"pgid"`
+		}
+		if json.Unmarshal(bytes.TrimSpace(raw), &entry) != nil {
+			continue
+		}
+		if entry.PGID == 0 || entry.Source == HealthBreadcrumbSource || entry.Source == HookSource {
+			continue
+		}
+		if looksLikeErrorLine(entry.Msg) {
+			counts[entry.PGID]++
+		}
+	}
+	return counts, from + int64(complete), nil
+}
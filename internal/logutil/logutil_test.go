@@ -269,3 +269,44 @@ func TestLooksLikeErrorLine(t *testing.T) {
 		}
 	}
 }
+
+func TestCountErrorLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "svc-error.log")
+	content := jsonLine("Error: connect ECONNREFUSED", "stderr", 100) + "\n" +
+		jsonLine("listening on :8080", "stderr", 100) + "\n" +
+		jsonLine("panic: nil map", "stderr", 200) + "\n" +
+		jsonLine("[svc] restart failed: error", HealthBreadcrumbSource, 200) + "\n" +
+		jsonLine("fatal: hook", HookSource, 200) + "\n" +
+		`{"msg":"Error half-writ`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	counts, next, err := CountErrorLines(path, 0)
+	if err != nil {
+		t.Fatalf("CountErrorLines: %v", err)
+	}
+	if counts[100] != 1 || counts[200] != 1 || len(counts) != 2 {
+		t.Errorf("counts = %v, want one error line each for pgids 100 and 200", counts)
+	}
+	if want := int64(strings.LastIndexByte(content, '\n') + 1); next != want {
+		t.Errorf("next = %d, want %d, the end of the last complete line", next, want)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	_, _ = f.WriteString(`ten","source":"stderr","pgid":100}` + "\n")
+	_ = f.Close()
+	if counts, _, _ = CountErrorLines(path, next); counts[100] != 1 || len(counts) != 1 {
+		t.Errorf("counts from %d = %v, want only the finished line", next, counts)
+	}
+
+	if err := os.WriteFile(path, []byte(jsonLine("exception", "stderr", 300)+"\n"), 0o600); err != nil {
+		t.Fatalf("rotate log: %v", err)
+	}
+	if counts, _, _ = CountErrorLines(path, next); counts[300] != 1 {
+		t.Errorf("counts after rotation = %v, want the new file read from the start", counts)
+	}
+}
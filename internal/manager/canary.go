@@ -0,0 +1,164 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/logutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+const (
+	// canaryHealthFailureLimit is how many health checks in a row the
+	// incoming instance may fail during a canary window before it is rolled
+	// back. One failure is tolerated as a blip, as the readiness gate does by
+	// resetting its streak.
+	canaryHealthFailureLimit = 3
+	// canaryErrorLineAllowance is how many more error lines than twice the
+	// outgoing instance's the incoming one may log over a canary window. The
+	// outgoing instance is the baseline: both serve the same traffic, so an
+	// error rate the old code already has isn't held against the new.
+	canaryErrorLineAllowance = 5
+)
+
+// canaryValidate reports why a reload can't run a canary window for config:
+// the window needs both instances serving side by side, which a proxy: true
+// service (the proxy moves new connections to the incoming instance at once)
+// and a rolling reload of several instances don't do.
+func canaryValidate(config *types.ServiceConfig) error {
+	switch {
+	case config.Proxy:
+		return fmt.Errorf("a canary reload is not supported with proxy: true")
+	case InstanceCount(config) > 1:
+		return fmt.Errorf("a canary reload is not supported with instances: %d", InstanceCount(config))
+	}
+	return nil
+}
+
+// runCanary keeps the outgoing and incoming instances of a reload serving
+// side by side for cfg.Canary, and decides whether the incoming one holds up
+// (see canaryVerdict). Every cfg.ProbeInterval it checks the incoming
+// instance is still alive, runs its health check through probe, and tallies
+// the error lines each instance wrote to the service's error log since the
+// window opened. The first failed check ends the window early. The result
+// is recorded for eos info either way; acting on it is the caller's.
+func (m *LocalManager) runCanary(name string, oldPGID, newPGID int, newStartedAtTicks int64, probe ReadinessProbe, check HealthProbe, cfg ReloadConfig) types.CanaryResult {
+	result := types.CanaryResult{
+		ServiceName: name,
+		StartedAt:   time.Now(),
+		Window:      cfg.Canary,
+		OldPGID:     oldPGID,
+		NewPGID:     newPGID,
+	}
+	errorLog, offset := m.canaryErrorLog(name)
+	m.logger.Info("reload: canary window open", "service", name, "old_pgid", oldPGID, "new_pgid", newPGID, "window", cfg.Canary)
+
+	window := time.NewTimer(cfg.Canary)
+	defer window.Stop()
+	ticker := time.NewTicker(cfg.ProbeInterval)
+	defer ticker.Stop()
+
+	consecutiveFailures := 0
+	for result.Reason == "" {
+		select {
+		case <-m.ctx.Done():
+			result.Reason = "canceled before the window closed"
+		case <-window.C:
+			offset = m.canaryTallyErrors(&result, errorLog, offset)
+			result.Reason = canaryVerdict(&result, consecutiveFailures)
+			if result.Reason == "" {
+				result.Outcome = types.CanaryOutcomePromoted
+				result.EndedAt = time.Now()
+				m.canaryRecord(result)
+				return result
+			}
+		case <-ticker.C:
+			result.NewExited = !m.tracker.aliveMatching(newPGID, newStartedAtTicks)
+			if !result.NewExited {
+				result.HealthChecks++
+				if probe(m.ctx, newPGID, newStartedAtTicks, check) {
+					consecutiveFailures = 0
+				} else {
+					result.HealthFailures++
+					consecutiveFailures++
+				}
+			}
+			offset = m.canaryTallyErrors(&result, errorLog, offset)
+			result.Reason = canaryVerdict(&result, consecutiveFailures)
+		}
+	}
+	result.Outcome = types.CanaryOutcomeRolledBack
+	result.EndedAt = time.Now()
+	m.logger.Warn("reload: canary failed", "service", name, "new_pgid", newPGID, "reason", result.Reason)
+	m.canaryRecord(result)
+	return result
+}
+
+// canaryVerdict is the reason the incoming instance of a canary window fails
+// on the metrics gathered so far, or "" while it holds up: it exited, failed
+// canaryHealthFailureLimit health checks in a row, or logged more error lines
+// than the outgoing instance's rate allows (see canaryErrorLineAllowance).
+func canaryVerdict(result *types.CanaryResult, consecutiveFailures int) string {
+	switch {
+	case result.NewExited:
+		return "new instance exited"
+	case consecutiveFailures >= canaryHealthFailureLimit:
+		return fmt.Sprintf("new instance failed %d health checks in a row", consecutiveFailures)
+	case result.NewErrorLines > 2*result.OldErrorLines+canaryErrorLineAllowance:
+		return fmt.Sprintf("new instance logged %d error lines to the old instance's %d", result.NewErrorLines, result.OldErrorLines)
+	}
+	return ""
+}
+
+// canaryErrorLog returns the path of name's error log and its current size,
+// so the canary window only counts lines written once it opened. An empty
+// path (no error log yet) leaves error lines uncounted.
+func (m *LocalManager) canaryErrorLog(name string) (string, int64) {
+	path, err := m.GetServiceLogFilePath(m.ctx, name, true)
+	if err != nil {
+		m.logger.Warn("reload: canary can't read the error log, error lines won't be counted", "service", name, "error", err)
+		return "", 0
+	}
+	info, err := os.Stat(*path)
+	if err != nil {
+		return *path, 0
+	}
+	return *path, info.Size()
+}
+
+// canaryTallyErrors adds the error lines each instance wrote to path since
+// offset to result, returning the offset to read from next.
+func (m *LocalManager) canaryTallyErrors(result *types.CanaryResult, path string, offset int64) int64 {
+	if path == "" {
+		return offset
+	}
+	counts, next, err := logutil.CountErrorLines(path, offset)
+	if err != nil {
+		m.logger.Debug("reload: counting canary error lines", "service", result.ServiceName, "error", err)
+		return offset
+	}
+	result.NewErrorLines += counts[result.NewPGID]
+	result.OldErrorLines += counts[result.OldPGID]
+	return next
+}
+
+// canaryRecord persists result as its service's most recent canary. A DB
+// failure is logged, not fatal: the reload it describes has already been
+// decided.
+func (m *LocalManager) canaryRecord(result types.CanaryResult) {
+	if err := m.db.RecordCanaryResult(m.ctx, result); err != nil {
+		m.logger.Error("reload: recording canary result", "service", result.ServiceName, "error", err)
+	}
+}
+
+// GetCanaryResult returns name's most recent canary reload, or nil when it
+// has never been reloaded with one.
+func (m *LocalManager) GetCanaryResult(ctx context.Context, name string) (*types.CanaryResult, error) {
+	result, found, err := m.db.GetCanaryResult(ctx, name)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestCanaryVerdict(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   types.CanaryResult
+		failures int
+		want     string
+	}{
+		{"holding up", types.CanaryResult{NewErrorLines: 7, OldErrorLines: 1}, 2, ""},
+		{"exited", types.CanaryResult{NewExited: true}, 0, "new instance exited"},
+		{"failing health checks", types.CanaryResult{}, 3, "failed 3 health checks"},
+		{"error lines", types.CanaryResult{NewErrorLines: 8, OldErrorLines: 1}, 0, "logged 8 error lines to the old instance's 1"},
+	}
+	for _, tt := range tests {
+		got := canaryVerdict(&tt.result, tt.failures)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: canaryVerdict = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanaryValidate(t *testing.T) {
+	if err := canaryValidate(&types.ServiceConfig{}); err != nil {
+		t.Errorf("canaryValidate(single instance) = %v, want nil", err)
+	}
+	if err := canaryValidate(&types.ServiceConfig{Proxy: true, Port: 8080}); err == nil {
+		t.Error("canaryValidate(proxy: true) = nil, want an error")
+	}
+	if err := canaryValidate(&types.ServiceConfig{Instances: 2}); err == nil {
+		t.Error("canaryValidate(instances: 2) = nil, want an error")
+	}
+}
+
+var canaryTestConfig = ReloadConfig{
+	GracePeriod:      2 * time.Second,
+	TickerPeriod:     20 * time.Millisecond,
+	ReadinessTimeout: 2 * time.Second,
+	ProbeInterval:    20 * time.Millisecond,
+	Canary:           300 * time.Millisecond,
+}
+
+// TestReloadServiceCanaryPromotes checks a new instance that holds up over
+// the window takes over, and the outcome is recorded.
+func TestReloadServiceCanaryPromotes(t *testing.T) {
+	mgr, name := registerLongRunningService(t, "canary-ok")
+	oldPGID, err := mgr.StartService(t.Context(), name)
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { killGroup(oldPGID) })
+
+	result, err := mgr.ReloadService(name, alwaysReady, canaryTestConfig)
+	if err != nil {
+		t.Fatalf("ReloadService: %v", err)
+	}
+	t.Cleanup(func() { killGroup(result.NewPGID) })
+	if result.Canary == nil || result.Canary.Outcome != types.CanaryOutcomePromoted || result.Canary.HealthChecks == 0 {
+		t.Fatalf("Canary = %+v, want a promoted canary with health checks run", result.Canary)
+	}
+	if !waitGone(oldPGID) {
+		t.Errorf("old instance pgid %d should have been drained after the window", oldPGID)
+	}
+
+	recorded, err := mgr.GetCanaryResult(t.Context(), name)
+	if err != nil || recorded == nil || recorded.Outcome != types.CanaryOutcomePromoted || recorded.NewPGID != result.NewPGID {
+		t.Errorf("GetCanaryResult = %+v, %v; want the promoted canary recorded", recorded, err)
+	}
+}
+
+// TestReloadServiceCanaryRollsBack passes the readiness gate and then fails
+// every health check, and checks the new instance is stopped and its row
+// removed while the old one keeps serving.
+func TestReloadServiceCanaryRollsBack(t *testing.T) {
+	mgr, name := registerLongRunningService(t, "canary-fail")
+	oldPGID, err := mgr.StartService(t.Context(), name)
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { killGroup(oldPGID) })
+
+	var probes atomic.Int32
+	readyThenFailing := func(context.Context, int, int64, HealthProbe) bool {
+		return probes.Add(1) <= reloadReadyConsecutivePasses
+	}
+	cfg := canaryTestConfig
+	cfg.Canary = 10 * time.Second
+	result, err := mgr.ReloadService(name, readyThenFailing, cfg)
+	if !errors.Is(err, ErrCanaryFailed) {
+		t.Fatalf("ReloadService err = %v, want ErrCanaryFailed", err)
+	}
+	t.Cleanup(func() { killGroup(result.NewPGID) })
+	if result.Canary == nil || result.Canary.Outcome != types.CanaryOutcomeRolledBack || result.Canary.HealthFailures < canaryHealthFailureLimit {
+		t.Fatalf("Canary = %+v, want rolled back on failed health checks", result.Canary)
+	}
+	if result.Canary.EndedAt.Sub(result.Canary.StartedAt) >= cfg.Canary {
+		t.Errorf("canary ran its whole window; a failed check should end it early")
+	}
+	if !waitGone(result.NewPGID) {
+		t.Errorf("new instance pgid %d should have been stopped", result.NewPGID)
+	}
+	if !procutil.IsAlive(oldPGID) {
+		t.Errorf("old instance pgid %d must keep serving after a failed canary", oldPGID)
+	}
+	recent, err := mgr.GetMostRecentProcessHistoryEntry(t.Context(), name)
+	if err != nil || recent.PGID != oldPGID {
+		t.Errorf("most-recent history = %+v, %v; want the surviving old instance %d", recent, err, oldPGID)
+	}
+	if recorded, _ := mgr.GetCanaryResult(t.Context(), name); recorded == nil || recorded.Outcome != types.CanaryOutcomeRolledBack {
+		t.Errorf("GetCanaryResult = %+v, want the rolled-back canary recorded", recorded)
+	}
+}
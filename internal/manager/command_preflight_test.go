@@ -39,7 +39,7 @@ func TestFirstCommandBinary(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.command, func(t *testing.T) {
-			binary, ok := FirstCommandBinary(tc.command)
+			binary, ok := FirstCommandBinary(types.ServiceCommand{Shell: tc.command})
 			if ok != tc.wantOK || binary != tc.wantBinary {
 				t.Errorf("FirstCommandBinary(%q) = (%q, %v), want (%q, %v)", tc.command, binary, ok, tc.wantBinary, tc.wantOK)
 			}
@@ -47,6 +47,28 @@ func TestFirstCommandBinary(t *testing.T) {
 	}
 }
 
+func TestFirstCommandBinary_ExecForm(t *testing.T) {
+	cases := []struct {
+		argv       []string
+		wantBinary string
+		wantOK     bool
+	}{
+		{[]string{"node", "dist/server.js"}, "node", true},
+		// argv[0] is taken exactly: no builtin, quoting or assignment rules.
+		{[]string{"exit", "0"}, "exit", true},
+		{[]string{"PORT=3000", "node"}, "PORT=3000", true},
+		{[]string{"./start.sh"}, "", false},
+		{[]string{"/usr/bin/node", "server.js"}, "", false},
+	}
+
+	for _, tc := range cases {
+		binary, ok := FirstCommandBinary(types.ServiceCommand{Argv: tc.argv})
+		if ok != tc.wantOK || binary != tc.wantBinary {
+			t.Errorf("FirstCommandBinary(%q) = (%q, %v), want (%q, %v)", tc.argv, binary, ok, tc.wantBinary, tc.wantOK)
+		}
+	}
+}
+
 func TestIsEnvAssignment(t *testing.T) {
 	cases := []struct {
 		tok  string
@@ -198,7 +220,7 @@ func TestValidateCommandBinary(t *testing.T) {
 		}
 		t.Setenv("PATH", binDir)
 
-		config := &types.ServiceConfig{Command: "npm start", Runtime: types.Runtime{Type: "node"}}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Shell: "npm start"}, Runtime: types.Runtime{Type: "node"}}
 		if err := validateCommandBinary(config, serviceDir); err != nil {
 			t.Errorf("expected no error, got: %v", err)
 		}
@@ -209,7 +231,7 @@ func TestValidateCommandBinary(t *testing.T) {
 		t.Setenv("PATH", t.TempDir())
 		t.Setenv("HOME", t.TempDir())
 
-		config := &types.ServiceConfig{Command: "npm start", Runtime: types.Runtime{Type: "node"}}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Shell: "npm start"}, Runtime: types.Runtime{Type: "node"}}
 		err := validateCommandBinary(config, serviceDir)
 		if err == nil {
 			t.Fatal("expected an error")
@@ -223,7 +245,7 @@ func TestValidateCommandBinary(t *testing.T) {
 		serviceDir := t.TempDir()
 		t.Setenv("PATH", t.TempDir())
 
-		config := &types.ServiceConfig{Command: "cd www && npm start", Runtime: types.Runtime{Type: "node"}}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Shell: "cd www && npm start"}, Runtime: types.Runtime{Type: "node"}}
 		if err := validateCommandBinary(config, serviceDir); err != nil {
 			t.Errorf("expected a complex command to be skipped, got: %v", err)
 		}
@@ -231,7 +253,7 @@ func TestValidateCommandBinary(t *testing.T) {
 
 	t.Run("bails silently when buildEnvironment itself would fail", func(t *testing.T) {
 		serviceDir := t.TempDir()
-		config := &types.ServiceConfig{Command: "npm start", EnvFile: "../outside-service-dir"}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Shell: "npm start"}, EnvFile: types.EnvFileList{"../outside-service-dir"}}
 		if err := validateCommandBinary(config, serviceDir); err != nil {
 			t.Errorf("expected a buildEnvironment failure to be left for actual launch, got: %v", err)
 		}
@@ -245,9 +267,54 @@ func TestValidateCommandBinary(t *testing.T) {
 		}
 		t.Setenv("PATH", t.TempDir())
 
-		config := &types.ServiceConfig{Command: "npm start", Runtime: types.Runtime{Type: "node", Path: runtimeDir}}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Shell: "npm start"}, Runtime: types.Runtime{Type: "node", Path: runtimeDir}}
 		if err := validateCommandBinary(config, serviceDir); err != nil {
 			t.Errorf("expected runtime.path to put npm on PATH, got: %v", err)
 		}
 	})
+
+	t.Run("exec form checks argv[0] on PATH", func(t *testing.T) {
+		serviceDir := t.TempDir()
+		t.Setenv("PATH", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Argv: []string{"node", "server.js"}}}
+		if err := validateCommandBinary(config, serviceDir); err == nil || !strings.Contains(err.Error(), "node") {
+			t.Errorf("expected an error naming node, got: %v", err)
+		}
+	})
+
+	t.Run("exec form checks a relative argv[0] against the service directory", func(t *testing.T) {
+		serviceDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(serviceDir, "start.sh"), []byte("#!/bin/sh"), 0755); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		config := &types.ServiceConfig{Command: types.ServiceCommand{Argv: []string{"./start.sh"}}}
+		if err := validateCommandBinary(config, serviceDir); err != nil {
+			t.Errorf("expected ./start.sh to resolve against the service directory, got: %v", err)
+		}
+
+		config.Command.Argv = []string{"./missing.sh"}
+		if err := validateCommandBinary(config, serviceDir); err == nil {
+			t.Error("expected an error for a missing path-qualified argv[0]")
+		}
+	})
+}
+
+func TestLmResolveExecBinary(t *testing.T) {
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "node"), []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	env := []string{"HOME=/nonexistent", "PATH=" + binDir}
+
+	if got := lmResolveExecBinary("node", env); got != filepath.Join(binDir, "node") {
+		t.Errorf("lmResolveExecBinary(node) = %q, want it resolved against the service's PATH", got)
+	}
+	if got := lmResolveExecBinary("./start.sh", env); got != "./start.sh" {
+		t.Errorf("lmResolveExecBinary(./start.sh) = %q, want it unchanged", got)
+	}
+	if got := lmResolveExecBinary("missing", env); got != "missing" {
+		t.Errorf("lmResolveExecBinary(missing) = %q, want it unchanged", got)
+	}
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Elysium-Labs-EU/eos/internal/cronutil"
 	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
 	"gopkg.in/yaml.v3"
 )
 
@@ -53,7 +54,7 @@ func LoadServiceConfig(configFilePath string) (*types.ServiceConfig, error) {
 	if err := ValidateServiceName(config.Name); err != nil {
 		return nil, fmt.Errorf("invalid service name in %s: %w", cleanedConfigFilePath, err)
 	}
-	if config.Command == "" {
+	if config.Command.IsZero() {
 		return nil, fmt.Errorf("service command is required in %s", cleanedConfigFilePath)
 	}
 
@@ -128,15 +129,23 @@ func ValidateServiceConfig(configFilePath string) (*types.ServiceConfig, []error
 		return nil, []error{err}
 	}
 
-	var errs []error
-	errs = append(errs, cfgvValidateServiceFields(config)...)
-	errs = append(errs, cfgvValidateInlineLogSinks(config.LogSinks)...)
-	if len(errs) > 0 {
+	if errs := cfgvValidateParsedConfig(config, filepath.Dir(configFilePath)); len(errs) > 0 {
 		return nil, errs
 	}
 	return config, nil
 }
 
+// cfgvValidateParsedConfig is ValidateServiceConfig's checks on a config
+// already parsed, for a service run from serviceDirectoryPath.
+func cfgvValidateParsedConfig(config *types.ServiceConfig, serviceDirectoryPath string) []error {
+	var errs []error
+	errs = append(errs, cfgvValidateServiceFields(config)...)
+	errs = append(errs, cfgvValidateIdentityFields(config)...)
+	errs = append(errs, cfgvValidateInlineLogSinks(config.LogSinks)...)
+	errs = append(errs, cfgvValidateEnvFiles(config, serviceDirectoryPath)...)
+	return errs
+}
+
 func cfgvLoadAndParseConfig(configFilePath string) (*types.ServiceConfig, error) {
 	cleanedConfigFilePath := filepath.Clean(configFilePath)
 	data, err := os.ReadFile(cleanedConfigFilePath)
@@ -155,8 +164,10 @@ func cfgvValidateServiceFields(config *types.ServiceConfig) []error {
 	if err := ValidateServiceName(config.Name); err != nil {
 		errs = append(errs, err)
 	}
-	if config.Command == "" {
+	if config.Command.IsZero() {
 		errs = append(errs, fmt.Errorf("service command is required"))
+	} else if config.Command.IsExec() && config.Command.Argv[0] == "" {
+		errs = append(errs, fmt.Errorf("command: the first element of an exec-form command must name the program to run"))
 	}
 	if err := ValidateRuntimeBinary(config.Runtime); err != nil {
 		errs = append(errs, fmt.Errorf("runtime: %w", err))
@@ -167,6 +178,76 @@ func cfgvValidateServiceFields(config *types.ServiceConfig) []error {
 	if depErrs := ValidateDependencies(config.Name, config.DependsOn, config.MaxWait); len(depErrs) > 0 {
 		errs = append(errs, depErrs...)
 	}
+	// Planned with no cgroup controllers: which mechanism enforces each limit
+	// is the daemon's call at launch, but the parse is the same either way.
+	if _, err := planLimits(config.Limits, nil); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, envValidate(config)...)
+	errs = append(errs, hookValidate(config.Hooks)...)
+	errs = append(errs, stopValidate(config)...)
+	errs = append(errs, restartValidate(config)...)
+	errs = append(errs, healthValidate(config)...)
+	errs = append(errs, notifyValidate(config)...)
+	errs = append(errs, forkingValidate(config)...)
+	errs = append(errs, socketValidate(config)...)
+	errs = append(errs, proxyValidate(config)...)
+	errs = append(errs, instancesValidate(config)...)
+	errs = append(errs, rolloutValidate(config)...)
+	errs = append(errs, buildValidate(config)...)
+	errs = append(errs, watchValidate(config)...)
+	return errs
+}
+
+// cfgvValidateEnvFiles reports syntax errors, by file and line, in whichever
+// env_file entries exist on disk. A missing file or one outside the service
+// directory isn't an error here: both are reported when the service starts,
+// and a file may legitimately be written after the service is added.
+func cfgvValidateEnvFiles(config *types.ServiceConfig, serviceDirectoryPath string) []error {
+	envFilePaths, err := ResolveEnvFilePaths(config, serviceDirectoryPath)
+	if err != nil {
+		return nil
+	}
+	var errs []error
+	for i, envFilePath := range envFilePaths {
+		// #nosec G304 - envFilePath validated against traversal by ResolveEnvFilePaths above
+		contents, err := os.ReadFile(envFilePath)
+		if err != nil {
+			continue
+		}
+		if _, err := parseDotenv(string(contents), nil); err != nil {
+			errs = append(errs, fmt.Errorf("env_file %s: %w", config.EnvFile[i], err))
+		}
+	}
+	return errs
+}
+
+// cfgvValidateIdentityFields checks that user:, group: and every
+// supplementary_groups entry resolve on this host. Whether the daemon may
+// actually switch to them depends on the identity the daemon runs as, not
+// the process running validation, so that half is checked at launch by
+// resolveLaunchCredential.
+func cfgvValidateIdentityFields(config *types.ServiceConfig) []error {
+	var errs []error
+	if config.User != "" {
+		if _, err := userutil.LookupUser(config.User); err != nil {
+			errs = append(errs, fmt.Errorf("user: %w", err))
+		}
+	}
+	if config.Group != "" {
+		if _, err := userutil.LookupGroupID(config.Group); err != nil {
+			errs = append(errs, fmt.Errorf("group: %w", err))
+		}
+	}
+	for i, name := range config.SupplementaryGroups {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, fmt.Errorf("supplementary_groups[%d]: empty group name", i))
+			continue
+		}
+		if _, err := userutil.LookupGroupID(name); err != nil {
+			errs = append(errs, fmt.Errorf("supplementary_groups[%d]: %w", i, err))
+		}
+	}
 	return errs
 }
 
@@ -240,16 +321,28 @@ var commandSeparators = regexp.MustCompile(`&&|\|\||[;|]`)
 // segment that detaches escapes the group and eos loses the ability to
 // stop/kill it. This is a string heuristic on the configured command, not a
 // runtime check — it won't catch a program that daemonizes internally.
-func DetectSelfDetachRisk(command string) []string {
+//
+// A daemon with cgroup v2 tracking (see groupTracker) does keep hold of a
+// detached segment, since setsid can't leave a cgroup. The warning still
+// fires: validation runs without knowing which daemon, on which host, will
+// end up launching the command.
+//
+// An exec-form command has a single segment, argv itself, so only argv[0]
+// is checked.
+func DetectSelfDetachRisk(command types.ServiceCommand) []string {
+	segments := commandSeparators.Split(command.Shell, -1)
+	if command.IsExec() {
+		segments = []string{command.String()}
+	}
 	var warnings []string
-	for _, segment := range commandSeparators.Split(command, -1) {
+	for _, segment := range segments {
 		fields := strings.Fields(segment)
 		if len(fields) == 0 {
 			continue
 		}
 		if selfDetachCommands[fields[0]] {
 			warnings = append(warnings, fmt.Sprintf(
-				"command segment %q starts with %q, which detaches from eos's process group; eos will not be able to stop or kill it via the normal service commands",
+				"command segment %q starts with %q, which detaches from eos's process group; eos will not be able to stop or kill it via the normal service commands unless its daemon tracks services by cgroup v2",
 				strings.TrimSpace(segment), fields[0],
 			))
 		}
@@ -35,7 +35,7 @@ runtime:
 	if config.Name != "cms" {
 		t.Errorf("Expected name 'cms', got %s", config.Name)
 	}
-	if config.Command != "/home/user/start-script.sh" {
+	if config.Command.Shell != "/home/user/start-script.sh" {
 		t.Errorf("Expected command '/home/user/start-script.sh' got '%s'", config.Command)
 	}
 	if config.Port != 1337 {
@@ -78,8 +78,8 @@ log_sinks:
 	if config.Port != 3000 {
 		t.Errorf("Expected port '3000' got '%d'", config.Port)
 	}
-	if config.EnvFile != ".env.production" {
-		t.Errorf("Expected env_file '.env.production', got %q", config.EnvFile)
+	if len(config.EnvFile) != 1 || config.EnvFile[0] != ".env.production" {
+		t.Errorf("Expected env_file '.env.production', got %v", config.EnvFile)
 	}
 	if config.MemoryLimitMb != 512 {
 		t.Errorf("Expected memory_limit_mb 512, got %d", config.MemoryLimitMb)
@@ -358,7 +358,7 @@ func TestValidateServiceConfig_badRuntimeAndLogSink(t *testing.T) {
 	configFile := filepath.Join(tempDir, "service.yaml")
 	config := &types.ServiceConfig{
 		Name:    "svc",
-		Command: "./start.sh",
+		Command: types.ServiceCommand{Shell: "./start.sh"},
 		Runtime: types.Runtime{Type: "node"},
 		LogSinks: []types.LogSinkRef{
 			{Inline: &types.LogSink{}},
@@ -393,12 +393,96 @@ func TestValidateServiceConfig_badRuntimeAndLogSink(t *testing.T) {
 	}
 }
 
+func TestValidateServiceConfig_unknownUserAndGroups(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "service.yaml")
+	const fixture = `
+name: svc
+command: ./start.sh
+user: eos-no-such-user-xyz
+group: eos-no-such-group-xyz
+supplementary_groups: ["", eos-no-such-group-xyz]
+`
+	if err := os.WriteFile(configFile, []byte(fixture), 0644); err != nil {
+		t.Fatalf("writing test config file should not error: %v", err)
+	}
+
+	_, errs := ValidateServiceConfig(configFile)
+	for _, want := range []string{"user:", "group:", "supplementary_groups[0]: empty group name", "supplementary_groups[1]:"} {
+		found := false
+		for _, e := range errs {
+			if strings.Contains(e.Error(), want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an error containing %q, got: %v", want, errs)
+		}
+	}
+}
+
+func TestValidateServiceConfig_invalidLimits(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "service.yaml")
+	const fixture = `
+name: svc
+command: ./start.sh
+limits:
+  memory_max: 512X
+`
+	if err := os.WriteFile(configFile, []byte(fixture), 0644); err != nil {
+		t.Fatalf("writing test config file should not error: %v", err)
+	}
+
+	_, errs := ValidateServiceConfig(configFile)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "limits.memory_max") {
+		t.Errorf("expected a single limits.memory_max error, got: %v", errs)
+	}
+}
+
+func TestValidateServiceConfig_execFormCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "service.yaml")
+	if err := os.WriteFile(configFile, []byte("name: svc\ncommand: [\"node\", \"server.js\"]\n"), 0644); err != nil {
+		t.Fatalf("writing test config file should not error: %v", err)
+	}
+	if _, errs := ValidateServiceConfig(configFile); len(errs) != 0 {
+		t.Errorf("expected an exec-form command to validate, got: %v", errs)
+	}
+
+	if err := os.WriteFile(configFile, []byte("name: svc\ncommand: [\"\", \"server.js\"]\n"), 0644); err != nil {
+		t.Fatalf("writing test config file should not error: %v", err)
+	}
+	if _, errs := ValidateServiceConfig(configFile); len(errs) != 1 {
+		t.Errorf("expected a single error for an empty argv[0], got: %v", errs)
+	}
+}
+
+func TestValidateServiceConfig_envFileSyntaxError(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "service.yaml")
+	if err := os.WriteFile(configFile, []byte("name: svc\ncommand: ./start.sh\nenv_file: [.env, .env.local, missing.env]\n"), 0644); err != nil {
+		t.Fatalf("writing test config file should not error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".env"), []byte("FOO=bar\n"), 0644); err != nil {
+		t.Fatalf("writing .env should not error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".env.local"), []byte("OK=1\nKEY=\"unterminated\n"), 0644); err != nil {
+		t.Fatalf("writing .env.local should not error: %v", err)
+	}
+
+	_, errs := ValidateServiceConfig(configFile)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "env_file .env.local: line 2:") {
+		t.Errorf("expected one error naming .env.local line 2 (missing.env is not checked), got: %v", errs)
+	}
+}
+
 func TestValidateServiceConfig_valid(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "service.yaml")
 	config := &types.ServiceConfig{
 		Name:    "svc",
-		Command: "./start.sh",
+		Command: types.ServiceCommand{Shell: "./start.sh"},
 	}
 	yamlData, err := yaml.Marshal(config)
 	if err != nil {
@@ -440,7 +524,7 @@ func TestValidateServiceConfig_invalidCronRestart(t *testing.T) {
 	configFile := filepath.Join(tempDir, "service.yaml")
 	config := &types.ServiceConfig{
 		Name:        "svc",
-		Command:     "./start.sh",
+		Command:     types.ServiceCommand{Shell: "./start.sh"},
 		CronRestart: "not a cron expression",
 	}
 	yamlData, err := yaml.Marshal(config)
@@ -469,7 +553,7 @@ func TestValidateServiceConfig_invalidCronRestart(t *testing.T) {
 func TestLoadServiceConfigWithCronRestart(t *testing.T) {
 	expectedConfig := &types.ServiceConfig{
 		Name:        "website",
-		Command:     "pnpm start",
+		Command:     types.ServiceCommand{Shell: "pnpm start"},
 		CronRestart: "0 3 * * *",
 	}
 	yamlData, err := yaml.Marshal(expectedConfig)
@@ -581,10 +665,19 @@ func TestDetectSelfDetachRisk(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			warnings := DetectSelfDetachRisk(tt.command)
+			warnings := DetectSelfDetachRisk(types.ServiceCommand{Shell: tt.command})
 			if len(warnings) != tt.wantWarnings {
 				t.Errorf("DetectSelfDetachRisk(%q) = %v, want %d warning(s)", tt.command, warnings, tt.wantWarnings)
 			}
 		})
 	}
+
+	// An exec-form command is one segment: separators in its arguments are
+	// literal, never a second command.
+	if warnings := DetectSelfDetachRisk(types.ServiceCommand{Argv: []string{"setsid", "node", "server.js"}}); len(warnings) != 1 {
+		t.Errorf("exec form led by setsid: got %v, want 1 warning", warnings)
+	}
+	if warnings := DetectSelfDetachRisk(types.ServiceCommand{Argv: []string{"echo", "&&", "nohup"}}); len(warnings) != 0 {
+		t.Errorf("exec form with separator-like arguments: got %v, want none", warnings)
+	}
 }
@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// configHash is the sha256 of config's JSON form: the parsed config rather
+// than the file's bytes, so a comment or formatting edit to service.yaml
+// doesn't count as a change.
+func configHash(config *types.ServiceConfig) (string, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("encoding config: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// envFileHash is the sha256 over the contents of config's env files, in
+// order. A missing file hashes as absent rather than failing, as a launch
+// with one missing has already failed or been told about it.
+func envFileHash(config *types.ServiceConfig, serviceDirectoryPath string) (string, error) {
+	paths, err := ResolveEnvFilePaths(config, serviceDirectoryPath)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00", path)
+		data, err := os.ReadFile(path) //nolint:gosec // path is confined to the service directory by ResolveEnvFilePaths
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			h.Write([]byte("absent\x00"))
+		case err != nil:
+			return "", fmt.Errorf("reading env file: %w", err)
+		default:
+			fmt.Fprintf(h, "%d\x00", len(data))
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// recordLaunchConfig snapshots the config pgid was launched with, for
+// GetConfigDrift. A failure is logged rather than failing a launch that is
+// already running.
+func (m *LocalManager) recordLaunchConfig(service *types.ServiceCatalogEntry, config *types.ServiceConfig, pgid int) {
+	launch := types.LaunchConfig{
+		PGID:        pgid,
+		ServiceName: service.Name,
+		Config:      *config,
+		RecordedAt:  time.Now(),
+	}
+	var err error
+	if launch.ConfigHash, err = configHash(config); err == nil {
+		launch.EnvFileHash, err = envFileHash(config, service.DirectoryPath)
+	}
+	if err == nil {
+		err = m.db.RecordLaunchConfig(m.ctx, launch)
+	}
+	if err != nil {
+		m.logger.Warn("recording launch config, eos status can't tell if it changes", "service", service.Name, "pgid", pgid, "error", err)
+	}
+}
+
+// GetConfigDrift compares name's running launch's config with its config on
+// disk. It returns nil when nothing is running or the launch predates
+// config snapshots.
+func (m *LocalManager) GetConfigDrift(ctx context.Context, name string) (*types.ConfigDrift, error) {
+	history, err := m.db.GetProcessHistoryEntriesByServiceName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get process history for %s: %w", name, err)
+	}
+	pgid := livePGIDInHistory(history, m.tracker)
+	if pgid == 0 {
+		return nil, nil
+	}
+	return m.ConfigDriftForLaunch(ctx, name, pgid)
+}
+
+// ConfigDriftForLaunch compares the config pgid was launched with against
+// name's config on disk, or returns nil when none was recorded for it.
+func (m *LocalManager) ConfigDriftForLaunch(ctx context.Context, name string, pgid int) (*types.ConfigDrift, error) {
+	launch, found, err := m.db.GetLaunchConfig(ctx, pgid)
+	if err != nil || !found {
+		return nil, err
+	}
+	service, err := m.GetServiceCatalogEntry(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	current, err := LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err != nil {
+		return nil, fmt.Errorf("load service config for %s: %w", name, err)
+	}
+
+	drift := &types.ConfigDrift{ServiceName: name, PGID: pgid}
+	hash, err := configHash(current)
+	if err != nil {
+		return nil, err
+	}
+	if hash != launch.ConfigHash {
+		if drift.Changes, err = DiffServiceConfig(&launch.Config, current); err != nil {
+			return nil, err
+		}
+	}
+	envHash, err := envFileHash(current, service.DirectoryPath)
+	if err != nil {
+		return nil, err
+	}
+	drift.EnvChanged = envHash != launch.EnvFileHash
+	return drift, nil
+}
+
+// DiffServiceConfig lists the fields that differ between launched and
+// current by their dotted service.yaml path, such as health_check.interval or
+// env.PORT. Lists are compared whole. Reordering env: alone, which the hash
+// sees, is not reported as a field change.
+func DiffServiceConfig(launched, current *types.ServiceConfig) ([]types.ConfigChange, error) {
+	oldFields, err := configFields(launched)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := configFields(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []types.ConfigChange
+	for field, value := range oldFields {
+		if newValue, ok := newFields[field]; !ok || newValue != value {
+			changes = append(changes, types.ConfigChange{Field: field, Old: value, New: newValue})
+		}
+	}
+	for field, value := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			changes = append(changes, types.ConfigChange{Field: field, New: value})
+		}
+	}
+	slices.SortFunc(changes, func(a, b types.ConfigChange) int { return strings.Compare(a.Field, b.Field) })
+	return changes, nil
+}
+
+// configFields flattens config's JSON form into its leaf fields, keyed by
+// dotted path, each rendered as a string: unquoted for a string, compact
+// JSON for anything else.
+func configFields(config *types.ServiceConfig) (map[string]string, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(encoded, &tree); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	fields := make(map[string]string)
+	if err := flattenConfigFields(fields, "", tree); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenConfigFields(fields map[string]string, prefix string, value any) error {
+	if object, ok := value.(map[string]any); ok {
+		for key, child := range object {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if err := flattenConfigFields(fields, path, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if s, ok := value.(string); ok {
+		fields[prefix] = s
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("encoding %s: %w", prefix, err)
+	}
+	fields[prefix] = strings.TrimSpace(buf.String())
+	return nil
+}
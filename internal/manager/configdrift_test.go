@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// TestGetConfigDriftAfterEdits starts a service, then edits its service.yaml
+// and env file: each edit shows up as drift from the running launch, while a
+// comment alone does not.
+func TestGetConfigDriftAfterEdits(t *testing.T) {
+	m, dir := deployTestService(t, "drift-app")
+	yamlPath := filepath.Join(dir, "service.yaml")
+	base := "name: drift-app\ncommand: sleep 300\nenv_file: .env\n"
+	if err := os.WriteFile(yamlPath, []byte(base), 0644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("MODE=one\n"), 0644); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	pgid, err := m.StartService(t.Context(), "drift-app")
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { killGroup(pgid) })
+
+	drift, err := m.GetConfigDrift(t.Context(), "drift-app")
+	if err != nil || drift == nil || drift.Pending() {
+		t.Fatalf("GetConfigDrift = %+v, %v; want an unchanged launch", drift, err)
+	}
+
+	if err := os.WriteFile(yamlPath, []byte("# tuned\n"+base), 0644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	if drift, _ = m.GetConfigDrift(t.Context(), "drift-app"); drift == nil || drift.Pending() {
+		t.Errorf("GetConfigDrift after a comment = %+v, want no change", drift)
+	}
+
+	if err := os.WriteFile(yamlPath, []byte(base+"port: 8080\nenv:\n  LEVEL: debug\n"), 0644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("MODE=two\n"), 0644); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	drift, err = m.GetConfigDrift(t.Context(), "drift-app")
+	if err != nil || drift == nil {
+		t.Fatalf("GetConfigDrift = %+v, %v", drift, err)
+	}
+	want := []types.ConfigChange{{Field: "env.LEVEL", New: "debug"}, {Field: "port", New: "8080"}}
+	if len(drift.Changes) != len(want) || drift.Changes[0] != want[0] || drift.Changes[1] != want[1] {
+		t.Errorf("Changes = %+v, want %+v", drift.Changes, want)
+	}
+	if !drift.EnvChanged || drift.PGID != pgid {
+		t.Errorf("drift = %+v, want the env file change seen on pgid %d", drift, pgid)
+	}
+}
+
+func TestDiffServiceConfigNestedAndRemoved(t *testing.T) {
+	launched := &types.ServiceConfig{
+		Name: "web", Command: types.ServiceCommand{Shell: "./web"}, MemoryLimitMb: 128,
+		HealthCheck: &types.ServiceHealthCheck{Interval: "10s"},
+	}
+	current := &types.ServiceConfig{
+		Name: "web", Command: types.ServiceCommand{Shell: "./web --fast"},
+		HealthCheck: &types.ServiceHealthCheck{Interval: "30s"},
+	}
+	changes, err := DiffServiceConfig(launched, current)
+	if err != nil {
+		t.Fatalf("DiffServiceConfig: %v", err)
+	}
+	want := []types.ConfigChange{
+		{Field: "command", Old: "./web", New: "./web --fast"},
+		{Field: "health_check.interval", Old: "10s", New: "30s"},
+		{Field: "memory_limit_mb", Old: "128"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
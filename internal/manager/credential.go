@@ -0,0 +1,131 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Elysium-Labs-EU/eos/internal/ownership"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
+)
+
+// launchCredential is the identity a service process drops to at launch,
+// resolved from service.yaml's user:/group:/supplementary_groups:. With only
+// group: set, uid stays the daemon's own and groups is empty.
+type launchCredential struct {
+	groups []uint32
+	uid    uint32
+	gid    uint32
+}
+
+// serviceCredential is resolveLaunchCredential against the identity
+// WithIdentity gave m. A manager given none refuses any user:/group:/
+// supplementary_groups: rather than guess whether it may switch.
+func (m *LocalManager) serviceCredential(config *types.ServiceConfig) (*launchCredential, error) {
+	if m.identity == nil {
+		if credRequested(config) {
+			return nil, errors.New("user:/group:/supplementary_groups: set but this manager doesn't know the identity it runs as")
+		}
+		return nil, nil
+	}
+	return resolveLaunchCredential(config, m.identity.ProcessUID(), m.identity.ProcessGID())
+}
+
+// credRequested reports whether config asks for any identity of its own.
+func credRequested(config *types.ServiceConfig) bool {
+	return config.User != "" || config.Group != "" || len(config.SupplementaryGroups) > 0
+}
+
+// resolveLaunchCredential resolves config's user:/group:/supplementary_groups:
+// against the daemon's own identity (daemonUID/daemonGID, the process's
+// effective ids from userutil.ResolveIdentity). A user: without
+// supplementary_groups: gets that user's own groups, as a login would
+// (initgroups(3)); an explicit list replaces them.
+//
+// It returns nil when none of them are set, or when they resolve to exactly
+// the identity the daemon already runs as, user:'s own groups included —
+// there is nothing to switch, and handing os/exec a Credential anyway would
+// make a non-root daemon fail its own setgroups(2) call.
+//
+// A non-root daemon asked for any other identity is refused here rather than
+// at cmd.Start: setuid/setgid/setgroups all need privileges only root has,
+// and the kernel's bare "operation not permitted" from the forked child says
+// nothing about which config field caused it.
+func resolveLaunchCredential(config *types.ServiceConfig, daemonUID, daemonGID uint32) (*launchCredential, error) {
+	if !credRequested(config) {
+		return nil, nil
+	}
+
+	cred := launchCredential{uid: daemonUID, gid: daemonGID}
+	if config.User != "" {
+		u, err := userutil.LookupUser(config.User)
+		if err != nil {
+			return nil, fmt.Errorf("user: %w", err)
+		}
+		uid, gid, err := userutil.UserCredentials(u)
+		if err != nil {
+			return nil, fmt.Errorf("user: %w", err)
+		}
+		cred.uid, cred.gid = uid, gid
+		if len(config.SupplementaryGroups) == 0 {
+			if cred.groups, err = userutil.UserGroupIDs(u); err != nil {
+				return nil, fmt.Errorf("user: %w", err)
+			}
+		}
+	}
+	if config.Group != "" {
+		gid, err := userutil.LookupGroupID(config.Group)
+		if err != nil {
+			return nil, fmt.Errorf("group: %w", err)
+		}
+		cred.gid = gid
+	}
+	for i, name := range config.SupplementaryGroups {
+		gid, err := userutil.LookupGroupID(name)
+		if err != nil {
+			return nil, fmt.Errorf("supplementary_groups[%d]: %w", i, err)
+		}
+		cred.groups = append(cred.groups, gid)
+	}
+
+	if daemonUID == 0 {
+		return &cred, nil
+	}
+	if cred.uid == daemonUID && cred.gid == daemonGID && len(config.SupplementaryGroups) == 0 {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(
+		"daemon runs as uid %d and cannot switch to uid %d gid %d%s; run the daemon as root (sudo eos system startup) or remove user:/group:/supplementary_groups: from service.yaml",
+		daemonUID, cred.uid, cred.gid, credDescribeGroups(cred.groups))
+}
+
+// credDescribeGroups renders a non-empty supplementary group list as a
+// suffix for resolveLaunchCredential's refusal message.
+func credDescribeGroups(groups []uint32) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(groups))
+	for _, gid := range groups {
+		ids = append(ids, fmt.Sprint(gid))
+	}
+	return " with supplementary groups " + strings.Join(ids, ",")
+}
+
+// credChownServiceLogs hands the service's stdout/stderr log files to the
+// identity it now runs as. The daemon itself still does all the writing
+// through the pipes, so this isn't needed for the service to run — it keeps
+// the log readable (and removable) by the same unprivileged account that
+// owns the service, instead of stranding it as root-only after the drop.
+func credChownServiceLogs(lio launchIO, cred *launchCredential) error {
+	for _, w := range []*RotatingFileWriter{lio.logFile, lio.errorLogFile} {
+		if w == nil {
+			continue
+		}
+		if err := ownership.ChownTolerant(w.LogPath, int(cred.uid), int(cred.gid)); err != nil {
+			return fmt.Errorf("handing log file to service user: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package manager
+
+import (
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// nobodyUser returns the host's "nobody" account, the one non-root user every
+// Linux and macOS box is expected to have. Resolving against a real account
+// (rather than the user running the tests) keeps these cases independent of
+// whether the suite itself runs as root.
+func nobodyUser(t *testing.T) (u *user.User, uid, gid uint32) {
+	t.Helper()
+	u, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("no nobody user on this host")
+	}
+	uid64, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		t.Skipf("nobody has a non-numeric uid %q", u.Uid)
+	}
+	gid64, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		t.Skipf("nobody has a non-numeric gid %q", u.Gid)
+	}
+	return u, uint32(uid64), uint32(gid64)
+}
+
+func TestResolveLaunchCredential_unset(t *testing.T) {
+	cred, err := resolveLaunchCredential(&types.ServiceConfig{Name: "svc", Command: types.ServiceCommand{Shell: "./start.sh"}}, 1000, 1000)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cred != nil {
+		t.Errorf("expected nil credential when user/group are unset, got %+v", cred)
+	}
+}
+
+func TestServiceCredential_withoutIdentityRefusesASwitch(t *testing.T) {
+	m := &LocalManager{}
+
+	if cred, err := m.serviceCredential(&types.ServiceConfig{Name: "svc"}); err != nil || cred != nil {
+		t.Errorf("expected no credential and no error without user:/group:, got %+v, %v", cred, err)
+	}
+	if _, err := m.serviceCredential(&types.ServiceConfig{User: "nobody"}); err == nil {
+		t.Error("expected user: to be refused by a manager given no identity")
+	}
+}
+
+func TestResolveLaunchCredential_rootDaemonSwitches(t *testing.T) {
+	_, uid, gid := nobodyUser(t)
+
+	cred, err := resolveLaunchCredential(&types.ServiceConfig{User: "nobody"}, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cred == nil {
+		t.Fatal("expected a credential for a root daemon switching to nobody")
+	}
+	if cred.uid != uid || cred.gid != gid {
+		t.Errorf("expected uid %d gid %d (nobody's primary group), got uid %d gid %d", uid, gid, cred.uid, cred.gid)
+	}
+	// Without supplementary_groups: the user's own groups, as initgroups(3)
+	// would set them, never the daemon's.
+	if !slices.Contains(cred.groups, gid) {
+		t.Errorf("expected nobody's own groups, primary gid %d included, got %v", gid, cred.groups)
+	}
+}
+
+func TestResolveLaunchCredential_numericUser(t *testing.T) {
+	u, uid, _ := nobodyUser(t)
+
+	cred, err := resolveLaunchCredential(&types.ServiceConfig{User: u.Uid}, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cred == nil || cred.uid != uid {
+		t.Errorf("expected uid %d for numeric user %q, got %+v", uid, u.Uid, cred)
+	}
+}
+
+func TestResolveLaunchCredential_groupOverridesPrimaryGroup(t *testing.T) {
+	_, _, nobodyGID := nobodyUser(t)
+	if nobodyGID == 0 {
+		t.Skip("nobody's primary group is gid 0 on this host")
+	}
+
+	// gid 0 is the one group every host is guaranteed to have an entry for.
+	cred, err := resolveLaunchCredential(&types.ServiceConfig{User: "nobody", Group: "0", SupplementaryGroups: []string{"0"}}, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if cred == nil || cred.gid != 0 {
+		t.Fatalf("expected group: to override nobody's primary gid %d with 0, got %+v", nobodyGID, cred)
+	}
+	if len(cred.groups) != 1 || cred.groups[0] != 0 {
+		t.Errorf("expected supplementary groups [0], got %v", cred.groups)
+	}
+}
+
+func TestResolveLaunchCredential_nonRootSameIdentityIsNoop(t *testing.T) {
+	_, uid, gid := nobodyUser(t)
+
+	cred, err := resolveLaunchCredential(&types.ServiceConfig{User: "nobody"}, uid, gid)
+	if err != nil {
+		t.Fatalf("expected no error when the daemon already runs as the requested user, got: %v", err)
+	}
+	if cred != nil {
+		t.Errorf("expected nil credential (nothing to switch), got %+v", cred)
+	}
+}
+
+func TestResolveLaunchCredential_nonRootRefusesOtherUser(t *testing.T) {
+	_, uid, gid := nobodyUser(t)
+
+	_, err := resolveLaunchCredential(&types.ServiceConfig{User: "nobody"}, uid+1, gid)
+	if err == nil {
+		t.Fatal("expected a non-root daemon to refuse switching to another user")
+	}
+	if !strings.Contains(err.Error(), "cannot switch") {
+		t.Errorf("expected a 'cannot switch' error, got: %v", err)
+	}
+}
+
+func TestResolveLaunchCredential_nonRootRefusesSupplementaryGroups(t *testing.T) {
+	_, uid, gid := nobodyUser(t)
+
+	_, err := resolveLaunchCredential(&types.ServiceConfig{SupplementaryGroups: []string{"0"}}, uid, gid)
+	if err == nil {
+		t.Fatal("expected a non-root daemon to refuse setting supplementary groups")
+	}
+	if !strings.Contains(err.Error(), "supplementary groups 0") {
+		t.Errorf("expected the refused groups in the error, got: %v", err)
+	}
+}
+
+func TestResolveLaunchCredential_unknownNames(t *testing.T) {
+	cases := []struct {
+		config  types.ServiceConfig
+		wantErr string
+	}{
+		{types.ServiceConfig{User: "eos-no-such-user-xyz"}, "user:"},
+		{types.ServiceConfig{Group: "eos-no-such-group-xyz"}, "group:"},
+		{types.ServiceConfig{SupplementaryGroups: []string{"eos-no-such-group-xyz"}}, "supplementary_groups[0]:"},
+	}
+	for _, tc := range cases {
+		_, err := resolveLaunchCredential(&tc.config, 0, 0)
+		if err == nil {
+			t.Errorf("%+v: expected an error, got nil", tc.config)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%+v: expected error containing %q, got: %v", tc.config, tc.wantErr, err)
+		}
+	}
+}
+
+func TestLmApplyUserEnv(t *testing.T) {
+	u, _, _ := nobodyUser(t)
+
+	env := lmApplyUserEnv("nobody", []string{"HOME=/root", "USER=root", "PATH=/usr/bin"})
+	for _, want := range []string{"HOME=" + u.HomeDir, "USER=nobody", "LOGNAME=nobody", "PATH=/usr/bin"} {
+		found := false
+		for _, e := range env {
+			if e == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %q in env, got %v", want, env)
+		}
+	}
+
+	unchanged := lmApplyUserEnv("", []string{"HOME=/root"})
+	if len(unchanged) != 1 || unchanged[0] != "HOME=/root" {
+		t.Errorf("expected env unchanged with no user set, got %v", unchanged)
+	}
+}
@@ -263,10 +263,12 @@ func (dm *DaemonManager) sendRequest(ctx context.Context, method types.MethodNam
 		return types.DaemonResponse{}, fmt.Errorf("reading response: %w", err)
 	}
 	if !response.Success {
+		// The response rides along with the error: a failed call may still
+		// carry Data describing how far it got (see ReloadService).
 		if sentinel := ErrorFromCode(response.ErrorCode); sentinel != nil {
-			return types.DaemonResponse{}, sentinel
+			return response, sentinel
 		}
-		return types.DaemonResponse{}, fmt.Errorf("daemon error: %s", response.Error)
+		return response, fmt.Errorf("daemon error: %s", response.Error)
 	}
 
 	return response, nil
@@ -389,27 +391,129 @@ func (dm *DaemonManager) RestartService(ctx context.Context, name string, graceP
 // processes live and the readiness probe can reach them; this call just carries
 // the timing knobs over and returns the swapped process groups.
 func (dm *DaemonManager) ReloadService(ctx context.Context, name string, cfg ReloadConfig) (ReloadResult, error) {
-	args, err := json.Marshal(types.ReloadServiceArgs{
+	args, err := json.Marshal(reloadArgs(name, cfg))
+	if err != nil {
+		return ReloadResult{}, fmt.Errorf("ReloadService: marshaling args: %w", err)
+	}
+	response, err := dm.sendRequest(ctx, types.MethodReloadService, args)
+	if err != nil {
+		// A rolling reload that failed and rolled back still reports what it
+		// did to each instance, and a failed canary the metrics it failed on.
+		var partial types.ReloadServiceResponse
+		if len(response.Data) == 0 || json.Unmarshal(response.Data, &partial) != nil {
+			return ReloadResult{}, fmt.Errorf("ReloadService: request errored: %w", err)
+		}
+		return reloadResultFrom(partial), fmt.Errorf("ReloadService: request errored: %w", err)
+	}
+
+	var result types.ReloadServiceResponse
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return ReloadResult{}, fmt.Errorf("ReloadService: parse response data: %w", err)
+	}
+
+	return reloadResultFrom(result), nil
+}
+
+func reloadResultFrom(response types.ReloadServiceResponse) ReloadResult {
+	return ReloadResult{
+		Instances:  response.Instances,
+		OldPGID:    response.OldPGID,
+		NewPGID:    response.NewPGID,
+		Steps:      response.Steps,
+		FailedStep: response.FailedStep,
+		Canary:     response.Canary,
+	}
+}
+
+// reloadArgs is the wire form of a reload of name with cfg, shared by
+// reload, deploy and rollback.
+func reloadArgs(name string, cfg ReloadConfig) types.ReloadServiceArgs {
+	return types.ReloadServiceArgs{
 		Name:             name,
 		GracePeriod:      cfg.GracePeriod.String(),
 		TickerPeriod:     cfg.TickerPeriod.String(),
 		ReadinessTimeout: cfg.ReadinessTimeout.String(),
 		ProbeInterval:    cfg.ProbeInterval.String(),
+		Canary:           canaryArg(cfg.Canary),
+	}
+}
+
+// DeployService asks the daemon to deploy artifact as a new release of name
+// (see LocalManager.DeployService). artifact must be a path the daemon can
+// read.
+func (dm *DaemonManager) DeployService(ctx context.Context, name, artifact string, cfg DeployConfig) (DeployResult, error) {
+	args, err := json.Marshal(types.DeployServiceArgs{
+		ReloadServiceArgs: reloadArgs(name, cfg.ReloadConfig),
+		Artifact:          artifact,
+		Keep:              cfg.Keep,
 	})
 	if err != nil {
-		return ReloadResult{}, fmt.Errorf("ReloadService: marshaling args: %w", err)
+		return DeployResult{}, fmt.Errorf("DeployService: marshaling args: %w", err)
 	}
-	response, err := dm.sendRequest(ctx, types.MethodReloadService, args)
+	return dm.sendDeployRequest(ctx, "DeployService", types.MethodDeployService, args)
+}
+
+// RollbackService asks the daemon to switch name back to release, or to the
+// one before its current release when "" (see LocalManager.RollbackService).
+func (dm *DaemonManager) RollbackService(ctx context.Context, name, release string, cfg ReloadConfig) (DeployResult, error) {
+	args, err := json.Marshal(types.RollbackServiceArgs{
+		ReloadServiceArgs: reloadArgs(name, cfg),
+		Release:           release,
+	})
 	if err != nil {
-		return ReloadResult{}, fmt.Errorf("ReloadService: request errored: %w", err)
+		return DeployResult{}, fmt.Errorf("RollbackService: marshaling args: %w", err)
 	}
+	return dm.sendDeployRequest(ctx, "RollbackService", types.MethodRollbackService, args)
+}
 
-	var result types.ReloadServiceResponse
-	if err := json.Unmarshal(response.Data, &result); err != nil {
-		return ReloadResult{}, fmt.Errorf("ReloadService: parse response data: %w", err)
+// sendDeployRequest sends a deploy or rollback and parses its response,
+// which a failed one carries too once it got as far as a release.
+func (dm *DaemonManager) sendDeployRequest(ctx context.Context, label string, method types.MethodName, args []byte) (DeployResult, error) {
+	response, err := dm.sendRequest(ctx, method, args)
+	var parsed types.DeployServiceResponse
+	if err != nil {
+		if len(response.Data) == 0 || json.Unmarshal(response.Data, &parsed) != nil {
+			return DeployResult{}, fmt.Errorf("%s: request errored: %w", label, err)
+		}
+		return deployResultFrom(parsed), fmt.Errorf("%s: request errored: %w", label, err)
+	}
+	if err := json.Unmarshal(response.Data, &parsed); err != nil {
+		return DeployResult{}, fmt.Errorf("%s: parse response data: %w", label, err)
 	}
+	return deployResultFrom(parsed), nil
+}
 
-	return ReloadResult{OldPGID: result.OldPGID, NewPGID: result.NewPGID}, nil
+func deployResultFrom(response types.DeployServiceResponse) DeployResult {
+	return DeployResult{
+		Release:    response.Release,
+		Previous:   response.Previous,
+		Activation: response.Activation,
+		Reload:     reloadResultFrom(response.Reload),
+		Pruned:     response.Pruned,
+	}
+}
+
+// GetReleases asks the daemon for name's recorded releases, newest first.
+func (dm *DaemonManager) GetReleases(ctx context.Context, name string) ([]types.Release, error) {
+	args, _ := json.Marshal(types.GetReleasesArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetReleases, args)
+	if err != nil {
+		return nil, fmt.Errorf("GetReleases: request errored: %w", err)
+	}
+
+	var releases []types.Release
+	if err := json.Unmarshal(response.Data, &releases); err != nil {
+		return nil, fmt.Errorf("GetReleases: parse response data: %w", err)
+	}
+	return releases, nil
+}
+
+// canaryArg is ReloadServiceArgs.Canary for window: empty for no canary.
+func canaryArg(window time.Duration) string {
+	if window <= 0 {
+		return ""
+	}
+	return window.String()
 }
 
 func (dm *DaemonManager) StopService(ctx context.Context, name string, gracePeriod time.Duration, tickerPeriod time.Duration) (StopServiceResult, error) {
@@ -436,6 +540,76 @@ func (dm *DaemonManager) StopService(ctx context.Context, name string, gracePeri
 	return result, nil
 }
 
+// StartServiceInstance asks the daemon to start instance index of name on
+// its own (see LocalManager.StartServiceInstance).
+func (dm *DaemonManager) StartServiceInstance(ctx context.Context, name string, index int) (int, error) {
+	args, err := json.Marshal(types.StartServiceInstanceArgs{Name: name, Instance: index})
+	if err != nil {
+		return 0, fmt.Errorf("StartServiceInstance: marshaling args: %w", err)
+	}
+	response, err := dm.sendRequest(ctx, types.MethodStartServiceInstance, args)
+	if err != nil {
+		return 0, fmt.Errorf("StartServiceInstance: request errored: %w", err)
+	}
+
+	var result map[string]int
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return 0, fmt.Errorf("StartServiceInstance: parse response data: %w", err)
+	}
+
+	return result["pid"], nil
+}
+
+// StopServiceInstance asks the daemon to stop instance index of name,
+// leaving its other instances running (see LocalManager.StopServiceInstance).
+func (dm *DaemonManager) StopServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (StopServiceResult, error) {
+	args, err := json.Marshal(types.StopServiceInstanceArgs{
+		Name:         name,
+		GracePeriod:  gracePeriod.String(),
+		TickerPeriod: tickerPeriod.String(),
+		Instance:     index,
+	})
+	if err != nil {
+		return StopServiceResult{}, fmt.Errorf("StopServiceInstance: marshaling args: %w", err)
+	}
+	response, err := dm.sendRequest(ctx, types.MethodStopServiceInstance, args)
+	if err != nil {
+		return StopServiceResult{}, fmt.Errorf("StopServiceInstance: request errored: %w", err)
+	}
+
+	var result StopServiceResult
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return StopServiceResult{}, fmt.Errorf("StopServiceInstance: parse response data: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestartServiceInstance asks the daemon to replace instance index of name
+// with a fresh launch (see LocalManager.RestartServiceInstance).
+func (dm *DaemonManager) RestartServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error) {
+	args, err := json.Marshal(types.RestartServiceInstanceArgs{
+		Name:         name,
+		GracePeriod:  gracePeriod.String(),
+		TickerPeriod: tickerPeriod.String(),
+		Instance:     index,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("RestartServiceInstance: marshaling args: %w", err)
+	}
+	response, err := dm.sendRequest(ctx, types.MethodRestartServiceInstance, args)
+	if err != nil {
+		return 0, fmt.Errorf("RestartServiceInstance: request errored: %w", err)
+	}
+
+	var result map[string]int
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return 0, fmt.Errorf("RestartServiceInstance: parse response data: %w", err)
+	}
+
+	return result["pid"], nil
+}
+
 func (dm *DaemonManager) ForceStopService(ctx context.Context, name string) (StopServiceResult, error) {
 	args, err := json.Marshal(types.ForceStopServiceArgs{Name: name})
 	if err != nil {
@@ -611,6 +785,27 @@ func (dm *DaemonManager) GetLiveOrphanProcessGroups(ctx context.Context, name st
 	return result.Entries, nil
 }
 
+// GetInstanceProcessHistoryEntries returns the most recent process-history
+// entry of each of name's instances (see
+// LocalManager.GetInstanceProcessHistoryEntries).
+func (dm *DaemonManager) GetInstanceProcessHistoryEntries(ctx context.Context, name string) ([]types.ProcessHistory, error) {
+	args, err := json.Marshal(types.GetInstanceProcessHistoryEntriesArgs{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("GetInstanceProcessHistoryEntries: marshaling args: %w", err)
+	}
+	response, err := dm.sendRequest(ctx, types.MethodGetInstanceProcessHistoryEntries, args)
+	if err != nil {
+		return nil, fmt.Errorf("GetInstanceProcessHistoryEntries: request errored: %w", err)
+	}
+
+	var result types.GetInstanceProcessHistoryEntriesResponse
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return nil, fmt.Errorf("GetInstanceProcessHistoryEntries: parse response data: %w", err)
+	}
+
+	return result.Entries, nil
+}
+
 // SetDependencyWaitStatus tells the daemon that name is currently blocked
 // waiting on pending to become ready, so a concurrent `eos status`/`eos api
 // status` request against this same daemon can show it. Best-effort
@@ -653,6 +848,102 @@ func (dm *DaemonManager) GetDependencyWaitStatus(ctx context.Context, name strin
 	return *result.Status, true, nil
 }
 
+// GetEffectiveLimits asks the daemon for the limits the kernel is enforcing
+// on name's most recent live launch (see LocalManager.GetEffectiveLimits).
+func (dm *DaemonManager) GetEffectiveLimits(ctx context.Context, name string) (types.EffectiveLimits, error) {
+	args, _ := json.Marshal(types.GetEffectiveLimitsArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetEffectiveLimits, args)
+	if err != nil {
+		return types.EffectiveLimits{}, fmt.Errorf("GetEffectiveLimits: request errored: %w", err)
+	}
+
+	var result types.EffectiveLimits
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return types.EffectiveLimits{}, fmt.Errorf("GetEffectiveLimits: parse response data: %w", err)
+	}
+	return result, nil
+}
+
+// GetNotifyStatus asks the daemon what name's most recent live launch has
+// reported over its notify socket (see LocalManager.GetNotifyStatus).
+func (dm *DaemonManager) GetNotifyStatus(ctx context.Context, name string) (types.NotifyStatus, error) {
+	args, _ := json.Marshal(types.GetNotifyStatusArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetNotifyStatus, args)
+	if err != nil {
+		return types.NotifyStatus{}, fmt.Errorf("GetNotifyStatus: request errored: %w", err)
+	}
+
+	var result types.NotifyStatus
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return types.NotifyStatus{}, fmt.Errorf("GetNotifyStatus: parse response data: %w", err)
+	}
+	return result, nil
+}
+
+// GetCanaryResult asks the daemon for name's most recent canary reload (see
+// LocalManager.GetCanaryResult).
+func (dm *DaemonManager) GetCanaryResult(ctx context.Context, name string) (*types.CanaryResult, error) {
+	args, _ := json.Marshal(types.GetCanaryResultArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetCanaryResult, args)
+	if err != nil {
+		return nil, fmt.Errorf("GetCanaryResult: request errored: %w", err)
+	}
+
+	var result *types.CanaryResult
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return nil, fmt.Errorf("GetCanaryResult: parse response data: %w", err)
+	}
+	return result, nil
+}
+
+// GetConfigDrift asks the daemon how name's running launch's config differs
+// from its config on disk (see LocalManager.GetConfigDrift).
+func (dm *DaemonManager) GetConfigDrift(ctx context.Context, name string) (*types.ConfigDrift, error) {
+	args, _ := json.Marshal(types.GetConfigDriftArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetConfigDrift, args)
+	if err != nil {
+		return nil, fmt.Errorf("GetConfigDrift: request errored: %w", err)
+	}
+
+	var drift *types.ConfigDrift
+	if err := json.Unmarshal(response.Data, &drift); err != nil {
+		return nil, fmt.Errorf("GetConfigDrift: parse response data: %w", err)
+	}
+	return drift, nil
+}
+
+// ResolveServiceEnvironment asks the daemon for the environment name would
+// launch with over the daemon's own (see LocalManager.ResolveServiceEnvironment).
+func (dm *DaemonManager) ResolveServiceEnvironment(ctx context.Context, name string) ([]ResolvedEnvVar, error) {
+	args, _ := json.Marshal(types.ResolveServiceEnvironmentArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodResolveServiceEnvironment, args)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveServiceEnvironment: request errored: %w", err)
+	}
+
+	var result []ResolvedEnvVar
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return nil, fmt.Errorf("ResolveServiceEnvironment: parse response data: %w", err)
+	}
+	return result, nil
+}
+
+// GetProxyStatus asks the daemon for name's proxy and the instances behind
+// it (see LocalManager.GetProxyStatus).
+func (dm *DaemonManager) GetProxyStatus(ctx context.Context, name string) (types.ProxyStatus, error) {
+	args, _ := json.Marshal(types.GetProxyStatusArgs{ServiceName: name})
+	response, err := dm.sendRequest(ctx, types.MethodGetProxyStatus, args)
+	if err != nil {
+		return types.ProxyStatus{}, fmt.Errorf("GetProxyStatus: request errored: %w", err)
+	}
+
+	var result types.ProxyStatus
+	if err := json.Unmarshal(response.Data, &result); err != nil {
+		return types.ProxyStatus{}, fmt.Errorf("GetProxyStatus: parse response data: %w", err)
+	}
+	return result, nil
+}
+
 type ServiceLogFilesResult struct {
 	LogFilePath      string `json:"logFile"`
 	ErrorLogFilePath string `json:"errorLogFile"`
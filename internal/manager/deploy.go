@@ -0,0 +1,653 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+const (
+	// BuildDefaultTimeout bounds a build: command whose config sets no
+	// timeout of its own. Builds install dependencies and compile, so it is
+	// far longer than HookDefaultTimeout.
+	BuildDefaultTimeout = 10 * time.Minute
+	// DeployDefaultKeep is how many releases eos deploy keeps when it isn't
+	// told otherwise.
+	DeployDefaultKeep = 5
+)
+
+const (
+	releasesDirName = "releases"
+	currentLinkName = "current"
+	// releaseNameLayout names a release directory after its UTC deploy
+	// time, so names sort in deploy order.
+	releaseNameLayout = "20060102150405"
+	// buildPhase tags the build: command's log lines, as a hook's name does.
+	buildPhase = "build"
+)
+
+// DeployConfig carries the knobs of an eos deploy: the reload it finishes
+// with, and how many releases to keep (DeployDefaultKeep when 0).
+type DeployConfig struct {
+	ReloadConfig
+	Keep int
+}
+
+// DeployResult reports what eos deploy or eos rollback put live.
+type DeployResult struct {
+	Release types.Release
+	// Previous is the release current pointed at before, "" when the
+	// service had never been deployed.
+	Previous   string
+	Activation types.DeployActivation
+	Reload     ReloadResult
+	// Pruned names the releases deleted to stay within DeployConfig.Keep.
+	Pruned []string
+}
+
+// buildValidate checks a build: command has a command and a timeout that
+// parses.
+func buildValidate(config *types.ServiceConfig) []error {
+	if config.Build == nil {
+		return nil
+	}
+	var errs []error
+	if config.Build.Command.IsZero() || (config.Build.Command.IsExec() && config.Build.Command.Argv[0] == "") {
+		errs = append(errs, fmt.Errorf("build: a command is required"))
+	}
+	if _, err := ParseHookTimeout(config.Build.Timeout); err != nil {
+		errs = append(errs, fmt.Errorf("build: %w", err))
+	}
+	return errs
+}
+
+// deployRoot is the directory holding service's releases/ and current
+// symlink. Once a service has been deployed its catalog entry points at
+// current, so the root is that link's parent; before its first deploy it is
+// the service's own directory.
+func deployRoot(service *types.ServiceCatalogEntry) string {
+	dir := filepath.Clean(service.DirectoryPath)
+	if filepath.Base(dir) == currentLinkName {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// deployLockName is the lockService key serializing deploys and rollbacks of
+// name. It can't collide with a service's own lock, as names have no '/', and
+// is taken separately from it so the reload a deploy ends with can take that.
+func deployLockName(name string) string {
+	return "deploy/" + name
+}
+
+// DeployService unpacks artifact, a directory or a .tar, .tar.gz or .tgz
+// archive, into a new directory under the service's releases/, runs its
+// build: command there, and atomically switches the current symlink to it.
+// It then puts the release live (see activateRelease). If that fails, current
+// is switched back to the release it pointed at before, which a reload that
+// never became ready has left serving, and the new release is marked failed.
+// Finally releases beyond cfg.Keep are deleted, oldest first, never the
+// current or the previous one.
+//
+// The first deploy of a service repoints its catalog entry at current, so
+// every later start or reload runs whichever release current names. The
+// artifact must hold the service's config file at its root, for the same
+// service.
+func (m *LocalManager) DeployService(name, artifact string, probe ReadinessProbe, cfg DeployConfig) (result DeployResult, err error) {
+	unlock := m.lockService(deployLockName(name))
+	defer unlock()
+
+	keep := cfg.Keep
+	if keep == 0 {
+		keep = DeployDefaultKeep
+	}
+	if keep < 1 {
+		return DeployResult{}, fmt.Errorf("keep must be at least 1, got %d", keep)
+	}
+	service, err := m.GetServiceCatalogEntry(m.ctx, name)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	root := deployRoot(&service)
+	previous, err := m.currentRelease(name)
+	if err != nil {
+		return DeployResult{}, err
+	}
+
+	release, err := m.unpackRelease(&service, root, artifact)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	result = DeployResult{Release: release, Previous: previous}
+	if prepErr := m.prepareRelease(&service, &release); prepErr != nil {
+		m.failRelease(name, release.Name, prepErr)
+		result.Release = m.releaseByName(name, release)
+		return result, prepErr
+	}
+
+	originalDir := service.DirectoryPath
+	if switchErr := m.switchRelease(&service, root, release.Name); switchErr != nil {
+		m.failRelease(name, release.Name, switchErr)
+		result.Release = m.releaseByName(name, release)
+		return result, switchErr
+	}
+	result.Activation, result.Reload, err = m.activateRelease(name, probe, cfg.ReloadConfig)
+	if err != nil {
+		m.switchBack(&service, root, originalDir, previous)
+		m.failRelease(name, release.Name, err)
+		result.Release = m.releaseByName(name, release)
+		return result, fmt.Errorf("deploying release %s of %s: %w", release.Name, name, err)
+	}
+	result.Release = m.releaseByName(name, release)
+	result.Pruned = m.pruneReleases(name, root, keep, release.Name, previous)
+	return result, nil
+}
+
+// RollbackService switches name's current symlink back to release, or when
+// release is "" to the newest release older than the current one that didn't
+// fail, and puts it live as DeployService does, switching forward again if
+// that fails.
+func (m *LocalManager) RollbackService(name, release string, probe ReadinessProbe, cfg ReloadConfig) (result DeployResult, err error) {
+	unlock := m.lockService(deployLockName(name))
+	defer unlock()
+
+	service, err := m.GetServiceCatalogEntry(m.ctx, name)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	releases, err := m.db.GetReleases(m.ctx, name)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	current := ""
+	for _, r := range releases {
+		if r.State == types.ReleaseStateCurrent {
+			current = r.Name
+		}
+	}
+	if current == "" {
+		return DeployResult{}, fmt.Errorf("%s has no current release to roll back from", name)
+	}
+	target, err := rollbackTarget(releases, current, release)
+	if err != nil {
+		return DeployResult{}, fmt.Errorf("rolling back %s: %w", name, err)
+	}
+	if _, statErr := os.Stat(target.Path); statErr != nil {
+		return DeployResult{}, fmt.Errorf("release %s of %s: %w", target.Name, name, statErr)
+	}
+
+	root := deployRoot(&service)
+	result = DeployResult{Release: target, Previous: current}
+	if switchErr := m.switchRelease(&service, root, target.Name); switchErr != nil {
+		return result, switchErr
+	}
+	result.Activation, result.Reload, err = m.activateRelease(name, probe, cfg)
+	if err != nil {
+		m.switchBack(&service, root, service.DirectoryPath, current)
+		m.failRelease(name, target.Name, err)
+		result.Release = m.releaseByName(name, target)
+		return result, fmt.Errorf("rolling back %s to release %s: %w", name, target.Name, err)
+	}
+	result.Release = m.releaseByName(name, target)
+	return result, nil
+}
+
+// rollbackTarget picks the release a rollback from current switches to:
+// the one named, or the newest older than current that didn't fail.
+// releases are newest first, as GetReleases returns them.
+func rollbackTarget(releases []types.Release, current, name string) (types.Release, error) {
+	for _, r := range releases {
+		switch {
+		case name != "" && r.Name == name:
+			if r.Name == current {
+				return types.Release{}, fmt.Errorf("release %s is already current", name)
+			}
+			return r, nil
+		case name == "" && r.Name < current && r.State != types.ReleaseStateFailed:
+			return r, nil
+		}
+	}
+	if name != "" {
+		return types.Release{}, fmt.Errorf("no release %s", name)
+	}
+	return types.Release{}, fmt.Errorf("no release before %s to roll back to", current)
+}
+
+// GetReleases returns name's recorded releases, newest first.
+func (m *LocalManager) GetReleases(ctx context.Context, name string) ([]types.Release, error) {
+	return m.db.GetReleases(ctx, name)
+}
+
+// currentRelease is the name of name's current release, "" when it has
+// none.
+func (m *LocalManager) currentRelease(name string) (string, error) {
+	releases, err := m.db.GetReleases(m.ctx, name)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range releases {
+		if r.State == types.ReleaseStateCurrent {
+			return r.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// releaseByName re-reads release's row after a state change, falling back
+// to the copy the caller has.
+func (m *LocalManager) releaseByName(name string, release types.Release) types.Release {
+	releases, err := m.db.GetReleases(m.ctx, name)
+	if err != nil {
+		return release
+	}
+	for _, r := range releases {
+		if r.Name == release.Name {
+			return r
+		}
+	}
+	return release
+}
+
+// unpackRelease creates the next release directory under root and unpacks
+// artifact into it, recording the release once it holds something. A
+// release that didn't unpack is deleted rather than kept as failed: there is
+// nothing in it to inspect.
+func (m *LocalManager) unpackRelease(service *types.ServiceCatalogEntry, root, artifact string) (types.Release, error) {
+	releaseName, releasePath, err := newReleaseDir(filepath.Join(root, releasesDirName), time.Now())
+	if err != nil {
+		return types.Release{}, fmt.Errorf("creating release directory for %s: %w", service.Name, err)
+	}
+	m.logger.Info("deploy: unpacking release", "service", service.Name, "release", releaseName, "artifact", artifact)
+	if unpackErr := unpackArtifact(artifact, releasePath); unpackErr != nil {
+		if rmErr := os.RemoveAll(releasePath); rmErr != nil {
+			m.logger.Error("deploy: removing partly unpacked release", "service", service.Name, "path", releasePath, "error", rmErr)
+		}
+		return types.Release{}, fmt.Errorf("unpacking %s: %w", artifact, unpackErr)
+	}
+	release := types.Release{
+		ServiceName: service.Name,
+		Name:        releaseName,
+		Path:        releasePath,
+		Source:      artifact,
+		State:       types.ReleaseStateRetired,
+		CreatedAt:   time.Now(),
+	}
+	if err := m.db.RecordRelease(m.ctx, release); err != nil {
+		return types.Release{}, err
+	}
+	return release, nil
+}
+
+// prepareRelease checks release holds a valid config for service and runs
+// its build: command.
+func (m *LocalManager) prepareRelease(service *types.ServiceCatalogEntry, release *types.Release) error {
+	config, errs := ValidateServiceConfig(filepath.Join(release.Path, service.ConfigFileName))
+	if len(errs) > 0 {
+		return fmt.Errorf("release %s has an invalid %s: %w", release.Name, service.ConfigFileName, errors.Join(errs...))
+	}
+	if config.Name != service.Name {
+		return fmt.Errorf("release %s is for service %q, not %q", release.Name, config.Name, service.Name)
+	}
+	return m.runBuild(service, config, release.Path)
+}
+
+// runBuild runs config's build: command, if it has one, in releasePath. It
+// runs as a hook does (see runHook), with the release as its directory, so
+// its env_file: entries resolve there and its output lands in the service's
+// logs tagged hook=build.
+func (m *LocalManager) runBuild(service *types.ServiceCatalogEntry, config *types.ServiceConfig, releasePath string) error {
+	if config.Build == nil {
+		return nil
+	}
+	timeout := BuildDefaultTimeout
+	if strings.TrimSpace(config.Build.Timeout) != "" {
+		var err error
+		if timeout, err = ParseHookTimeout(config.Build.Timeout); err != nil {
+			return fmt.Errorf("build: %w", err)
+		}
+	}
+	build := *service
+	build.DirectoryPath = releasePath
+	m.logger.Info("deploy: running build", "service", service.Name, "path", releasePath)
+	_, _, err := m.runHookCommand(&build, config, buildPhase, config.Build.Command, timeout)
+	return err
+}
+
+// switchRelease points root's current symlink at releaseName and records it
+// as the current release. On the service's first deploy it also repoints the
+// catalog entry at current.
+func (m *LocalManager) switchRelease(service *types.ServiceCatalogEntry, root, releaseName string) error {
+	if err := switchCurrentLink(root, releaseName); err != nil {
+		return fmt.Errorf("switching %s to release %s: %w", service.Name, releaseName, err)
+	}
+	link := filepath.Join(root, currentLinkName)
+	if filepath.Clean(service.DirectoryPath) != link {
+		if err := m.db.UpdateServiceCatalogEntry(m.ctx, service.Name, link, service.ConfigFileName); err != nil {
+			return fmt.Errorf("pointing %s at %s: %w", service.Name, link, err)
+		}
+	}
+	if err := m.db.ActivateRelease(m.ctx, service.Name, releaseName); err != nil {
+		return err
+	}
+	m.logger.Info("deploy: switched current", "service", service.Name, "release", releaseName)
+	return nil
+}
+
+// switchBack undoes switchRelease after the release failed to go live:
+// current goes back to previous, or for a first deploy the catalog entry
+// goes back to originalDir and current is removed. Failures are logged; the
+// caller's error is the activation's.
+func (m *LocalManager) switchBack(service *types.ServiceCatalogEntry, root, originalDir, previous string) {
+	if previous != "" {
+		if err := m.switchRelease(service, root, previous); err != nil {
+			m.logger.Error("deploy: switching back to the previous release", "service", service.Name, "release", previous, "error", err)
+		}
+		return
+	}
+	if err := m.db.UpdateServiceCatalogEntry(m.ctx, service.Name, originalDir, service.ConfigFileName); err != nil {
+		m.logger.Error("deploy: restoring the service directory", "service", service.Name, "path", originalDir, "error", err)
+	}
+	if err := os.Remove(filepath.Join(root, currentLinkName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.logger.Error("deploy: removing current", "service", service.Name, "error", err)
+	}
+}
+
+// failRelease marks a release failed with cause. A DB failure is logged: the
+// caller's error is cause.
+func (m *LocalManager) failRelease(name, release string, cause error) {
+	m.logger.Warn("deploy: release failed", "service", name, "release", release, "error", cause)
+	if err := m.db.FailRelease(m.ctx, name, release, cause.Error()); err != nil {
+		m.logger.Error("deploy: marking release failed", "service", name, "release", release, "error", err)
+	}
+}
+
+// activateRelease puts the release current now names live. A running
+// service is reloaded onto it. One that isn't running is restarted if it
+// failed, since it was meant to be up, and otherwise left stopped, to run
+// the release the next time it is started.
+func (m *LocalManager) activateRelease(name string, probe ReadinessProbe, cfg ReloadConfig) (types.DeployActivation, ReloadResult, error) {
+	reload, err := m.ReloadService(name, probe, cfg)
+	if err == nil {
+		return types.DeployActivationReloaded, reload, nil
+	}
+	if !errors.Is(err, ErrServiceNotRunning) {
+		return types.DeployActivationReloaded, reload, err
+	}
+	latest, histErr := m.db.GetMostRecentProcessHistoryEntryByName(m.ctx, name)
+	if histErr != nil || latest.State != types.ProcessStateFailed {
+		return types.DeployActivationNotRunning, ReloadResult{}, nil
+	}
+	pgid, err := m.RestartService(m.ctx, name, cfg.GracePeriod, cfg.TickerPeriod)
+	return types.DeployActivationRestarted, ReloadResult{NewPGID: pgid}, err
+}
+
+// pruneReleases deletes name's oldest releases so that keep remain, never
+// removing the protected ones (the current and previous release), and
+// returns the names it deleted. Only directories under root's releases/ are
+// ever deleted. Failures are logged: the deploy itself succeeded.
+func (m *LocalManager) pruneReleases(name, root string, keep int, protected ...string) []string {
+	releases, err := m.db.GetReleases(m.ctx, name)
+	if err != nil {
+		m.logger.Error("deploy: listing releases to prune", "service", name, "error", err)
+		return nil
+	}
+	releasesDir := filepath.Join(root, releasesDirName)
+	var pruned []string
+	kept := 0
+	for _, release := range releases {
+		isProtected := false
+		for _, p := range protected {
+			isProtected = isProtected || (p != "" && p == release.Name)
+		}
+		if isProtected || kept < keep {
+			kept++
+			continue
+		}
+		if filepath.Dir(filepath.Clean(release.Path)) != releasesDir {
+			m.logger.Warn("deploy: not pruning a release outside releases/", "service", name, "release", release.Name, "path", release.Path)
+			continue
+		}
+		if err := os.RemoveAll(release.Path); err != nil {
+			m.logger.Error("deploy: pruning release", "service", name, "release", release.Name, "error", err)
+			continue
+		}
+		if _, err := m.db.RemoveRelease(m.ctx, name, release.Name); err != nil {
+			m.logger.Error("deploy: removing pruned release", "service", name, "release", release.Name, "error", err)
+			continue
+		}
+		pruned = append(pruned, release.Name)
+	}
+	return pruned
+}
+
+// newReleaseDir creates releasesDir/<now as releaseNameLayout>, suffixed
+// -2, -3... when a release was already made that second.
+func newReleaseDir(releasesDir string, now time.Time) (string, string, error) {
+	if err := os.MkdirAll(releasesDir, 0755); err != nil {
+		return "", "", err
+	}
+	base := now.UTC().Format(releaseNameLayout)
+	for i := 1; i < 100; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		path := filepath.Join(releasesDir, name)
+		err := os.Mkdir(path, 0755)
+		if err == nil {
+			return name, path, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", "", err
+		}
+	}
+	return "", "", fmt.Errorf("too many releases created at %s", base)
+}
+
+// switchCurrentLink atomically points root/current at releases/name: the
+// new link is made beside it and renamed over it, so current always names
+// one release or the other. The link is relative, so root can move.
+func switchCurrentLink(root, name string) error {
+	link := filepath.Join(root, currentLinkName)
+	if info, err := os.Lstat(link); err == nil && info.Mode()&fs.ModeSymlink == 0 {
+		return fmt.Errorf("%s exists and is not a symlink", link)
+	}
+	tmp := filepath.Join(root, "."+currentLinkName+".tmp")
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Symlink(filepath.Join(releasesDirName, name), tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// unpackArtifact fills dest from artifact: a directory is copied, a .tar,
+// .tar.gz or .tgz archive extracted.
+func unpackArtifact(artifact, dest string) error {
+	info, err := os.Stat(artifact)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return copyTree(artifact, dest)
+	}
+	switch {
+	case strings.HasSuffix(artifact, ".tar.gz"), strings.HasSuffix(artifact, ".tgz"):
+		return extractTarball(artifact, dest, true)
+	case strings.HasSuffix(artifact, ".tar"):
+		return extractTarball(artifact, dest, false)
+	}
+	return fmt.Errorf("an artifact must be a directory or a .tar, .tar.gz or .tgz archive")
+}
+
+// copyTree copies the directory src into dest, keeping file modes and
+// copying symlinks as symlinks. Anything else (sockets, devices) is skipped.
+// src may not contain dest, which a deploy from the service's own directory
+// would otherwise copy into itself.
+func copyTree(src, dest string) error {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if rel, relErr := filepath.Rel(src, dest); relErr == nil && filepath.IsLocal(rel) {
+		return fmt.Errorf("%s is inside the artifact directory", dest)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&fs.ModeSymlink != 0:
+			linkTarget, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(linkTarget, target)
+		case info.Mode().IsRegular():
+			in, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer in.Close() //nolint:errcheck // read-only file
+			return writeReleaseFile(target, in, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// extractTarball extracts the archive at path into dest. An entry, or a
+// link's target, that would land outside dest is refused, so a hostile
+// archive can't write anywhere else through ../ or an absolute path.
+func extractTarball(path, dest string, gzipped bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close() //nolint:errcheck // read-only stream
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !filepath.IsLocal(hdr.Name) {
+			if filepath.Clean(hdr.Name) == "." {
+				continue
+			}
+			return fmt.Errorf("archive entry %q escapes the release directory", hdr.Name)
+		}
+		if err := refuseSymlinkedParent(dest, hdr.Name); err != nil {
+			return err
+		}
+		target := filepath.Join(dest, hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeReleaseFile(target, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) || !filepath.IsLocal(filepath.Join(filepath.Dir(hdr.Name), hdr.Linkname)) {
+				return fmt.Errorf("archive symlink %q -> %q escapes the release directory", hdr.Name, hdr.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			if !filepath.IsLocal(hdr.Linkname) {
+				return fmt.Errorf("archive hard link %q -> %q escapes the release directory", hdr.Name, hdr.Linkname)
+			}
+			if err := refuseSymlinkedParent(dest, hdr.Linkname); err != nil {
+				return err
+			}
+			if err := os.Link(filepath.Join(dest, hdr.Linkname), target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// refuseSymlinkedParent refuses an archive entry whose parent directories
+// in dest include a symlink an earlier entry made. Link targets are only
+// checked lexically, so writing through one could still leave dest.
+func refuseSymlinkedParent(dest, name string) error {
+	dir := filepath.Dir(filepath.Clean(name))
+	if dir == "." {
+		return nil
+	}
+	path := dest
+	for _, part := range strings.Split(dir, string(filepath.Separator)) {
+		path = filepath.Join(path, part)
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("archive entry %q is inside a symlink", name)
+		}
+	}
+	return nil
+}
+
+// writeReleaseFile writes r to a new file at path with mode perm.
+func writeReleaseFile(path string, r io.Reader, perm fs.FileMode) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
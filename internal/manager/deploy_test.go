@@ -0,0 +1,229 @@
+package manager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// deployTestService registers name, with its service.yaml in a directory of
+// its own, and returns the manager and that directory.
+func deployTestService(t *testing.T, name string) (*LocalManager, string) {
+	t.Helper()
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	t.Setenv("EOS_BASE_DIR", tempDir)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	dir := filepath.Join(tempDir, name)
+	writeDeployArtifact(t, dir, name, "")
+	entry, err := NewServiceCatalogEntry(name, dir, "service.yaml")
+	if err != nil {
+		t.Fatalf("catalog entry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("add catalog entry: %v", err)
+	}
+	return m, dir
+}
+
+// writeDeployArtifact writes an artifact directory for name at dir, with a
+// build: command when build is set.
+func writeDeployArtifact(t *testing.T, dir, name, build string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	yaml := "name: " + name + "\ncommand: sleep 300\n"
+	if build != "" {
+		yaml += "build:\n  command: " + build + "\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+}
+
+// writeTarball writes a .tar.gz holding files, each a path and its content;
+// a content starting "->" makes a symlink to the rest.
+func writeTarball(t *testing.T, path string, files [][2]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f[0], Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(f[1]))}
+		if target, ok := strings.CutPrefix(f[1], "->"); ok {
+			hdr = &tar.Header{Name: f[0], Typeflag: tar.TypeSymlink, Linkname: target}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(f[1])); err != nil {
+				t.Fatalf("tar write: %v", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write tarball: %v", err)
+	}
+}
+
+// TestDeployServiceReleasesAndRollback deploys a directory with a build:
+// command, then a tarball, to a stopped service, and rolls back to the
+// first: each switches current and records the release's state.
+func TestDeployServiceReleasesAndRollback(t *testing.T) {
+	m, dir := deployTestService(t, "deploy-app")
+	artifact := filepath.Join(t.TempDir(), "build")
+	writeDeployArtifact(t, artifact, "deploy-app", "touch built")
+
+	first, err := m.DeployService("deploy-app", artifact, alwaysReady, DeployConfig{ReloadConfig: rolloutTestConfig})
+	if err != nil {
+		t.Fatalf("first DeployService: %v", err)
+	}
+	if first.Activation != types.DeployActivationNotRunning || first.Previous != "" {
+		t.Errorf("first deploy = %+v, want a stopped service left stopped with no previous release", first)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "current", "built")); statErr != nil {
+		t.Errorf("build: should have run in the release current points at: %v", statErr)
+	}
+	entry, err := m.GetServiceCatalogEntry(t.Context(), "deploy-app")
+	if err != nil || entry.DirectoryPath != filepath.Join(dir, "current") {
+		t.Errorf("catalog entry = %+v, %v; want it pointed at current", entry, err)
+	}
+
+	tarball := filepath.Join(t.TempDir(), "app.tgz")
+	writeTarball(t, tarball, [][2]string{{"service.yaml", "name: deploy-app\ncommand: sleep 300\n"}, {"VERSION", "2"}})
+	second, err := m.DeployService("deploy-app", tarball, alwaysReady, DeployConfig{ReloadConfig: rolloutTestConfig})
+	if err != nil {
+		t.Fatalf("second DeployService: %v", err)
+	}
+	if second.Previous != first.Release.Name || second.Release.State != types.ReleaseStateCurrent {
+		t.Errorf("second deploy = %+v, want it current after %s", second, first.Release.Name)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "current", "VERSION")); string(data) != "2" {
+		t.Errorf("current/VERSION = %q, want the tarball's", data)
+	}
+
+	back, err := m.RollbackService("deploy-app", "", alwaysReady, rolloutTestConfig)
+	if err != nil {
+		t.Fatalf("RollbackService: %v", err)
+	}
+	if back.Release.Name != first.Release.Name {
+		t.Errorf("rolled back to %s, want %s", back.Release.Name, first.Release.Name)
+	}
+	target, err := os.Readlink(filepath.Join(dir, "current"))
+	if err != nil || target != filepath.Join("releases", first.Release.Name) {
+		t.Errorf("current -> %q, %v; want releases/%s", target, err, first.Release.Name)
+	}
+}
+
+// TestDeployServiceSwitchesBackWhenNotReady deploys to a running service
+// whose new release never becomes ready: current goes back to the previous
+// release, the old instance keeps serving, and the new release is failed.
+func TestDeployServiceSwitchesBackWhenNotReady(t *testing.T) {
+	m, dir := deployTestService(t, "deploy-unready")
+	artifact := filepath.Join(t.TempDir(), "build")
+	writeDeployArtifact(t, artifact, "deploy-unready", "")
+	first, err := m.DeployService("deploy-unready", artifact, alwaysReady, DeployConfig{ReloadConfig: rolloutTestConfig})
+	if err != nil {
+		t.Fatalf("first DeployService: %v", err)
+	}
+	pgid, err := m.StartService(t.Context(), "deploy-unready")
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { killGroup(pgid) })
+
+	result, err := m.DeployService("deploy-unready", artifact, readyFirst(0), DeployConfig{ReloadConfig: rolloutTestConfig})
+	if result.Reload.NewPGID != 0 {
+		t.Cleanup(func() { killGroup(result.Reload.NewPGID) })
+	}
+	if !errors.Is(err, ErrReloadNotReady) {
+		t.Fatalf("DeployService err = %v, want ErrReloadNotReady", err)
+	}
+	if result.Release.State != types.ReleaseStateFailed {
+		t.Errorf("release = %+v, want it failed", result.Release)
+	}
+	target, _ := os.Readlink(filepath.Join(dir, "current"))
+	if target != filepath.Join("releases", first.Release.Name) {
+		t.Errorf("current -> %q, want it switched back to %s", target, first.Release.Name)
+	}
+	if !procutil.IsAlive(pgid) {
+		t.Errorf("old instance pgid %d must keep serving", pgid)
+	}
+}
+
+func TestDeployServicePrunes(t *testing.T) {
+	m, _ := deployTestService(t, "deploy-prune")
+	artifact := filepath.Join(t.TempDir(), "build")
+	writeDeployArtifact(t, artifact, "deploy-prune", "")
+
+	var last DeployResult
+	for range 3 {
+		var err error
+		if last, err = m.DeployService("deploy-prune", artifact, alwaysReady, DeployConfig{ReloadConfig: rolloutTestConfig, Keep: 1}); err != nil {
+			t.Fatalf("DeployService: %v", err)
+		}
+	}
+	releases, err := m.GetReleases(t.Context(), "deploy-prune")
+	if err != nil {
+		t.Fatalf("GetReleases: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Name != last.Release.Name || releases[1].Name != last.Previous {
+		t.Errorf("releases = %+v, want only the current and previous kept", releases)
+	}
+	if len(last.Pruned) != 1 {
+		t.Errorf("Pruned = %v, want the oldest release", last.Pruned)
+	}
+}
+
+func TestDeployServiceRejectsOtherServicesConfig(t *testing.T) {
+	m, _ := deployTestService(t, "deploy-mine")
+	artifact := filepath.Join(t.TempDir(), "build")
+	writeDeployArtifact(t, artifact, "someone-else", "")
+
+	result, err := m.DeployService("deploy-mine", artifact, alwaysReady, DeployConfig{ReloadConfig: rolloutTestConfig})
+	if err == nil || !strings.Contains(err.Error(), "someone-else") {
+		t.Fatalf("DeployService err = %v, want the config's service name refused", err)
+	}
+	if result.Release.State != types.ReleaseStateFailed {
+		t.Errorf("release = %+v, want it recorded as failed", result.Release)
+	}
+}
+
+func TestExtractTarballRefusesEscapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		files [][2]string
+	}{
+		{"parent path", [][2]string{{"../evil", "x"}}},
+		{"absolute symlink", [][2]string{{"etc", "->/etc"}}},
+		{"symlink out", [][2]string{{"a/up", "->../.."}}},
+		{"through a symlink", [][2]string{{"a/here", "->."}, {"a/here/x", "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tarball := filepath.Join(t.TempDir(), "bad.tgz")
+			writeTarball(t, tarball, tt.files)
+			if err := unpackArtifact(tarball, t.TempDir()); err == nil {
+				t.Error("expected the archive to be refused")
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotenvSyntaxError is a malformed env_file entry, located by the 1-based
+// line the problem was found on.
+type dotenvSyntaxError struct {
+	msg  string
+	line int
+}
+
+func (e *dotenvSyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// EnvFileEntry is one assignment parsed out of an env_file, with the lines
+// (1-based, inclusive) it occupies: more than one for a quoted value that
+// spans lines, such as a PEM key.
+type EnvFileEntry struct {
+	Name      string
+	Value     string
+	FirstLine int
+	LastLine  int
+}
+
+// parseDotenv parses env_file contents the way the dotenv family of tools
+// does:
+//
+//   - blank lines and lines starting with # are skipped;
+//   - a line may start with "export ", as in a file meant to be sourced;
+//   - an unquoted value runs to the end of the line, trimmed, and a # after
+//     whitespace starts a comment;
+//   - a single-quoted value is literal, with no escapes or expansion;
+//   - a double-quoted value expands \n, \r, \t, \", \\ and \$;
+//   - either quoted form may span lines, and may be followed by a comment;
+//   - unquoted and double-quoted values expand ${NAME} and ${NAME:-default}
+//     (see expandEnvReference) against the entries above them, then lookup;
+//     unquoted ones also read $$ as a literal $, like env: values.
+//
+// Anything else — a line with no =, an invalid name, an unterminated quote —
+// is a dotenvSyntaxError naming its line. Entries come back in file order; a
+// name assigned twice appears twice, the later one winning.
+func parseDotenv(contents string, lookup func(name string) (string, bool)) ([]EnvFileEntry, error) {
+	p := dotenvParser{src: contents, line: 1, lookup: lookup}
+	var entries []EnvFileEntry
+	for p.pos < len(p.src) {
+		line := p.src[p.pos:]
+		if i := strings.IndexByte(line, '\n'); i != -1 {
+			line = line[:i]
+		}
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			p.skipLine()
+			continue
+		}
+		entry, err := p.entry()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		p.parsed = append(p.parsed, entry)
+	}
+	return entries, nil
+}
+
+type dotenvParser struct {
+	lookup func(name string) (string, bool)
+	parsed []EnvFileEntry
+	src    string
+	pos    int
+	line   int
+}
+
+func (p *dotenvParser) errorf(line int, format string, args ...any) error {
+	return &dotenvSyntaxError{line: line, msg: fmt.Sprintf(format, args...)}
+}
+
+// resolve looks name up among the entries parsed so far, the latest
+// assignment winning, and falls back to lookup.
+func (p *dotenvParser) resolve(name string) (string, bool) {
+	for i := len(p.parsed) - 1; i >= 0; i-- {
+		if p.parsed[i].Name == name {
+			return p.parsed[i].Value, true
+		}
+	}
+	if p.lookup == nil {
+		return "", false
+	}
+	return p.lookup(name)
+}
+
+// skipLine moves past the rest of the current line and its newline.
+func (p *dotenvParser) skipLine() {
+	if i := strings.IndexByte(p.src[p.pos:], '\n'); i != -1 {
+		p.pos += i + 1
+		p.line++
+		return
+	}
+	p.pos = len(p.src)
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// entry parses one assignment starting at the current line.
+func (p *dotenvParser) entry() (EnvFileEntry, error) {
+	first := p.line
+	p.skipBlanks()
+	if rest, ok := strings.CutPrefix(p.src[p.pos:], "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		p.pos += len("export")
+		p.skipBlanks()
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune("= \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	p.skipBlanks()
+	if p.pos == len(p.src) || p.src[p.pos] != '=' {
+		return EnvFileEntry{}, p.errorf(first, "expected NAME=value, got %q", strings.TrimSpace(p.currentLine(start)))
+	}
+	if !isEnvName(name) {
+		return EnvFileEntry{}, p.errorf(first, "%q is not a valid variable name", name)
+	}
+	p.pos++
+	p.skipBlanks()
+
+	var value string
+	var err error
+	switch {
+	case p.pos < len(p.src) && p.src[p.pos] == '\'':
+		value, err = p.singleQuoted(first)
+	case p.pos < len(p.src) && p.src[p.pos] == '"':
+		value, err = p.doubleQuoted(first)
+	default:
+		value, err = p.unquoted(first)
+	}
+	if err != nil {
+		return EnvFileEntry{}, err
+	}
+	entry := EnvFileEntry{Name: name, Value: value, FirstLine: first, LastLine: p.line}
+	p.skipLine()
+	return entry, nil
+}
+
+// currentLine returns the line containing offset, for error messages.
+func (p *dotenvParser) currentLine(offset int) string {
+	begin := strings.LastIndexByte(p.src[:offset], '\n') + 1
+	end := strings.IndexByte(p.src[begin:], '\n')
+	if end == -1 {
+		return p.src[begin:]
+	}
+	return p.src[begin : begin+end]
+}
+
+func (p *dotenvParser) unquoted(first int) (string, error) {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end == -1 {
+		end = len(p.src) - p.pos
+	}
+	raw := p.src[p.pos : p.pos+end]
+	p.pos += end
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && (i == 0 || raw[i-1] == ' ' || raw[i-1] == '\t') {
+			raw = raw[:i]
+			break
+		}
+	}
+	value, err := interpolateEnvValue(strings.TrimSpace(raw), p.resolve)
+	if err != nil {
+		return "", p.errorf(first, "%v", err)
+	}
+	return value, nil
+}
+
+func (p *dotenvParser) singleQuoted(first int) (string, error) {
+	p.pos++
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end == -1 {
+		return "", p.errorf(first, "unterminated single-quoted value")
+	}
+	value := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + 1
+	return value, p.afterQuote(first)
+}
+
+func (p *dotenvParser) doubleQuoted(first int) (string, error) {
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), p.afterQuote(first)
+		case c == '\\' && p.pos+1 < len(p.src):
+			escaped := p.src[p.pos+1]
+			switch escaped {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(escaped)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(escaped)
+			}
+			if escaped == '\n' {
+				p.line++
+			}
+			p.pos += 2
+		case c == '$' && strings.HasPrefix(p.src[p.pos:], "${"):
+			resolved, n, err := expandEnvReference(p.src[p.pos:], p.resolve)
+			if err != nil {
+				return "", p.errorf(p.line, "%v", err)
+			}
+			b.WriteString(resolved)
+			p.pos += n
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf(first, "unterminated double-quoted value")
+}
+
+// afterQuote checks that only whitespace or a comment follows a closing
+// quote on its line.
+func (p *dotenvParser) afterQuote(first int) error {
+	p.skipBlanks()
+	if p.pos == len(p.src) {
+		return nil
+	}
+	switch p.src[p.pos] {
+	case '\n', '#':
+		return nil
+	case '\r':
+		if p.pos+1 == len(p.src) || p.src[p.pos+1] == '\n' {
+			return nil
+		}
+	}
+	rest := p.src[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i != -1 {
+		rest = rest[:i]
+	}
+	return p.errorf(p.line, "unexpected %q after the closing quote of the value starting on line %d", strings.TrimSpace(rest), first)
+}
+
+// FormatEnvFileAssignment renders name=value as an env_file line that
+// parseDotenv reads back as exactly value: bare when that's unambiguous,
+// double-quoted with escapes otherwise.
+func FormatEnvFileAssignment(name, value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n#'\"\\$") {
+		return name + "=" + value
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+	return name + `="` + escaper.Replace(value) + `"`
+}
+
+// ParseEnvFileEntries parses env_file contents with no variables defined
+// outside the file, for tools that edit the file in place (eos env set and
+// unset) rather than launch with it.
+func ParseEnvFileEntries(contents string) ([]EnvFileEntry, error) {
+	return parseDotenv(contents, nil)
+}
@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	contents := `# generated by the deploy tooling
+export HOST=db.internal
+PLAIN = value with spaces   # trailing comment
+HASH=abc#def
+SINGLE='literal ${HOST} \n # not a comment'
+DOUBLE="tab\there \"quoted\" \$HOST ${HOST}"   # comment after quotes
+URL=postgres://${HOST}:${PORT:-5432}/${APP}
+EMPTY=
+PEM="-----BEGIN KEY-----
+line one
+-----END KEY-----"
+AFTER=done
+`
+	lookup := func(name string) (string, bool) {
+		if name == "APP" {
+			return "shop", true
+		}
+		return "", false
+	}
+
+	entries, err := parseDotenv(contents, lookup)
+	if err != nil {
+		t.Fatalf("parseDotenv: %v", err)
+	}
+
+	want := []EnvFileEntry{
+		{Name: "HOST", Value: "db.internal", FirstLine: 2, LastLine: 2},
+		{Name: "PLAIN", Value: "value with spaces", FirstLine: 3, LastLine: 3},
+		{Name: "HASH", Value: "abc#def", FirstLine: 4, LastLine: 4},
+		{Name: "SINGLE", Value: `literal ${HOST} \n # not a comment`, FirstLine: 5, LastLine: 5},
+		{Name: "DOUBLE", Value: "tab\there \"quoted\" $HOST db.internal", FirstLine: 6, LastLine: 6},
+		{Name: "URL", Value: "postgres://db.internal:5432/shop", FirstLine: 7, LastLine: 7},
+		{Name: "EMPTY", Value: "", FirstLine: 8, LastLine: 8},
+		{Name: "PEM", Value: "-----BEGIN KEY-----\nline one\n-----END KEY-----", FirstLine: 9, LastLine: 11},
+		{Name: "AFTER", Value: "done", FirstLine: 12, LastLine: 12},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("parseDotenv returned %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestParseDotenv_laterAssignmentWinsForReferences(t *testing.T) {
+	entries, err := parseDotenv("A=one\nA=two\nB=${A}\n", nil)
+	if err != nil {
+		t.Fatalf("parseDotenv: %v", err)
+	}
+	if got := entries[len(entries)-1]; got.Name != "B" || got.Value != "two" {
+		t.Errorf("B = %+v, want two", got)
+	}
+}
+
+func TestParseDotenv_errorsNameTheLine(t *testing.T) {
+	cases := []struct {
+		contents string
+		wantLine string
+	}{
+		{"A=1\nno-equals-sign\n", "line 2:"},
+		{"A=1\n\n1BAD=x\n", "line 3:"},
+		{"A=1\nKEY='never closed\nmore\n", "line 2:"},
+		{"KEY=\"never closed\n", "line 1:"},
+		{"KEY=\"ok\" trailing\n", "line 1:"},
+		{"A=1\nKEY=${HOST\n", "line 2:"},
+		{"KEY=\"multi\nline\" junk\n", "line 2:"},
+	}
+	for _, tc := range cases {
+		_, err := parseDotenv(tc.contents, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), tc.wantLine) {
+			t.Errorf("parseDotenv(%q) err = %v, want one starting %q", tc.contents, err, tc.wantLine)
+		}
+	}
+}
+
+func TestFormatEnvFileAssignment_roundTrips(t *testing.T) {
+	for _, value := range []string{"plain", "", "with space", "a#b", "it's", `say "hi"`, `C:\path`, "$HOME", "-----BEGIN-----\nbody\n-----END-----"} {
+		line := FormatEnvFileAssignment("KEY", value)
+		entries, err := parseDotenv(line+"\n", nil)
+		if err != nil || len(entries) != 1 || entries[0].Value != value {
+			t.Errorf("FormatEnvFileAssignment(%q) = %q, which parses back as %+v (err %v)", value, line, entries, err)
+		}
+	}
+	if got := FormatEnvFileAssignment("KEY", "plain"); got != "KEY=plain" {
+		t.Errorf("FormatEnvFileAssignment(plain) = %q, want it unquoted", got)
+	}
+}
@@ -0,0 +1,233 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// EnvSource names the layer of a service's launch environment that set a
+// variable's final value.
+type EnvSource string
+
+const (
+	// EnvSourceDaemon is inherited unchanged from the daemon's own
+	// environment, or under clean_env passed through by pass_env.
+	EnvSourceDaemon EnvSource = "daemon"
+	// EnvSourceEOS is set by eos itself from service.yaml: PORT, PATH with
+	// runtime.path prepended (or cleanEnvDefaultPath under clean_env), and
+	// HOME/USER/LOGNAME for user:.
+	EnvSourceEOS EnvSource = "eos"
+	// EnvSourceEnvFile is read from the service's env_file.
+	EnvSourceEnvFile EnvSource = "env_file"
+	// EnvSourceInline is service.yaml's own env: map.
+	EnvSourceInline EnvSource = "inline"
+)
+
+// cleanEnvDefaultPath is the PATH a clean_env service gets when pass_env
+// doesn't pass the daemon's own: systemd's default for the units it spawns,
+// so an exec-form command and /bin/sh still find system binaries.
+const cleanEnvDefaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// ResolvedEnvVar is one variable of a service's launch environment and the
+// layer it came from.
+type ResolvedEnvVar struct {
+	Name   string    `json:"name"`
+	Value  string    `json:"value"`
+	Source EnvSource `json:"source"`
+}
+
+// ResolveEnvironment returns the environment a service would launch with
+// over daemonEnv, sorted by name, each variable labelled with the layer that
+// set it, so eos env can show it without the service running. daemonEnv must
+// be the environment of the process that would launch the service (see
+// LocalManager.ResolveServiceEnvironment): a ${VAR} reference to an inherited
+// variable, or PATH under runtime.path, resolves against it.
+func ResolveEnvironment(config *types.ServiceConfig, serviceDirectoryPath string, daemonEnv []string) ([]ResolvedEnvVar, error) {
+	env, sources, err := envLayers(config, serviceDirectoryPath, daemonEnv)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make([]ResolvedEnvVar, 0, len(env))
+	for _, envVar := range env {
+		name, value, _ := strings.Cut(envVar, "=")
+		source, ok := sources[name]
+		if !ok {
+			source = EnvSourceDaemon
+		}
+		resolved = append(resolved, ResolvedEnvVar{Name: name, Value: value, Source: source})
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].Name < resolved[j].Name })
+	return resolved, nil
+}
+
+// ResolveServiceEnvironment resolves the environment name would launch with
+// if started now: its env_file and service.yaml as they are on disk, over
+// this process's own environment, the one its launches inherit. Asked of the
+// daemon, that's the daemon's environment rather than the CLI's.
+func (m *LocalManager) ResolveServiceEnvironment(ctx context.Context, name string) ([]ResolvedEnvVar, error) {
+	service, err := m.GetServiceCatalogEntry(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err != nil {
+		return nil, fmt.Errorf("load service config for %s: %w", name, err)
+	}
+	return ResolveEnvironment(config, service.DirectoryPath, os.Environ())
+}
+
+// envLayers builds a service's launch environment, each layer overriding the
+// ones before it:
+//
+//  1. daemonEnv, the daemon's own environment, or under clean_env only the
+//     variables pass_env names (see envInherited);
+//  2. the variables eos derives from service.yaml (user:, runtime.path, port);
+//  3. env_file;
+//  4. the inline env: map, in the order it's written.
+//
+// It also returns the layer that set each variable beyond the first; a name
+// missing from sources is inherited unchanged.
+func envLayers(config *types.ServiceConfig, serviceDirectoryPath string, daemonEnv []string) ([]string, map[string]EnvSource, error) {
+	env := envInherited(config, daemonEnv)
+	sources := make(map[string]EnvSource)
+
+	inherited := slices.Clone(env)
+	if config.CleanEnv && !slices.Contains(config.PassEnv, "PATH") {
+		env = append(env, "PATH="+cleanEnvDefaultPath)
+	}
+	env = lmApplyUserEnv(config.User, env)
+	env = lmApplyRuntimePathEnv(config.Runtime.Path, env)
+	env = lmApplyPortEnv(config.Port, env)
+	for _, envVar := range env {
+		if !slices.Contains(inherited, envVar) {
+			name, _, _ := strings.Cut(envVar, "=")
+			sources[name] = EnvSourceEOS
+		}
+	}
+
+	if len(config.EnvFile) > 0 {
+		envFileVars, err := ParseEnvFile(config, serviceDirectoryPath, env)
+		if err != nil {
+			return nil, nil, err
+		}
+		env = lmOverlayEnvVars(env, envFileVars)
+		for _, envVar := range envFileVars {
+			name, _, _ := strings.Cut(envVar, "=")
+			sources[name] = EnvSourceEnvFile
+		}
+	}
+
+	for _, v := range config.Env {
+		value, err := interpolateEnvValue(v.Value, func(name string) (string, bool) {
+			index, value := doesEnvVarAlreadyExist(name+"=", env)
+			return value, index > -1
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("env.%s: %w", v.Name, err)
+		}
+		env = lmOverlayEnvVars(env, []string{v.Name + "=" + value})
+		sources[v.Name] = EnvSourceInline
+	}
+
+	return env, sources, nil
+}
+
+// envInherited returns the part of daemonEnv a service inherits: all of it,
+// or under clean_env only the variables pass_env names. A pass_env name the
+// daemon doesn't have is skipped, not set empty.
+func envInherited(config *types.ServiceConfig, daemonEnv []string) []string {
+	if !config.CleanEnv {
+		return daemonEnv
+	}
+	passed := make([]string, 0, len(config.PassEnv))
+	for _, envVar := range daemonEnv {
+		name, _, _ := strings.Cut(envVar, "=")
+		if slices.Contains(config.PassEnv, name) {
+			passed = append(passed, envVar)
+		}
+	}
+	return passed
+}
+
+// interpolateEnvValue expands ${VAR} and ${VAR:-default} in value using
+// lookup, with the shell's semantics: an unset ${VAR} expands to "", and the
+// default applies when VAR is unset or empty. $$ is a literal $; a $ not
+// followed by { or $ is left as written. The default is taken literally, not
+// expanded again.
+func interpolateEnvValue(value string, lookup func(name string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			resolved, n, err := expandEnvReference(value[i:], lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(resolved)
+			i += n - 1
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// expandEnvReference expands the ${NAME} or ${NAME:-default} reference s
+// starts with, returning its value and how many bytes of s it spans.
+func expandEnvReference(s string, lookup func(name string) (string, bool)) (string, int, error) {
+	end := strings.IndexByte(s, '}')
+	if end == -1 {
+		return "", 0, fmt.Errorf("unterminated ${ in %q", s)
+	}
+	expr := s[2:end]
+	name, fallback, hasFallback := strings.Cut(expr, ":-")
+	if !isEnvName(name) {
+		return "", 0, fmt.Errorf("${%s} is not a variable reference; use ${NAME} or ${NAME:-default}", expr)
+	}
+	resolved, _ := lookup(name)
+	if hasFallback && resolved == "" {
+		resolved = fallback
+	}
+	return resolved, end + 1, nil
+}
+
+// envValidate checks env:, clean_env and pass_env for what can be known
+// before launch: each name is a valid variable name, each env: value's
+// ${...} references parse, and pass_env isn't set without the clean_env it
+// only means something under.
+func envValidate(config *types.ServiceConfig) []error {
+	var errs []error
+	if len(config.PassEnv) > 0 && !config.CleanEnv {
+		errs = append(errs, errors.New("pass_env: has no effect without clean_env: true"))
+	}
+	for _, name := range config.PassEnv {
+		if !isEnvName(name) {
+			errs = append(errs, fmt.Errorf("pass_env: %q is not a valid variable name", name))
+		}
+	}
+	for _, v := range config.Env {
+		if !isEnvName(v.Name) {
+			errs = append(errs, fmt.Errorf("env: %q is not a valid variable name", v.Name))
+			continue
+		}
+		if _, err := interpolateEnvValue(v.Value, func(string) (string, bool) { return "", false }); err != nil {
+			errs = append(errs, fmt.Errorf("env.%s: %w", v.Name, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestInterpolateEnvValue(t *testing.T) {
+	vars := map[string]string{"HOST": "db.internal", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"postgres://${HOST}:5432", "postgres://db.internal:5432"},
+		{"${MISSING}", ""},
+		{"${MISSING:-5432}", "5432"},
+		{"${EMPTY:-set}", "set"},
+		{"${HOST:-unused}", "db.internal"},
+		{"cost: $$5", "cost: $5"},
+		{"$HOST stays", "$HOST stays"},
+		{"trailing $", "trailing $"},
+	}
+	for _, tc := range cases {
+		got, err := interpolateEnvValue(tc.value, lookup)
+		if err != nil || got != tc.want {
+			t.Errorf("interpolateEnvValue(%q) = (%q, %v), want (%q, nil)", tc.value, got, err, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"${HOST", "${}", "${1X}", "${HOST-x}"} {
+		if _, err := interpolateEnvValue(bad, lookup); err == nil {
+			t.Errorf("interpolateEnvValue(%q) err=nil, want an error", bad)
+		}
+	}
+}
+
+func TestResolveEnvironment_layersAndSources(t *testing.T) {
+	serviceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(serviceDir, ".env"), []byte("FROM_FILE=file\nOVERRIDDEN=file\n"), 0o600); err != nil {
+		t.Fatalf("writing .env: %v", err)
+	}
+	t.Setenv("EOS_TEST_DAEMON", "daemon")
+
+	config := &types.ServiceConfig{
+		Port:    8080,
+		EnvFile: types.EnvFileList{".env"},
+		Env: types.ServiceEnv{
+			{Name: "OVERRIDDEN", Value: "inline"},
+			{Name: "URL", Value: "http://localhost:${PORT}/${FROM_FILE}/${EOS_TEST_DAEMON:-unseen}"},
+			// Refers to the entry above it, already interpolated.
+			{Name: "NESTED", Value: "${URL}/x"},
+		},
+	}
+	// Values resolve over the daemon environment passed in, not this
+	// process's own.
+	resolved, err := ResolveEnvironment(config, serviceDir, []string{"EOS_TEST_DAEMON=passed", "OVERRIDDEN=daemon"})
+	if err != nil {
+		t.Fatalf("ResolveEnvironment: %v", err)
+	}
+
+	want := map[string]ResolvedEnvVar{
+		"EOS_TEST_DAEMON": {Name: "EOS_TEST_DAEMON", Value: "passed", Source: EnvSourceDaemon},
+		"PORT":            {Name: "PORT", Value: "8080", Source: EnvSourceEOS},
+		"FROM_FILE":       {Name: "FROM_FILE", Value: "file", Source: EnvSourceEnvFile},
+		"OVERRIDDEN":      {Name: "OVERRIDDEN", Value: "inline", Source: EnvSourceInline},
+		"URL":             {Name: "URL", Value: "http://localhost:8080/file/passed", Source: EnvSourceInline},
+		"NESTED":          {Name: "NESTED", Value: "http://localhost:8080/file/passed/x", Source: EnvSourceInline},
+	}
+	for _, v := range resolved {
+		w, ok := want[v.Name]
+		if !ok {
+			t.Errorf("%+v listed, want only the daemon environment passed in and the service's own layers", v)
+			continue
+		}
+		if v != w {
+			t.Errorf("%s = %+v, want %+v", v.Name, v, w)
+		}
+		delete(want, v.Name)
+	}
+	for name := range want {
+		t.Errorf("%s missing from the resolved environment", name)
+	}
+
+	// A real launch layers the same variables over the daemon's environment.
+	env, err := buildEnvironment(config, serviceDir)
+	if err != nil {
+		t.Fatalf("buildEnvironment: %v", err)
+	}
+	for _, want := range []string{"EOS_TEST_DAEMON=daemon", "URL=http://localhost:8080/file/daemon", "NESTED=http://localhost:8080/file/daemon/x"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("buildEnvironment is missing %s", want)
+		}
+	}
+}
+
+func TestEnvValidate(t *testing.T) {
+	errs := envValidate(&types.ServiceConfig{Env: types.ServiceEnv{
+		{Name: "OK", Value: "${HOME:-/tmp}"},
+		{Name: "BAD-NAME", Value: "x"},
+		{Name: "BAD_REF", Value: "${unterminated"},
+	}})
+	if len(errs) != 2 {
+		t.Fatalf("envValidate errs = %v, want 2", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "BAD-NAME") || !strings.Contains(errs[1].Error(), "env.BAD_REF") {
+		t.Errorf("envValidate errs = %v, want one naming BAD-NAME and one env.BAD_REF", errs)
+	}
+
+	if errs := envValidate(&types.ServiceConfig{PassEnv: []string{"HOME"}}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "clean_env") {
+		t.Errorf("pass_env without clean_env: errs = %v, want one naming clean_env", errs)
+	}
+	if errs := envValidate(&types.ServiceConfig{CleanEnv: true, PassEnv: []string{"HOME", "1BAD"}}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "1BAD") {
+		t.Errorf("invalid pass_env name: errs = %v, want one naming 1BAD", errs)
+	}
+}
+
+func TestResolveEnvironment_cleanEnvPassesOnlyTheAllowlist(t *testing.T) {
+	serviceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(serviceDir, ".env"), []byte("FROM_FILE=file\n"), 0o600); err != nil {
+		t.Fatalf("writing .env: %v", err)
+	}
+	t.Setenv("EOS_TEST_SECRET", "leak")
+	t.Setenv("LANG", "C.UTF-8")
+
+	config := &types.ServiceConfig{CleanEnv: true, PassEnv: []string{"LANG", "EOS_TEST_UNSET"}, EnvFile: types.EnvFileList{".env"}}
+	resolved, err := ResolveEnvironment(config, serviceDir, os.Environ())
+	if err != nil {
+		t.Fatalf("ResolveEnvironment: %v", err)
+	}
+
+	got := make(map[string]ResolvedEnvVar, len(resolved))
+	for _, v := range resolved {
+		got[v.Name] = v
+	}
+	if len(got) != 3 {
+		t.Errorf("resolved = %+v, want only LANG, PATH and FROM_FILE", resolved)
+	}
+	if v := got["LANG"]; v.Value != "C.UTF-8" || v.Source != EnvSourceDaemon {
+		t.Errorf("LANG = %+v, want the daemon's value passed through", v)
+	}
+	if v := got["PATH"]; v.Value != cleanEnvDefaultPath || v.Source != EnvSourceEOS {
+		t.Errorf("PATH = %+v, want the clean_env default set by eos", v)
+	}
+	if _, ok := got["EOS_TEST_SECRET"]; ok {
+		t.Error("EOS_TEST_SECRET reached a clean_env service without being in pass_env")
+	}
+	if _, ok := got["EOS_TEST_UNSET"]; ok {
+		t.Error("a pass_env name the daemon doesn't have was set anyway")
+	}
+
+	env, err := buildEnvironment(config, serviceDir)
+	if err != nil {
+		t.Fatalf("buildEnvironment: %v", err)
+	}
+	if !slices.Contains(env, "LANG=C.UTF-8") || slices.Contains(env, "EOS_TEST_SECRET=leak") {
+		t.Errorf("buildEnvironment = %v, want LANG passed through from the daemon and EOS_TEST_SECRET withheld", env)
+	}
+}
@@ -18,6 +18,10 @@ var (
 	// passes the readiness probe within the timeout. The outgoing instance is
 	// left serving, so the reload is a no-op cutover rather than an outage.
 	ErrReloadNotReady = errors.New("reload aborted: new instance not ready")
+	// ErrCanaryFailed is returned when a canary reload's incoming instance
+	// passed the readiness probe but didn't hold up over the canary window.
+	// It is stopped and the outgoing instance keeps serving.
+	ErrCanaryFailed = errors.New("reload rolled back: canary failed")
 )
 
 const (
@@ -28,6 +32,7 @@ const (
 	CodeAlreadyRunning           = "already_running"
 	CodeServiceNameCaseConflict  = "service_name_case_conflict"
 	CodeReloadNotReady           = "reload_not_ready"
+	CodeCanaryFailed             = "canary_failed"
 )
 
 var errCodeMap = map[string]error{
@@ -38,6 +43,7 @@ var errCodeMap = map[string]error{
 	CodeAlreadyRunning:           ErrAlreadyRunning,
 	CodeServiceNameCaseConflict:  ErrServiceNameCaseConflict,
 	CodeReloadNotReady:           ErrReloadNotReady,
+	CodeCanaryFailed:             ErrCanaryFailed,
 }
 
 // ErrorCode returns a machine-readable code for known sentinel errors, empty string otherwise.
@@ -0,0 +1,278 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+const (
+	// forkingStartTimeout bounds how long a type: forking service's command
+	// may take to fork its main process and exit; systemd's default
+	// TimeoutStartSec.
+	forkingStartTimeout = 90 * time.Second
+	// forkingPIDFileWait bounds how long, once the command has exited, eos
+	// waits for pid_file to name a live main process, for a daemon that
+	// writes it just after its launcher exits.
+	forkingPIDFileWait = 5 * time.Second
+	// forkingPollInterval is how often pid_file is read again within
+	// forkingPIDFileWait, and how often a main process eos can't wait for is
+	// checked for having exited.
+	forkingPollInterval = 100 * time.Millisecond
+)
+
+// forkingValidate reports each invalid type: forking/pid_file setting.
+func forkingValidate(config *types.ServiceConfig) []error {
+	forking := ServiceType(config.Type) == ServiceTypeForking
+	var errs []error
+	switch {
+	case forking && strings.TrimSpace(config.PIDFile) == "":
+		errs = append(errs, errors.New("pid_file: type: forking needs the file its command writes the main process's PID to"))
+	case !forking && config.PIDFile != "":
+		errs = append(errs, errors.New("pid_file: needs type: forking, the only type that reads it"))
+	}
+	if forking && InstanceCount(config) > 1 {
+		errs = append(errs, errors.New("instances: cannot be combined with type: forking, whose instances would share one pid_file"))
+	}
+	return errs
+}
+
+// forkingPIDFilePath resolves config's pid_file against the service
+// directory.
+func forkingPIDFilePath(service *types.ServiceCatalogEntry, config *types.ServiceConfig) string {
+	if filepath.IsAbs(config.PIDFile) {
+		return filepath.Clean(config.PIDFile)
+	}
+	return filepath.Join(service.DirectoryPath, config.PIDFile)
+}
+
+// forkingCapture is captureIdentity for a type: forking launch. It waits for
+// the command to exit successfully, then reads the main process it forked
+// from pid_file. From then on the launch is tracked by the main process's
+// group: that is the pgid returned, the launch's cgroup leaf is renamed after
+// it, and stop, liveness and memory and CPU sampling all follow it, singling
+// out the main process itself wherever kill_mode asks for one (see mainPID).
+// A command that fails, outlasts forkingStartTimeout or leaves no valid PID
+// behind fails the start, and whatever it left in the launch is killed.
+func (m *LocalManager) forkingCapture(service *types.ServiceCatalogEntry, config *types.ServiceConfig, cmd *exec.Cmd) (pgid int, startedAtTicks int64, err error) {
+	launcher := cmd.Process.Pid
+	launcherTicks, err := procutil.StartTime(launcher)
+	if err != nil {
+		cleanPGID, wrapErr := killAndWrap(m.tracker, launcher, err, "reading process start time")
+		_ = cmd.Wait() // reap; nothing else waits for the launcher on this path
+		return cleanPGID, 0, wrapErr
+	}
+	if waitErr := m.forkingAwaitLauncher(cmd); waitErr != nil {
+		m.forkingAbort(service.Name, launcher, 0)
+		return 0, 0, fmt.Errorf("type: forking: %w", waitErr)
+	}
+
+	path := forkingPIDFilePath(service, config)
+	main, mainTicks, err := forkingReadMain(path, launcherTicks)
+	if err != nil {
+		m.forkingAbort(service.Name, launcher, 0)
+		return 0, 0, fmt.Errorf("type: forking: %w", err)
+	}
+	pgid, err = syscall.Getpgid(main)
+	if err != nil {
+		m.forkingAbort(service.Name, launcher, main)
+		return 0, 0, fmt.Errorf("type: forking: reading the process group of main process %d: %w", main, err)
+	}
+
+	// A main process that stayed in the launcher's group is tracked by the
+	// launch's own identity. One that started a group of its own, as a
+	// daemon calling setsid does, is tracked by that group, whose leader
+	// has its own start time; a leader already gone, after a double fork
+	// say, is never compared against (see procutil.IsAliveMatching).
+	startedAtTicks = launcherTicks
+	if pgid != launcher {
+		if startedAtTicks, err = procutil.StartTime(pgid); err != nil {
+			startedAtTicks = mainTicks
+		}
+		if rekeyErr := m.tracker.rekey(service.Name, launcher, pgid); rekeyErr != nil {
+			m.logger.Warn("renaming forked service's cgroup, tracking it by process group instead", "service", service.Name, "pgid", pgid, "error", rekeyErr)
+		}
+	}
+
+	m.forkingMu.Lock()
+	m.forking[pgid] = main
+	m.forkingMu.Unlock()
+	policy := m.stopPolicyFor(service.Name, config, m.shutdownGracePeriod)
+	m.serviceWg.Go(func() {
+		m.forkingWatch(service.Name, pgid, main, policy)
+	})
+	m.logger.Debug("tracking forked main process", "service", service.Name, "pgid", pgid, "main_pid", main, "pid_file", path)
+	return pgid, startedAtTicks, nil
+}
+
+// forkingAbort cleans up a type: forking launch that failed before it was
+// tracked by its main process: it kills what the launch left running, main
+// too when it was read from pid_file, since a main process in a session of
+// its own is out of reach of the launcher's group, and releases the launch's
+// cgroup leaf once it has emptied, or forkingPIDFileWait has passed.
+func (m *LocalManager) forkingAbort(service string, launcher, main int) {
+	_ = m.tracker.signal(launcher, syscall.SIGKILL)
+	if main > 0 {
+		_ = syscall.Kill(main, syscall.SIGKILL)
+	}
+	deadline := time.Now().Add(forkingPIDFileWait)
+	for m.tracker.alive(launcher) && time.Now().Before(deadline) {
+		time.Sleep(forkingPollInterval)
+	}
+	m.tracker.release(service, launcher)
+}
+
+// forkingAwaitLauncher waits for a type: forking launch's command to exit,
+// and errors unless it exited 0 within forkingStartTimeout.
+func (m *LocalManager) forkingAwaitLauncher(cmd *exec.Cmd) error {
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(forkingStartTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		_ = m.tracker.signal(cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		return fmt.Errorf("command did not exit within %s", forkingStartTimeout)
+	}
+	// The daemon's SIGCHLD reaper leaves the command to this Wait (see
+	// launchStart), so ProcessState is nil only where the platform can't
+	// hold it back, and pid_file alone decides.
+	if cmd.ProcessState != nil && !cmd.ProcessState.Success() {
+		return fmt.Errorf("command exited with code %d instead of forking its main process", cmd.ProcessState.ExitCode())
+	}
+	return nil
+}
+
+// forkingReadMain reads the main process's PID from path, waiting up to
+// forkingPIDFileWait for it to name a live process started after the
+// launcher, whose start time is launcherTicks: a PID file left behind by an
+// earlier run names one that has exited, or a recycled PID started earlier.
+func forkingReadMain(path string, launcherTicks int64) (main int, mainTicks int64, err error) {
+	deadline := time.Now().Add(forkingPIDFileWait)
+	for {
+		main, mainTicks, err = forkingCheckPIDFile(path, launcherTicks)
+		if err == nil || time.Now().After(deadline) {
+			return main, mainTicks, err
+		}
+		time.Sleep(forkingPollInterval)
+	}
+}
+
+func forkingCheckPIDFile(path string, launcherTicks int64) (main int, mainTicks int64, err error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- pid_file is user-defined in their service.yaml config
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading pid_file: %w", err)
+	}
+	main, err = strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || main <= 1 {
+		return 0, 0, fmt.Errorf("pid_file %s does not hold a PID: %q", path, strings.TrimSpace(string(data)))
+	}
+	mainTicks, err = procutil.StartTime(main)
+	if err != nil {
+		return 0, 0, fmt.Errorf("pid_file %s names process %d, which is not running", path, main)
+	}
+	if mainTicks < launcherTicks {
+		return 0, 0, fmt.Errorf("pid_file %s names process %d, which started before the service's command", path, main)
+	}
+	return main, mainTicks, nil
+}
+
+// forkingWatch follows a type: forking launch's main process until it exits,
+// recording its exit code for GetServiceExitCode as captureIdentity's reaper
+// does for any other launch. Should m.ctx be canceled first, it stops the
+// launch as cmd.Cancel would any other's, since no exec.Cmd is left to: with
+// policy's signal then SIGKILL after policy.Timeout under a shutdown grace
+// period, and with an immediate SIGKILL, os/exec's own default, without one.
+// It is tracked by serviceWg, so WaitServices covers the main process too,
+// and releases the launch's cgroup leaf once it has emptied.
+func (m *LocalManager) forkingWatch(service string, pgid, main int, policy StopPolicy) {
+	defer func() {
+		m.tracker.release(service, pgid)
+		m.forkingMu.Lock()
+		delete(m.forking, pgid)
+		m.forkingMu.Unlock()
+	}()
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		if m.forkingWaitMain(pgid, main) {
+			return
+		}
+		for m.tracker.alive(pgid) {
+			time.Sleep(forkingPollInterval)
+		}
+	}()
+
+	select {
+	case <-exited:
+		return
+	case <-m.ctx.Done():
+	}
+	if m.shutdownGracePeriod <= 0 {
+		_ = m.tracker.signal(pgid, syscall.SIGKILL)
+		<-exited
+		return
+	}
+	_ = m.shutdownCancel(pgid, policy)
+	timer := time.NewTimer(policy.Timeout)
+	defer timer.Stop()
+	select {
+	case <-exited:
+	case <-timer.C:
+		_ = m.tracker.signal(pgid, syscall.SIGKILL)
+		<-exited
+	}
+}
+
+// forkingWaitMain waits for main and records its exit code under pgid. It
+// reports false when main can't be waited for: it isn't this process's
+// child, because this process isn't the child subreaper (a daemon becomes
+// it at start only with a type: forking service in its catalog, see
+// process.catalogHasForkingService) or a process between them is still
+// alive, or the daemon's SIGCHLD reaper got to it first.
+func (m *LocalManager) forkingWaitMain(pgid, main int) bool {
+	proc, err := os.FindProcess(main)
+	if err != nil {
+		return false
+	}
+	state, err := proc.Wait()
+	if err != nil {
+		return false
+	}
+	m.exitCodesMu.Lock()
+	m.exitCodes[pgid] = state.ExitCode()
+	m.exitCodesMu.Unlock()
+	return true
+}
+
+// forkingMainPID is the main process of pgid's type: forking launch, or 0
+// when it isn't one this manager is following.
+func (m *LocalManager) forkingMainPID(pgid int) int {
+	m.forkingMu.Lock()
+	defer m.forkingMu.Unlock()
+	return m.forking[pgid]
+}
+
+// mainPID is the process kill_mode leader and mixed single out in pgid's
+// launch (see groupTracker.stop): a type: forking launch's main process, the
+// MAINPID a type: notify launch named, or 0 for its leader.
+func (m *LocalManager) mainPID(pgid int) int {
+	if main := m.forkingMainPID(pgid); main != 0 {
+		return main
+	}
+	return m.notifyMainPID(pgid)
+}
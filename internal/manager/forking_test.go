@@ -0,0 +1,173 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestForkingValidate(t *testing.T) {
+	if errs := forkingValidate(&types.ServiceConfig{Type: "forking", PIDFile: "run/app.pid"}); len(errs) != 0 {
+		t.Errorf("forkingValidate(forking, pid_file) = %v, want no errors", errs)
+	}
+	for _, config := range []types.ServiceConfig{
+		{Type: "forking"},
+		{PIDFile: "run/app.pid"},
+		{Type: "notify", PIDFile: "run/app.pid"},
+		{Type: "forking", PIDFile: "run/app.pid", Instances: 2},
+	} {
+		if errs := forkingValidate(&config); len(errs) != 1 {
+			t.Errorf("forkingValidate(%+v) = %v, want one error", config, errs)
+		}
+	}
+}
+
+// TestStartService_Forking launches a command that forks a daemon into a
+// session of its own, writes its PID to pid_file and exits: eos tracks the
+// daemon, not the launcher, and stopping the service stops the daemon.
+func TestStartService_Forking(t *testing.T) {
+	if _, err := exec.LookPath("setsid"); err != nil {
+		t.Skip("setsid not available")
+	}
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	serviceDir := filepath.Join(tempDir, "legacy")
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		t.Fatalf("could not create test directory: %v", err)
+	}
+	config := &types.ServiceConfig{
+		Name:    "legacy",
+		Command: types.ServiceCommand{Shell: "setsid sh -c 'echo $$ > legacy.pid; exec sleep 30' &"},
+		Type:    "forking",
+		PIDFile: "legacy.pid",
+	}
+	yamlData, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal test config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(serviceDir, "service.yaml"), yamlData, 0644); err != nil {
+		t.Fatalf("error occurred during writing the yaml file, got: %v", err)
+	}
+	entry, err := NewServiceCatalogEntry("legacy", serviceDir, "service.yaml")
+	if err != nil {
+		t.Fatalf("NewServiceCatalogEntry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("AddServiceCatalogEntry: %v", err)
+	}
+
+	pgid, err := m.StartService(t.Context(), "legacy")
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+
+	data, err := os.ReadFile(filepath.Join(serviceDir, "legacy.pid"))
+	if err != nil {
+		t.Fatalf("reading pid_file: %v", err)
+	}
+	main, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	if pgid != main {
+		t.Fatalf("StartService tracked pgid %d, want the forked daemon's own group %d", pgid, main)
+	}
+	if got := m.mainPID(pgid); got != main {
+		t.Errorf("mainPID(%d) = %d, want %d", pgid, got, main)
+	}
+	if !m.IsProcessGroupAlive(pgid) {
+		t.Fatal("the forked daemon should be alive once its launcher has exited")
+	}
+	latest, err := m.GetMostRecentProcessHistoryEntry(t.Context(), "legacy")
+	if err != nil || latest.PGID != main {
+		t.Fatalf("latest history row = %+v (%v), want it keyed by the daemon's pgid %d", latest, err, main)
+	}
+
+	if _, err := m.StopService(t.Context(), "legacy", 2*time.Second, 20*time.Millisecond); err != nil {
+		t.Fatalf("StopService: %v", err)
+	}
+	m.WaitServices()
+	if procutil.IsAlive(main) {
+		t.Error("the forked daemon outlived eos stop")
+	}
+}
+
+// TestForkingCheckPIDFile proves a PID file is only trusted when it names a
+// live process started after the launcher.
+func TestForkingCheckPIDFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.pid")
+	selfTicks, err := procutil.StartTime(os.Getpid())
+	if err != nil {
+		t.Fatalf("StartTime: %v", err)
+	}
+
+	if _, _, err := forkingCheckPIDFile(path, selfTicks); err == nil {
+		t.Error("a missing pid_file should be an error")
+	}
+	for _, contents := range []string{"", "not a pid", "1"} {
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, _, err := forkingCheckPIDFile(path, selfTicks); err == nil {
+			t.Errorf("pid_file %q should be an error", contents)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if main, _, err := forkingCheckPIDFile(path, selfTicks); err != nil || main != os.Getpid() {
+		t.Errorf("forkingCheckPIDFile = %d, %v; want this process", main, err)
+	}
+	if _, _, err := forkingCheckPIDFile(path, selfTicks+1); err == nil {
+		t.Error("a process started before the launcher should be refused as a stale PID")
+	}
+}
+
+// TestForkingAbort_killsMainOutsideTheLaunchersGroup proves a failed
+// type: forking launch doesn't leave behind a main process that moved into a
+// session of its own, out of reach of the launcher's group.
+func TestForkingAbort_killsMainOutsideTheLaunchersGroup(t *testing.T) {
+	if _, err := exec.LookPath("setsid"); err != nil {
+		t.Skip("setsid not available")
+	}
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	pidFile := filepath.Join(t.TempDir(), "main.pid")
+	launcher := exec.Command("sh", "-c", "setsid sh -c 'echo $$ > "+pidFile+"; exec sleep 30' & wait")
+	launcher.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := launcher.Start(); err != nil {
+		t.Fatalf("starting launcher: %v", err)
+	}
+	go func() { _ = launcher.Wait() }()
+	main, _, err := forkingReadMain(pidFile, 0)
+	if err != nil {
+		t.Fatalf("forkingReadMain: %v", err)
+	}
+	t.Cleanup(func() { _ = syscall.Kill(main, syscall.SIGKILL) })
+
+	m.forkingAbort("legacy", launcher.Process.Pid, main)
+	deadline := time.Now().Add(5 * time.Second)
+	for procutil.LeaderAlive(main) {
+		if time.Now().After(deadline) {
+			t.Fatalf("main process %d outlived the aborted launch", main)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if procutil.IsAlive(launcher.Process.Pid) {
+		t.Error("the launcher's group outlived the aborted launch")
+	}
+}
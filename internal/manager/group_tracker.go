@@ -0,0 +1,195 @@
+package manager
+
+import (
+	"os"
+	"slices"
+	"syscall"
+
+	"github.com/Elysium-Labs-EU/eos/internal/cgroup"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+)
+
+// groupTracker decides whether a launched instance is alive and delivers
+// signals to it. With a delegated cgroup v2 subtree (see WithCgroups) each
+// launch lives in its own cgroup leaf, found again by PGID, and that leaf is
+// the source of truth: it still holds a child that called setsid(2) and left
+// the process group, which kill(-pgid, ...) would miss entirely. Without
+// one — no cgroup v2, no delegation, or a launch that predates either — every
+// method falls back to the process-group path eos has always used.
+//
+// The zero value is the PGID-only tracker, so a LocalManager built without
+// WithCgroups (tests, the CLI's in-process manager) behaves exactly as
+// before.
+type groupTracker struct {
+	// cgroups is nil when the daemon couldn't resolve a writable eos.slice.
+	cgroups *cgroup.Hierarchy
+}
+
+// launchDir returns the cgroup leaf for pgid, or ok=false when this launch
+// isn't cgroup-tracked.
+func (t groupTracker) launchDir(pgid int) (string, bool) {
+	if t.cgroups == nil || pgid <= 1 {
+		return "", false
+	}
+	return cgroup.Find(*t.cgroups, pgid)
+}
+
+// alive is procutil.IsAlive, answered from the launch's cgroup when it has
+// one.
+func (t groupTracker) alive(pgid int) bool {
+	if dir, ok := t.launchDir(pgid); ok {
+		if populated, err := cgroup.Populated(dir); err == nil {
+			return populated
+		}
+	}
+	return procutil.IsAlive(pgid)
+}
+
+// aliveMatching is procutil.IsAliveMatching, answered from the launch's
+// cgroup when it has one. The start-time comparison is unnecessary there: a
+// recycled PGID belongs to a process outside the leaf, so it can never make
+// the leaf read as populated.
+func (t groupTracker) aliveMatching(pgid int, startedAtTicks int64) bool {
+	if dir, ok := t.launchDir(pgid); ok {
+		if populated, err := cgroup.Populated(dir); err == nil {
+			return populated
+		}
+	}
+	return procutil.IsAliveMatching(pgid, startedAtTicks)
+}
+
+// signal delivers sig to every process of the launch: the whole cgroup leaf
+// when tracked (SIGKILL via cgroup.kill), the process group otherwise. Both
+// paths report a launch with nothing left to signal as syscall.ESRCH.
+func (t groupTracker) signal(pgid int, sig syscall.Signal) error {
+	if dir, ok := t.launchDir(pgid); ok {
+		return cgroup.Signal(dir, sig)
+	}
+	return syscall.Kill(-pgid, sig)
+}
+
+// member reports whether pid belongs to pgid's launch: its cgroup leaf when
+// it has one, its process group otherwise.
+func (t groupTracker) member(pgid, pid int) bool {
+	if pid <= 1 {
+		return false
+	}
+	if dir, ok := t.launchDir(pgid); ok {
+		procs, err := cgroup.Procs(dir)
+		return err == nil && slices.Contains(procs, pid)
+	}
+	got, err := syscall.Getpgid(pid)
+	return err == nil && got == pgid
+}
+
+// rekey renames the cgroup leaf of the launch committed as from after to,
+// the process group a type: forking launch is tracked by once its command
+// has exited (see forkingCapture). The service's exited leaves are pruned
+// first, so a stale leaf can't already hold the name. A launch without a
+// leaf is a no-op.
+func (t groupTracker) rekey(service string, from, to int) error {
+	dir, ok := t.launchDir(from)
+	if !ok || from == to {
+		return nil
+	}
+	cgroup.Prune(*t.cgroups, service)
+	return os.Rename(dir, cgroup.LaunchDir(*t.cgroups, service, to))
+}
+
+// release removes the cgroup leaf of service's launch pgid once that launch
+// has exited (see cgroup.Release). A launch without cgroups is a no-op.
+func (t groupTracker) release(service string, pgid int) {
+	if t.cgroups == nil || pgid <= 1 {
+		return
+	}
+	cgroup.Release(*t.cgroups, service, pgid)
+}
+
+// liveUntracked returns the PGIDs of populated launch leaves under service
+// that none of known accounts for — processes still running in eos.slice
+// whose process_history row is gone or was never written (e.g. the daemon
+// died between cmd.Start and recording the launch).
+func (t groupTracker) liveUntracked(service string, known map[int]bool) []int {
+	if t.cgroups == nil {
+		return nil
+	}
+	var pgids []int
+	for _, pgid := range cgroup.LiveLaunches(*t.cgroups, service) {
+		if !known[pgid] {
+			pgids = append(pgids, pgid)
+		}
+	}
+	return pgids
+}
+
+// stage prepares a cgroup leaf for a launch about to start and points attr
+// at it, pruning the service's exited leaves first. It returns nil when
+// cgroups aren't in use. An error leaves attr untouched: the caller logs it
+// and launches with PGID tracking only, rather than refusing to start a
+// service over a supervision upgrade.
+func (t groupTracker) stage(service string, attr *syscall.SysProcAttr) (*cgroup.Staged, error) {
+	if t.cgroups == nil {
+		return nil, nil
+	}
+	cgroup.Prune(*t.cgroups, service)
+	staged, err := cgroup.Stage(*t.cgroups, service)
+	if err != nil {
+		return nil, err
+	}
+	cgroup.SetCgroupFD(attr, int(staged.Dir.Fd())) //nolint:gosec // G115: an open fd always fits in int
+	return &staged, nil
+}
+
+// commit names a started launch's staged leaf after its PGID (see
+// cgroup.Commit). A nil staged is a no-op.
+func (t groupTracker) commit(service string, pgid int, staged *cgroup.Staged) error {
+	if staged == nil {
+		return nil
+	}
+	_, err := cgroup.Commit(*t.cgroups, service, pgid, *staged)
+	return err
+}
+
+// abort releases a staged leaf whose launch failed to start. A nil staged is
+// a no-op.
+func (t groupTracker) abort(staged *cgroup.Staged) {
+	if staged != nil {
+		cgroup.Abort(*staged)
+	}
+}
+
+// controllers returns the cgroup controllers available to a launch staged as
+// staged (see cgroup.Hierarchy.Controllers): none when it wasn't staged, so
+// planLimits routes every limit to its rlimit fallback.
+func (t groupTracker) controllers(staged *cgroup.Staged) []string {
+	if staged == nil {
+		return nil
+	}
+	return t.cgroups.Controllers
+}
+
+// writeLimits writes l into a staged leaf before the launch is cloned into
+// it. A nil staged is a no-op.
+func (t groupTracker) writeLimits(staged *cgroup.Staged, l cgroup.Limits) error {
+	if staged == nil {
+		return nil
+	}
+	return cgroup.WriteLimits(staged.Path, l)
+}
+
+// oomKills returns how many of pgid's processes the kernel OOM-killed for
+// breaching the launch's memory.max. ok is false when the launch isn't
+// cgroup-tracked or its leaf has no memory controller — there's no reliable
+// OOM signal on the PGID path, where RLIMIT_AS makes allocations fail
+// instead.
+func (t groupTracker) oomKills(pgid int) (int, bool) {
+	dir, ok := t.launchDir(pgid)
+	if !ok {
+		return 0, false
+	}
+	kills, err := cgroup.OOMKills(dir)
+	if err != nil {
+		return 0, false
+	}
+	return kills, true
+}
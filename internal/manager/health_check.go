@@ -0,0 +1,361 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+const (
+	// HealthCheckDefaultInterval is how often a health_check with no interval
+	// of its own runs.
+	HealthCheckDefaultInterval = 10 * time.Second
+	// HealthCheckDefaultTimeout bounds one run of a health_check with no
+	// timeout of its own.
+	HealthCheckDefaultTimeout = 2 * time.Second
+	// HealthCheckDefaultFailureThreshold is how many failed liveness checks
+	// in a row restart a service whose health_check sets no threshold.
+	HealthCheckDefaultFailureThreshold = 3
+	// healthPortTimeout bounds the TCP dial that stands in for a readiness
+	// check when a service has a port but no health_check.
+	healthPortTimeout = 500 * time.Millisecond
+	// healthBodyLimit caps how much of an HTTP check's response body is read
+	// when looking for body_contains.
+	healthBodyLimit = 64 << 10
+)
+
+// HealthProbe is one resolved check. Check enforces the check's own timeout
+// and returns why it failed, or nil when it passed.
+type HealthProbe interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheck is a service's resolved health_check: its liveness and
+// readiness probes and when the health monitor runs them.
+type HealthCheck struct {
+	Liveness         HealthProbe
+	Readiness        HealthProbe
+	Interval         time.Duration
+	Timeout          time.Duration
+	StartPeriod      time.Duration
+	FailureThreshold int
+}
+
+// healthTimings parses check's interval, timeout, start period and failure
+// threshold, applying the defaults.
+func healthTimings(check *types.ServiceHealthCheck) (HealthCheck, []error) {
+	resolved := HealthCheck{
+		Interval:         HealthCheckDefaultInterval,
+		Timeout:          HealthCheckDefaultTimeout,
+		FailureThreshold: HealthCheckDefaultFailureThreshold,
+	}
+	var errs []error
+	for _, field := range []struct {
+		target *time.Duration
+		name   string
+		value  string
+	}{
+		{&resolved.Interval, "interval", check.Interval},
+		{&resolved.Timeout, "timeout", check.Timeout},
+		{&resolved.StartPeriod, "start_period", check.StartPeriod},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("health_check.%s: invalid duration %q: %w", field.name, field.value, err))
+		case d <= 0:
+			errs = append(errs, fmt.Errorf("health_check.%s: invalid duration %q: must be positive", field.name, field.value))
+		default:
+			*field.target = d
+		}
+	}
+	switch {
+	case check.FailureThreshold < 0:
+		errs = append(errs, fmt.Errorf("health_check.failure_threshold: must not be negative, got %d", check.FailureThreshold))
+	case check.FailureThreshold > 0:
+		resolved.FailureThreshold = check.FailureThreshold
+	}
+	return resolved, errs
+}
+
+// healthValidateProbe checks probe sets exactly one of http, tcp and exec,
+// each with what it needs; field names it in errors.
+func healthValidateProbe(field string, probe types.HealthCheckProbe, servicePort int) []error {
+	set := 0
+	var errs []error
+	if probe.HTTP != nil {
+		set++
+		if probe.HTTP.Port == 0 && servicePort == 0 {
+			errs = append(errs, fmt.Errorf("%s.http: a port is required when the service has none", field))
+		}
+		if _, _, err := healthParseStatus(probe.HTTP.Status); err != nil {
+			errs = append(errs, fmt.Errorf("%s.http.status: %w", field, err))
+		}
+		if probe.HTTP.Path != "" && !strings.HasPrefix(probe.HTTP.Path, "/") {
+			errs = append(errs, fmt.Errorf("%s.http.path: %q must start with /", field, probe.HTTP.Path))
+		}
+	}
+	if probe.TCP != nil {
+		set++
+		if probe.TCP.Port == 0 && servicePort == 0 {
+			errs = append(errs, fmt.Errorf("%s.tcp: a port is required when the service has none", field))
+		}
+	}
+	if probe.Exec != nil {
+		set++
+		if probe.Exec.IsZero() || (probe.Exec.IsExec() && probe.Exec.Argv[0] == "") {
+			errs = append(errs, fmt.Errorf("%s.exec: a command is required", field))
+		}
+	}
+	if set != 1 {
+		errs = append(errs, fmt.Errorf("%s: exactly one of http, tcp or exec is required", field))
+	}
+	return errs
+}
+
+// healthValidate reports each invalid health_check setting.
+func healthValidate(config *types.ServiceConfig) []error {
+	if config.HealthCheck == nil {
+		return nil
+	}
+	_, errs := healthTimings(config.HealthCheck)
+	errs = append(errs, healthValidateProbe("health_check", config.HealthCheck.Liveness(), config.Port)...)
+	if config.HealthCheck.Readiness != nil {
+		errs = append(errs, healthValidateProbe("health_check.readiness", *config.HealthCheck.Readiness, config.Port)...)
+	}
+	return errs
+}
+
+// healthParseStatus parses an http check's status: one code, or an
+// inclusive range of them. Empty is 200-399.
+func healthParseStatus(status string) (low, high int, err error) {
+	if strings.TrimSpace(status) == "" {
+		return 200, 399, nil
+	}
+	lowText, highText, isRange := strings.Cut(status, "-")
+	if !isRange {
+		highText = lowText
+	}
+	low, lowErr := strconv.Atoi(strings.TrimSpace(lowText))
+	high, highErr := strconv.Atoi(strings.TrimSpace(highText))
+	if lowErr != nil || highErr != nil || low < 100 || high > 599 || low > high {
+		return 0, 0, fmt.Errorf("%q is not a status code or a range like 200-299", status)
+	}
+	return low, high, nil
+}
+
+// HealthCheckFor resolves config's health_check for service, or returns nil
+// when it has none. Exec checks get the service's environment and user, as
+// the service itself would.
+func (m *LocalManager) HealthCheckFor(service *types.ServiceCatalogEntry, config *types.ServiceConfig) (*HealthCheck, error) {
+	if config.HealthCheck == nil {
+		return nil, nil
+	}
+	if errs := healthValidate(config); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	check, _ := healthTimings(config.HealthCheck)
+	liveness, err := m.healthProbeFor(service, config, config.HealthCheck.Liveness(), check.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	check.Liveness, check.Readiness = liveness, liveness
+	if config.HealthCheck.Readiness != nil {
+		if check.Readiness, err = m.healthProbeFor(service, config, *config.HealthCheck.Readiness, check.Timeout); err != nil {
+			return nil, err
+		}
+	}
+	return &check, nil
+}
+
+// ReadinessProbeFor returns the probe eos reload's cutover gates on: the
+// health_check's readiness probe, a TCP dial to the service's port when it
+// has no health_check, or nil (liveness alone) when it has neither. A type:
+// notify service skips the port dial, READY=1 taking its place (see
+// notifyReadiness).
+func (m *LocalManager) ReadinessProbeFor(service *types.ServiceCatalogEntry, config *types.ServiceConfig) (HealthProbe, error) {
+	check, err := m.HealthCheckFor(service, config)
+	switch {
+	case err != nil:
+		return nil, err
+	case check != nil:
+		return check.Readiness, nil
+	case config.Port != 0 && ServiceType(config.Type) != ServiceTypeNotify:
+		return tcpProbe{port: config.Port, timeout: healthPortTimeout}, nil
+	}
+	return nil, nil
+}
+
+func (m *LocalManager) healthProbeFor(service *types.ServiceCatalogEntry, config *types.ServiceConfig, probe types.HealthCheckProbe, timeout time.Duration) (HealthProbe, error) {
+	switch {
+	case probe.HTTP != nil:
+		low, high, _ := healthParseStatus(probe.HTTP.Status)
+		port := probe.HTTP.Port
+		if port == 0 {
+			port = config.Port
+		}
+		path := probe.HTTP.Path
+		if path == "" {
+			path = "/"
+		}
+		return httpProbe{
+			url:          fmt.Sprintf("http://localhost:%d%s", port, path),
+			headers:      probe.HTTP.Headers,
+			bodyContains: probe.HTTP.BodyContains,
+			statusLow:    low,
+			statusHigh:   high,
+			timeout:      timeout,
+		}, nil
+	case probe.TCP != nil:
+		port := probe.TCP.Port
+		if port == 0 {
+			port = config.Port
+		}
+		return tcpProbe{port: port, timeout: timeout}, nil
+	default:
+		env, err := buildEnvironment(config, service.DirectoryPath)
+		if err != nil {
+			return nil, fmt.Errorf("health_check: building environment: %w", err)
+		}
+		cred, err := m.serviceCredential(config)
+		if err != nil {
+			return nil, fmt.Errorf("health_check: resolving user/group: %w", err)
+		}
+		return execProbe{m: m, command: *probe.Exec, dir: service.DirectoryPath, env: env, cred: cred, timeout: timeout}, nil
+	}
+}
+
+type tcpProbe struct {
+	port    int
+	timeout time.Duration
+}
+
+func (p tcpProbe) Check(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("localhost:%d", p.port))
+	if err != nil {
+		return fmt.Errorf("port %d: %w", p.port, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+type httpProbe struct {
+	headers      map[string]string
+	url          string
+	bodyContains string
+	statusLow    int
+	statusHigh   int
+	timeout      time.Duration
+}
+
+func (p httpProbe) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", p.url, err)
+	}
+	for name, value := range p.headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	// A redirect is an answer in its own right: following it could take
+	// the check off localhost.
+	client := http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", p.url, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body, nothing to recover on close
+	if resp.StatusCode < p.statusLow || resp.StatusCode > p.statusHigh {
+		return fmt.Errorf("GET %s: status %d, want %d-%d", p.url, resp.StatusCode, p.statusLow, p.statusHigh)
+	}
+	if p.bodyContains == "" {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, healthBodyLimit))
+	if err != nil {
+		return fmt.Errorf("GET %s: reading body: %w", p.url, err)
+	}
+	if !bytes.Contains(body, []byte(p.bodyContains)) {
+		return fmt.Errorf("GET %s: body does not contain %q", p.url, p.bodyContains)
+	}
+	return nil
+}
+
+type execProbe struct {
+	m       *LocalManager
+	cred    *launchCredential
+	command types.ServiceCommand
+	dir     string
+	env     []string
+	timeout time.Duration
+}
+
+// Check runs the command in its own process group, killed as a whole on
+// timeout, and fails on a non-zero exit with the last line it printed.
+func (p execProbe) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	cmd := p.m.commandFor(ctx, p.command, p.env)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if p.cred != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: p.cred.uid, Gid: p.cred.gid, Groups: p.cred.groups}
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+	cmd.Dir = p.dir
+	cmd.Env = p.env
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	// Held from the daemon's SIGCHLD reaper so Wait always reads the
+	// check's exit status (see procutil.StartHeld).
+	if err := procutil.StartHeld(p.m.held, cmd); err != nil {
+		return fmt.Errorf("%s: %w", p.command.String(), err)
+	}
+	defer procutil.ReleaseHeld(p.m.held, cmd.Process.Pid)
+	err := cmd.Wait()
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s: timed out after %s", p.command.String(), p.timeout)
+	case errors.Is(err, syscall.ECHILD):
+		// Only where the platform can't hold the check back does the
+		// daemon's reaper take its exit status first. A check that may have
+		// failed doesn't count as one that passed.
+		return fmt.Errorf("%s: exit status lost to the daemon's reaper", p.command.String())
+	case err != nil:
+		if last := healthLastLine(output.String()); last != "" {
+			return fmt.Errorf("%s: %w: %s", p.command.String(), err, last)
+		}
+		return fmt.Errorf("%s: %w", p.command.String(), err)
+	}
+	return nil
+}
+
+// healthLastLine returns the last non-blank line of output.
+func healthLastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
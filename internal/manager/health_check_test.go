@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestHealthParseStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		wantLow  int
+		wantHigh int
+		wantErr  bool
+	}{
+		{status: "", wantLow: 200, wantHigh: 399},
+		{status: "204", wantLow: 204, wantHigh: 204},
+		{status: "200-299", wantLow: 200, wantHigh: 299},
+		{status: "299-200", wantErr: true},
+		{status: "ok", wantErr: true},
+		{status: "99", wantErr: true},
+	}
+	for _, tt := range tests {
+		low, high, err := healthParseStatus(tt.status)
+		if (err != nil) != tt.wantErr || low != tt.wantLow || high != tt.wantHigh {
+			t.Errorf("healthParseStatus(%q) = %d, %d, %v; want %d, %d, error %v", tt.status, low, high, err, tt.wantLow, tt.wantHigh, tt.wantErr)
+		}
+	}
+}
+
+func TestHealthValidate(t *testing.T) {
+	errs := healthValidate(&types.ServiceConfig{
+		HealthCheck: &types.ServiceHealthCheck{
+			HTTP:             &types.HTTPHealthCheck{Path: "healthz", Status: "2xx"},
+			TCP:              &types.TCPHealthCheck{Port: 8080},
+			Readiness:        &types.HealthCheckProbe{},
+			Interval:         "often",
+			FailureThreshold: -1,
+		},
+	})
+	want := []string{
+		"health_check.interval:",
+		"health_check.failure_threshold:",
+		"health_check.http: a port is required",
+		"health_check.http.status:",
+		"health_check.http.path:",
+		"health_check: exactly one of",
+		"health_check.readiness: exactly one of",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("healthValidate returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(errs[i].Error(), prefix) {
+			t.Errorf("error %d = %q, want it to start with %q", i, errs[i], prefix)
+		}
+	}
+
+	if errs := healthValidate(&types.ServiceConfig{Port: 3000, HealthCheck: &types.ServiceHealthCheck{TCP: &types.TCPHealthCheck{}}}); len(errs) != 0 {
+		t.Errorf("a tcp check on the service's port: %v, want no errors", errs)
+	}
+}
+
+func TestHealthCheckFor_Probes(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+	service := &types.ServiceCatalogEntry{Name: "checked", DirectoryPath: t.TempDir()}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || r.Header.Get("X-Probe") != "eos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+	_, portText, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+
+	tests := []struct {
+		check   *types.ServiceHealthCheck
+		name    string
+		wantErr string
+	}{
+		{name: "http passes", check: &types.ServiceHealthCheck{HTTP: &types.HTTPHealthCheck{Path: "/healthz", Headers: map[string]string{"X-Probe": "eos"}, BodyContains: `"ok"`}}},
+		{name: "http wrong status", check: &types.ServiceHealthCheck{HTTP: &types.HTTPHealthCheck{Path: "/missing"}}, wantErr: "status 404"},
+		{name: "http body mismatch", check: &types.ServiceHealthCheck{HTTP: &types.HTTPHealthCheck{Path: "/healthz", Headers: map[string]string{"X-Probe": "eos"}, BodyContains: "ready"}}, wantErr: "body does not contain"},
+		{name: "tcp passes", check: &types.ServiceHealthCheck{TCP: &types.TCPHealthCheck{}}},
+		{name: "exec passes", check: &types.ServiceHealthCheck{Exec: &types.ServiceCommand{Argv: []string{"true"}}}},
+		{name: "exec fails with its last line", check: &types.ServiceHealthCheck{Exec: &types.ServiceCommand{Shell: "echo starting; echo 'db down' >&2; exit 1"}}, wantErr: "db down"},
+		{name: "exec times out", check: &types.ServiceHealthCheck{Exec: &types.ServiceCommand{Argv: []string{"sleep", "30"}}, Timeout: "200ms"}, wantErr: "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := m.HealthCheckFor(service, &types.ServiceConfig{Name: service.Name, Port: port, HealthCheck: tt.check})
+			if err != nil {
+				t.Fatalf("HealthCheckFor: %v", err)
+			}
+			err = check.Liveness.Check(t.Context())
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Check = %v, want a pass", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Check = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestHealthCheckFor_ExecFailureSurvivesTheDaemonReaper runs a failing exec
+// check while a daemon-style SIGCHLD reaper drains children in a loop: the
+// check is held back from it, so it never passes for a lost exit status.
+func TestHealthCheckFor_ExecFailureSurvivesTheDaemonReaper(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	held := procutil.NewHeldChildren()
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}), WithHeldChildren(held))
+	service := &types.ServiceCatalogEntry{Name: "checked", DirectoryPath: t.TempDir()}
+	check, err := m.HealthCheckFor(service, &types.ServiceConfig{Name: service.Name, HealthCheck: &types.ServiceHealthCheck{Exec: &types.ServiceCommand{Shell: "exit 1"}}})
+	if err != nil {
+		t.Fatalf("HealthCheckFor: %v", err)
+	}
+	startTestReaper(t, held)
+
+	for range 300 {
+		if err := check.Liveness.Check(t.Context()); err == nil || !strings.Contains(err.Error(), "exit status 1") {
+			t.Fatalf("Check = %v, want the command's failure", err)
+		}
+	}
+}
+
+func TestReadinessProbeFor(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+	service := &types.ServiceCatalogEntry{Name: "ready", DirectoryPath: t.TempDir()}
+
+	if probe, err := m.ReadinessProbeFor(service, &types.ServiceConfig{}); probe != nil || err != nil {
+		t.Errorf("no port, no health_check = %v, %v; want no probe", probe, err)
+	}
+	if probe, err := m.ReadinessProbeFor(service, &types.ServiceConfig{Port: 3000}); err != nil || probe != (tcpProbe{port: 3000, timeout: healthPortTimeout}) {
+		t.Errorf("port only = %#v, %v; want a dial to the port", probe, err)
+	}
+	probe, err := m.ReadinessProbeFor(service, &types.ServiceConfig{
+		Port: 3000,
+		HealthCheck: &types.ServiceHealthCheck{
+			HTTP:      &types.HTTPHealthCheck{},
+			Readiness: &types.HealthCheckProbe{TCP: &types.TCPHealthCheck{Port: 3001}},
+		},
+	})
+	if err != nil || probe != (tcpProbe{port: 3001, timeout: HealthCheckDefaultTimeout}) {
+		t.Errorf("readiness = %#v, %v; want the readiness check, not liveness", probe, err)
+	}
+}
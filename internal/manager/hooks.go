@@ -0,0 +1,318 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/logutil"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// HookDefaultTimeout bounds a lifecycle hook whose config sets no timeout of
+// its own. Long enough for a typical migration; a hook that needs more says
+// so explicitly.
+const HookDefaultTimeout = 60 * time.Second
+
+// hookOutputDrain bounds how long runHook waits, once the hook has exited, for
+// its output to finish forwarding. Only a process that escaped the hook's
+// process group (setsid) while still holding its stdout can take longer; its
+// pipe is closed rather than waited out.
+const hookOutputDrain = 2 * time.Second
+
+// Hook names, as they appear under hooks: in service.yaml and in the "hook"
+// field of the log lines a hook writes.
+const (
+	hookPreStart  = "pre_start"
+	hookPostStart = "post_start"
+	hookPreStop   = "pre_stop"
+	hookPostStop  = "post_stop"
+)
+
+// hookFor returns the hook configured for phase, or nil.
+func hookFor(hooks types.ServiceHooks, phase string) *types.ServiceHook {
+	switch phase {
+	case hookPreStart:
+		return hooks.PreStart
+	case hookPostStart:
+		return hooks.PostStart
+	case hookPreStop:
+		return hooks.PreStop
+	case hookPostStop:
+		return hooks.PostStop
+	}
+	return nil
+}
+
+// ParseHookTimeout resolves a hook's configured timeout to a duration, falling
+// back to HookDefaultTimeout when empty.
+func ParseHookTimeout(timeout string) (time.Duration, error) {
+	if strings.TrimSpace(timeout) == "" {
+		return HookDefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", timeout)
+	}
+	return d, nil
+}
+
+// hookValidate checks each configured hook has a command and a timeout that
+// parses.
+func hookValidate(hooks types.ServiceHooks) []error {
+	var errs []error
+	for _, phase := range []string{hookPreStart, hookPostStart, hookPreStop, hookPostStop} {
+		hook := hookFor(hooks, phase)
+		if hook == nil {
+			continue
+		}
+		if hook.Command.IsZero() || (hook.Command.IsExec() && hook.Command.Argv[0] == "") {
+			errs = append(errs, fmt.Errorf("hooks.%s: a command is required", phase))
+		}
+		if _, err := ParseHookTimeout(hook.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("hooks.%s: %w", phase, err))
+		}
+	}
+	return errs
+}
+
+// runHook runs service's phase hook, if config has one, and waits for it to
+// finish. The hook gets what the service itself would: its directory, its
+// environment (see buildEnvironment) and its user:/group:. It runs in its own
+// process group, SIGKILLed on timeout, and anything it leaves behind in that
+// group is killed once it exits: a hook runs to completion, it doesn't start
+// daemons. Its stdout and stderr go to the service's two log files, each line
+// tagged source=hook with the hook's name and process group.
+//
+// pgid and startedAtTicks identify the hook's own process once it started
+// (0 when there is no hook or it never started), for a caller recording a
+// failed pre_start as a process_history row. A non-zero exit, a timeout or a
+// failure to start at all is an error naming the hook.
+func (m *LocalManager) runHook(service *types.ServiceCatalogEntry, config *types.ServiceConfig, phase string) (pgid int, startedAtTicks int64, err error) {
+	hook := hookFor(config.Hooks, phase)
+	if hook == nil {
+		return 0, 0, nil
+	}
+	timeout, err := ParseHookTimeout(hook.Timeout)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s hook: %w", phase, err)
+	}
+	return m.runHookCommand(service, config, phase, hook.Command, timeout)
+}
+
+// runHookCommand is runHook once the hook is resolved: it runs command as
+// phase in service's directory and waits up to timeout for it. runBuild
+// shares it, with the directory pointed at the release being built.
+func (m *LocalManager) runHookCommand(service *types.ServiceCatalogEntry, config *types.ServiceConfig, phase string, command types.ServiceCommand, timeout time.Duration) (pgid int, startedAtTicks int64, err error) {
+	env, err := buildEnvironment(config, service.DirectoryPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s hook: building environment: %w", phase, err)
+	}
+	cred, err := m.serviceCredential(config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s hook: resolving user/group: %w", phase, err)
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, timeout)
+	defer cancel()
+	cmd := m.commandFor(ctx, command, env)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if cred != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: cred.uid, Gid: cred.gid, Groups: cred.groups}
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.Dir = service.DirectoryPath
+	cmd.Env = env
+
+	out, err := m.hookOpenOutput(service.Name, config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s hook: %w", phase, err)
+	}
+	defer out.release(m, service.Name)
+	cmd.Stdout = out.writeLog
+	cmd.Stderr = out.writeErr
+
+	m.logger.Debug("running hook", "service", service.Name, "hook", phase, "cmd", command.String())
+	// Held from the daemon's SIGCHLD reaper so the Wait below always reads
+	// the hook's exit status (see procutil.StartHeld).
+	if startErr := procutil.StartHeld(m.held, cmd); startErr != nil {
+		return 0, 0, fmt.Errorf("%s hook: starting: %w", phase, startErr)
+	}
+	pgid = cmd.Process.Pid
+	defer procutil.ReleaseHeld(m.held, pgid)
+	// Read before Wait reaps the leader, while its /proc entry still exists.
+	startedAtTicks, _ = procutil.StartTime(pgid)
+	out.forward(service.Name, phase, pgid)
+
+	// Kill whatever the hook left running in its group before reaping its
+	// leader: until then the leader is a zombie holding the PGID, so it
+	// can't have been reused by an unrelated group. Where the leader can't
+	// be awaited unreaped, the group is left alone rather than risk
+	// signaling a reused PGID.
+	if procutil.AwaitExit(pgid) == nil {
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+	waitErr := cmd.Wait()
+	out.drain()
+
+	switch {
+	case waitErr != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return pgid, startedAtTicks, fmt.Errorf("%s hook timed out after %s", phase, timeout)
+	case errors.Is(waitErr, syscall.ECHILD):
+		// Only where the platform can't hold the hook back does the daemon's
+		// reaper take its exit status first. A hook that may have failed
+		// doesn't count as one that passed.
+		return pgid, startedAtTicks, fmt.Errorf("%s hook: exit status lost to the daemon's reaper", phase)
+	case waitErr != nil:
+		return pgid, startedAtTicks, fmt.Errorf("%s hook failed: %w", phase, waitErr)
+	}
+	return pgid, startedAtTicks, nil
+}
+
+// runHookLogged runs a hook whose failure mustn't stop what it's attached to
+// (post_start, pre_stop, post_stop), logging the failure instead.
+func (m *LocalManager) runHookLogged(service *types.ServiceCatalogEntry, config *types.ServiceConfig, phase string) {
+	if _, _, err := m.runHook(service, config, phase); err != nil {
+		m.logger.Warn("hook failed", "service", service.Name, "hook", phase, "error", err)
+	}
+}
+
+// recordPreStartFailure records a failed pre_start as the service's newest
+// process_history row — state Failed, the hook's error, and the hook's own
+// process group as its PGID, which is also what its log lines are tagged
+// with — so eos status shows why the service isn't running. A hook that never
+// started has no PGID to key a row on, and is only reported to the caller.
+// registerInstance creates the service_instances row the history row
+// belongs to, for a service that wasn't running before. DB failures are
+// logged: the caller's error is the hook's.
+func (m *LocalManager) recordPreStartFailure(name string, pgid int, startedAtTicks int64, hookErr error, registerInstance bool) {
+	if pgid == 0 {
+		return
+	}
+	if registerInstance {
+		if err := m.db.RegisterServiceInstance(m.ctx, name); err != nil {
+			m.logger.Error("recording pre_start failure", "service", name, "error", err)
+			return
+		}
+	}
+	if _, err := m.db.RegisterProcessHistoryEntry(m.ctx, pgid, startedAtTicks, name, types.ProcessStateFailed); err != nil {
+		m.logger.Error("recording pre_start failure", "service", name, "pgid", pgid, "error", err)
+		return
+	}
+	if err := m.db.UpdateProcessHistoryEntry(m.ctx, pgid, database.ProcessHistoryUpdate{
+		Error:     new(hookErr.Error()),
+		StoppedAt: new(time.Now()),
+	}); err != nil {
+		m.logger.Error("recording pre_start failure", "service", name, "pgid", pgid, "error", err)
+	}
+}
+
+// stopHooksApply reports whether a stop of name runs config's pre_stop and
+// post_stop: it has either hook, and something of the service is alive to
+// stop.
+func (m *LocalManager) stopHooksApply(name string, config *types.ServiceConfig) bool {
+	if config.Hooks.PreStop == nil && config.Hooks.PostStop == nil {
+		return false
+	}
+	history, err := m.db.GetProcessHistoryEntriesByServiceName(m.ctx, name)
+	return err == nil && livePGIDInHistory(history, m.tracker) != 0
+}
+
+// hookOutput is the pair of pipes a hook writes its stdout and stderr to, and
+// the service log writers they're forwarded into.
+type hookOutput struct {
+	logFile      *RotatingFileWriter
+	errorLogFile *RotatingFileWriter
+	readLog      *os.File
+	writeLog     *os.File
+	readErr      *os.File
+	writeErr     *os.File
+	wg           sync.WaitGroup
+}
+
+// hookOpenOutput acquires the service's two log writers and creates the
+// hook's stdout/stderr pipes, releasing whatever it already took on failure.
+func (m *LocalManager) hookOpenOutput(name string, config *types.ServiceConfig) (*hookOutput, error) {
+	logFile, errorLogFile, err := m.prepareLogFiles(name, config)
+	if err != nil {
+		return nil, fmt.Errorf("preparing log files: %w", err)
+	}
+	out := &hookOutput{logFile: logFile, errorLogFile: errorLogFile}
+	if out.readLog, out.writeLog, err = newPipeForStd(); err != nil {
+		out.release(m, name)
+		return nil, err
+	}
+	if out.readErr, out.writeErr, err = newPipeForStd(); err != nil {
+		out.release(m, name)
+		return nil, err
+	}
+	return out, nil
+}
+
+// forward closes the write ends handed to the started hook and forwards each
+// line it writes into the service's logs.
+func (o *hookOutput) forward(name, phase string, pgid int) {
+	_ = o.writeLog.Close()
+	_ = o.writeErr.Close()
+	streams := []struct {
+		r      *os.File
+		logger *slog.Logger
+	}{
+		{o.readLog, logutil.NewJSONLogger(o.logFile, false)},
+		{o.readErr, logutil.NewJSONLogger(o.errorLogFile, false)},
+	}
+	for _, s := range streams {
+		o.wg.Go(func() {
+			scanner := bufio.NewScanner(s.r)
+			for scanner.Scan() {
+				s.logger.Info(scanner.Text(), "service", name, "pgid", pgid, "source", logutil.HookSource, "hook", phase)
+			}
+		})
+	}
+}
+
+// drain waits up to hookOutputDrain for forwarding to reach EOF, then closes
+// the read ends regardless.
+func (o *hookOutput) drain() {
+	done := make(chan struct{})
+	go func() {
+		o.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(hookOutputDrain):
+	}
+	_ = o.readLog.Close()
+	_ = o.readErr.Close()
+	<-done
+}
+
+// release closes any pipe ends still open and releases both log writers.
+func (o *hookOutput) release(m *LocalManager, name string) {
+	for _, f := range []*os.File{o.readLog, o.writeLog, o.readErr, o.writeErr} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	if err := m.releaseServiceLogWriter(name, false); err != nil {
+		m.logger.Error("releasing log file", "service", name, "error", err)
+	}
+	if err := m.releaseServiceLogWriter(name, true); err != nil {
+		m.logger.Error("releasing error log file", "service", name, "error", err)
+	}
+}
@@ -0,0 +1,218 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestParseHookTimeout(t *testing.T) {
+	if d, err := ParseHookTimeout(""); err != nil || d != HookDefaultTimeout {
+		t.Errorf("ParseHookTimeout(\"\") = %v, %v; want the default", d, err)
+	}
+	if d, err := ParseHookTimeout("90s"); err != nil || d != 90*time.Second {
+		t.Errorf("ParseHookTimeout(90s) = %v, %v", d, err)
+	}
+	for _, bad := range []string{"soon", "0s", "-5s"} {
+		if _, err := ParseHookTimeout(bad); err == nil {
+			t.Errorf("ParseHookTimeout(%q) should fail", bad)
+		}
+	}
+}
+
+func TestHookValidate(t *testing.T) {
+	hooks := types.ServiceHooks{
+		PreStart:  &types.ServiceHook{Command: types.ServiceCommand{Shell: "npm run migrate"}, Timeout: "5m"},
+		PostStart: &types.ServiceHook{},
+		PreStop:   &types.ServiceHook{Command: types.ServiceCommand{Argv: []string{""}}},
+		PostStop:  &types.ServiceHook{Command: types.ServiceCommand{Shell: "true"}, Timeout: "later"},
+	}
+	errs := hookValidate(hooks)
+	if len(errs) != 3 {
+		t.Fatalf("hookValidate returned %d errors, want 3: %v", len(errs), errs)
+	}
+	for i, want := range []string{"hooks.post_start", "hooks.pre_stop", "hooks.post_stop"} {
+		if !strings.HasPrefix(errs[i].Error(), want) {
+			t.Errorf("error %d = %q, want it to name %s", i, errs[i], want)
+		}
+	}
+}
+
+// hookTestService registers a service running a long sleep with the given
+// hooks and returns its name and directory.
+func hookTestService(t *testing.T, m *LocalManager, tempDir string, hooks types.ServiceHooks) (string, string) {
+	t.Helper()
+	name := "hooked"
+	dir := filepath.Join(tempDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("creating service dir: %v", err)
+	}
+	data, err := yaml.Marshal(types.ServiceConfig{Name: name, Command: types.ServiceCommand{Shell: "sleep 30"}, Hooks: hooks})
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.yaml"), data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	entry, err := NewServiceCatalogEntry(name, dir, "service.yaml")
+	if err != nil {
+		t.Fatalf("catalog entry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("add catalog entry: %v", err)
+	}
+	return name, dir
+}
+
+func TestStartService_preStartFailureAbortsStart(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+	name, _ := hookTestService(t, m, tempDir, types.ServiceHooks{
+		PreStart: &types.ServiceHook{Command: types.ServiceCommand{Shell: "echo migrating; exit 3"}},
+	})
+
+	_, err := m.StartService(t.Context(), name)
+	if err == nil || !strings.Contains(err.Error(), "pre_start hook failed") {
+		t.Fatalf("StartService err = %v, want the pre_start failure", err)
+	}
+
+	latest, err := m.GetMostRecentProcessHistoryEntry(t.Context(), name)
+	if err != nil {
+		t.Fatalf("GetMostRecentProcessHistoryEntry: %v", err)
+	}
+	if latest.State != types.ProcessStateFailed || latest.Error == nil || !strings.Contains(*latest.Error, "pre_start") {
+		t.Errorf("latest history row = %+v, want a Failed row carrying the hook error", latest)
+	}
+
+	logged, err := os.ReadFile(filepath.Join(CreateLogDirPath(tempDir), CreateOutputLogFilename(name)))
+	if err != nil {
+		t.Fatalf("reading service log: %v", err)
+	}
+	if !strings.Contains(string(logged), "migrating") || !strings.Contains(string(logged), `"source":"hook"`) {
+		t.Errorf("service log = %s, want the hook's output tagged source=hook", logged)
+	}
+}
+
+func TestRunHook_runsInServiceDirectoryAndTimesOut(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+	config := &types.ServiceConfig{
+		Name: "hooked",
+		Hooks: types.ServiceHooks{
+			PreStart: &types.ServiceHook{Command: types.ServiceCommand{Shell: "pwd > ran-here"}},
+			PreStop:  &types.ServiceHook{Command: types.ServiceCommand{Argv: []string{"sleep", "30"}}, Timeout: "200ms"},
+		},
+	}
+	service := types.ServiceCatalogEntry{Name: "hooked", DirectoryPath: t.TempDir()}
+
+	if _, _, err := m.runHook(&service, config, hookPreStart); err != nil {
+		t.Fatalf("pre_start: %v", err)
+	}
+	ranHere, err := os.ReadFile(filepath.Join(service.DirectoryPath, "ran-here"))
+	if err != nil || strings.TrimSpace(string(ranHere)) != service.DirectoryPath {
+		t.Errorf("pre_start ran in %q (err %v), want %q", ranHere, err, service.DirectoryPath)
+	}
+
+	start := time.Now()
+	_, _, err = m.runHook(&service, config, hookPreStop)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("pre_stop err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timed-out hook took %s to return", elapsed)
+	}
+
+	if pgid, _, err := m.runHook(&service, config, hookPostStop); pgid != 0 || err != nil {
+		t.Errorf("unconfigured post_stop = %d, %v; want a no-op", pgid, err)
+	}
+}
+
+// TestRunHook_killsWhatTheHookLeftRunning proves a process a hook
+// backgrounds into its own group is killed once the hook itself exits.
+func TestRunHook_killsWhatTheHookLeftRunning(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+	config := &types.ServiceConfig{
+		Name: "hooked",
+		Hooks: types.ServiceHooks{
+			PostStart: &types.ServiceHook{Command: types.ServiceCommand{Shell: "sleep 30 > /dev/null 2>&1 & echo $! > left-running"}},
+		},
+	}
+	service := types.ServiceCatalogEntry{Name: "hooked", DirectoryPath: t.TempDir()}
+
+	if _, _, err := m.runHook(&service, config, hookPostStart); err != nil {
+		t.Fatalf("post_start: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(service.DirectoryPath, "left-running"))
+	if err != nil {
+		t.Fatalf("reading the backgrounded pid: %v", err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	deadline := time.Now().Add(5 * time.Second)
+	for procutil.LeaderAlive(pid) {
+		if time.Now().After(deadline) {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("process %d the hook backgrounded outlived it", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestRunHook_exitStatusSurvivesTheDaemonReaper runs a failing hook while a
+// daemon-style SIGCHLD reaper drains children in a loop: the hook is held
+// back from it, so its failure is still seen.
+func TestRunHook_exitStatusSurvivesTheDaemonReaper(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	held := procutil.NewHeldChildren()
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}), WithHeldChildren(held))
+	config := &types.ServiceConfig{
+		Name: "hooked",
+		Hooks: types.ServiceHooks{
+			PreStart: &types.ServiceHook{Command: types.ServiceCommand{Shell: "exit 3"}},
+		},
+	}
+	service := types.ServiceCatalogEntry{Name: "hooked", DirectoryPath: t.TempDir()}
+
+	startTestReaper(t, held)
+
+	for range 50 {
+		if _, _, err := m.runHook(&service, config, hookPreStart); err == nil || !strings.Contains(err.Error(), "pre_start hook failed") {
+			t.Fatalf("pre_start err = %v, want the hook's failure", err)
+		}
+	}
+}
+
+// startTestReaper drains this process's exited children in a loop, as the
+// daemon's SIGCHLD reaper does, leaving those in held, until the test ends.
+func startTestReaper(t *testing.T, held *procutil.HeldChildren) {
+	t.Helper()
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			var status syscall.WaitStatus
+			_, _ = procutil.ReapExited(held, &status)
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+}
@@ -0,0 +1,314 @@
+package manager
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// maxInstances bounds instances: so a typo can't fork thousands of process
+// groups at once.
+const maxInstances = 64
+
+// InstanceCount returns how many instances of a service config describes:
+// instances: when set, 1 otherwise.
+func InstanceCount(config *types.ServiceConfig) int {
+	return max(config.Instances, 1)
+}
+
+// InstanceName is how one instance of a service is addressed on the command
+// line and shown in eos status: "name:index".
+func InstanceName(name string, index int) string {
+	return name + ":" + strconv.Itoa(index)
+}
+
+// ParseInstanceName splits a "name:index" selector (see InstanceName). ok is
+// false for a plain service name, which ValidateServiceName guarantees never
+// contains a colon, or when index isn't a non-negative integer.
+func ParseInstanceName(selector string) (name string, index int, ok bool) {
+	name, suffix, found := strings.Cut(selector, ":")
+	if !found {
+		return selector, 0, false
+	}
+	index, err := strconv.Atoi(suffix)
+	if err != nil || index < 0 {
+		return selector, 0, false
+	}
+	return name, index, true
+}
+
+// instancesValidate reports invalid instances: and increment_port: settings.
+func instancesValidate(config *types.ServiceConfig) []error {
+	var errs []error
+	// A decoded 0 can't be told apart from the key being left out, so it
+	// passes as unset, one instance, and the message says so.
+	if config.Instances < 0 || config.Instances > maxInstances {
+		errs = append(errs, fmt.Errorf("instances: %d must be between 1 and %d, or left unset for one", config.Instances, maxInstances))
+	}
+	if config.IncrementPort {
+		switch {
+		case config.Port == 0:
+			errs = append(errs, errors.New("increment_port: a port is required to increment from"))
+		case config.Port+InstanceCount(config)-1 > 65535:
+			errs = append(errs, fmt.Errorf("increment_port: port %d plus %d instances runs past 65535", config.Port, InstanceCount(config)))
+		}
+	}
+	if config.Proxy && InstanceCount(config) > 1 {
+		errs = append(errs, errors.New("instances: cannot be combined with proxy"))
+	}
+	return errs
+}
+
+// InstancePort returns the port instance index of a service listens on:
+// config.Port, offset by index with increment_port.
+func InstancePort(config *types.ServiceConfig, index int) int {
+	if config.IncrementPort && config.Port != 0 {
+		return config.Port + index
+	}
+	return config.Port
+}
+
+// instanceEnv returns the variables that tell a launch which instance it is:
+// EOS_INSTANCE_ID, and with increment_port its own PORT. A service without
+// instances: set gets none.
+func instanceEnv(config *types.ServiceConfig, index int) []string {
+	if config.Instances == 0 {
+		return nil
+	}
+	env := []string{"EOS_INSTANCE_ID=" + strconv.Itoa(index)}
+	if config.IncrementPort && config.Port != 0 {
+		env = append(env, "PORT="+strconv.Itoa(InstancePort(config, index)))
+	}
+	return env
+}
+
+// instanceConfigForLaunch returns config as the instance launched as pgid
+// sees it under increment_port: a copy with that instance's own Port.
+func (m *LocalManager) instanceConfigForLaunch(pgid int, config *types.ServiceConfig) *types.ServiceConfig {
+	entry, err := m.db.GetProcessHistoryEntryByPGID(m.ctx, pgid)
+	if err != nil || entry.Instance == 0 {
+		return config
+	}
+	launch := *config
+	launch.Port = InstancePort(config, entry.Instance)
+	return &launch
+}
+
+// latestInstanceRows returns the most recent row of each instance index in
+// history, ordered by index. Most recent means latest started_at, the same
+// ordering GetMostRecentProcessHistoryEntry uses for the service as a whole.
+func latestInstanceRows(history []types.ProcessHistory) []types.ProcessHistory {
+	latest := make(map[int]types.ProcessHistory)
+	for _, row := range history {
+		current, ok := latest[row.Instance]
+		if !ok || startedAfter(row.StartedAt, current.StartedAt) {
+			latest[row.Instance] = row
+		}
+	}
+	rows := make([]types.ProcessHistory, 0, len(latest))
+	for _, row := range latest {
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b types.ProcessHistory) int { return cmp.Compare(a.Instance, b.Instance) })
+	return rows
+}
+
+// startedAfter reports whether a is later than b, with an unset start
+// earliest of all.
+func startedAfter(a, b *time.Time) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	}
+	return a.After(*b)
+}
+
+// GetInstanceProcessHistoryEntries returns the most recent process-history
+// entry of each of name's instances, ordered by instance index. A service
+// that never ran with instances: set has just one, the entry
+// GetMostRecentProcessHistoryEntry returns.
+func (m *LocalManager) GetInstanceProcessHistoryEntries(ctx context.Context, name string) ([]types.ProcessHistory, error) {
+	history, err := m.db.GetProcessHistoryEntriesByServiceName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get process history for %s: %w", name, err)
+	}
+	return latestInstanceRows(history), nil
+}
+
+// launchExtraInstances launches instances 1 and up of a service whose
+// instance 0 StartService or RestartService has just launched. Should one
+// fail, every instance is killed: a service never runs short of the
+// instances it asks for without the caller being told.
+func (m *LocalManager) launchExtraInstances(service *types.ServiceCatalogEntry, config *types.ServiceConfig, resolvedSinks []types.LogSink, startErrLabel string) error {
+	for index := 1; index < InstanceCount(config); index++ {
+		if _, err := m.launchInstance(service, config, resolvedSinks, index, startErrLabel); err != nil {
+			if _, killErr := m.forceKillServiceLocked(service.Name); killErr != nil {
+				m.logger.Error("killing instances after a failed launch", "service", service.Name, "error", killErr)
+			}
+			return fmt.Errorf("launching instance %d of %s: %w", index, service.Name, err)
+		}
+	}
+	return nil
+}
+
+// launchInstance launches instance index of service with log IO of its own
+// and records it as a Starting row for that index. Hooks are the service's,
+// not an instance's, and stay with StartService and RestartService.
+func (m *LocalManager) launchInstance(service *types.ServiceCatalogEntry, config *types.ServiceConfig, resolvedSinks []types.LogSink, index int, startErrLabel string) (pgid int, err error) {
+	lio, err := m.prepareLaunchIO(service.Name, config)
+	if err != nil {
+		return 0, err
+	}
+	launchSuccess := false
+	defer lmDeferCleanupIO(m, lio, service.Name, &launchSuccess, &err)()
+
+	pgid, startedAtTicks, err := m.launchAndCapture(service, config, index, lio, resolvedSinks, &launchSuccess, startErrLabel)
+	if err != nil {
+		return pgid, err
+	}
+	if _, histErr := m.db.RegisterInstanceProcessHistoryEntry(m.ctx, pgid, startedAtTicks, service.Name, index, types.ProcessStateStarting); histErr != nil {
+		return killAndWrap(m.tracker, pgid, histErr, "register process history entry")
+	}
+	return pgid, nil
+}
+
+// loadInstanceForLaunch is loadServiceForLaunch for one instance: it also
+// checks index against the service's instances:.
+func (m *LocalManager) loadInstanceForLaunch(name string, index int) (types.ServiceCatalogEntry, *types.ServiceConfig, []types.LogSink, error) {
+	service, config, resolvedSinks, err := m.loadServiceForLaunch(name)
+	if err != nil {
+		return types.ServiceCatalogEntry{}, nil, nil, err
+	}
+	if index < 0 || index >= InstanceCount(config) {
+		return types.ServiceCatalogEntry{}, nil, nil, fmt.Errorf("%s has no instance %d (instances: %d)", name, index, InstanceCount(config))
+	}
+	return service, config, resolvedSinks, nil
+}
+
+// liveInstanceRows returns the rows of instance index in name's history
+// whose launch is still alive.
+func (m *LocalManager) liveInstanceRows(name string, index int) ([]types.ProcessHistory, error) {
+	history, err := m.db.GetProcessHistoryEntriesByServiceName(m.ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get process history for %s: %w", name, err)
+	}
+	var live []types.ProcessHistory
+	for _, row := range history {
+		if row.Instance == index && m.tracker.aliveMatching(row.PGID, row.StartedAtTicks) {
+			live = append(live, row)
+		}
+	}
+	return live, nil
+}
+
+// StartServiceInstance starts instance index of an already registered
+// service on its own, as after eos stop name:index. It returns
+// ErrAlreadyRunning when that instance is still alive.
+func (m *LocalManager) StartServiceInstance(ctx context.Context, name string, index int) (int, error) {
+	unlock := m.lockService(name)
+	defer unlock()
+
+	service, config, resolvedSinks, err := m.loadInstanceForLaunch(name, index)
+	if err != nil {
+		return 0, err
+	}
+	live, err := m.liveInstanceRows(name, index)
+	if err != nil {
+		return 0, err
+	}
+	if len(live) > 0 {
+		return 0, ErrAlreadyRunning
+	}
+	// The service's instance row is shared by its instances; only the first
+	// one to start creates it, keeping the restart count of the others.
+	if _, instErr := m.GetServiceInstance(ctx, name); errors.Is(instErr, ErrServiceNotRunning) {
+		if regErr := m.db.RegisterServiceInstance(ctx, name); regErr != nil {
+			return 0, fmt.Errorf("register service instance %s: %w", name, regErr)
+		}
+	} else if instErr != nil {
+		return 0, fmt.Errorf("get service instance for %s: %w", name, instErr)
+	}
+	return m.launchInstance(&service, config, resolvedSinks, index, "start command")
+}
+
+// StopServiceInstance stops instance index of name per the service's stop
+// policy, leaving its other instances running. The stop hooks belong to the
+// service as a whole and don't run.
+func (m *LocalManager) StopServiceInstance(_ context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (StopServiceResult, error) {
+	unlock := m.lockService(name)
+	defer unlock()
+	return m.stopInstanceLocked(name, index, gracePeriod, tickerPeriod)
+}
+
+// stopInstanceLocked is StopServiceInstance's core, for a caller already
+// holding the per-service lock. Each live launch of the instance is stopped
+// like a reload's outgoing one (see terminateInstance), force-killed should
+// it outlast the grace period.
+func (m *LocalManager) stopInstanceLocked(name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (StopServiceResult, error) {
+	_, config, _ := m.loadServiceForStop(name)
+	policy := m.stopPolicyFor(name, config, gracePeriod)
+	live, err := m.liveInstanceRows(name, index)
+	if err != nil {
+		return StopServiceResult{}, err
+	}
+	result := StopServiceResult{Stopped: make(map[int]bool), Errored: make(map[int]string)}
+	for _, row := range live {
+		drained, termErr := m.terminateInstance(name, row.PGID, row.StartedAtTicks, policy, tickerPeriod)
+		switch {
+		case termErr != nil:
+			result.Errored[row.PGID] = termErr.Error()
+		case drained:
+			m.markInstanceStopped(row.PGID)
+			result.Stopped[row.PGID] = true
+		}
+	}
+	return result, nil
+}
+
+// RestartServiceInstance replaces instance index of name with a fresh
+// launch, leaving its other instances running, and counts it against the
+// service's restart count like RestartService. The health monitor restarts
+// an instance of a service running several this way, so one crashing never
+// bounces its siblings.
+func (m *LocalManager) RestartServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error) {
+	unlock := m.lockService(name)
+	defer unlock()
+
+	service, config, resolvedSinks, err := m.loadInstanceForLaunch(name, index)
+	if err != nil {
+		return 0, err
+	}
+	serviceInstance, err := m.GetServiceInstance(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("get service instance for %s: %w", name, err)
+	}
+
+	stopResult, err := m.stopInstanceLocked(name, index, gracePeriod, tickerPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("stopping instance %d of %s: %w", index, name, err)
+	}
+	if len(stopResult.Errored) > 0 {
+		return 0, fmt.Errorf("stopping instance %d of %s: %v", index, name, stopResult.Errored)
+	}
+
+	pgid, err := m.launchInstance(&service, config, resolvedSinks, index, "restart command")
+	if err != nil {
+		return pgid, err
+	}
+	if updErr := m.db.UpdateServiceInstance(m.ctx, name, database.ServiceInstanceUpdate{
+		RestartCount: new(serviceInstance.RestartCount + 1),
+	}); updErr != nil {
+		m.logger.Error("recording instance restart", "service", name, "instance", index, "error", updErr)
+	}
+	return pgid, nil
+}
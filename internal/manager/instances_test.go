@@ -0,0 +1,164 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInstancesValidate(t *testing.T) {
+	if errs := instancesValidate(&types.ServiceConfig{Instances: 4, IncrementPort: true, Port: 8080}); len(errs) != 0 {
+		t.Errorf("instancesValidate(4 instances from 8080) = %v, want no errors", errs)
+	}
+	errs := instancesValidate(&types.ServiceConfig{Instances: 65, IncrementPort: true, Proxy: true})
+	want := []string{"instances: 65", "increment_port: a port", "instances: cannot be combined with proxy"}
+	if len(errs) != len(want) {
+		t.Fatalf("instancesValidate returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(errs[i].Error(), prefix) {
+			t.Errorf("error %d = %q, want prefix %q", i, errs[i], prefix)
+		}
+	}
+	if errs := instancesValidate(&types.ServiceConfig{}); len(errs) != 0 {
+		t.Errorf("instancesValidate(unset) = %v, want no errors", errs)
+	}
+	if errs := instancesValidate(&types.ServiceConfig{Instances: -1}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "or left unset") {
+		t.Errorf("instancesValidate(-1) = %v, want the range error naming unset as the way to run one", errs)
+	}
+	if errs := instancesValidate(&types.ServiceConfig{Instances: 3, IncrementPort: true, Port: 65534}); len(errs) != 1 {
+		t.Errorf("instancesValidate(3 instances from 65534) = %v, want the port range error", errs)
+	}
+}
+
+func TestParseInstanceName(t *testing.T) {
+	tests := []struct {
+		selector string
+		name     string
+		index    int
+		ok       bool
+	}{
+		{"cms:2", "cms", 2, true},
+		{"cms:0", "cms", 0, true},
+		{"cms", "cms", 0, false},
+		{"cms:", "cms:", 0, false},
+		{"cms:-1", "cms:-1", 0, false},
+		{"cms:web", "cms:web", 0, false},
+	}
+	for _, tt := range tests {
+		name, index, ok := ParseInstanceName(tt.selector)
+		if name != tt.name || index != tt.index || ok != tt.ok {
+			t.Errorf("ParseInstanceName(%q) = %q, %d, %v; want %q, %d, %v", tt.selector, name, index, ok, tt.name, tt.index, tt.ok)
+		}
+	}
+}
+
+func TestLatestInstanceRows(t *testing.T) {
+	at := func(seconds int) *time.Time {
+		ts := time.Unix(int64(seconds), 0)
+		return &ts
+	}
+	rows := latestInstanceRows([]types.ProcessHistory{
+		{PGID: 10, Instance: 1, StartedAt: at(1)},
+		{PGID: 11, Instance: 0, StartedAt: at(2)},
+		{PGID: 12, Instance: 1, StartedAt: at(3)},
+		{PGID: 13, Instance: 0},
+	})
+	if len(rows) != 2 || rows[0].PGID != 11 || rows[1].PGID != 12 {
+		t.Errorf("latestInstanceRows = %+v, want pgid 11 for instance 0 and 12 for instance 1", rows)
+	}
+}
+
+// TestInstances_StartStopOne starts a service with instances: 3 and
+// increment_port, checks each instance sees its own EOS_INSTANCE_ID and PORT,
+// then stops and restarts instance 1 on its own while the others keep running.
+func TestInstances_StartStopOne(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
+
+	const name = "multi"
+	dir := filepath.Join(tempDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("creating service dir: %v", err)
+	}
+	data, err := yaml.Marshal(types.ServiceConfig{
+		Name:          name,
+		Command:       types.ServiceCommand{Shell: `echo "$PORT" > "port-$EOS_INSTANCE_ID"; exec sleep 30`},
+		Port:          41000,
+		Instances:     3,
+		IncrementPort: true,
+	})
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.yaml"), data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	entry, err := NewServiceCatalogEntry(name, dir, "service.yaml")
+	if err != nil {
+		t.Fatalf("catalog entry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("add catalog entry: %v", err)
+	}
+
+	if _, err := m.StartService(t.Context(), name); err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { _, _ = m.ForceStopService(t.Context(), name) })
+
+	rows, err := m.GetInstanceProcessHistoryEntries(t.Context(), name)
+	if err != nil {
+		t.Fatalf("GetInstanceProcessHistoryEntries: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d instance rows, want 3: %+v", len(rows), rows)
+	}
+	for index, row := range rows {
+		if row.Instance != index {
+			t.Errorf("row %d is for instance %d", index, row.Instance)
+		}
+		portFile := filepath.Join(dir, "port-"+strconv.Itoa(index))
+		deadline := time.Now().Add(5 * time.Second)
+		var got []byte
+		for time.Now().Before(deadline) {
+			if got, err = os.ReadFile(portFile); err == nil && len(got) > 0 {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if want := strconv.Itoa(41000 + index); strings.TrimSpace(string(got)) != want {
+			t.Errorf("instance %d saw PORT %q, want %s", index, got, want)
+		}
+	}
+
+	stopped, err := m.StopServiceInstance(t.Context(), name, 1, time.Second, 20*time.Millisecond)
+	if err != nil || len(stopped.Stopped) != 1 || !stopped.Stopped[rows[1].PGID] {
+		t.Fatalf("StopServiceInstance = %+v, %v; want instance 1's pgid %d stopped", stopped, err, rows[1].PGID)
+	}
+	for _, index := range []int{0, 2} {
+		if !m.IsProcessGroupAlive(rows[index].PGID) {
+			t.Errorf("instance %d died with instance 1", index)
+		}
+	}
+
+	pgid, err := m.StartServiceInstance(t.Context(), name, 1)
+	if err != nil || pgid == rows[1].PGID {
+		t.Fatalf("StartServiceInstance = %d, %v; want a fresh launch", pgid, err)
+	}
+	if _, err := m.StartServiceInstance(t.Context(), name, 1); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("second StartServiceInstance err = %v, want ErrAlreadyRunning", err)
+	}
+	if _, err := m.StartServiceInstance(t.Context(), name, 3); err == nil {
+		t.Error("StartServiceInstance(3) succeeded on a service with 3 instances")
+	}
+}
@@ -0,0 +1,199 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Elysium-Labs-EU/eos/internal/cgroup"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// cpuPeriodMicros is the cpu.max period every launch leaf uses: the kernel's
+// own default, 100ms. cpu_quota's percentage scales the quota against it.
+const cpuPeriodMicros = 100000
+
+// limitPlan is where each field of a service's limits: block gets enforced
+// for one launch (see planLimits).
+type limitPlan struct {
+	// rlimits are set with prlimit(2) while the launch is stopped at its
+	// exec, before it runs (see launchStart).
+	rlimits []plannedRlimit
+	// unenforced names fields no mechanism available to this launch can
+	// enforce. The launch still starts; the caller logs them.
+	unenforced []string
+	// cgroup is written into the launch's staged leaf before it starts.
+	cgroup cgroup.Limits
+}
+
+type plannedRlimit struct {
+	value    uint64
+	resource procutil.Rlimit
+}
+
+// planLimits parses limits and routes each field to the launch's cgroup leaf
+// when controllers (the ones enabled for that leaf; nil without one) include
+// the field's controller, and to an rlimit otherwise. memory_max's
+// fallback, RLIMIT_AS, is per process rather than per service — it caps each
+// process's address space, not the service's resident memory — so it's a
+// coarser stand-in, not an equivalent. cpu_quota and pids_max have no
+// fallback: RLIMIT_NPROC counts every process of the service's uid, the
+// daemon's own included when they share one, so it would cap far more than
+// the service.
+//
+// It's also the validator for the block (see cfgvValidateLimits): a config
+// that plans without error here will start.
+func planLimits(limits types.ServiceLimits, controllers []string) (limitPlan, error) {
+	var plan limitPlan
+	if limits.MemoryMax != "" {
+		bytes, unlimited, err := parseLimitSize(limits.MemoryMax, "max")
+		if err != nil {
+			return limitPlan{}, fmt.Errorf("limits.memory_max: %w", err)
+		}
+		if bytes == 0 && !unlimited {
+			return limitPlan{}, errors.New("limits.memory_max: must be greater than 0")
+		}
+		if slices.Contains(controllers, "memory") {
+			plan.cgroup.MemoryMax = limCgroupValue(bytes, unlimited)
+		} else {
+			plan.rlimits = append(plan.rlimits, plannedRlimit{resource: procutil.RlimitAS, value: limRlimitValue(bytes, unlimited)})
+		}
+	}
+	if limits.CPUQuota != "" {
+		percent, err := parseCPUQuota(limits.CPUQuota)
+		if err != nil {
+			return limitPlan{}, fmt.Errorf("limits.cpu_quota: %w", err)
+		}
+		if slices.Contains(controllers, "cpu") {
+			plan.cgroup.CPUMax = fmt.Sprintf("%d %d", percent*cpuPeriodMicros/100, cpuPeriodMicros)
+		} else {
+			plan.unenforced = append(plan.unenforced, "cpu_quota")
+		}
+	}
+	if limits.PidsMax < 0 || limits.Nofile < 0 {
+		return limitPlan{}, errors.New("limits.pids_max and limits.nofile must be positive")
+	}
+	if limits.PidsMax > 0 {
+		if slices.Contains(controllers, "pids") {
+			plan.cgroup.PidsMax = strconv.Itoa(limits.PidsMax)
+		} else {
+			plan.unenforced = append(plan.unenforced, "pids_max")
+		}
+	}
+	if limits.Nofile > 0 {
+		plan.rlimits = append(plan.rlimits, plannedRlimit{resource: procutil.RlimitNofile, value: uint64(limits.Nofile)})
+	}
+	if limits.Core != "" {
+		bytes, unlimited, err := parseLimitSize(limits.Core, "unlimited")
+		if err != nil {
+			return limitPlan{}, fmt.Errorf("limits.core: %w", err)
+		}
+		plan.rlimits = append(plan.rlimits, plannedRlimit{resource: procutil.RlimitCore, value: limRlimitValue(bytes, unlimited)})
+	}
+	return plan, nil
+}
+
+// parseLimitSize parses a byte count with an optional K, M, G or T suffix
+// (powers of 1024, matching systemd's MemoryMax=), or unlimitedWord.
+func parseLimitSize(s, unlimitedWord string) (bytes uint64, unlimited bool, err error) {
+	if s == unlimitedWord {
+		return 0, true, nil
+	}
+	digits, shift := s, 0
+	for i, suffix := range []string{"K", "M", "G", "T"} {
+		if rest, ok := strings.CutSuffix(strings.ToUpper(s), suffix); ok {
+			digits, shift = rest, 10*(i+1)
+			break
+		}
+	}
+	value, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%q is not a size like 512M or %q", s, unlimitedWord)
+	}
+	if value > (procutil.RlimInfinity-1)>>shift {
+		return 0, false, fmt.Errorf("%q is too large", s)
+	}
+	return value << shift, false, nil
+}
+
+// parseCPUQuota parses a percentage of one CPU, e.g. "150%".
+func parseCPUQuota(s string) (int, error) {
+	digits, ok := strings.CutSuffix(s, "%")
+	percent, err := strconv.Atoi(digits)
+	if !ok || err != nil || percent <= 0 {
+		return 0, fmt.Errorf("%q is not a positive percentage like 50%% or 200%%", s)
+	}
+	return percent, nil
+}
+
+func limCgroupValue(bytes uint64, unlimited bool) string {
+	if unlimited {
+		return "max"
+	}
+	return strconv.FormatUint(bytes, 10)
+}
+
+func limRlimitValue(bytes uint64, unlimited bool) uint64 {
+	if unlimited {
+		return procutil.RlimInfinity
+	}
+	return bytes
+}
+
+// limApplyRlimits sets each planned rlimit on pid, stopped at its exec. It
+// stops at the first failure — typically raising a hard limit above the
+// daemon's own without CAP_SYS_RESOURCE — and the launch is killed before it
+// runs: a service the config says is capped must not run uncapped.
+func limApplyRlimits(pid int, rlimits []plannedRlimit) error {
+	for _, r := range rlimits {
+		if err := procutil.SetRlimit(pid, r.resource, r.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEffectiveLimits reads back the limits the kernel is enforcing on name's
+// most recent live launch. A service with no live launch returns the zero
+// EffectiveLimits (PGID 0), not an error: there's nothing running to read.
+func (m *LocalManager) GetEffectiveLimits(ctx context.Context, name string) (types.EffectiveLimits, error) {
+	latest, err := m.GetMostRecentProcessHistoryEntry(ctx, name)
+	if errors.Is(err, ErrProcessNotFound) {
+		return types.EffectiveLimits{}, nil
+	}
+	if err != nil {
+		return types.EffectiveLimits{}, fmt.Errorf("get most recent process history entry for %s: %w", name, err)
+	}
+	if !m.tracker.aliveMatching(latest.PGID, latest.StartedAtTicks) {
+		return types.EffectiveLimits{}, nil
+	}
+
+	effective := types.EffectiveLimits{PGID: latest.PGID}
+	if dir, ok := m.tracker.launchDir(latest.PGID); ok {
+		leaf := cgroup.ReadLimits(dir)
+		effective.Cgroup = true
+		effective.MemoryMax, effective.CPUMax, effective.PidsMax = leaf.MemoryMax, leaf.CPUMax, leaf.PidsMax
+	}
+	effective.Nofile = limReadRlimit(latest.PGID, procutil.RlimitNofile)
+	effective.Core = limReadRlimit(latest.PGID, procutil.RlimitCore)
+	effective.AddressSpace = limReadRlimit(latest.PGID, procutil.RlimitAS)
+	return effective, nil
+}
+
+// limReadRlimit formats pid's soft resource limit the way /proc/<pid>/limits
+// does, or "" when it can't be read (the leader already exited, or a
+// platform without prlimit).
+func limReadRlimit(pid int, resource procutil.Rlimit) string {
+	value, err := procutil.GetRlimit(pid, resource)
+	switch {
+	case err != nil:
+		return ""
+	case value == procutil.RlimInfinity:
+		return "unlimited"
+	}
+	return strconv.FormatUint(value, 10)
+}
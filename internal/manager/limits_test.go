@@ -0,0 +1,158 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/cgroup"
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseLimitSize(t *testing.T) {
+	cases := []struct {
+		in        string
+		want      uint64
+		unlimited bool
+	}{
+		{"1024", 1024, false},
+		{"512M", 512 << 20, false},
+		{"2g", 2 << 30, false},
+		{"0", 0, false},
+		{"max", 0, true},
+	}
+	for _, tc := range cases {
+		got, unlimited, err := parseLimitSize(tc.in, "max")
+		if err != nil || got != tc.want || unlimited != tc.unlimited {
+			t.Errorf("parseLimitSize(%q) = (%d, %v, %v), want (%d, %v, nil)", tc.in, got, unlimited, err, tc.want, tc.unlimited)
+		}
+	}
+	for _, bad := range []string{"", "M", "1.5G", "-1", "unlimited", "99999999999T"} {
+		if _, _, err := parseLimitSize(bad, "max"); err == nil {
+			t.Errorf("parseLimitSize(%q) err=nil, want an error", bad)
+		}
+	}
+}
+
+func TestPlanLimits_cgroupWhenControllersAvailable(t *testing.T) {
+	limits := types.ServiceLimits{MemoryMax: "512M", CPUQuota: "150%", PidsMax: 64, Nofile: 4096, Core: "0"}
+
+	plan, err := planLimits(limits, []string{"memory", "cpu", "pids"})
+	if err != nil {
+		t.Fatalf("planLimits: %v", err)
+	}
+	wantCgroup := cgroup.Limits{MemoryMax: "536870912", CPUMax: "150000 100000", PidsMax: "64"}
+	if plan.cgroup != wantCgroup {
+		t.Errorf("plan.cgroup = %+v, want %+v", plan.cgroup, wantCgroup)
+	}
+	// nofile and core have no cgroup counterpart.
+	wantRlimits := []plannedRlimit{{resource: procutil.RlimitNofile, value: 4096}, {resource: procutil.RlimitCore, value: 0}}
+	if !slices.Equal(plan.rlimits, wantRlimits) {
+		t.Errorf("plan.rlimits = %+v, want %+v", plan.rlimits, wantRlimits)
+	}
+	if len(plan.unenforced) != 0 {
+		t.Errorf("plan.unenforced = %v, want none", plan.unenforced)
+	}
+}
+
+func TestPlanLimits_rlimitFallbackWithoutCgroup(t *testing.T) {
+	limits := types.ServiceLimits{MemoryMax: "max", CPUQuota: "50%", PidsMax: 64}
+
+	plan, err := planLimits(limits, nil)
+	if err != nil {
+		t.Fatalf("planLimits: %v", err)
+	}
+	if plan.cgroup != (cgroup.Limits{}) {
+		t.Errorf("plan.cgroup = %+v, want empty with no controllers", plan.cgroup)
+	}
+	// pids_max never falls back to RLIMIT_NPROC, which would cap every
+	// process of the service's uid.
+	wantRlimits := []plannedRlimit{{resource: procutil.RlimitAS, value: procutil.RlimInfinity}}
+	if !slices.Equal(plan.rlimits, wantRlimits) {
+		t.Errorf("plan.rlimits = %+v, want %+v", plan.rlimits, wantRlimits)
+	}
+	if !slices.Equal(plan.unenforced, []string{"cpu_quota", "pids_max"}) {
+		t.Errorf("plan.unenforced = %v, want [cpu_quota pids_max]", plan.unenforced)
+	}
+}
+
+func TestPlanLimits_rejectsInvalid(t *testing.T) {
+	for _, limits := range []types.ServiceLimits{
+		{MemoryMax: "0"},
+		{CPUQuota: "150"},
+		{CPUQuota: "0%"},
+		{PidsMax: -1},
+		{Core: "max"},
+	} {
+		if _, err := planLimits(limits, nil); err == nil {
+			t.Errorf("planLimits(%+v) err=nil, want an error", limits)
+		}
+	}
+}
+
+// TestStartService_RlimitsKeepTheExecFormArgv proves a launch's rlimits are
+// set without wrapping its command: an exec-form service runs its own argv,
+// already capped.
+func TestStartService_RlimitsKeepTheExecFormArgv(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("setting rlimits on a launch needs prlimit(2)")
+	}
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	serviceDir := filepath.Join(tempDir, "capped")
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		t.Fatalf("could not create test directory: %v", err)
+	}
+	config := &types.ServiceConfig{
+		Name:    "capped",
+		Command: types.ServiceCommand{Argv: []string{"sleep", "30"}},
+		Limits:  types.ServiceLimits{Nofile: 64},
+	}
+	yamlData, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal test config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(serviceDir, "service.yaml"), yamlData, 0644); err != nil {
+		t.Fatalf("error occurred during writing the yaml file, got: %v", err)
+	}
+	entry, err := NewServiceCatalogEntry("capped", serviceDir, "service.yaml")
+	if err != nil {
+		t.Fatalf("NewServiceCatalogEntry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("AddServiceCatalogEntry: %v", err)
+	}
+
+	pgid, err := m.StartService(t.Context(), "capped")
+	if err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	t.Cleanup(func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pgid))
+	if err != nil {
+		t.Fatalf("reading cmdline: %v", err)
+	}
+	if got := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00"); !slices.Equal(got, config.Command.Argv) {
+		t.Errorf("service runs %q, want its exec-form argv %q", got, config.Command.Argv)
+	}
+	if nofile, err := procutil.GetRlimit(pgid, procutil.RlimitNofile); err != nil || nofile != 64 {
+		t.Errorf("nofile = %d, %v; want 64", nofile, err)
+	}
+
+	if _, err := m.StopService(t.Context(), "capped", 2*time.Second, 20*time.Millisecond); err != nil {
+		t.Fatalf("StopService: %v", err)
+	}
+	m.WaitServices()
+}
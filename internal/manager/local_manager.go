@@ -11,17 +11,20 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Elysium-Labs-EU/eos/internal/buildinfo"
+	"github.com/Elysium-Labs-EU/eos/internal/cgroup"
 	"github.com/Elysium-Labs-EU/eos/internal/database"
 	"github.com/Elysium-Labs-EU/eos/internal/logutil"
 	"github.com/Elysium-Labs-EU/eos/internal/otelx"
 	"github.com/Elysium-Labs-EU/eos/internal/procutil"
 	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
 )
 
 type LocalManager struct {
@@ -56,6 +59,9 @@ type LocalManager struct {
 	logWriters   map[string]*sharedLogWriter
 	logger       *slog.Logger
 	sinkRegistry map[string]types.LogSink
+	// outputTee, when set (see WithOutputTee), is handed every line a
+	// service writes and every health breadcrumb, after its log file.
+	outputTee OutputTee
 	// exitCodes holds the exit code captureIdentity's reaper goroutine
 	// observed for a pgid that has already been reaped, keyed by pgid so a
 	// caller who only has the PGID (the health monitor, which never sees the
@@ -64,7 +70,24 @@ type LocalManager struct {
 	// this stays bounded by in-flight deaths rather than growing with total
 	// restarts over the daemon's lifetime. exitCodesMu guards the map.
 	exitCodes map[int]int
-	baseDir   string
+	// notify holds the socket and reported status of every live type:
+	// notify launch, keyed by pgid (see notifyServe). notifyMu guards it.
+	notify map[int]*notifyLaunch
+	// forking holds the main PID of every live type: forking launch, keyed
+	// by pgid (see forkingCapture). forkingMu guards it.
+	forking map[int]int
+	// held is the daemon's set of children its SIGCHLD reaper leaves to
+	// their own Wait (see WithHeldChildren); nil holds nothing.
+	held *procutil.HeldChildren
+	// sockets holds the listeners of every service with a sockets: list,
+	// keyed by service name, open across its launches (see socketsAttach).
+	// socketsMu guards it.
+	sockets map[string]*serviceSockets
+	// proxies holds the public listener of every service with proxy: true,
+	// keyed by service name, open across its launches (see proxyListen).
+	// proxiesMu guards it.
+	proxies map[string]*serviceProxy
+	baseDir string
 	// serviceWg tracks the async cmd.Wait() reaper goroutine launched for
 	// every started service (see captureIdentity). WaitServices blocks until
 	// every launched service has actually exited: without this, a caller that
@@ -88,6 +111,23 @@ type LocalManager struct {
 	reloadMu sync.Mutex
 	// exitCodesMu guards exitCodes.
 	exitCodesMu sync.Mutex
+	// notifyMu guards notify.
+	notifyMu sync.Mutex
+	// forkingMu guards forking.
+	forkingMu sync.Mutex
+	// socketsMu guards sockets.
+	socketsMu sync.Mutex
+	// proxiesMu guards proxies.
+	proxiesMu sync.Mutex
+	// identity is this process's own, from WithIdentity. serviceCredential
+	// compares a service's user:/group: against it to decide whether there
+	// is anything to switch, and whether this process is even allowed to;
+	// nil refuses any switch.
+	identity *userutil.Identity
+	// tracker answers liveness and delivers stop signals for every launched
+	// instance, through its cgroup leaf when WithCgroups resolved one and its
+	// process group otherwise (see groupTracker).
+	tracker groupTracker
 }
 
 // sharedLogWriter is a reference-counted RotatingFileWriter: refs tracks how
@@ -297,6 +337,20 @@ func WithSinkRegistry(registry map[string]types.LogSink) LocalManagerOption {
 	}
 }
 
+// OutputTee receives one line of a service's output: source is "stdout",
+// "stderr", or logutil.HealthBreadcrumbSource for the health monitor's own
+// notes. It is called from the service's log-forwarding goroutines, so
+// concurrently across services.
+type OutputTee func(service, source, line string)
+
+// WithOutputTee copies every service's output to tee as well as its log
+// files, for eos up to print it in the foreground.
+func WithOutputTee(tee OutputTee) LocalManagerOption {
+	return func(m *LocalManager) {
+		m.outputTee = tee
+	}
+}
+
 // WithTelemetry sets the tracer and metric instruments the service lifecycle
 // (StartService/StopService/RestartService/ForceStopService) records
 // through. Callers that don't supply this get otelx.NoopHandles(), so
@@ -320,8 +374,39 @@ func WithShutdownGracePeriod(d time.Duration) LocalManagerOption {
 	}
 }
 
+// WithCgroups places every launch in its own cgroup under h's eos.slice and
+// tracks it there (see groupTracker). h comes from cgroup.Detect at the
+// daemon's boundary; a nil h — detection failed — keeps PGID tracking. Only
+// the daemon passes this: a CLI-local manager lives in the caller's session
+// cgroup, which is neither delegated nor where the daemon would look.
+func WithCgroups(h *cgroup.Hierarchy) LocalManagerOption {
+	return func(m *LocalManager) {
+		m.tracker = groupTracker{cgroups: h}
+	}
+}
+
+// WithHeldChildren starts the children whose exit code a launch must read
+// itself, a type: forking launch's command, say, through held, the set the
+// daemon's SIGCHLD reaper leaves to them (see procutil.StartHeld). Only the
+// daemon passes this: without its reaper no one else waits for them.
+func WithHeldChildren(held *procutil.HeldChildren) LocalManagerOption {
+	return func(m *LocalManager) {
+		m.held = held
+	}
+}
+
+// WithIdentity sets the identity this process runs as, from
+// userutil.ResolveIdentity at the caller's boundary. Services with
+// user:/group:/supplementary_groups: are refused without it rather than
+// judged against a guess.
+func WithIdentity(identity userutil.Identity) LocalManagerOption {
+	return func(m *LocalManager) {
+		m.identity = &identity
+	}
+}
+
 func NewLocalManager(db *database.DB, baseDir string, ctx context.Context, logger *slog.Logger, opts ...LocalManagerOption) *LocalManager {
-	m := &LocalManager{db: db, baseDir: baseDir, ctx: ctx, logger: logger, executor: osExecutor{}, telemetry: otelx.NoopHandles(), serviceLocks: make(map[string]*sync.Mutex), logWriters: make(map[string]*sharedLogWriter), reloadInProgress: make(map[string]bool), exitCodes: make(map[int]int)}
+	m := &LocalManager{db: db, baseDir: baseDir, ctx: ctx, logger: logger, executor: osExecutor{}, telemetry: otelx.NoopHandles(), serviceLocks: make(map[string]*sync.Mutex), logWriters: make(map[string]*sharedLogWriter), reloadInProgress: make(map[string]bool), exitCodes: make(map[int]int), notify: make(map[int]*notifyLaunch), forking: make(map[int]int), sockets: make(map[string]*serviceSockets), proxies: make(map[string]*serviceProxy)}
 	for _, opt := range opts {
 		opt(m)
 	}
@@ -367,6 +452,8 @@ func (m *LocalManager) RemoveServiceInstance(ctx context.Context, name string) (
 }
 
 func (m *LocalManager) RemoveServiceCatalogEntry(ctx context.Context, name string) (bool, error) {
+	m.socketsRelease(name)
+	m.proxyRelease(name)
 	removed, err := m.db.RemoveServiceCatalogEntry(ctx, name)
 	if err != nil {
 		return false, fmt.Errorf("remove service catalog entry: %w", err)
@@ -468,6 +555,8 @@ func (m *LocalManager) GetMostRecentProcessHistoryEntry(ctx context.Context, nam
 // (see interface doc). It re-derives "most recent" via the same SQL ordering
 // GetMostRecentProcessHistoryEntry uses, rather than picking it out of the
 // full history slice in Go, so the two never disagree on which row that is.
+// With instances: set, each instance's own most recent row is current too,
+// not an orphan (see latestInstanceRows).
 func (m *LocalManager) GetLiveOrphanProcessGroups(ctx context.Context, name string) ([]types.ProcessHistory, error) {
 	history, err := m.db.GetProcessHistoryEntriesByServiceName(ctx, name)
 	if err != nil {
@@ -479,28 +568,54 @@ func (m *LocalManager) GetLiveOrphanProcessGroups(ctx context.Context, name stri
 		return nil, fmt.Errorf("get process history for %s: %w", name, err)
 	}
 
-	return liveOrphanRows(history, mostRecent.PGID), nil
+	current := make(map[int]bool)
+	for _, row := range latestInstanceRows(history) {
+		if row.Instance > 0 {
+			current[row.PGID] = true
+		}
+	}
+	orphans := slices.DeleteFunc(liveOrphanRows(history, mostRecent.PGID, m.tracker), func(row types.ProcessHistory) bool {
+		return current[row.PGID]
+	})
+	return append(orphans, lmUntrackedOrphanRows(name, history, mostRecent.PGID, m.tracker)...), nil
 }
 
 // liveOrphanRows returns every row in history other than mostRecentPGID whose
-// process group is still alive, using procutil.IsAliveMatching to rule out a
+// process group is still alive, using tracker's aliveMatching to rule out a
 // live PGID the kernel has since recycled for an unrelated process. Mirrors
-// the whole-history liveness scan stopServiceWithSignal already does on the
+// the whole-history liveness scan sendStopSignal already does on the
 // write path (see lmSignalHistoryEntry), applied to the read path instead.
-func liveOrphanRows(history []types.ProcessHistory, mostRecentPGID int) []types.ProcessHistory {
+func liveOrphanRows(history []types.ProcessHistory, mostRecentPGID int, tracker groupTracker) []types.ProcessHistory {
 	var orphans []types.ProcessHistory
 	for i := range history {
 		row := &history[i]
 		if row.PGID <= 0 || row.PGID == mostRecentPGID {
 			continue
 		}
-		if procutil.IsAliveMatching(row.PGID, row.StartedAtTicks) {
+		if tracker.aliveMatching(row.PGID, row.StartedAtTicks) {
 			orphans = append(orphans, *row)
 		}
 	}
 	return orphans
 }
 
+// lmUntrackedOrphanRows reports populated cgroup leaves under name that no
+// history row accounts for, as synthetic Unknown rows. Only cgroup tracking
+// can see these: a PGID scan has nothing to scan without a recorded PGID, but
+// everything under eos.slice was launched by eos, so a live leaf with no row
+// is an orphan by construction.
+func lmUntrackedOrphanRows(name string, history []types.ProcessHistory, mostRecentPGID int, tracker groupTracker) []types.ProcessHistory {
+	known := map[int]bool{mostRecentPGID: true}
+	for i := range history {
+		known[history[i].PGID] = true
+	}
+	var rows []types.ProcessHistory
+	for _, pgid := range tracker.liveUntracked(name, known) {
+		rows = append(rows, types.ProcessHistory{ServiceName: name, PGID: pgid, State: types.ProcessStateUnknown})
+	}
+	return rows
+}
+
 func (m *LocalManager) UpdateServiceCatalogEntry(ctx context.Context, name string, newDirectoryPath string, newConfigFileName string) error {
 	err := m.db.UpdateServiceCatalogEntry(ctx, name, newDirectoryPath, newConfigFileName)
 	if err != nil {
@@ -586,6 +701,7 @@ func (m *LocalManager) pipeToLogFile(r *os.File, w io.Writer, name string, pgid
 	scanner := bufio.NewScanner(r)
 	scanErr := lmScanAndForward(scanner, "stdout", sinks, func(line string) {
 		logger.Info(line, "service", name, "pgid", pgid, "source", "stdout")
+		m.teeOutput(name, "stdout", line)
 	})
 	if scanErr != nil && m.ctx.Err() == nil {
 		m.logger.Error("scanning log pipe", "service", name, "error", scanErr)
@@ -598,6 +714,12 @@ func (m *LocalManager) pipeToLogFile(r *os.File, w io.Writer, name string, pgid
 	}
 }
 
+func (m *LocalManager) teeOutput(name, source, line string) {
+	if m.outputTee != nil {
+		m.outputTee(name, source, line)
+	}
+}
+
 // lmScanAndForward reads scanner line by line, passing each line to logLine
 // and forwarding it to any sink in sinks subscribed to stream. Returns the
 // scanner's terminal error, if any.
@@ -631,6 +753,7 @@ func (m *LocalManager) pipeToErrorLogFile(r *os.File, errFileLogger *slog.Logger
 	scanner := bufio.NewScanner(r)
 	scanErr := lmScanAndForward(scanner, "stderr", sinks, func(line string) {
 		errFileLogger.Info(line, "service", name, "pgid", pgid, "source", "stderr")
+		m.teeOutput(name, "stderr", line)
 	})
 	if scanErr != nil && m.ctx.Err() == nil {
 		m.logger.Error("scanning error log pipe", "service", name, "error", scanErr)
@@ -645,13 +768,13 @@ func (m *LocalManager) pipeToErrorLogFile(r *os.File, errFileLogger *slog.Logger
 
 // livePGIDInHistory returns the PGID of the first Running or Starting history
 // entry that still has a live OS process, or 0 if none do.
-func livePGIDInHistory(history []types.ProcessHistory) int {
+func livePGIDInHistory(history []types.ProcessHistory, tracker groupTracker) int {
 	for i := range history {
 		p := &history[i]
 		if p.State != types.ProcessStateRunning && p.State != types.ProcessStateStarting {
 			continue
 		}
-		if procutil.IsAliveMatching(p.PGID, p.StartedAtTicks) {
+		if tracker.aliveMatching(p.PGID, p.StartedAtTicks) {
 			return p.PGID
 		}
 	}
@@ -732,10 +855,16 @@ func (lio launchIO) closeAll(m *LocalManager, serviceName string) error {
 	return errors.Join(errs...)
 }
 
-// buildLaunchCommand constructs the /bin/sh command that runs a service, wiring
-// its process group, working directory, environment, and stdout/stderr pipes.
+// buildLaunchCommand constructs the command that runs a service — /bin/sh -c
+// for a shell-form command, argv exec'd directly for an exec-form one — wiring
+// its process group, working directory, environment, stdout/stderr pipes, and
+// — when service.yaml sets user:/group: — the identity it drops to.
 func (m *LocalManager) buildLaunchCommand(service *types.ServiceCatalogEntry, config *types.ServiceConfig, lio launchIO) (*exec.Cmd, error) {
-	cmd := m.executor.CommandContext(m.ctx, "/bin/sh", "-c", config.Command) // #nosec G204 -- command is user-defined in their service.yaml config
+	env, err := buildEnvironment(config, service.DirectoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("building environment for %s: %w", service.Name, err)
+	}
+	cmd := m.commandFor(m.ctx, config.Command, env)
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	// Without this, canceling m.ctx (daemon shutdown on SIGTERM/SIGINT) falls
 	// back to os/exec's default cancellation policy: cmd.Process.Kill()
@@ -743,23 +872,48 @@ func (m *LocalManager) buildLaunchCommand(service *types.ServiceCatalogEntry, co
 	// sequence at all — bypassing shutdownGracePeriod entirely (issue #93).
 	// Setting Cancel/WaitDelay makes Go's own exec runtime enforce
 	// SIGTERM-then-wait-then-SIGKILL on cancellation instead.
+	// A service's own stop_signal, kill_mode and stop_timeout apply here
+	// just as they do to eos stop.
 	if m.shutdownGracePeriod > 0 {
+		policy := m.stopPolicyFor(service.Name, config, m.shutdownGracePeriod)
 		cmd.Cancel = func() error {
-			return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+			return m.shutdownCancel(cmd.Process.Pid, policy)
 		}
-		cmd.WaitDelay = m.shutdownGracePeriod
+		cmd.WaitDelay = policy.Timeout
 	}
 	cmd.Dir = service.DirectoryPath
-	env, err := buildEnvironment(config, service.DirectoryPath)
+	cmd.Env = env
+	cred, err := m.serviceCredential(config)
 	if err != nil {
-		return nil, fmt.Errorf("building environment for %s: %w", service.Name, err)
+		return nil, fmt.Errorf("resolving user/group for %s: %w", service.Name, err)
+	}
+	if cred != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: cred.uid, Gid: cred.gid, Groups: cred.groups}
+		if chownErr := credChownServiceLogs(lio, cred); chownErr != nil {
+			return nil, fmt.Errorf("preparing log files for %s: %w", service.Name, chownErr)
+		}
 	}
-	cmd.Env = env
 	cmd.Stdout = lio.writeLog
 	cmd.Stderr = lio.writeErr
 	return cmd, nil
 }
 
+// commandFor builds the exec.Cmd that runs command under ctx: /bin/sh -c for
+// the shell form, or argv exec'd directly for the exec form, its argv[0]
+// looked up on env's PATH (see lmResolveExecBinary) while the process still
+// sees argv[0] as written.
+func (m *LocalManager) commandFor(ctx context.Context, command types.ServiceCommand, env []string) *exec.Cmd {
+	name, args := "/bin/sh", []string{"-c", command.Shell}
+	if command.IsExec() {
+		name, args = lmResolveExecBinary(command.Argv[0], env), command.Argv[1:]
+	}
+	cmd := m.executor.CommandContext(ctx, name, args...) // #nosec G204 -- command is user-defined in their service.yaml config
+	if command.IsExec() {
+		cmd.Args[0] = command.Argv[0]
+	}
+	return cmd
+}
+
 // wireLogPipes closes the now-handed-off write ends, starts any log sinks, and
 // launches the goroutines that forward the process's stdout/stderr to the log
 // files and sinks. It must be called once Start has succeeded. pgid is this
@@ -791,12 +945,12 @@ func (m *LocalManager) wireLogPipes(lio launchIO, resolvedSinks []types.LogSink,
 	return nil
 }
 
-// killAndWrap kills a launched process group after a post-start bookkeeping
-// step failed, and wraps err with action context. If the kill itself fails the
+// killAndWrap kills a launched instance after a post-start bookkeeping step
+// failed, and wraps err with action context. If the kill itself fails the
 // process may still be alive, so pgid 0 is returned to flag manual cleanup;
 // otherwise the (now cleaned-up) pgid is returned.
-func killAndWrap(pgid int, err error, action string) (int, error) {
-	if killErr := syscall.Kill(-pgid, syscall.SIGKILL); killErr != nil {
+func killAndWrap(tracker groupTracker, pgid int, err error, action string) (int, error) {
+	if killErr := tracker.signal(pgid, syscall.SIGKILL); killErr != nil {
 		return 0, fmt.Errorf("%s %d: %w; kill process: %w - manual intervention required", action, pgid, err, killErr)
 	}
 	return pgid, fmt.Errorf("%s (process cleaned up): %w", action, err)
@@ -805,18 +959,20 @@ func killAndWrap(pgid int, err error, action string) (int, error) {
 // captureIdentity derives the process-group id from a freshly started leader
 // (its PID, since Setpgid makes it the group leader), reads its start time
 // before the reaper can collect it, then launches the async reaper tracked by
-// m.serviceWg (see WaitServices). On a start-time read failure it kills the
-// group and reaps synchronously.
-func (m *LocalManager) captureIdentity(cmd *exec.Cmd) (pgid int, startedAtTicks int64, err error) {
+// m.serviceWg (see WaitServices), which also releases the launch's cgroup
+// leaf once it has emptied. On a start-time read failure it kills the group
+// and reaps synchronously.
+func (m *LocalManager) captureIdentity(service string, cmd *exec.Cmd) (pgid int, startedAtTicks int64, err error) {
 	pgid = cmd.Process.Pid
 	startedAtTicks, err = procutil.StartTime(pgid)
 	if err != nil {
-		cleanPGID, wrapErr := killAndWrap(pgid, err, "reading process start time")
+		cleanPGID, wrapErr := killAndWrap(m.tracker, pgid, err, "reading process start time")
 		_ = cmd.Wait() // reap; the async reaper below never launched on this path
 		return cleanPGID, 0, wrapErr
 	}
 	m.serviceWg.Go(func() {
 		_ = cmd.Wait()
+		m.tracker.release(service, pgid)
 		// ProcessState is nil only when the daemon's own SIGCHLD-driven Wait4(-1,
 		// WNOHANG) loop (internal/process/daemon.go) won the reap race for this
 		// child instead of this Wait() call — cmd.Wait() then returns ECHILD with
@@ -875,12 +1031,12 @@ func (m *LocalManager) reconcileStartHistory(name string, processHistory []types
 func (m *LocalManager) lmReconcileHistoryEntry(name string, p *types.ProcessHistory) error {
 	switch p.State {
 	case types.ProcessStateRunning, types.ProcessStateUnknown:
-		if procutil.IsAliveMatching(p.PGID, p.StartedAtTicks) {
+		if m.tracker.aliveMatching(p.PGID, p.StartedAtTicks) {
 			return fmt.Errorf("service already running with PGID %d", p.PGID)
 		}
 		m.lmMarkStaleHistoryEntry(name, p.PGID, types.ProcessStateStopped, "failed to mark stale running entry as stopped")
 	case types.ProcessStateStarting:
-		if procutil.IsAliveMatching(p.PGID, p.StartedAtTicks) {
+		if m.tracker.aliveMatching(p.PGID, p.StartedAtTicks) {
 			return fmt.Errorf("service already starting with PGID %d", p.PGID)
 		}
 		m.lmMarkStaleHistoryEntry(name, p.PGID, types.ProcessStateFailed, "failed to mark stale starting entry as failed")
@@ -928,20 +1084,107 @@ func (m *LocalManager) loadServiceForLaunch(name string) (types.ServiceCatalogEn
 	return service, config, resolvedSinks, nil
 }
 
-// launchAndCapture builds the service command, starts it, wires its log pipes,
-// and captures its process identity. On a successful Start it sets
+// launchStart starts a launch's cmd. With rlimits planned, it's stopped at
+// its exec to set them before it runs (see procutil.StartAtExec), so they
+// cap the service's first instruction and everything it forks while its
+// argv, exec-form or shell, stays as written; a failure to set them kills it
+// there. A type: forking launch's command is held back from the daemon's
+// SIGCHLD reaper (see procutil.StartHeld) until launchAndCapture returns,
+// so forkingCapture always sees how it exited.
+func (m *LocalManager) launchStart(config *types.ServiceConfig, cmd *exec.Cmd, rlimits []plannedRlimit) error {
+	forking := ServiceType(config.Type) == ServiceTypeForking
+	if len(rlimits) > 0 {
+		err := procutil.StartAtExec(m.held, cmd, func(pid int) error {
+			if err := limApplyRlimits(pid, rlimits); err != nil {
+				return fmt.Errorf("applying resource limits: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !forking {
+			procutil.ReleaseHeld(m.held, cmd.Process.Pid)
+		}
+		return nil
+	}
+	if !forking {
+		return cmd.Start()
+	}
+	return procutil.StartHeld(m.held, cmd)
+}
+
+// launchAndCapture builds the service command for instance (0 unless
+// instances: is set, see instanceEnv), starts it under its limits: block (see
+// planLimits), wires its log pipes, and captures its process identity. On a
+// successful Start it sets
 // *launchSuccess so the caller's deferred IO cleanup is skipped. startErrLabel
 // distinguishes "start command" from "restart command" in the error.
-func (m *LocalManager) launchAndCapture(service *types.ServiceCatalogEntry, config *types.ServiceConfig, lio launchIO, resolvedSinks []types.LogSink, launchSuccess *bool, startErrLabel string) (pgid int, startedAtTicks int64, err error) {
+func (m *LocalManager) launchAndCapture(service *types.ServiceCatalogEntry, config *types.ServiceConfig, instance int, lio launchIO, resolvedSinks []types.LogSink, launchSuccess *bool, startErrLabel string) (pgid int, startedAtTicks int64, err error) {
 	cmd, err := m.buildLaunchCommand(service, config, lio)
 	if err != nil {
 		return 0, 0, err
 	}
+	cmd.Env = lmOverlayEnvVars(cmd.Env, instanceEnv(config, instance))
+	boundSockets, sockErr := m.socketsAttach(service, config, cmd)
+	if sockErr != nil {
+		return 0, 0, fmt.Errorf("preparing sockets for %s: %w", service.Name, sockErr)
+	}
+	// A launch that never starts gives back the listeners it bound, so the
+	// port isn't held for a service that isn't running; ones an earlier
+	// launch bound stay held for it.
+	boundProxy := false
+	defer func() {
+		if *launchSuccess {
+			return
+		}
+		if boundSockets {
+			m.socketsRelease(service.Name)
+		}
+		if boundProxy {
+			m.proxyRelease(service.Name)
+		}
+	}()
+	proxyPort, boundProxy, err := m.proxyPrepare(service.Name, config, cmd)
+	if err != nil {
+		return 0, 0, fmt.Errorf("preparing proxy for %s: %w", service.Name, err)
+	}
+	notify, err := m.notifyListen(config, cmd)
+	if err != nil {
+		return 0, 0, fmt.Errorf("preparing %s for type: notify: %w", service.Name, err)
+	}
 
-	if startErr := cmd.Start(); startErr != nil {
+	staged, stageErr := m.tracker.stage(service.Name, cmd.SysProcAttr)
+	if stageErr != nil {
+		m.logger.Warn("placing service in its own cgroup, tracking by process group instead", "service", service.Name, "error", stageErr)
+	}
+	plan, err := planLimits(config.Limits, m.tracker.controllers(staged))
+	if err != nil {
+		m.tracker.abort(staged)
+		m.notifyDiscard(notify)
+		return 0, 0, err
+	}
+	if limitErr := m.tracker.writeLimits(staged, plan.cgroup); limitErr != nil {
+		m.tracker.abort(staged)
+		m.notifyDiscard(notify)
+		return 0, 0, fmt.Errorf("applying cgroup limits for %s: %w", service.Name, limitErr)
+	}
+	if len(plan.unenforced) > 0 {
+		m.logger.Warn("service limits need cgroup v2 tracking and are not enforced", "service", service.Name, "limits", plan.unenforced)
+	}
+	if startErr := m.launchStart(config, cmd, plan.rlimits); startErr != nil {
+		m.tracker.abort(staged)
+		m.notifyDiscard(notify)
 		return 0, 0, fmt.Errorf("%s: %w", startErrLabel, startErr)
 	}
+	if ServiceType(config.Type) == ServiceTypeForking {
+		defer procutil.ReleaseHeld(m.held, cmd.Process.Pid)
+	}
 	*launchSuccess = true
+	m.notifyServe(cmd.Process.Pid, notify)
+	if commitErr := m.tracker.commit(service.Name, cmd.Process.Pid, staged); commitErr != nil {
+		m.logger.Warn("naming service cgroup, tracking by process group instead", "service", service.Name, "pgid", cmd.Process.Pid, "error", commitErr)
+	}
 
 	// The leader's PID is also this launch's PGID (Setpgid, see
 	// buildLaunchCommand) and is already known here, before captureIdentity
@@ -957,22 +1200,33 @@ func (m *LocalManager) launchAndCapture(service *types.ServiceCatalogEntry, conf
 	// time before the reaper runs, so an instant-exit process is still readable
 	// and Getpgid can't race the reap into an ESRCH failure.
 	m.logger.Debug("process started", "service", service.Name, "pgid", cmd.Process.Pid)
-	return m.captureIdentity(cmd)
+	if ServiceType(config.Type) == ServiceTypeForking {
+		pgid, startedAtTicks, err = m.forkingCapture(service, config, cmd)
+	} else {
+		pgid, startedAtTicks, err = m.captureIdentity(service.Name, cmd)
+	}
+	if err == nil && proxyPort != 0 {
+		m.proxyRegister(service.Name, pgid, proxyPort)
+	}
+	if err == nil {
+		m.recordLaunchConfig(service, config, pgid)
+	}
+	return pgid, startedAtTicks, err
 }
 
 // recordStartedInstance persists the service instance and process-history rows
 // for a freshly started service. On any DB failure it kills the process group.
 func (m *LocalManager) recordStartedInstance(service *types.ServiceCatalogEntry, pgid int, startedAtTicks int64) (int, error) {
 	if regErr := m.db.RegisterServiceInstance(m.ctx, service.Name); regErr != nil {
-		return killAndWrap(pgid, regErr, "register service instance")
+		return killAndWrap(m.tracker, pgid, regErr, "register service instance")
 	}
 	if updErr := m.db.UpdateServiceInstance(m.ctx, service.Name, database.ServiceInstanceUpdate{
 		StartedAt: new(time.Now()),
 	}); updErr != nil {
-		return killAndWrap(pgid, updErr, "update service instance")
+		return killAndWrap(m.tracker, pgid, updErr, "update service instance")
 	}
 	if _, histErr := m.db.RegisterProcessHistoryEntry(m.ctx, pgid, startedAtTicks, service.Name, types.ProcessStateStarting); histErr != nil {
-		return killAndWrap(pgid, histErr, "register process history entry")
+		return killAndWrap(m.tracker, pgid, histErr, "register process history entry")
 	}
 	return pgid, nil
 }
@@ -984,10 +1238,10 @@ func (m *LocalManager) recordRestartedInstance(service *types.ServiceCatalogEntr
 		StartedAt:    new(time.Now()),
 		RestartCount: new(restartCount + 1),
 	}); updErr != nil {
-		return killAndWrap(pgid, updErr, "update service instance")
+		return killAndWrap(m.tracker, pgid, updErr, "update service instance")
 	}
 	if _, histErr := m.db.RegisterProcessHistoryEntry(m.ctx, pgid, startedAtTicks, service.Name, types.ProcessStateStarting); histErr != nil {
-		return killAndWrap(pgid, histErr, "register process history entry")
+		return killAndWrap(m.tracker, pgid, histErr, "register process history entry")
 	}
 	return pgid, nil
 }
@@ -1001,11 +1255,11 @@ func (m *LocalManager) recordRestartedInstance(service *types.ServiceCatalogEntr
 // out-of-band without a clean `eos stop`, which never got the chance to
 // remove this row) and is not an error: the caller self-heals by proceeding
 // with the start.
-func lmCheckAlreadyRunning(serviceInstance *types.ServiceInstance, processHistory []types.ProcessHistory) error {
+func lmCheckAlreadyRunning(serviceInstance *types.ServiceInstance, processHistory []types.ProcessHistory, tracker groupTracker) error {
 	if serviceInstance == nil {
 		return nil
 	}
-	if livePGID := livePGIDInHistory(processHistory); livePGID > 0 {
+	if livePGID := livePGIDInHistory(processHistory, tracker); livePGID > 0 {
 		return ErrAlreadyRunning
 	}
 	return nil
@@ -1050,7 +1304,7 @@ func (m *LocalManager) StartService(ctx context.Context, name string) (pgid int,
 		return 0, fmt.Errorf("get process history for %s: %w", name, err)
 	}
 
-	if runningErr := lmCheckAlreadyRunning(serviceInstance, processHistory); runningErr != nil {
+	if runningErr := lmCheckAlreadyRunning(serviceInstance, processHistory, m.tracker); runningErr != nil {
 		return 0, runningErr
 	}
 
@@ -1073,8 +1327,13 @@ func (m *LocalManager) StartService(ctx context.Context, name string) (pgid int,
 		return 0, cmdErr
 	}
 
-	m.logger.Debug("launching service", "service", name, "cmd", config.Command)
-	pgid, startedAtTicks, err := m.launchAndCapture(&service, config, lio, resolvedSinks, &launchSuccess, "start command")
+	if hookPGID, hookTicks, hookErr := m.runHook(&service, config, hookPreStart); hookErr != nil {
+		m.recordPreStartFailure(name, hookPGID, hookTicks, hookErr, true)
+		return 0, hookErr
+	}
+
+	m.logger.Debug("launching service", "service", name, "cmd", config.Command.String())
+	pgid, startedAtTicks, err := m.launchAndCapture(&service, config, 0, lio, resolvedSinks, &launchSuccess, "start command")
 	if err != nil {
 		return pgid, err
 	}
@@ -1083,8 +1342,13 @@ func (m *LocalManager) StartService(ctx context.Context, name string) (pgid int,
 	if err != nil {
 		return pgid, err
 	}
+	m.proxyActivate(name, pgid)
 	m.logger.Debug("state=Starting recorded", "service", name, "pgid", pgid)
+	if extraErr := m.launchExtraInstances(&service, config, resolvedSinks, "start command"); extraErr != nil {
+		return pgid, extraErr
+	}
 
+	m.runHookLogged(&service, config, hookPostStart)
 	return pgid, nil
 }
 
@@ -1161,13 +1425,27 @@ func (m *LocalManager) RestartService(ctx context.Context, name string, gracePer
 		return 0, stopErr
 	}
 
+	if hookPGID, hookTicks, hookErr := m.runHook(&service, config, hookPreStart); hookErr != nil {
+		m.recordPreStartFailure(name, hookPGID, hookTicks, hookErr, false)
+		return 0, hookErr
+	}
+
 	m.logger.Debug("stop complete, launching restart", "service", name)
-	pgid, startedAtTicks, err := m.launchAndCapture(&service, config, lio, resolvedSinks, &launchSuccess, "restart command")
+	pgid, startedAtTicks, err := m.launchAndCapture(&service, config, 0, lio, resolvedSinks, &launchSuccess, "restart command")
 	if err != nil {
 		return pgid, err
 	}
 
-	return m.recordRestartedInstance(&service, serviceInstance.RestartCount, pgid, startedAtTicks)
+	pgid, err = m.recordRestartedInstance(&service, serviceInstance.RestartCount, pgid, startedAtTicks)
+	if err != nil {
+		return pgid, err
+	}
+	m.proxyActivate(name, pgid)
+	if extraErr := m.launchExtraInstances(&service, config, resolvedSinks, "restart command"); extraErr != nil {
+		return pgid, extraErr
+	}
+	m.runHookLogged(&service, config, hookPostStart)
+	return pgid, nil
 }
 
 func (m *LocalManager) prepareLogFiles(serviceName string, config *types.ServiceConfig) (logFile *RotatingFileWriter, errorLogFile *RotatingFileWriter, err error) {
@@ -1197,11 +1475,13 @@ type StopServiceResult struct {
 	StaleData map[int]string
 }
 
-// waitForPendingStops polls until every pending PID has exited or the grace
-// period elapses. PIDs still alive once the grace period is exceeded are marked
-// in errored ("exceeded grace period"). Returns the set that exited cleanly, or
-// canceled=true when m.ctx is done (caller should abandon and re-check later).
-func (m *LocalManager) waitForPendingStops(name string, pending map[int]bool, errored map[int]string, requestStartTime time.Time, gracePeriod, tickerPeriod time.Duration) (stopped map[int]bool, canceled bool) {
+// waitForPendingStops polls until every pending PID has exited or
+// policy.Timeout elapses, SIGKILLing what a KillModeMixed leader leaves
+// behind as soon as the leader is gone. PIDs still alive once the grace
+// period is exceeded are marked in errored ("exceeded grace period"). Returns
+// the set that exited cleanly, or canceled=true when m.ctx is done (caller
+// should abandon and re-check later).
+func (m *LocalManager) waitForPendingStops(name string, pending map[int]bool, errored map[int]string, requestStartTime time.Time, policy StopPolicy, tickerPeriod time.Duration) (stopped map[int]bool, canceled bool) {
 	ticker := time.NewTicker(tickerPeriod)
 	defer ticker.Stop()
 
@@ -1211,12 +1491,19 @@ func (m *LocalManager) waitForPendingStops(name string, pending map[int]bool, er
 	for {
 		select {
 		case <-ticker.C:
-			if time.Since(requestStartTime) > gracePeriod {
+			if time.Since(requestStartTime) > policy.Timeout {
 				lmMarkGracePeriodExceeded(pending, stopped, errored)
 				return stopped, false
 			}
 
-			lmPollPendingExits(pending, stopped)
+			if policy.KillMode == KillModeMixed {
+				for pendingPID := range pending {
+					if !stopped[pendingPID] && stopKillMixedRemainder(pendingPID, m.mainPID(pendingPID), m.tracker) {
+						m.logger.Debug("leader exited, killing the rest of its launch", "service", name, "pgid", pendingPID)
+					}
+				}
+			}
+			lmPollPendingExits(pending, stopped, m.tracker)
 
 			if len(stopped) == countPending {
 				m.logger.Debug("all processes exited", "service", name, "elapsed", time.Since(requestStartTime))
@@ -1241,12 +1528,12 @@ func lmMarkGracePeriodExceeded(pending map[int]bool, stopped map[int]bool, error
 
 // lmPollPendingExits checks each pending PID not already marked stopped and
 // adds it to stopped if it's no longer alive.
-func lmPollPendingExits(pending map[int]bool, stopped map[int]bool) {
+func lmPollPendingExits(pending map[int]bool, stopped map[int]bool, tracker groupTracker) {
 	for pendingPID := range pending {
 		if _, ok := stopped[pendingPID]; ok {
 			continue
 		}
-		if !isProcessAlive(pendingPID) {
+		if !tracker.alive(pendingPID) {
 			stopped[pendingPID] = true
 		}
 	}
@@ -1255,6 +1542,8 @@ func lmPollPendingExits(pending map[int]bool, stopped map[int]bool) {
 func (m *LocalManager) StopService(_ context.Context, name string, gracePeriod time.Duration, tickerPeriod time.Duration) (result StopServiceResult, err error) {
 	unlock := m.lockService(name)
 	defer unlock()
+	defer m.socketsRelease(name)
+	defer m.proxyRelease(name)
 	return m.stopServiceLocked(name, gracePeriod, tickerPeriod)
 }
 
@@ -1262,6 +1551,10 @@ func (m *LocalManager) StopService(_ context.Context, name string, gracePeriod t
 // per-service lock (via lockService); StopService acquires it, and
 // RestartService calls this directly because it holds the lock for the whole
 // stop-then-start sequence.
+//
+// A pre_stop hook runs before SIGTERM and a post_stop hook once everything
+// has exited, both only when something of the service was alive to stop. A
+// failing stop hook is logged and the stop goes ahead regardless.
 func (m *LocalManager) stopServiceLocked(name string, gracePeriod time.Duration, tickerPeriod time.Duration) (result StopServiceResult, err error) {
 	_, span := m.telemetry.StartSpan(m.ctx, "eos.service.stop", name)
 	defer func() {
@@ -1269,9 +1562,26 @@ func (m *LocalManager) stopServiceLocked(name string, gracePeriod time.Duration,
 		otelx.RecordOutcome(m.ctx, m.telemetry.ServiceStops, name, err)
 	}()
 
+	service, config, loaded := m.loadServiceForStop(name)
+	policy := m.stopPolicyFor(name, config, gracePeriod)
+	runHooks := loaded && m.stopHooksApply(name, config)
+	if runHooks {
+		m.runHookLogged(&service, config, hookPreStop)
+	}
+	result, err = m.signalAndAwaitStop(name, policy, tickerPeriod)
+	if err == nil && runHooks && len(result.Stopped) > 0 && len(result.Errored) == 0 {
+		m.runHookLogged(&service, config, hookPostStop)
+	}
+	return result, err
+}
+
+// signalAndAwaitStop sends policy's signal to every live process group in
+// name's history and waits up to policy.Timeout for them to exit, updating
+// their rows.
+func (m *LocalManager) signalAndAwaitStop(name string, policy StopPolicy, tickerPeriod time.Duration) (StopServiceResult, error) {
 	requestStartTime := time.Now()
-	m.logger.Debug("sending SIGTERM", "service", name)
-	stopResult, err := m.stopServiceWithSignal(name, syscall.SIGTERM)
+	m.logger.Debug("sending stop signal", "service", name, "signal", policy.Signal, "kill_mode", policy.KillMode)
+	stopResult, err := m.sendStopSignal(name, policy)
 
 	if err != nil {
 		return StopServiceResult{}, err
@@ -1306,7 +1616,7 @@ func (m *LocalManager) stopServiceLocked(name string, gracePeriod time.Duration,
 	}
 
 	erroredProcesses := stopResult.Errored
-	stoppedProcesses, canceled := m.waitForPendingStops(name, stopResult.Pending, erroredProcesses, requestStartTime, gracePeriod, tickerPeriod)
+	stoppedProcesses, canceled := m.waitForPendingStops(name, stopResult.Pending, erroredProcesses, requestStartTime, policy, tickerPeriod)
 	if canceled {
 		// User canceled, return empty result. System will check all again.
 		return StopServiceResult{}, nil
@@ -1334,6 +1644,20 @@ func isProcessAlive(pgid int) bool {
 	return procutil.IsAlive(pgid)
 }
 
+// IsProcessGroupAlive reports whether the instance launched as pgid still has
+// a live process, through its cgroup when it has one (see groupTracker). The
+// health monitor asks this instead of procutil.IsAlive so a service whose
+// real worker detached from the process group isn't misread as crashed.
+func (m *LocalManager) IsProcessGroupAlive(pgid int) bool {
+	return m.tracker.alive(pgid)
+}
+
+// GetServiceOOMKills reports the OOM-kill count of pgid's cgroup leaf (see
+// groupTracker.oomKills).
+func (m *LocalManager) GetServiceOOMKills(pgid int) (kills int, ok bool) {
+	return m.tracker.oomKills(pgid)
+}
+
 func (m *LocalManager) ForceStopService(_ context.Context, name string) (result StopServiceResult, err error) {
 	unlock := m.lockService(name)
 	defer unlock()
@@ -1343,6 +1667,8 @@ func (m *LocalManager) ForceStopService(_ context.Context, name string) (result
 		otelx.End(span, err)
 		otelx.RecordOutcome(m.ctx, m.telemetry.ServiceStops, name, err)
 	}()
+	defer m.socketsRelease(name)
+	defer m.proxyRelease(name)
 
 	return m.forceKillServiceLocked(name)
 }
@@ -1354,7 +1680,7 @@ func (m *LocalManager) ForceStopService(_ context.Context, name string) (result
 // an automatic restart-after-timeout classify and persist history the same
 // way.
 func (m *LocalManager) forceKillServiceLocked(name string) (StopServiceResult, error) {
-	stopResult, err := m.stopServiceWithSignal(name, syscall.SIGKILL)
+	stopResult, err := m.sendStopSignal(name, forceKillPolicy)
 	if err != nil {
 		return StopServiceResult{}, err
 	}
@@ -1413,7 +1739,9 @@ type StopRequestResult struct {
 	Pending     map[int]bool
 }
 
-func (m *LocalManager) stopServiceWithSignal(name string, signal syscall.Signal) (StopRequestResult, error) {
+// sendStopSignal delivers policy's signal to every live launch in name's
+// history (see groupTracker.stop), classifying each row's outcome.
+func (m *LocalManager) sendStopSignal(name string, policy StopPolicy) (StopRequestResult, error) {
 	processHistory, err := m.db.GetProcessHistoryEntriesByServiceName(m.ctx, name)
 	if err != nil {
 		return StopRequestResult{}, fmt.Errorf("getting process history: %w", err)
@@ -1424,7 +1752,7 @@ func (m *LocalManager) stopServiceWithSignal(name string, signal syscall.Signal)
 	errored := make(map[int]string)
 
 	for i := range processHistory {
-		lmSignalHistoryEntry(&processHistory[i], signal, pending, alreadyDead, errored)
+		lmSignalHistoryEntry(&processHistory[i], m.mainPID(processHistory[i].PGID), policy, m.tracker, pending, alreadyDead, errored)
 	}
 
 	return StopRequestResult{
@@ -1434,8 +1762,9 @@ func (m *LocalManager) stopServiceWithSignal(name string, signal syscall.Signal)
 	}, nil
 }
 
-// lmSignalHistoryEntry signals p's process group with signal if it's still
-// alive-matching, classifying the outcome into pending/alreadyDead/errored.
+// lmSignalHistoryEntry stops p's launch per policy, with main its MAINPID
+// (see groupTracker.stop), if it's still alive-matching, classifying the
+// outcome into pending/alreadyDead/errored.
 //
 // The decision to check liveness and attempt a signal never gates on p.State
 // (Failed/Stopped included): that field is only a snapshot from whenever it
@@ -1455,14 +1784,14 @@ func (m *LocalManager) stopServiceWithSignal(name string, signal syscall.Signal)
 // checked, but nothing changed, so its history row (State/StoppedAt) is left
 // untouched rather than rewritten to "just stopped now" on every single stop
 // call. A long-lived service accumulates one process_history row per past
-// restart, and stopServiceWithSignal is called against the service's entire
+// restart, and sendStopSignal is called against the service's entire
 // history, not just its current row — without this, every terminal-state row
 // ever recorded would have its StoppedAt refreshed to time.Now() on every
 // future eos stop/restart, corrupting "when did this actually stop" for
 // display/audit purposes, and (worse) could shift which row
 // GetMostRecentProcessHistoryEntry picks as most recent out from under a
 // caller relying on StoppedAt/started_at ordering.
-func lmSignalHistoryEntry(p *types.ProcessHistory, signal syscall.Signal, pending, alreadyDead map[int]bool, errored map[int]string) {
+func lmSignalHistoryEntry(p *types.ProcessHistory, main int, policy StopPolicy, tracker groupTracker, pending, alreadyDead map[int]bool, errored map[int]string) {
 	processPGID := p.PGID
 	wasTerminal := p.State == types.ProcessStateFailed || p.State == types.ProcessStateStopped
 
@@ -1475,14 +1804,14 @@ func lmSignalHistoryEntry(p *types.ProcessHistory, signal syscall.Signal, pendin
 	// (see its doc comment) — only an actual start-time mismatch means the
 	// PGID was recycled, and only then is the process we started already
 	// gone.
-	if !procutil.IsAliveMatching(processPGID, p.StartedAtTicks) {
+	if !tracker.aliveMatching(processPGID, p.StartedAtTicks) {
 		if !wasTerminal {
 			alreadyDead[processPGID] = true
 		}
 		return
 	}
 
-	err := syscall.Kill(-processPGID, signal)
+	err := tracker.stop(processPGID, main, policy)
 	switch {
 	case errors.Is(err, syscall.ESRCH):
 		if !wasTerminal {
@@ -1496,7 +1825,7 @@ func lmSignalHistoryEntry(p *types.ProcessHistory, signal syscall.Signal, pendin
 		// group whose leader is now a zombie returns EPERM. Classify by
 		// liveness, not the raw errno: if it's no longer alive-matching it's
 		// already gone, not a stop failure.
-		if !procutil.IsAliveMatching(processPGID, p.StartedAtTicks) {
+		if !tracker.aliveMatching(processPGID, p.StartedAtTicks) {
 			if !wasTerminal {
 				alreadyDead[processPGID] = true
 			}
@@ -1575,8 +1904,18 @@ var shellSpecialBuiltins = map[string]bool{
 // — because misparsing any of those would either name the wrong binary or
 // block a service that actually works. A missed detection costs what the
 // check is trying to fix; a false one costs more.
-func FirstCommandBinary(command string) (string, bool) {
-	trimmed := strings.TrimSpace(command)
+//
+// An exec-form command has none of that ambiguity: argv[0] is the binary,
+// exactly as written. It still reports ok=false when argv[0] is
+// path-qualified, since that isn't looked up on PATH at all.
+func FirstCommandBinary(command types.ServiceCommand) (string, bool) {
+	if command.IsExec() {
+		if command.Argv[0] == "" || strings.ContainsRune(command.Argv[0], '/') {
+			return "", false
+		}
+		return command.Argv[0], true
+	}
+	trimmed := strings.TrimSpace(command.Shell)
 	if trimmed == "" {
 		return "", false
 	}
@@ -1605,7 +1944,13 @@ func FirstCommandBinary(command string) (string, bool) {
 // rather than mistake for the command itself.
 func isEnvAssignment(tok string) bool {
 	name, _, found := strings.Cut(tok, "=")
-	if !found || name == "" {
+	return found && isEnvName(name)
+}
+
+// isEnvName reports whether name is a valid shell variable name: letters,
+// digits and underscores, not starting with a digit.
+func isEnvName(name string) bool {
+	if name == "" {
 		return false
 	}
 	for i := 0; i < len(name); i++ {
@@ -1624,15 +1969,39 @@ func isEnvAssignment(tok string) bool {
 // file directly under any directory named in pathValue (a colon-separated
 // PATH string).
 func binaryInPathValue(binary, pathValue string) bool {
+	_, ok := lmFindInPathValue(binary, pathValue)
+	return ok
+}
+
+// lmFindInPathValue returns the full path of the first executable binary
+// under a directory named in pathValue, the way a shell's PATH search would.
+func lmFindInPathValue(binary, pathValue string) (string, bool) {
 	for _, dir := range filepath.SplitList(pathValue) {
 		if dir == "" {
 			continue
 		}
 		if lmCheckRuntimeBinary(dir, binary) == nil {
-			return true
+			return filepath.Join(dir, binary), true
 		}
 	}
-	return false
+	return "", false
+}
+
+// lmResolveExecBinary resolves a bare exec-form argv[0] against the PATH in
+// the service's own environment rather than the daemon's, so an exec-form
+// command finds the same binary /bin/sh would have for the shell form. A
+// path-qualified argv[0], or one not found there, is returned unchanged:
+// os/exec resolves a relative path against cmd.Dir, and a missing binary
+// fails at Start with its own error.
+func lmResolveExecBinary(argv0 string, env []string) string {
+	if strings.ContainsRune(argv0, '/') {
+		return argv0
+	}
+	_, pathValue := doesEnvVarAlreadyExist("PATH=", env)
+	if path, ok := lmFindInPathValue(argv0, pathValue); ok {
+		return path
+	}
+	return argv0
 }
 
 // hostToolDirGlobs are conventional per-user install locations for the
@@ -1693,7 +2062,12 @@ func commandNotFoundError(binary string, config *types.ServiceConfig) error {
 // that dies on a restart loop with no named cause. It only fires when
 // FirstCommandBinary is certain command is a single simple invocation;
 // anything else is left to fail, if it does, at actual launch time instead.
+// A path-qualified exec-form argv[0] is checked directly instead, relative
+// to the service directory the launch runs in.
 func validateCommandBinary(config *types.ServiceConfig, serviceDirectoryPath string) error {
+	if config.Command.IsExec() && strings.ContainsRune(config.Command.Argv[0], '/') {
+		return lmCheckCommandPath(config.Command.Argv[0], serviceDirectoryPath)
+	}
 	binary, ok := FirstCommandBinary(config.Command)
 	if !ok {
 		return nil
@@ -1711,6 +2085,27 @@ func validateCommandBinary(config *types.ServiceConfig, serviceDirectoryPath str
 	return commandNotFoundError(binary, config)
 }
 
+// lmCheckCommandPath verifies a path-qualified exec-form argv[0] names an
+// executable file, resolving a relative one against the service directory
+// the way the launch's working directory will.
+func lmCheckCommandPath(argv0, serviceDirectoryPath string) error {
+	path := argv0
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(serviceDirectoryPath, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("command %s: %w", argv0, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("command %s is a directory", argv0)
+	}
+	if info.Mode()&0111 == 0 {
+		return fmt.Errorf("command %s is not executable", argv0)
+	}
+	return nil
+}
+
 // lmResolveRuntimeDir expands a relative runtime path against the user's
 // home directory; an absolute path is returned unchanged.
 func lmResolveRuntimeDir(path string) (string, error) {
@@ -1767,21 +2162,11 @@ func ValidateRuntimePath(runtime types.Runtime) error {
 	return nil
 }
 
+// buildEnvironment returns the environment a service launches with (see
+// envLayers for the order its layers apply in).
 func buildEnvironment(config *types.ServiceConfig, serviceDirectoryPath string) ([]string, error) {
-	env := os.Environ()
-
-	env = lmApplyRuntimePathEnv(config.Runtime.Path, env)
-	env = lmApplyPortEnv(config.Port, env)
-
-	if config.EnvFile != "" {
-		envFileVars, err := ParseEnvFile(config, serviceDirectoryPath)
-		if err != nil {
-			return nil, err
-		}
-		env = lmOverlayEnvVars(env, envFileVars)
-	}
-
-	return env, nil
+	env, _, err := envLayers(config, serviceDirectoryPath, os.Environ())
+	return env, err
 }
 
 // lmApplyRuntimePathEnv prepends runtimePath onto env's PATH entry (creating
@@ -1798,6 +2183,23 @@ func lmApplyRuntimePathEnv(runtimePath string, env []string) []string {
 	return env
 }
 
+// lmApplyUserEnv points HOME, USER and LOGNAME at userName's own account when
+// one is set, so a service dropped to another identity doesn't inherit the
+// daemon's (HOME=/root under the system-wide unit, a directory the dropped
+// process can't even read). It runs before the env_file overlay so an explicit
+// override there still wins. An unresolvable user leaves env unchanged: the
+// launch path reports that through resolveLaunchCredential instead.
+func lmApplyUserEnv(userName string, env []string) []string {
+	if userName == "" {
+		return env
+	}
+	u, err := userutil.LookupUser(userName)
+	if err != nil {
+		return env
+	}
+	return lmOverlayEnvVars(env, []string{"HOME=" + u.HomeDir, "USER=" + u.Username, "LOGNAME=" + u.Username})
+}
+
 // lmApplyPortEnv appends a PORT entry to env, or returns env unchanged when
 // port is unset (0).
 func lmApplyPortEnv(port int, env []string) []string {
@@ -1823,74 +2225,70 @@ func lmOverlayEnvVars(env []string, overlay []string) []string {
 	return env
 }
 
-// ResolveEnvFilePath returns the absolute path to a service's env_file,
-// rejecting paths that escape the service directory. Returns an empty string
-// if config.EnvFile is unset.
-func ResolveEnvFilePath(config *types.ServiceConfig, serviceDirectoryPath string) (string, error) {
-	if config.EnvFile == "" {
-		return "", nil
+// ResolveEnvFilePaths returns the absolute paths to a service's env_file
+// entries, in order, rejecting any that escape the service directory. Returns
+// nil if config.EnvFile is unset.
+func ResolveEnvFilePaths(config *types.ServiceConfig, serviceDirectoryPath string) ([]string, error) {
+	if len(config.EnvFile) == 0 {
+		return nil, nil
 	}
 
 	cleanedServiceDirectoryPath := filepath.Clean(serviceDirectoryPath)
-	envFilePath := filepath.Clean(filepath.Join(cleanedServiceDirectoryPath, config.EnvFile))
+	envFilePaths := make([]string, 0, len(config.EnvFile))
+	for _, envFile := range config.EnvFile {
+		envFilePath := filepath.Clean(filepath.Join(cleanedServiceDirectoryPath, envFile))
 
-	// Prevents path traversal outside service directory
-	if !strings.HasPrefix(envFilePath, cleanedServiceDirectoryPath+string(filepath.Separator)) && envFilePath != cleanedServiceDirectoryPath {
-		return "", fmt.Errorf("env file path %q escapes service directory", config.EnvFile)
+		// Prevents path traversal outside service directory
+		if !strings.HasPrefix(envFilePath, cleanedServiceDirectoryPath+string(filepath.Separator)) && envFilePath != cleanedServiceDirectoryPath {
+			return nil, fmt.Errorf("env file path %q escapes service directory", envFile)
+		}
+		envFilePaths = append(envFilePaths, envFilePath)
 	}
 
-	return envFilePath, nil
+	return envFilePaths, nil
 }
 
-// ParseEnvFile resolves the KEY=VALUE pairs defined in a service's env_file,
-// relative to its service directory. Returns nil if config.EnvFile is unset.
-// Later duplicate keys within the file override earlier ones.
-func ParseEnvFile(config *types.ServiceConfig, serviceDirectoryPath string) ([]string, error) {
-	if config.EnvFile == "" {
+// ParseEnvFile resolves the variables defined in a service's env_file
+// entries, relative to its service directory, as KEY=VALUE pairs. Files are
+// layered in order, a later file overriding an earlier one's keys, and
+// ${NAME} references resolve against base (the environment the files are
+// layered over) plus everything read before them. Returns nil if
+// config.EnvFile is unset. A syntax error names the file and line.
+func ParseEnvFile(config *types.ServiceConfig, serviceDirectoryPath string, base []string) ([]string, error) {
+	if len(config.EnvFile) == 0 {
 		return nil, nil
 	}
 
-	envFilePath, pathErr := ResolveEnvFilePath(config, serviceDirectoryPath)
+	envFilePaths, pathErr := ResolveEnvFilePaths(config, serviceDirectoryPath)
 	if pathErr != nil {
 		return nil, pathErr
 	}
 
-	// #nosec G304 - envFilePath validated against traversal by ResolveEnvFilePath above
-	envFileContents, readErr := os.ReadFile(envFilePath)
-	if readErr != nil {
-		return nil, fmt.Errorf("reading env file: %w", readErr)
-	}
-
-	return lmParseEnvFileLines(string(envFileContents)), nil
-}
-
-// lmParseEnvFileLines parses KEY=VALUE lines out of env file contents,
-// skipping blank lines, #-comments, and lines with no "="; later duplicate
-// keys override earlier ones.
-func lmParseEnvFileLines(contents string) []string {
 	envFileVars := []string{}
-	for envVar := range strings.SplitSeq(contents, "\n") {
-		envVar = strings.TrimSpace(envVar)
-		if envVar == "" {
-			continue
+	lookup := func(name string) (string, bool) {
+		if index, value := doesEnvVarAlreadyExist(name+"=", envFileVars); index > -1 {
+			return value, true
 		}
-		if strings.HasPrefix(envVar, "#") {
-			continue
-		}
-		before, _, found := strings.Cut(envVar, "=")
-		if !found {
-			continue
+		index, value := doesEnvVarAlreadyExist(name+"=", base)
+		return value, index > -1
+	}
+	for i, envFilePath := range envFilePaths {
+		// #nosec G304 - envFilePath validated against traversal by ResolveEnvFilePaths above
+		envFileContents, readErr := os.ReadFile(envFilePath)
+		if readErr != nil {
+			return nil, fmt.Errorf("reading env file: %w", readErr)
 		}
 
-		index, _ := doesEnvVarAlreadyExist(before+"=", envFileVars)
-		if index > -1 {
-			envFileVars[index] = envVar
-		} else {
-			envFileVars = append(envFileVars, envVar)
+		entries, parseErr := parseDotenv(string(envFileContents), lookup)
+		if parseErr != nil {
+			return nil, fmt.Errorf("env_file %s: %w", config.EnvFile[i], parseErr)
+		}
+		for _, entry := range entries {
+			envFileVars = lmOverlayEnvVars(envFileVars, []string{entry.Name + "=" + entry.Value})
 		}
 	}
 
-	return envFileVars
+	return envFileVars, nil
 }
 
 func doesEnvVarAlreadyExist(envName string, env []string) (int, string) {
@@ -203,7 +203,7 @@ func TestStartService(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -259,7 +259,7 @@ func TestStartService_CommandBinaryMissing(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "nonexistent-binary-xyz-262",
+		Command: types.ServiceCommand{Shell: "nonexistent-binary-xyz-262"},
 	}
 
 	yamlData, err := yaml.Marshal(testFile)
@@ -306,7 +306,7 @@ func TestLocalManager_GetServiceExitCode_CleanExit(t *testing.T) {
 	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	manager := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
 
-	testFile := &types.ServiceConfig{Name: "one-shot", Command: "exit 0"}
+	testFile := &types.ServiceConfig{Name: "one-shot", Command: types.ServiceCommand{Shell: "exit 0"}}
 	yamlData, err := yaml.Marshal(testFile)
 	if err != nil {
 		t.Fatalf("Failed to marshal test config: %v", err)
@@ -360,7 +360,7 @@ func TestLocalManager_GetServiceExitCode_NonZeroExit(t *testing.T) {
 	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	manager := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t), WithExecutor(fakeExecutor{}))
 
-	testFile := &types.ServiceConfig{Name: "one-shot-fail", Command: "exit 3"}
+	testFile := &types.ServiceConfig{Name: "one-shot-fail", Command: types.ServiceCommand{Shell: "exit 3"}}
 	yamlData, err := yaml.Marshal(testFile)
 	if err != nil {
 		t.Fatalf("Failed to marshal test config: %v", err)
@@ -427,7 +427,7 @@ func TestStartServiceStaleStartingEntryIsIgnored(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -511,7 +511,7 @@ func TestStartServiceSelfHealsStaleServiceInstance(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -585,7 +585,7 @@ func TestStartServiceBlocksWhenServiceInstanceLive(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -649,7 +649,7 @@ func TestStartServiceBlocksWhenUnknownEntryLive(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -716,7 +716,7 @@ func TestStartServiceSelfHealsStaleUnknownEntry(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -784,12 +784,12 @@ func TestStartServiceWithValidEnvLocation(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
 		},
-		EnvFile: ".env",
+		EnvFile: types.EnvFileList{".env"},
 	}
 
 	yamlData, err := yaml.Marshal(testFile)
@@ -836,12 +836,12 @@ func TestStartServiceWithInvalidEnvLocation(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "./start-script.sh",
+		Command: types.ServiceCommand{Shell: "./start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
 		},
-		EnvFile: "../../test/../../dummy",
+		EnvFile: types.EnvFileList{"../../test/../../dummy"},
 	}
 
 	yamlData, err := yaml.Marshal(testFile)
@@ -1057,9 +1057,9 @@ func TestStopServiceWithSignal_deadHistoryRows(t *testing.T) {
 		t.Fatalf("RegisterProcessHistoryEntry failed: %v", err)
 	}
 
-	result, err := mgr.stopServiceWithSignal(name, syscall.SIGTERM)
+	result, err := mgr.sendStopSignal(name, StopPolicy{Signal: syscall.SIGTERM})
 	if err != nil {
-		t.Fatalf("stopServiceWithSignal: %v", err)
+		t.Fatalf("sendStopSignal: %v", err)
 	}
 	if len(result.Pending)+len(result.Errored)+len(result.AlreadyDead) != 0 {
 		t.Errorf("expected empty result (already-terminal, still-dead rows left untouched), got %+v", result)
@@ -1082,9 +1082,9 @@ func TestStopServiceWithSignal_deadPGID(t *testing.T) {
 		t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 	}
 
-	result, err := mgr.stopServiceWithSignal(name, syscall.SIGTERM)
+	result, err := mgr.sendStopSignal(name, StopPolicy{Signal: syscall.SIGTERM})
 	if err != nil {
-		t.Fatalf("stopServiceWithSignal: %v", err)
+		t.Fatalf("sendStopSignal: %v", err)
 	}
 	if _, ok := result.AlreadyDead[deadPGID]; !ok {
 		t.Errorf("expected deadPGID in AlreadyDead, got %+v", result)
@@ -1132,9 +1132,9 @@ func TestStopServiceWithSignal_reusedPGID(t *testing.T) {
 		t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 	}
 
-	result, err := mgr.stopServiceWithSignal(name, syscall.SIGTERM)
+	result, err := mgr.sendStopSignal(name, StopPolicy{Signal: syscall.SIGTERM})
 	if err != nil {
-		t.Fatalf("stopServiceWithSignal: %v", err)
+		t.Fatalf("sendStopSignal: %v", err)
 	}
 	if _, ok := result.AlreadyDead[pgid]; !ok {
 		t.Errorf("expected reused pgid %d in AlreadyDead, got %+v", pgid, result)
@@ -1154,7 +1154,7 @@ func TestStopServiceWithSignal_reusedPGID(t *testing.T) {
 // real long-running child, which keeps running under the same PGID after the
 // wrapper (the group leader, whose own pid is the stored PGID) has been
 // reaped. Before the fix, IsAliveMatching read this as "already dead" (the
-// leader's own StartTime lookup failed) and stopServiceWithSignal never sent
+// leader's own StartTime lookup failed) and sendStopSignal never sent
 // a signal at all — the real process kept running while eos recorded a clean
 // stop.
 func TestStopServiceWithSignal_leaderReapedChildSurvives(t *testing.T) {
@@ -1190,9 +1190,9 @@ func TestStopServiceWithSignal_leaderReapedChildSurvives(t *testing.T) {
 		t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 	}
 
-	result, err := mgr.stopServiceWithSignal(name, syscall.SIGTERM)
+	result, err := mgr.sendStopSignal(name, StopPolicy{Signal: syscall.SIGTERM})
 	if err != nil {
-		t.Fatalf("stopServiceWithSignal: %v", err)
+		t.Fatalf("sendStopSignal: %v", err)
 	}
 	if _, ok := result.AlreadyDead[pgid]; ok {
 		t.Errorf("pgid %d landed in AlreadyDead — signal was skipped, reproducing issue #215", pgid)
@@ -1247,9 +1247,9 @@ func TestStopServiceWithSignal_failedStateStillAlive(t *testing.T) {
 		t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 	}
 
-	result, err := mgr.stopServiceWithSignal(name, syscall.SIGTERM)
+	result, err := mgr.sendStopSignal(name, StopPolicy{Signal: syscall.SIGTERM})
 	if err != nil {
-		t.Fatalf("stopServiceWithSignal: %v", err)
+		t.Fatalf("sendStopSignal: %v", err)
 	}
 	if _, ok := result.AlreadyDead[pgid]; ok {
 		t.Errorf("pgid %d landed in AlreadyDead — Failed state skipped the liveness check, reproducing issue #215's second gate", pgid)
@@ -1264,7 +1264,7 @@ func TestStopServiceWithSignal_failedStateStillAlive(t *testing.T) {
 
 // TestRestartService_failedStateStillAlive proves the restart flow shares the
 // same fix: RestartService's stop-before-restart step (lmStopForRestart ->
-// stopServiceLocked -> stopServiceWithSignal) must also kill a Failed-marked
+// stopServiceLocked -> sendStopSignal) must also kill a Failed-marked
 // but genuinely live old instance before launching the new one, not leave it
 // running alongside the freshly started replacement.
 func TestRestartService_failedStateStillAlive(t *testing.T) {
@@ -1295,7 +1295,7 @@ func TestRestartService_failedStateStillAlive(t *testing.T) {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		t.Fatalf("mkdir: %v", err)
 	}
-	cfg := &types.ServiceConfig{Name: name, Command: "sleep 30"}
+	cfg := &types.ServiceConfig{Name: name, Command: types.ServiceCommand{Shell: "sleep 30"}}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
@@ -1361,7 +1361,7 @@ func TestRestartService_escalatesToSIGKILL_whenSIGTERMIgnored(t *testing.T) {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		t.Fatalf("mkdir: %v", err)
 	}
-	cfg := &types.ServiceConfig{Name: name, Command: "sleep 30"}
+	cfg := &types.ServiceConfig{Name: name, Command: types.ServiceCommand{Shell: "sleep 30"}}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
@@ -1679,7 +1679,7 @@ func TestUpdateProcessHistoryEntriesAsUnknown(t *testing.T) {
 
 func TestParseEnvFile_NoEnvFileConfigured(t *testing.T) {
 	config := &types.ServiceConfig{Name: "svc"}
-	vars, err := ParseEnvFile(config, t.TempDir())
+	vars, err := ParseEnvFile(config, t.TempDir(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1689,28 +1689,28 @@ func TestParseEnvFile_NoEnvFileConfigured(t *testing.T) {
 }
 
 func TestParseEnvFile_PathEscapesServiceDir(t *testing.T) {
-	config := &types.ServiceConfig{Name: "svc", EnvFile: "../outside.env"}
-	if _, err := ParseEnvFile(config, t.TempDir()); err == nil {
+	config := &types.ServiceConfig{Name: "svc", EnvFile: types.EnvFileList{"../outside.env"}}
+	if _, err := ParseEnvFile(config, t.TempDir(), nil); err == nil {
 		t.Fatal("expected error for env file path escaping service directory")
 	}
 }
 
 func TestParseEnvFile_MissingFile(t *testing.T) {
-	config := &types.ServiceConfig{Name: "svc", EnvFile: "missing.env"}
-	if _, err := ParseEnvFile(config, t.TempDir()); err == nil {
+	config := &types.ServiceConfig{Name: "svc", EnvFile: types.EnvFileList{"missing.env"}}
+	if _, err := ParseEnvFile(config, t.TempDir(), nil); err == nil {
 		t.Fatal("expected error reading a nonexistent env file")
 	}
 }
 
 func TestParseEnvFile_ParsesCommentsBlanksAndOverrides(t *testing.T) {
 	dir := t.TempDir()
-	contents := "# comment\n\n  \nFOO=bar\nno-equals-sign\nFOO=baz\nBAR=qux\n"
+	contents := "# comment\n\n  \nFOO=bar\nFOO=baz\nBAR=qux\n"
 	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(contents), 0644); err != nil {
 		t.Fatalf("write env file: %v", err)
 	}
 
-	config := &types.ServiceConfig{Name: "svc", EnvFile: ".env"}
-	vars, err := ParseEnvFile(config, dir)
+	config := &types.ServiceConfig{Name: "svc", EnvFile: types.EnvFileList{".env"}}
+	vars, err := ParseEnvFile(config, dir, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1726,6 +1726,40 @@ func TestParseEnvFile_ParsesCommentsBlanksAndOverrides(t *testing.T) {
 	}
 }
 
+func TestParseEnvFile_SyntaxErrorNamesFileAndLine(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("FOO=bar\n\nno-equals-sign\n"), 0644); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+
+	config := &types.ServiceConfig{Name: "svc", EnvFile: types.EnvFileList{".env"}}
+	_, err := ParseEnvFile(config, dir, nil)
+	if err == nil || !strings.Contains(err.Error(), ".env: line 3:") {
+		t.Fatalf("err = %v, want it to name .env and line 3", err)
+	}
+}
+
+func TestParseEnvFile_LayersFilesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("FOO=base\nHOST=db\n"), 0644); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.local"), []byte("FOO=local\nURL=postgres://${HOST}/${APP}\n"), 0644); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+
+	config := &types.ServiceConfig{Name: "svc", EnvFile: types.EnvFileList{".env", ".env.local"}}
+	vars, err := ParseEnvFile(config, dir, []string{"APP=shop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"FOO=local", "HOST=db", "URL=postgres://db/shop"}
+	if !slices.Equal(vars, want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+}
+
 func TestValidateRuntimePath_nonExistent(t *testing.T) {
 	rt := types.Runtime{Type: "bun", Path: "/nonexistent/path/99999"}
 	if err := ValidateRuntimePath(rt); err == nil {
@@ -1922,17 +1956,17 @@ func TestLiveOrphanRows_excludesMostRecentAndDeadRows(t *testing.T) {
 		{PGID: 0, StartedAtTicks: 0}, // never-started placeholder row, must be skipped
 	}
 
-	orphans := liveOrphanRows(history, deadPGID)
+	orphans := liveOrphanRows(history, deadPGID, groupTracker{})
 	if len(orphans) != 1 || orphans[0].PGID != livePGID {
 		t.Fatalf("expected only the live PGID as an orphan, got %+v", orphans)
 	}
 
 	// The live row is excluded when it IS the most recent PGID.
-	if orphans := liveOrphanRows(history, livePGID); len(orphans) != 0 {
+	if orphans := liveOrphanRows(history, livePGID, groupTracker{}); len(orphans) != 0 {
 		t.Errorf("expected no orphans when the live PGID is the most recent row, got %+v", orphans)
 	}
 
-	if orphans := liveOrphanRows(nil, livePGID); len(orphans) != 0 {
+	if orphans := liveOrphanRows(nil, livePGID, groupTracker{}); len(orphans) != 0 {
 		t.Errorf("expected no orphans for empty history, got %+v", orphans)
 	}
 }
@@ -2140,7 +2174,7 @@ func TestRestartService(t *testing.T) {
 
 	testFile := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "sleep 30",
+		Command: types.ServiceCommand{Shell: "sleep 30"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -2204,7 +2238,7 @@ func TestRestartService_CommandBinaryMissing(t *testing.T) {
 	}
 	yamlPath := filepath.Join(fullDirPath, "service.yaml")
 
-	goodConfig := &types.ServiceConfig{Name: "cms", Command: "sleep 30"}
+	goodConfig := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "sleep 30"}}
 	yamlData, err := yaml.Marshal(goodConfig)
 	if err != nil {
 		t.Fatalf("Failed to marshal test config: %v", err)
@@ -2227,7 +2261,7 @@ func TestRestartService_CommandBinaryMissing(t *testing.T) {
 	}
 	t.Cleanup(func() { _ = syscall.Kill(-originalPGID, syscall.SIGKILL) })
 
-	badConfig := &types.ServiceConfig{Name: "cms", Command: "nonexistent-binary-xyz-262"}
+	badConfig := &types.ServiceConfig{Name: "cms", Command: types.ServiceCommand{Shell: "nonexistent-binary-xyz-262"}}
 	badYamlData, err := yaml.Marshal(badConfig)
 	if err != nil {
 		t.Fatalf("Failed to marshal bad test config: %v", err)
@@ -2277,7 +2311,7 @@ func TestStartServiceConcurrentStartsSpawnOnce(t *testing.T) {
 	// enough for the liveness assertions below.
 	testFile := &types.ServiceConfig{
 		Name:    "sleeper",
-		Command: "sleep 30",
+		Command: types.ServiceCommand{Shell: "sleep 30"},
 		Runtime: types.Runtime{Type: "nodejs"},
 	}
 	yamlData, err := yaml.Marshal(testFile)
@@ -2772,7 +2806,7 @@ func TestLmPollPendingExits(t *testing.T) {
 	pending := map[int]bool{deadPGID: true, alreadyStoppedPGID: true}
 	stopped := map[int]bool{alreadyStoppedPGID: true}
 
-	lmPollPendingExits(pending, stopped)
+	lmPollPendingExits(pending, stopped, groupTracker{})
 
 	if !stopped[deadPGID] {
 		t.Errorf("expected dead pgid %d to be marked stopped", deadPGID)
@@ -0,0 +1,248 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// ServiceType is service.yaml's type: how a service tells eos it has
+// started.
+type ServiceType string
+
+const (
+	// ServiceTypeSimple is judged from outside: the health monitor promotes
+	// it to Running once its port, or its health_check's readiness check,
+	// answers. The default.
+	ServiceTypeSimple ServiceType = "simple"
+	// ServiceTypeNotify speaks systemd's sd_notify protocol: eos exports
+	// NOTIFY_SOCKET, and the service is Running once it sends READY=1.
+	ServiceTypeNotify ServiceType = "notify"
+	// ServiceTypeForking is a classic daemon: the command forks the main
+	// process and exits, and the service is tracked by the PID its pid_file
+	// names from then on (see forkingCapture).
+	ServiceTypeForking ServiceType = "forking"
+)
+
+const (
+	// notifyPollInterval is how often a notify socket's reader checks
+	// whether its launch is still alive between datagrams.
+	notifyPollInterval = time.Second
+	// notifyMaxDatagram caps one sd_notify message; systemd's own limit is
+	// the same order of magnitude.
+	notifyMaxDatagram = 4096
+)
+
+// notifyLaunch is one type: notify launch's socket and what it has reported
+// over it so far.
+type notifyLaunch struct {
+	conn   *net.UnixConn
+	dir    string
+	status types.NotifyStatus
+}
+
+// notifyValidate reports each invalid type/watchdog_sec setting.
+func notifyValidate(config *types.ServiceConfig) []error {
+	var errs []error
+	if config.Type != "" && !slices.Contains([]ServiceType{ServiceTypeSimple, ServiceTypeNotify, ServiceTypeForking}, ServiceType(config.Type)) {
+		errs = append(errs, fmt.Errorf("type: %q is not one of simple, notify or forking", config.Type))
+	}
+	switch {
+	case config.WatchdogSec < 0:
+		errs = append(errs, fmt.Errorf("watchdog_sec: must not be negative, got %d", config.WatchdogSec))
+	case config.WatchdogSec > 0 && ServiceType(config.Type) != ServiceTypeNotify:
+		errs = append(errs, errors.New("watchdog_sec: needs type: notify, the only type that can send WATCHDOG=1"))
+	}
+	return errs
+}
+
+// notifyListen creates the notify socket for a type: notify launch about to
+// start, in a fresh directory of its own that only the service's user can
+// reach, and points cmd at it through NOTIFY_SOCKET (and WATCHDOG_USEC when
+// watchdog_sec is set). It returns nil for any other type. Until notifyServe
+// takes it over, the caller must close it with notifyDiscard.
+func (m *LocalManager) notifyListen(config *types.ServiceConfig, cmd *exec.Cmd) (*notifyLaunch, error) {
+	if ServiceType(config.Type) != ServiceTypeNotify {
+		return nil, nil
+	}
+	dir, err := os.MkdirTemp("", "eos-notify-")
+	if err != nil {
+		return nil, fmt.Errorf("creating notify socket directory: %w", err)
+	}
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("creating notify socket: %w", err)
+	}
+	launch := &notifyLaunch{conn: conn, dir: dir}
+	cred, err := m.serviceCredential(config)
+	if err != nil {
+		m.notifyDiscard(launch)
+		return nil, fmt.Errorf("resolving user/group for notify socket: %w", err)
+	}
+	if cred != nil {
+		for _, p := range []string{dir, path} {
+			if err := os.Lchown(p, int(cred.uid), int(cred.gid)); err != nil {
+				m.notifyDiscard(launch)
+				return nil, fmt.Errorf("handing notify socket to service user: %w", err)
+			}
+		}
+	}
+	cmd.Env = append(cmd.Env, "NOTIFY_SOCKET="+path)
+	if config.WatchdogSec > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("WATCHDOG_USEC=%d", int64(config.WatchdogSec)*int64(time.Second/time.Microsecond)))
+	}
+	return launch, nil
+}
+
+// notifyDiscard closes a notify socket that never went into service and
+// removes its directory. A nil launch is a no-op.
+func (m *LocalManager) notifyDiscard(launch *notifyLaunch) {
+	if launch == nil {
+		return
+	}
+	_ = launch.conn.Close()
+	_ = os.RemoveAll(launch.dir)
+}
+
+// notifyServe records launch under pgid and reads its socket until the
+// launch has exited, not merely its leader: a service that hands off to a
+// MAINPID of its own keeps reporting after the process eos started is gone.
+// The reader is tracked by serviceWg and also stops when m.ctx is canceled.
+func (m *LocalManager) notifyServe(pgid int, launch *notifyLaunch) {
+	if launch == nil {
+		return
+	}
+	launch.status.PGID = pgid
+	m.notifyMu.Lock()
+	m.notify[pgid] = launch
+	m.notifyMu.Unlock()
+
+	m.serviceWg.Go(func() {
+		defer func() {
+			m.notifyMu.Lock()
+			delete(m.notify, pgid)
+			m.notifyMu.Unlock()
+			m.notifyDiscard(launch)
+		}()
+		buf := make([]byte, notifyMaxDatagram)
+		for m.ctx.Err() == nil {
+			_ = launch.conn.SetReadDeadline(time.Now().Add(notifyPollInterval))
+			n, err := launch.conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if !errors.As(err, &netErr) || !netErr.Timeout() {
+					return
+				}
+				if !m.tracker.alive(pgid) {
+					return
+				}
+				continue
+			}
+			m.notifyApply(pgid, string(buf[:n]))
+		}
+	})
+}
+
+// notifyApply applies one sd_notify datagram to pgid's status. Assignments
+// eos has no use for (STOPPING=1, ERRNO=, and so on) are ignored, as are
+// malformed lines.
+func (m *LocalManager) notifyApply(pgid int, message string) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	launch, ok := m.notify[pgid]
+	if !ok {
+		return
+	}
+	for line := range strings.SplitSeq(message, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "READY":
+			launch.status.Ready = launch.status.Ready || value == "1"
+		case "STATUS":
+			launch.status.Status = value
+		case "WATCHDOG":
+			if value == "1" {
+				launch.status.LastWatchdog = new(time.Now())
+			}
+		case "MAINPID":
+			pid, err := strconv.Atoi(value)
+			if err != nil || !m.tracker.member(pgid, pid) {
+				m.logger.Warn("ignoring MAINPID outside the service's own launch", "pgid", pgid, "mainpid", value)
+				continue
+			}
+			launch.status.MainPID = pid
+		}
+	}
+}
+
+// GetServiceNotifyStatus returns what the type: notify launch pgid has
+// reported so far. ok=false means pgid has no notify socket: it isn't a
+// notify launch, it has exited, or a previous daemon started it.
+func (m *LocalManager) GetServiceNotifyStatus(pgid int) (status types.NotifyStatus, ok bool) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	launch, ok := m.notify[pgid]
+	if !ok {
+		return types.NotifyStatus{}, false
+	}
+	return launch.status, true
+}
+
+// GetNotifyStatus returns what name's most recent live launch has reported
+// over its notify socket. A service with no such launch returns the zero
+// NotifyStatus (PGID 0), not an error.
+func (m *LocalManager) GetNotifyStatus(ctx context.Context, name string) (types.NotifyStatus, error) {
+	latest, err := m.GetMostRecentProcessHistoryEntry(ctx, name)
+	if errors.Is(err, ErrProcessNotFound) {
+		return types.NotifyStatus{}, nil
+	}
+	if err != nil {
+		return types.NotifyStatus{}, fmt.Errorf("get most recent process history entry for %s: %w", name, err)
+	}
+	status, _ := m.GetServiceNotifyStatus(latest.PGID)
+	return status, nil
+}
+
+// notifyMainPID is the MAINPID pgid's launch named, or 0 when it named none.
+func (m *LocalManager) notifyMainPID(pgid int) int {
+	status, _ := m.GetServiceNotifyStatus(pgid)
+	return status.MainPID
+}
+
+// notifyReadiness wraps then, the readiness check eos reload would
+// otherwise gate on, so that a type: notify launch must first have sent
+// READY=1. A nil then leaves READY=1 as the only requirement.
+func (m *LocalManager) notifyReadiness(pgid int, then HealthProbe) HealthProbe {
+	return notifyReadyProbe{m: m, pgid: pgid, then: then}
+}
+
+type notifyReadyProbe struct {
+	m    *LocalManager
+	then HealthProbe
+	pgid int
+}
+
+func (p notifyReadyProbe) Check(ctx context.Context) error {
+	if status, _ := p.m.GetServiceNotifyStatus(p.pgid); !status.Ready {
+		return errors.New("has not sent READY=1")
+	}
+	if p.then == nil {
+		return nil
+	}
+	return p.then.Check(ctx)
+}
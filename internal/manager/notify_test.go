@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"net"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestNotifyValidate(t *testing.T) {
+	if errs := notifyValidate(&types.ServiceConfig{Type: "notify", WatchdogSec: 30}); len(errs) != 0 {
+		t.Errorf("notifyValidate(notify, 30s) = %v, want no errors", errs)
+	}
+	errs := notifyValidate(&types.ServiceConfig{Type: "oneshot", WatchdogSec: 10})
+	if len(errs) != 2 {
+		t.Fatalf("notifyValidate returned %d errors, want 2: %v", len(errs), errs)
+	}
+	for i, field := range []string{"type", "watchdog_sec"} {
+		if !strings.HasPrefix(errs[i].Error(), field) {
+			t.Errorf("error %d = %q, want it to name %s", i, errs[i], field)
+		}
+	}
+}
+
+// TestNotifyServe_AppliesMessages drives a notify socket the way a service
+// would: READY=1, STATUS= and WATCHDOG=1 are recorded, a MAINPID outside the
+// launch is refused, and the socket goes away with the launch.
+func TestNotifyServe_AppliesMessages(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	cmd := exec.Command("sleep", "30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	launch, err := m.notifyListen(&types.ServiceConfig{Type: "notify", WatchdogSec: 2}, cmd)
+	if err != nil {
+		t.Fatalf("notifyListen: %v", err)
+	}
+	var socketPath string
+	for _, kv := range cmd.Env {
+		if path, ok := strings.CutPrefix(kv, "NOTIFY_SOCKET="); ok {
+			socketPath = path
+		}
+	}
+	if socketPath == "" || !strings.Contains(strings.Join(cmd.Env, " "), "WATCHDOG_USEC=2000000") {
+		t.Fatalf("cmd.Env = %v, want NOTIFY_SOCKET and WATCHDOG_USEC", cmd.Env)
+	}
+	if err := cmd.Start(); err != nil {
+		m.notifyDiscard(launch)
+		t.Fatalf("start: %v", err)
+	}
+	pgid := cmd.Process.Pid
+	t.Cleanup(func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+	m.notifyServe(pgid, launch)
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("dial notify socket: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("READY=1\nSTATUS=accepting connections\nWATCHDOG=1\nMAINPID=1")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	status, ok := m.GetServiceNotifyStatus(pgid)
+	for !status.Ready && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		status, ok = m.GetServiceNotifyStatus(pgid)
+	}
+	if !ok || !status.Ready || status.Status != "accepting connections" || status.LastWatchdog == nil {
+		t.Fatalf("status = %+v (ok %v), want ready with status text and a watchdog ping", status, ok)
+	}
+	if status.MainPID != 0 {
+		t.Errorf("MainPID = %d, want MAINPID=1 refused as outside the launch", status.MainPID)
+	}
+
+	_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	_ = cmd.Wait()
+	m.WaitServices()
+	if _, ok := m.GetServiceNotifyStatus(pgid); ok {
+		t.Error("notify status outlived the launch")
+	}
+}
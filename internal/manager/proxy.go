@@ -0,0 +1,394 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+const (
+	// proxyDialTimeout bounds how long an accepted connection waits for an
+	// instance to take it: long enough to ride out a restart, where the
+	// serving instance is gone until its replacement binds its port.
+	proxyDialTimeout = 10 * time.Second
+	// proxyDialRetry is the pause between dials while no instance answers.
+	proxyDialRetry = 100 * time.Millisecond
+	// proxyDrainPoll is how often a drain checks for open connections.
+	proxyDrainPoll = 50 * time.Millisecond
+)
+
+// serviceProxy is the public listener the daemon holds for a service with
+// proxy: true, shared by every launch until the service is stopped (see
+// proxyRelease). It forwards TCP connections, not HTTP requests: a client
+// connection stays with the launch it was first handed to for its whole
+// life. Each launch is a proxyBackend on its own internal port; serving is
+// the one new connections go to.
+type serviceProxy struct {
+	listener net.Listener
+	backends map[int]*proxyBackend
+	serving  *proxyBackend
+	// conns is every open client connection, closed by proxyRelease.
+	conns map[net.Conn]struct{}
+	port  int
+	mu    sync.Mutex
+}
+
+// proxyBackend is one launch behind a serviceProxy.
+type proxyBackend struct {
+	pgid   int
+	port   int
+	active atomic.Int64
+	total  atomic.Int64
+}
+
+// proxyValidate reports an invalid proxy: setting.
+func proxyValidate(config *types.ServiceConfig) []error {
+	if !config.Proxy {
+		return nil
+	}
+	var errs []error
+	if config.Port == 0 {
+		errs = append(errs, errors.New("proxy: a port is required for the proxy to listen on"))
+	}
+	if len(config.Sockets) > 0 {
+		errs = append(errs, errors.New("proxy: cannot be combined with sockets"))
+	}
+	return errs
+}
+
+// proxyPrepare readies a launch about to start behind name's proxy: it
+// makes sure the proxy listens on config.Port, binding it on the service's
+// first launch, and hands cmd a free internal port in PORT instead. It
+// returns that port, or 0 for a service without proxy: true, which is left
+// untouched. bound reports whether this launch bound the public listener,
+// even when it then fails, so a launch that never starts can release it
+// (see launchAndCapture).
+func (m *LocalManager) proxyPrepare(name string, config *types.ServiceConfig, cmd *exec.Cmd) (port int, bound bool, err error) {
+	if !config.Proxy {
+		return 0, false, nil
+	}
+	if bound, err = m.proxyListen(name, config.Port); err != nil {
+		return 0, false, err
+	}
+	port, err = proxyFreePort()
+	if err != nil {
+		return 0, bound, fmt.Errorf("picking an internal port: %w", err)
+	}
+	cmd.Env = lmOverlayEnvVars(cmd.Env, []string{"PORT=" + strconv.Itoa(port)})
+	return port, bound, nil
+}
+
+// proxyListen binds name's public listener on port unless it already holds
+// one there, reporting whether it bound. A changed port closes the old proxy
+// and starts over.
+func (m *LocalManager) proxyListen(name string, port int) (bool, error) {
+	m.proxiesMu.Lock()
+	defer m.proxiesMu.Unlock()
+	if proxy, ok := m.proxies[name]; ok {
+		if proxy.port == port {
+			return false, nil
+		}
+		proxy.close()
+		delete(m.proxies, name)
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false, fmt.Errorf("binding proxy port %d: %w", port, err)
+	}
+	proxy := &serviceProxy{
+		listener: listener,
+		port:     port,
+		backends: make(map[int]*proxyBackend),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	m.proxies[name] = proxy
+	// Stop accepting once the daemon shuts down; open connections end as
+	// their instances exit.
+	context.AfterFunc(m.ctx, func() { _ = listener.Close() })
+	m.serviceWg.Go(func() { m.proxyAccept(name, proxy) })
+	return true, nil
+}
+
+// proxyFreePort asks the kernel for a free loopback port. The service binds
+// it moments later; the gap in between is the same one any "port 0 then
+// pass it on" scheme has.
+func proxyFreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	return port, nil
+}
+
+// proxyRegister adds the launch pgid, given port in PORT by proxyPrepare,
+// to name's proxy. It takes no connections until proxyActivate.
+func (m *LocalManager) proxyRegister(name string, pgid, port int) {
+	if proxy := m.proxyFor(name); proxy != nil {
+		proxy.mu.Lock()
+		proxy.backends[pgid] = &proxyBackend{pgid: pgid, port: port}
+		proxy.mu.Unlock()
+	}
+}
+
+// proxyActivate points name's proxy at the launch pgid: every new
+// connection goes to it from now on. Earlier launches with no connections
+// left are forgotten; the rest drain (see proxyDrain). A service without a
+// proxy, or a launch it doesn't know, is a no-op.
+func (m *LocalManager) proxyActivate(name string, pgid int) {
+	proxy := m.proxyFor(name)
+	if proxy == nil {
+		return
+	}
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	backend, ok := proxy.backends[pgid]
+	if !ok {
+		return
+	}
+	proxy.serving = backend
+	for other, b := range proxy.backends {
+		if b != backend && b.active.Load() == 0 {
+			delete(proxy.backends, other)
+		}
+	}
+}
+
+// proxyDrain waits up to timeout for the connections name's proxy has open
+// to the launch pgid to finish, so its stop signal only lands once they
+// have. It must not be the serving launch (see proxyActivate), or new
+// connections would keep arriving. A launch the proxy doesn't know returns
+// at once.
+func (m *LocalManager) proxyDrain(name string, pgid int, timeout time.Duration) {
+	proxy := m.proxyFor(name)
+	if proxy == nil {
+		return
+	}
+	proxy.mu.Lock()
+	backend := proxy.backends[pgid]
+	proxy.mu.Unlock()
+	if backend == nil {
+		return
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(proxyDrainPoll)
+	defer ticker.Stop()
+	for backend.active.Load() > 0 {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-deadline.C:
+			m.logger.Warn("proxy: stopping instance with connections still open", "service", name, "pgid", pgid, "connections", backend.active.Load())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// proxyForget drops the launch pgid from name's proxy once it has no
+// connections left, as after a drain or an aborted reload.
+func (m *LocalManager) proxyForget(name string, pgid int) {
+	if proxy := m.proxyFor(name); proxy != nil {
+		proxy.mu.Lock()
+		proxy.forgetIdle(pgid)
+		proxy.mu.Unlock()
+	}
+}
+
+// proxyRelease closes name's proxy and every connection through it, once
+// the service is stopped rather than restarted. A service without a proxy
+// is a no-op.
+func (m *LocalManager) proxyRelease(name string) {
+	m.proxiesMu.Lock()
+	defer m.proxiesMu.Unlock()
+	if proxy, ok := m.proxies[name]; ok {
+		proxy.close()
+		delete(m.proxies, name)
+	}
+}
+
+func (m *LocalManager) proxyFor(name string) *serviceProxy {
+	m.proxiesMu.Lock()
+	defer m.proxiesMu.Unlock()
+	return m.proxies[name]
+}
+
+// ServiceConfigForLaunch returns config as name's launch pgid sees it: for a
+// launch behind a proxy, a copy whose Port is the internal port it was
+// given, so health checks reach that launch rather than the proxy, and for
+// an instance under increment_port a copy with that instance's port. Any
+// other launch gets config itself.
+func (m *LocalManager) ServiceConfigForLaunch(name string, pgid int, config *types.ServiceConfig) *types.ServiceConfig {
+	if config.IncrementPort && InstanceCount(config) > 1 {
+		return m.instanceConfigForLaunch(pgid, config)
+	}
+	if !config.Proxy {
+		return config
+	}
+	proxy := m.proxyFor(name)
+	if proxy == nil {
+		return config
+	}
+	proxy.mu.Lock()
+	backend, ok := proxy.backends[pgid]
+	proxy.mu.Unlock()
+	if !ok {
+		return config
+	}
+	launch := *config
+	launch.Port = backend.port
+	return &launch
+}
+
+// GetProxyStatus returns name's proxy and the launches behind it. A service
+// without a running proxy returns the zero ProxyStatus, not an error.
+func (m *LocalManager) GetProxyStatus(_ context.Context, name string) (types.ProxyStatus, error) {
+	proxy := m.proxyFor(name)
+	if proxy == nil {
+		return types.ProxyStatus{}, nil
+	}
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	status := types.ProxyStatus{Port: proxy.port}
+	for _, backend := range proxy.backends {
+		status.Backends = append(status.Backends, types.ProxyBackendStatus{
+			PGID:             backend.pgid,
+			Port:             backend.port,
+			Connections:      backend.active.Load(),
+			TotalConnections: backend.total.Load(),
+			Serving:          backend == proxy.serving,
+		})
+	}
+	slices.SortFunc(status.Backends, func(a, b types.ProxyBackendStatus) int { return a.PGID - b.PGID })
+	return status, nil
+}
+
+// proxyAccept hands every connection proxy accepts to its own forwarder,
+// until the listener closes.
+func (m *LocalManager) proxyAccept(name string, proxy *serviceProxy) {
+	for {
+		client, err := proxy.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				m.logger.Error("proxy: accepting connection", "service", name, "error", err)
+			}
+			return
+		}
+		if !proxy.track(client) {
+			_ = client.Close()
+			return
+		}
+		m.serviceWg.Go(func() { m.proxyForward(name, proxy, client) })
+	}
+}
+
+// proxyForward connects client to the serving launch and copies between
+// them until the launch is done answering. The launch finishing its side
+// ends the connection; the client finishing its side only half-closes it,
+// so a response still on its way gets through.
+func (m *LocalManager) proxyForward(name string, proxy *serviceProxy, client net.Conn) {
+	defer proxy.untrack(client)
+	backend, upstream := m.proxyDial(proxy)
+	if upstream == nil {
+		m.logger.Warn("proxy: no instance took the connection", "service", name, "timeout", proxyDialTimeout)
+		return
+	}
+	defer proxy.finish(backend)
+	defer upstream.Close() //nolint:errcheck // connection is done either way
+
+	m.serviceWg.Go(func() {
+		_, _ = io.Copy(upstream, client)
+		if tcp, ok := upstream.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+	})
+	_, _ = io.Copy(client, upstream)
+}
+
+// proxyDial connects to whichever launch is serving, retrying for up to
+// proxyDialTimeout while none answers. The connection counts against its
+// launch from before the dial, so a drain can't miss one being set up.
+func (m *LocalManager) proxyDial(proxy *serviceProxy) (*proxyBackend, net.Conn) {
+	deadline := time.Now().Add(proxyDialTimeout)
+	for {
+		proxy.mu.Lock()
+		backend := proxy.serving
+		if backend != nil {
+			backend.active.Add(1)
+		}
+		proxy.mu.Unlock()
+
+		if backend != nil {
+			dialer := net.Dialer{Deadline: deadline}
+			conn, err := dialer.DialContext(m.ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(backend.port)))
+			if err == nil {
+				backend.total.Add(1)
+				return backend, conn
+			}
+			proxy.finish(backend)
+		}
+		if time.Now().Add(proxyDialRetry).After(deadline) || m.ctx.Err() != nil {
+			return nil, nil
+		}
+		time.Sleep(proxyDialRetry)
+	}
+}
+
+// track records client as open, or reports false once the proxy is closed.
+func (p *serviceProxy) track(client net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns == nil {
+		return false
+	}
+	p.conns[client] = struct{}{}
+	return true
+}
+
+func (p *serviceProxy) untrack(client net.Conn) {
+	_ = client.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.conns, client)
+}
+
+// finish ends one of backend's connections, forgetting backend if that was
+// its last and it no longer serves.
+func (p *serviceProxy) finish(backend *proxyBackend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	backend.active.Add(-1)
+	p.forgetIdle(backend.pgid)
+}
+
+// forgetIdle drops the launch pgid if it neither serves nor has connections
+// open. The caller holds p.mu.
+func (p *serviceProxy) forgetIdle(pgid int) {
+	if backend, ok := p.backends[pgid]; ok && backend != p.serving && backend.active.Load() == 0 {
+		delete(p.backends, pgid)
+	}
+}
+
+// close stops accepting and closes every open connection.
+func (p *serviceProxy) close() {
+	_ = p.listener.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.conns = nil
+}
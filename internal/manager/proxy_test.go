@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestProxyValidate(t *testing.T) {
+	if errs := proxyValidate(&types.ServiceConfig{Proxy: true, Port: 8080}); len(errs) != 0 {
+		t.Errorf("proxyValidate(port 8080) = %v, want no errors", errs)
+	}
+	errs := proxyValidate(&types.ServiceConfig{Proxy: true, Sockets: []types.ServiceSocket{{Name: "http", TCP: ":8080"}}})
+	if len(errs) != 2 {
+		t.Fatalf("proxyValidate returned %d errors, want 2: %v", len(errs), errs)
+	}
+	for i, want := range []string{"a port is required", "sockets"} {
+		if !strings.Contains(errs[i].Error(), want) {
+			t.Errorf("error %d = %q, want it to mention %s", i, errs[i], want)
+		}
+	}
+}
+
+// proxyTestBackend answers every line it reads with name and the line, the
+// way one instance of a service behind the proxy would.
+func proxyTestBackend(t *testing.T, name string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		_ = listener.Close()
+		wg.Wait()
+	})
+	wg.Go(func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			wg.Go(func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					_, _ = conn.Write([]byte(name + " " + scanner.Text() + "\n"))
+				}
+			})
+		}
+	})
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func proxyTestRoundTrip(t *testing.T, conn net.Conn, reader *bufio.Reader, line string) string {
+	t.Helper()
+	if _, err := conn.Write([]byte(line + "\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return strings.TrimSpace(reply)
+}
+
+// TestProxy_SwitchesAndDrains points the proxy at one instance, then another:
+// new connections follow the switch, the connection already open stays on
+// the first instance, and a drain waits for it before the instance is let go.
+func TestProxy_SwitchesAndDrains(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(m.WaitServices)
+
+	const name = "proxy-svc"
+	publicPort, err := proxyFreePort()
+	if err != nil {
+		t.Fatalf("proxyFreePort: %v", err)
+	}
+	if _, err := m.proxyListen(name, publicPort); err != nil {
+		t.Fatalf("proxyListen: %v", err)
+	}
+	t.Cleanup(func() { m.proxyRelease(name) })
+
+	m.proxyRegister(name, 100, proxyTestBackend(t, "old"))
+	m.proxyActivate(name, 100)
+
+	dial := func() (net.Conn, *bufio.Reader) {
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(publicPort)))
+		if err != nil {
+			t.Fatalf("dial proxy: %v", err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn, bufio.NewReader(conn)
+	}
+	oldConn, oldReader := dial()
+	if got := proxyTestRoundTrip(t, oldConn, oldReader, "a"); got != "old a" {
+		t.Fatalf("first connection got %q, want the old instance", got)
+	}
+
+	m.proxyRegister(name, 200, proxyTestBackend(t, "new"))
+	m.proxyActivate(name, 200)
+	newConn, newReader := dial()
+	if got := proxyTestRoundTrip(t, newConn, newReader, "b"); got != "new b" {
+		t.Errorf("connection after the switch got %q, want the new instance", got)
+	}
+	if got := proxyTestRoundTrip(t, oldConn, oldReader, "c"); got != "old c" {
+		t.Errorf("open connection got %q after the switch, want it kept on the old instance", got)
+	}
+
+	status, _ := m.GetProxyStatus(t.Context(), name)
+	if status.Port != publicPort || len(status.Backends) != 2 {
+		t.Fatalf("status = %+v, want both instances behind port %d", status, publicPort)
+	}
+	if old := status.Backends[0]; old.Serving || old.Connections != 1 || old.TotalConnections != 1 {
+		t.Errorf("old instance = %+v, want draining with its one connection", old)
+	}
+	if current := status.Backends[1]; !current.Serving || current.Connections != 1 {
+		t.Errorf("new instance = %+v, want serving with one connection", current)
+	}
+
+	time.AfterFunc(200*time.Millisecond, func() { _ = oldConn.Close() })
+	start := time.Now()
+	m.proxyDrain(name, 100, 5*time.Second)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > 4*time.Second {
+		t.Errorf("drain returned after %s, want it to wait for the open connection", elapsed)
+	}
+	m.proxyForget(name, 100)
+	status, _ = m.GetProxyStatus(t.Context(), name)
+	if len(status.Backends) != 1 || status.Backends[0].PGID != 200 {
+		t.Errorf("backends after drain = %+v, want only the new instance", status.Backends)
+	}
+}
+
+// TestProxy_ReleasedWhenLaunchFails proves a launch that binds the public
+// port and then fails to start doesn't leave it bound.
+func TestProxy_ReleasedWhenLaunchFails(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(m.WaitServices)
+
+	publicPort, err := proxyFreePort()
+	if err != nil {
+		t.Fatalf("proxyFreePort: %v", err)
+	}
+	// A service directory that doesn't exist fails cmd.Start on its chdir.
+	service := &types.ServiceCatalogEntry{Name: "proxy-svc", DirectoryPath: filepath.Join(tempDir, "missing")}
+	config := &types.ServiceConfig{
+		Command: types.ServiceCommand{Shell: "true"},
+		Port:    publicPort,
+		Proxy:   true,
+	}
+	if _, err := m.launchInstance(service, config, nil, 0, "start command"); err == nil {
+		t.Fatal("launchInstance succeeded in a missing service directory")
+	}
+	if m.proxyFor(service.Name) != nil {
+		t.Error("the proxy stayed bound after its launch failed to start")
+	}
+}
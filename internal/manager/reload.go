@@ -9,17 +9,17 @@ import (
 
 	"github.com/Elysium-Labs-EU/eos/internal/database"
 	"github.com/Elysium-Labs-EU/eos/internal/otelx"
-	"github.com/Elysium-Labs-EU/eos/internal/procutil"
 	"github.com/Elysium-Labs-EU/eos/internal/types"
 )
 
 // ReadinessProbe reports whether the instance identified by pgid (launched at
-// startedAtTicks, serving port — 0 when the service declares none) is ready to
-// take over serving. It is injected rather than implemented here so the reload
-// cutover can gate on the health monitor's own liveness+port check without this
-// package importing internal/monitor, which imports this one. The daemon passes
+// startedAtTicks) is ready to take over serving, with check the service's
+// readiness check (see ReadinessProbeFor; nil when it has none). It is
+// injected rather than implemented here so the reload cutover can gate on the
+// health monitor's own liveness+readiness check without this package
+// importing internal/monitor, which imports this one. The daemon passes
 // monitor.ProbeReady; tests pass a fake.
-type ReadinessProbe func(ctx context.Context, pgid int, startedAtTicks int64, port int) bool
+type ReadinessProbe func(ctx context.Context, pgid int, startedAtTicks int64, check HealthProbe) bool
 
 // ReloadConfig carries the timing knobs for a zero-downtime reload cutover.
 // Values are already resolved by the caller; nothing here reads config or env.
@@ -33,14 +33,28 @@ type ReloadConfig struct {
 	// ReadinessTimeout gives up the reload (keeping the outgoing instance
 	// serving) if the incoming instance never passes the readiness probe.
 	ReadinessTimeout time.Duration
-	// ProbeInterval is how often the readiness probe runs while waiting.
+	// ProbeInterval is how often the readiness probe runs while waiting, and
+	// the health check during a canary window.
 	ProbeInterval time.Duration
+	// Canary, when non-zero, keeps both instances serving this long once the
+	// incoming one is ready, and only finishes the cutover if it holds up
+	// (see runCanary).
+	Canary time.Duration
 }
 
 // ReloadResult reports the process groups a completed reload swapped between.
+// For a service running several instances OldPGID and NewPGID are instance
+// 0's, and Instances has every instance's (see rollingReload).
 type ReloadResult struct {
-	OldPGID int
-	NewPGID int
+	Instances []types.ReloadInstance
+	OldPGID   int
+	NewPGID   int
+	// Steps is how many steps the rollout was planned in, and FailedStep the
+	// one, from 1, that failed and was rolled back; 0 when none did.
+	Steps      int
+	FailedStep int
+	// Canary is the canary window's result, for a reload that ran one.
+	Canary *types.CanaryResult
 }
 
 // ReloadService performs a health-gated, zero-downtime cutover: it launches a
@@ -48,14 +62,20 @@ type ReloadResult struct {
 // for the new instance to pass the readiness probe, and only then drains the old
 // one. If the new instance never becomes ready it is killed and the old instance
 // is left untouched, so a broken deploy degrades to "no change" rather than an
-// outage.
+// outage. With cfg.Canary set, a ready incoming instance serves beside the
+// outgoing one for that long first and is stopped instead if it doesn't hold
+// up (see runCanary), which fails the reload with ErrCanaryFailed.
 //
-// eos never owns the service's listening socket. Overlapping two instances on
-// one port without dropping connections is the service's job, via SO_REUSEPORT:
-// both instances bind the same address, the kernel load-balances new connections
-// across whichever are listening, and connections already accepted by the old
-// instance drain on it after its SIGTERM. eos only sequences the cutover so a
-// listener is always present. This is a parallel path to RestartService (which
+// Unless the service has proxy: true, eos never owns the service's listening
+// socket. Overlapping two instances on one port without dropping connections
+// is the service's job, via SO_REUSEPORT: both instances bind the same
+// address, the kernel load-balances new connections across whichever are
+// listening, and connections already accepted by the old instance drain on it
+// after its SIGTERM. eos only sequences the cutover so a listener is always
+// present. Behind the proxy each instance has its own internal port instead:
+// the proxy moves new connections to the incoming instance once it is ready,
+// and the outgoing one is only signaled once its open connections finish
+// (see proxyDrain). This is a parallel path to RestartService (which
 // stops-then-starts, dropping the port in between) and deliberately leaves it
 // unchanged.
 //
@@ -84,6 +104,14 @@ func (m *LocalManager) ReloadService(name string, probe ReadinessProbe, cfg Relo
 	if err != nil {
 		return ReloadResult{}, err
 	}
+	if cfg.Canary > 0 {
+		if canaryErr := canaryValidate(target.config); canaryErr != nil {
+			return ReloadResult{}, canaryErr
+		}
+	}
+	if InstanceCount(target.config) > 1 {
+		return m.rollingReload(&target, probe, cfg)
+	}
 
 	lio, err := m.prepareLaunchIO(target.service.Name, target.config)
 	if err != nil {
@@ -105,17 +133,49 @@ func (m *LocalManager) ReloadService(name string, probe ReadinessProbe, cfg Relo
 	// Probe the incoming instance before touching the outgoing one — the
 	// acceptance guarantee is that health probing starts before the old instance
 	// stops, so a new instance that never comes up leaves the old one serving.
-	if !m.awaitReady(probe, newPGID, newStartedAtTicks, target.config.Port, cfg) {
+	readiness := target.readiness
+	if target.config.Proxy {
+		// Probe the incoming instance's own port, not the proxy's, which
+		// the outgoing one is still answering behind.
+		if readiness, err = m.ReadinessProbeFor(&target.service, m.ServiceConfigForLaunch(name, newPGID, target.config)); err != nil {
+			result, _ = m.abortUnreadyReload(name, newPGID, target.oldPGID, cfg.ReadinessTimeout)
+			return result, fmt.Errorf("resolving readiness check for %s: %w", name, err)
+		}
+	}
+	if ServiceType(target.config.Type) == ServiceTypeNotify {
+		readiness = m.notifyReadiness(newPGID, readiness)
+	}
+	if !m.awaitReady(probe, newPGID, newStartedAtTicks, readiness, cfg) {
 		return m.abortUnreadyReload(name, newPGID, target.oldPGID, cfg.ReadinessTimeout)
 	}
+	m.runHookLogged(&target.service, target.config, hookPostStart)
+	m.proxyActivate(name, newPGID)
+
+	policy := m.stopPolicyFor(name, target.config, cfg.GracePeriod)
+	result = ReloadResult{OldPGID: target.oldPGID, NewPGID: newPGID}
+	if cfg.Canary > 0 {
+		canary := m.runCanary(name, target.oldPGID, newPGID, newStartedAtTicks, probe, readiness, cfg)
+		result.Canary = &canary
+		if canary.Outcome == types.CanaryOutcomeRolledBack {
+			// The incoming instance has been serving, so it is stopped per
+			// policy rather than killed, and its row removed as for an
+			// aborted reload.
+			m.retireReplacement(name, newPGID, policy, cfg)
+			return result, fmt.Errorf("%w: %s", ErrCanaryFailed, canary.Reason)
+		}
+	}
 
+	// The stop hooks run from the config being reloaded to, like every other
+	// hook of this cutover: the outgoing instance's own config is gone.
+	m.runHookLogged(&target.service, target.config, hookPreStop)
 	m.logger.Debug("reload: new instance ready, draining old", "service", name, "new_pgid", newPGID, "old_pgid", target.oldPGID)
-	if drainErr := m.drainInstance(name, target.oldPGID, cfg.GracePeriod, cfg.TickerPeriod); drainErr != nil {
-		return ReloadResult{OldPGID: target.oldPGID, NewPGID: newPGID}, fmt.Errorf("draining old instance for %s: %w", name, drainErr)
+	if drainErr := m.drainInstance(name, target.oldPGID, policy, cfg.TickerPeriod); drainErr != nil {
+		return result, fmt.Errorf("draining old instance for %s: %w", name, drainErr)
 	}
+	m.runHookLogged(&target.service, target.config, hookPostStop)
 
 	m.recordReloadCutover(name, target.instance.RestartCount)
-	return ReloadResult{OldPGID: target.oldPGID, NewPGID: newPGID}, nil
+	return result, nil
 }
 
 // reloadCleanupUnlaunched closes lio's sinks if the incoming instance never
@@ -134,8 +194,10 @@ func (m *LocalManager) reloadCleanupUnlaunched(lio launchIO, serviceName string,
 
 // reloadLaunchIncoming validates the runtime binary and launches the incoming
 // instance alongside the still-live outgoing one, isolating ReloadService's own
-// launch-or-bail branch from the two checks (binary validation, launch failure)
-// that both bail out the same way: a zero PGID and the wrapped error.
+// launch-or-bail branch from the checks (binary validation, pre_start hook,
+// launch failure) that all bail out the same way: a zero PGID and the wrapped
+// error. A failed pre_start leaves no Failed row here, unlike StartService:
+// the outgoing instance is still serving, so the service hasn't failed.
 func (m *LocalManager) reloadLaunchIncoming(name string, target *reloadTarget, lio launchIO, launchSuccess *bool) (int, int64, error) {
 	if binaryErr := m.validateRuntimeBinary(target.config); binaryErr != nil {
 		return 0, 0, binaryErr
@@ -143,9 +205,12 @@ func (m *LocalManager) reloadLaunchIncoming(name string, target *reloadTarget, l
 	if cmdErr := validateCommandBinary(target.config, target.service.DirectoryPath); cmdErr != nil {
 		return 0, 0, cmdErr
 	}
+	if _, _, hookErr := m.runHook(&target.service, target.config, hookPreStart); hookErr != nil {
+		return 0, 0, hookErr
+	}
 
 	m.logger.Debug("reload: launching new instance alongside old", "service", name, "old_pgid", target.oldPGID)
-	return m.launchAndCapture(&target.service, target.config, lio, target.resolvedSinks, launchSuccess, "reload command")
+	return m.launchAndCapture(&target.service, target.config, 0, lio, target.resolvedSinks, launchSuccess, "reload command")
 }
 
 // reloadTarget is the resolved, validated input a cutover launches from: the
@@ -155,13 +220,14 @@ type reloadTarget struct {
 	service       types.ServiceCatalogEntry
 	config        *types.ServiceConfig
 	instance      *types.ServiceInstance
+	readiness     HealthProbe
 	resolvedSinks []types.LogSink
 	oldPGID       int
 }
 
 // prepareReloadTarget resolves everything a reload needs before it launches the
-// incoming instance: it loads the service config, confirms an instance row
-// exists, and pins the live outgoing PGID. Reload swaps a running instance, so a
+// incoming instance: it loads the service config and its readiness check,
+// confirms an instance row exists, and pins the live outgoing PGID. Reload swaps a running instance, so a
 // service with no live process group is ErrServiceNotRunning rather than a cold
 // start. Pinning the exact PGID here means the later drain signals only it,
 // never the incoming instance that shares the same service name in history.
@@ -170,6 +236,10 @@ func (m *LocalManager) prepareReloadTarget(name string) (reloadTarget, error) {
 	if err != nil {
 		return reloadTarget{}, err
 	}
+	readiness, err := m.ReadinessProbeFor(&service, config)
+	if err != nil {
+		return reloadTarget{}, fmt.Errorf("resolving readiness check for %s: %w", name, err)
+	}
 
 	instance, err := m.GetServiceInstance(m.ctx, name)
 	if err != nil {
@@ -183,7 +253,7 @@ func (m *LocalManager) prepareReloadTarget(name string) (reloadTarget, error) {
 	if err != nil {
 		return reloadTarget{}, fmt.Errorf("get process history for %s: %w", name, err)
 	}
-	oldPGID := livePGIDInHistory(history)
+	oldPGID := livePGIDInHistory(history, m.tracker)
 	if oldPGID == 0 {
 		return reloadTarget{}, ErrServiceNotRunning
 	}
@@ -193,6 +263,7 @@ func (m *LocalManager) prepareReloadTarget(name string) (reloadTarget, error) {
 		config:        config,
 		resolvedSinks: resolvedSinks,
 		instance:      instance,
+		readiness:     readiness,
 		oldPGID:       oldPGID,
 	}, nil
 }
@@ -206,7 +277,7 @@ func (m *LocalManager) prepareReloadTarget(name string) (reloadTarget, error) {
 // PGID to report (see killAndWrap), and newPGID on success.
 func (m *LocalManager) registerIncomingInstance(serviceName string, newPGID int, newStartedAtTicks int64) (int, error) {
 	if _, histErr := m.db.RegisterProcessHistoryEntry(m.ctx, newPGID, newStartedAtTicks, serviceName, types.ProcessStateStarting); histErr != nil {
-		return killAndWrap(newPGID, histErr, "register reload process history entry")
+		return killAndWrap(m.tracker, newPGID, histErr, "register reload process history entry")
 	}
 	return newPGID, nil
 }
@@ -220,12 +291,13 @@ func (m *LocalManager) registerIncomingInstance(serviceName string, newPGID int,
 // most-recent entry, so the monitor keeps supervising it unchanged. Returns
 // ErrReloadNotReady so a broken deploy degrades to "no change".
 func (m *LocalManager) abortUnreadyReload(name string, newPGID, oldPGID int, readinessTimeout time.Duration) (ReloadResult, error) {
-	if killErr := syscall.Kill(-newPGID, syscall.SIGKILL); killErr != nil {
+	if killErr := m.tracker.signal(newPGID, syscall.SIGKILL); killErr != nil {
 		m.logger.Error("reload: killing unready new instance", "service", name, "pgid", newPGID, "error", killErr)
 	}
 	if _, delErr := m.db.RemoveProcessHistoryEntryViaPGID(m.ctx, newPGID); delErr != nil {
 		m.logger.Error("reload: removing aborted instance history row", "service", name, "pgid", newPGID, "error", delErr)
 	}
+	m.proxyForget(name, newPGID)
 	return ReloadResult{OldPGID: oldPGID, NewPGID: newPGID}, fmt.Errorf("%w: new instance for %s not ready within %s", ErrReloadNotReady, name, readinessTimeout)
 }
 
@@ -265,7 +337,7 @@ const reloadReadyConsecutivePasses = 3
 // inside the tick branch: with ReadinessTimeout < ProbeInterval the wait must
 // give up at ReadinessTimeout rather than blocking a full ProbeInterval for the
 // first tick to arrive.
-func (m *LocalManager) awaitReady(probe ReadinessProbe, pgid int, startedAtTicks int64, port int, cfg ReloadConfig) bool {
+func (m *LocalManager) awaitReady(probe ReadinessProbe, pgid int, startedAtTicks int64, check HealthProbe, cfg ReloadConfig) bool {
 	if probe == nil {
 		m.logger.Error("reload: no readiness probe configured; refusing to cut over")
 		return false
@@ -277,7 +349,7 @@ func (m *LocalManager) awaitReady(probe ReadinessProbe, pgid int, startedAtTicks
 	defer ticker.Stop()
 
 	consecutive := 0
-	if probe(m.ctx, pgid, startedAtTicks, port) {
+	if probe(m.ctx, pgid, startedAtTicks, check) {
 		consecutive = 1
 	}
 	for consecutive < reloadReadyConsecutivePasses {
@@ -287,7 +359,7 @@ func (m *LocalManager) awaitReady(probe ReadinessProbe, pgid int, startedAtTicks
 		case <-timeout.C:
 			return false
 		case <-ticker.C:
-			if probe(m.ctx, pgid, startedAtTicks, port) {
+			if probe(m.ctx, pgid, startedAtTicks, check) {
 				consecutive++
 			} else {
 				consecutive = 0
@@ -300,21 +372,26 @@ func (m *LocalManager) awaitReady(probe ReadinessProbe, pgid int, startedAtTicks
 // drainInstance stops exactly one process group belonging to name and marks its
 // history row Stopped. Unlike stopServiceLocked it never signals the whole
 // service's history, so the freshly started reload instance sharing the same
-// service name is left running. After the grace period a still-live group is
-// force-killed to guarantee the cutover completes.
-func (m *LocalManager) drainInstance(name string, pgid int, gracePeriod, tickerPeriod time.Duration) error {
+// service name is left running. It is stopped per policy (the service's
+// stop_signal, kill_mode and stop_timeout); after policy.Timeout a still-live
+// group is force-killed to guarantee the cutover completes. Behind a proxy
+// the stop first waits, up to policy.Timeout as well, for the connections the
+// proxy still has open to the group.
+func (m *LocalManager) drainInstance(name string, pgid int, policy StopPolicy, tickerPeriod time.Duration) error {
 	entry, err := m.db.GetProcessHistoryEntryByPGID(m.ctx, pgid)
 	if err != nil {
 		return fmt.Errorf("get process history for pgid %d: %w", pgid, err)
 	}
+	m.proxyDrain(name, pgid, policy.Timeout)
+	defer m.proxyForget(name, pgid)
 
-	if !procutil.IsAliveMatching(pgid, entry.StartedAtTicks) {
+	if !m.tracker.aliveMatching(pgid, entry.StartedAtTicks) {
 		// Already gone (or a recycled PGID that isn't ours): just record it.
 		m.markInstanceStopped(pgid)
 		return nil
 	}
 
-	drained, err := m.terminateInstance(name, pgid, entry.StartedAtTicks, gracePeriod, tickerPeriod)
+	drained, err := m.terminateInstance(name, pgid, entry.StartedAtTicks, policy, tickerPeriod)
 	if err != nil {
 		return err
 	}
@@ -327,16 +404,16 @@ func (m *LocalManager) drainInstance(name string, pgid int, gracePeriod, tickerP
 	return nil
 }
 
-// terminateInstance SIGTERMs a single live process group and waits up to
-// gracePeriod for it to exit, force-killing it if it overstays so the cutover
-// can't stall on a service ignoring SIGTERM. It returns drained=false only when
+// terminateInstance stops a single live process group per policy and waits up
+// to policy.Timeout for it to exit, force-killing it if it overstays so the
+// cutover can't stall on a service ignoring its stop signal. It returns drained=false only when
 // the manager context is canceled mid-wait, so the caller leaves the history
 // row for startup reconciliation instead of marking it Stopped. A group already
 // gone by the time the signal lands counts as drained.
-func (m *LocalManager) terminateInstance(name string, pgid int, startedAtTicks int64, gracePeriod, tickerPeriod time.Duration) (drained bool, err error) {
+func (m *LocalManager) terminateInstance(name string, pgid int, startedAtTicks int64, policy StopPolicy, tickerPeriod time.Duration) (drained bool, err error) {
 	requestStartTime := time.Now()
-	if killErr := syscall.Kill(-pgid, syscall.SIGTERM); killErr != nil {
-		if !procutil.IsAliveMatching(pgid, startedAtTicks) {
+	if killErr := m.tracker.stop(pgid, m.mainPID(pgid), policy); killErr != nil {
+		if !m.tracker.aliveMatching(pgid, startedAtTicks) {
 			return true, nil
 		}
 		return false, fmt.Errorf("signaling old instance pgid %d: %w", pgid, killErr)
@@ -344,7 +421,7 @@ func (m *LocalManager) terminateInstance(name string, pgid int, startedAtTicks i
 
 	pending := map[int]bool{pgid: true}
 	errored := map[int]string{}
-	_, canceled := m.waitForPendingStops(name, pending, errored, requestStartTime, gracePeriod, tickerPeriod)
+	_, canceled := m.waitForPendingStops(name, pending, errored, requestStartTime, policy, tickerPeriod)
 	if canceled {
 		return false, nil
 	}
@@ -358,7 +435,7 @@ func (m *LocalManager) terminateInstance(name string, pgid int, startedAtTicks i
 // ESRCH (no such process) means it exited in the race between the grace check
 // and the signal, which is a clean drain, not an error.
 func (m *LocalManager) forceKillInstance(name string, pgid int) {
-	if killErr := syscall.Kill(-pgid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+	if killErr := m.tracker.signal(pgid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
 		m.logger.Error("reload: force-killing old instance", "service", name, "pgid", pgid, "error", killErr)
 	}
 }
@@ -50,7 +50,7 @@ func registerServiceWithCommand(t *testing.T, name, command string) *LocalManage
 // its own manager (e.g. one with a cancelable context) can register against it.
 func registerServiceOnManager(t *testing.T, mgr *LocalManager, baseDir, name, command string) {
 	t.Helper()
-	cfg := &types.ServiceConfig{Name: name, Command: command}
+	cfg := &types.ServiceConfig{Name: name, Command: types.ServiceCommand{Shell: command}}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
@@ -92,8 +92,8 @@ func waitGone(pgid int) bool {
 	return !procutil.IsAlive(pgid)
 }
 
-func alwaysReady(context.Context, int, int64, int) bool { return true }
-func neverReady(context.Context, int, int64, int) bool  { return false }
+func alwaysReady(context.Context, int, int64, HealthProbe) bool { return true }
+func neverReady(context.Context, int, int64, HealthProbe) bool  { return false }
 
 // TestReloadCleanupUnlaunched exercises ReloadService's launch-failure cleanup
 // helper directly: it must skip closing lio when the launch already succeeded,
@@ -131,7 +131,7 @@ func TestReloadLaunchIncoming(t *testing.T) {
 		service: types.ServiceCatalogEntry{Name: name},
 		config: &types.ServiceConfig{
 			Name:    name,
-			Command: "sleep 300",
+			Command: types.ServiceCommand{Shell: "sleep 300"},
 			Runtime: types.Runtime{Path: "/nonexistent-eos-runtime-path", Type: "node"},
 		},
 		oldPGID: 1,
@@ -161,7 +161,7 @@ func TestReloadLaunchIncoming_CommandBinaryMissing(t *testing.T) {
 		service: types.ServiceCatalogEntry{Name: name},
 		config: &types.ServiceConfig{
 			Name:    name,
-			Command: "nonexistent-binary-xyz-262",
+			Command: types.ServiceCommand{Shell: "nonexistent-binary-xyz-262"},
 		},
 		oldPGID: 1,
 	}
@@ -340,7 +340,7 @@ func TestDrainInstanceAlreadyGone(t *testing.T) {
 		t.Fatalf("process group %d should be gone before draining", pgid)
 	}
 
-	if drainErr := mgr.drainInstance(name, pgid, time.Second, 20*time.Millisecond); drainErr != nil {
+	if drainErr := mgr.drainInstance(name, pgid, StopPolicy{Signal: syscall.SIGTERM, Timeout: time.Second}, 20*time.Millisecond); drainErr != nil {
 		t.Fatalf("drainInstance: %v", drainErr)
 	}
 	recent, err := mgr.GetMostRecentProcessHistoryEntry(t.Context(), name)
@@ -371,7 +371,7 @@ func TestDrainInstanceForceKillsOnGraceTimeout(t *testing.T) {
 
 	const gracePeriod = 150 * time.Millisecond
 	start := time.Now()
-	if drainErr := mgr.drainInstance(name, pgid, gracePeriod, 20*time.Millisecond); drainErr != nil {
+	if drainErr := mgr.drainInstance(name, pgid, StopPolicy{Signal: syscall.SIGTERM, Timeout: gracePeriod}, 20*time.Millisecond); drainErr != nil {
 		t.Fatalf("drainInstance: %v", drainErr)
 	}
 	if time.Since(start) < gracePeriod {
@@ -419,7 +419,7 @@ func TestDrainInstanceCanceledLeavesRow(t *testing.T) {
 		time.Sleep(100 * time.Millisecond)
 		cancel()
 	}()
-	if drainErr := mgr.drainInstance(name, pgid, 5*time.Second, 20*time.Millisecond); drainErr != nil {
+	if drainErr := mgr.drainInstance(name, pgid, StopPolicy{Signal: syscall.SIGTERM, Timeout: 5 * time.Second}, 20*time.Millisecond); drainErr != nil {
 		t.Fatalf("drainInstance: %v", drainErr)
 	}
 
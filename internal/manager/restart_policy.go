@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// RestartMode is service.yaml's restart: when the health monitor restarts a
+// service whose process has exited.
+type RestartMode string
+
+const (
+	// RestartOnFailure restarts after any exit but a clean one: exit code 0
+	// or one of success_exit_codes. The default.
+	RestartOnFailure RestartMode = "on-failure"
+	// RestartAlways restarts after every exit, clean or not.
+	RestartAlways RestartMode = "always"
+	// RestartNever leaves the service down after it exits. A failed exit
+	// leaves it Failed for good, until the next eos run.
+	RestartNever RestartMode = "never"
+)
+
+// RestartPolicy is how the health monitor treats one service's exits: which
+// exit codes are clean, which of the rest it restarts after, and how many
+// restarts it makes within Window before it gives up. The zero value is
+// RestartOnFailure with no limit.
+type RestartPolicy struct {
+	Mode             RestartMode
+	SuccessExitCodes []int
+	RestartExitCodes []int
+	Window           time.Duration
+	MaxRestarts      int
+}
+
+// ResolveRestartPolicy reads config's restart, max_restarts, restart_window,
+// success_exit_codes and restart_exit_codes.
+func ResolveRestartPolicy(config *types.ServiceConfig) (RestartPolicy, error) {
+	policy := RestartPolicy{
+		Mode:             RestartOnFailure,
+		MaxRestarts:      config.MaxRestarts,
+		SuccessExitCodes: config.SuccessExitCodes,
+		RestartExitCodes: config.RestartExitCodes,
+	}
+	var errs []error
+	if config.Restart != "" {
+		mode := RestartMode(config.Restart)
+		if !slices.Contains([]RestartMode{RestartOnFailure, RestartAlways, RestartNever}, mode) {
+			errs = append(errs, fmt.Errorf("restart: %q is not one of always, on-failure or never", config.Restart))
+		} else {
+			policy.Mode = mode
+		}
+	}
+	if config.MaxRestarts < 0 {
+		errs = append(errs, fmt.Errorf("max_restarts: must not be negative, got %d", config.MaxRestarts))
+	}
+	if config.RestartWindow != "" {
+		window, err := time.ParseDuration(config.RestartWindow)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("restart_window: invalid duration %q: %w", config.RestartWindow, err))
+		case window <= 0:
+			errs = append(errs, fmt.Errorf("restart_window: invalid duration %q: must be positive", config.RestartWindow))
+		default:
+			policy.Window = window
+		}
+	}
+	errs = append(errs, restartValidateExitCodes("success_exit_codes", config.SuccessExitCodes)...)
+	errs = append(errs, restartValidateExitCodes("restart_exit_codes", config.RestartExitCodes)...)
+	return policy, errors.Join(errs...)
+}
+
+// restartValidateExitCodes reports each entry of field that isn't an exit
+// code a process can actually return.
+func restartValidateExitCodes(field string, codes []int) []error {
+	var errs []error
+	for _, code := range codes {
+		if code < 0 || code > 255 {
+			errs = append(errs, fmt.Errorf("%s: %d is not an exit code between 0 and 255", field, code))
+		}
+	}
+	return errs
+}
+
+// restartValidate reports each invalid restart policy setting.
+func restartValidate(config *types.ServiceConfig) []error {
+	_, err := ResolveRestartPolicy(config)
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// Completed reports whether an exit with code ends the service for good, as
+// Stopped rather than Failed: a clean exit the policy doesn't restart after.
+func (p RestartPolicy) Completed(code int) bool {
+	if p.Mode == RestartAlways || slices.Contains(p.RestartExitCodes, code) {
+		return false
+	}
+	return code == 0 || slices.Contains(p.SuccessExitCodes, code)
+}
+
+// Restarts reports whether an exit that isn't Completed is restarted after.
+// known=false is an exit whose code was never captured. restart_exit_codes
+// restart even under RestartNever.
+func (p RestartPolicy) Restarts(code int, known bool) bool {
+	if known && slices.Contains(p.RestartExitCodes, code) {
+		return true
+	}
+	return p.Mode != RestartNever
+}
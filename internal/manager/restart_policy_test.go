@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestResolveRestartPolicy(t *testing.T) {
+	policy, err := ResolveRestartPolicy(&types.ServiceConfig{})
+	if err != nil {
+		t.Fatalf("ResolveRestartPolicy(defaults): %v", err)
+	}
+	if policy.Mode != RestartOnFailure || policy.MaxRestarts != 0 || policy.Window != 0 {
+		t.Errorf("defaults = %+v, want on-failure with no limit", policy)
+	}
+
+	policy, err = ResolveRestartPolicy(&types.ServiceConfig{Restart: "always", MaxRestarts: 5, RestartWindow: "10m", SuccessExitCodes: []int{143}})
+	if err != nil {
+		t.Fatalf("ResolveRestartPolicy: %v", err)
+	}
+	if policy.Mode != RestartAlways || policy.MaxRestarts != 5 || policy.Window != 10*time.Minute {
+		t.Errorf("policy = %+v, want always, 5 restarts within 10m", policy)
+	}
+
+	errs := restartValidate(&types.ServiceConfig{
+		Restart:          "sometimes",
+		MaxRestarts:      -1,
+		RestartWindow:    "0s",
+		SuccessExitCodes: []int{256},
+		RestartExitCodes: []int{-1},
+	})
+	if len(errs) != 5 {
+		t.Fatalf("restartValidate returned %d errors, want 5: %v", len(errs), errs)
+	}
+	for i, field := range []string{"restart", "max_restarts", "restart_window", "success_exit_codes", "restart_exit_codes"} {
+		if !strings.HasPrefix(errs[i].Error(), field+":") {
+			t.Errorf("error %d = %q, want it to name %s", i, errs[i], field)
+		}
+	}
+}
+
+func TestRestartPolicy_CompletedAndRestarts(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        RestartPolicy
+		code          int
+		known         bool
+		wantCompleted bool
+		wantRestarts  bool
+	}{
+		{name: "on-failure clean exit", policy: RestartPolicy{}, code: 0, known: true, wantCompleted: true, wantRestarts: true},
+		{name: "on-failure crash", policy: RestartPolicy{}, code: 1, known: true, wantRestarts: true},
+		{name: "on-failure unknown exit", policy: RestartPolicy{}, wantRestarts: true},
+		{name: "success_exit_codes count as clean", policy: RestartPolicy{SuccessExitCodes: []int{143}}, code: 143, known: true, wantCompleted: true, wantRestarts: true},
+		{name: "always restarts a clean exit", policy: RestartPolicy{Mode: RestartAlways}, code: 0, known: true, wantRestarts: true},
+		{name: "never leaves a crash down", policy: RestartPolicy{Mode: RestartNever}, code: 1, known: true},
+		{name: "never still completes on a clean exit", policy: RestartPolicy{Mode: RestartNever}, code: 0, known: true, wantCompleted: true},
+		{name: "restart_exit_codes override never", policy: RestartPolicy{Mode: RestartNever, RestartExitCodes: []int{75}}, code: 75, known: true, wantRestarts: true},
+		{name: "restart_exit_codes override a clean exit", policy: RestartPolicy{RestartExitCodes: []int{0}}, code: 0, known: true, wantRestarts: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Completed(tt.code); tt.known && got != tt.wantCompleted {
+				t.Errorf("Completed(%d) = %v, want %v", tt.code, got, tt.wantCompleted)
+			}
+			if got := tt.policy.Restarts(tt.code, tt.known); got != tt.wantRestarts {
+				t.Errorf("Restarts(%d, %v) = %v, want %v", tt.code, tt.known, got, tt.wantRestarts)
+			}
+		})
+	}
+}
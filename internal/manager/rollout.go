@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"syscall"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// rolloutValidate reports invalid max_surge and max_unavailable settings.
+func rolloutValidate(config *types.ServiceConfig) []error {
+	var errs []error
+	count := InstanceCount(config)
+	if config.MaxSurge < 0 || config.MaxSurge > count {
+		errs = append(errs, fmt.Errorf("max_surge: %d must be between 0 and instances (%d)", config.MaxSurge, count))
+	}
+	if config.MaxUnavailable < 0 || config.MaxUnavailable > count {
+		errs = append(errs, fmt.Errorf("max_unavailable: %d must be between 0 and instances (%d)", config.MaxUnavailable, count))
+	}
+	return errs
+}
+
+// rolloutSteps splits a service's instance indices into the steps a rolling
+// reload replaces them in, max_surge plus max_unavailable at a time, or one
+// at a time when neither is set.
+func rolloutSteps(config *types.ServiceConfig) [][]int {
+	size := config.MaxSurge + config.MaxUnavailable
+	if size < 1 {
+		size = 1
+	}
+	indices := make([]int, InstanceCount(config))
+	for i := range indices {
+		indices[i] = i
+	}
+	return slices.Collect(slices.Chunk(indices, size))
+}
+
+// rollingReload is ReloadService for a service running several instances.
+// It replaces them step by step (see rolloutSteps): each step stops the
+// first max_unavailable of its instances, launches a replacement for every
+// one, and waits for each replacement to pass the readiness probe before the
+// next step begins. The outgoing instances a step overlapped keep running
+// until every step has passed, and are only then drained, so a step that
+// fails can roll the whole rollout back to them: every replacement launched
+// so far is stopped and its history row removed. An instance stopped ahead
+// of its replacement has nothing to go back to and is launched again instead.
+// Hooks run once for the service, as in a single-instance reload.
+func (m *LocalManager) rollingReload(target *reloadTarget, probe ReadinessProbe, cfg ReloadConfig) (ReloadResult, error) {
+	name := target.service.Name
+	config := target.config
+
+	history, err := m.db.GetProcessHistoryEntriesByServiceName(m.ctx, name)
+	if err != nil {
+		return ReloadResult{}, fmt.Errorf("get process history for %s: %w", name, err)
+	}
+	live := slices.DeleteFunc(history, func(row types.ProcessHistory) bool {
+		return (row.State != types.ProcessStateRunning && row.State != types.ProcessStateStarting) ||
+			!m.tracker.aliveMatching(row.PGID, row.StartedAtTicks)
+	})
+	steps := rolloutSteps(config)
+	result := ReloadResult{Instances: make([]types.ReloadInstance, InstanceCount(config)), Steps: len(steps)}
+	for i := range result.Instances {
+		result.Instances[i].Instance = i
+	}
+	for _, row := range latestInstanceRows(live) {
+		if row.Instance < len(result.Instances) {
+			result.Instances[row.Instance].OldPGID = row.PGID
+		}
+	}
+
+	if binaryErr := m.validateRuntimeBinary(config); binaryErr != nil {
+		return result, binaryErr
+	}
+	if cmdErr := validateCommandBinary(config, target.service.DirectoryPath); cmdErr != nil {
+		return result, cmdErr
+	}
+	if _, _, hookErr := m.runHook(&target.service, config, hookPreStart); hookErr != nil {
+		return result, hookErr
+	}
+
+	policy := m.stopPolicyFor(name, config, cfg.GracePeriod)
+	var stoppedAhead []int
+	for stepIndex, step := range steps {
+		stepNum := stepIndex + 1
+		m.logger.Debug("reload: rollout step", "service", name, "step", stepNum, "of", len(steps), "instances", step)
+		for _, index := range step[:min(config.MaxUnavailable, len(step))] {
+			oldPGID := result.Instances[index].OldPGID
+			if oldPGID == 0 {
+				continue
+			}
+			if drainErr := m.drainInstance(name, oldPGID, policy, cfg.TickerPeriod); drainErr != nil {
+				return m.rollbackRollout(target, &result, stepNum, stoppedAhead, policy, cfg,
+					fmt.Errorf("stopping instance %d of %s ahead of its replacement: %w", index, name, drainErr))
+			}
+			stoppedAhead = append(stoppedAhead, index)
+		}
+		for _, index := range step {
+			newPGID, launchErr := m.launchInstance(&target.service, config, target.resolvedSinks, index, "reload command")
+			if launchErr != nil {
+				return m.rollbackRollout(target, &result, stepNum, stoppedAhead, policy, cfg,
+					fmt.Errorf("launching instance %d of %s: %w", index, name, launchErr))
+			}
+			result.Instances[index].NewPGID = newPGID
+			result.Instances[index].Step = stepNum
+		}
+		for _, index := range step {
+			if !m.rolloutAwaitReady(target, probe, result.Instances[index].NewPGID, cfg) {
+				return m.rollbackRollout(target, &result, stepNum, stoppedAhead, policy, cfg,
+					fmt.Errorf("%w: instance %d of %s not ready within %s (step %d of %d)", ErrReloadNotReady, index, name, cfg.ReadinessTimeout, stepNum, len(steps)))
+			}
+		}
+	}
+	m.runHookLogged(&target.service, config, hookPostStart)
+
+	m.runHookLogged(&target.service, config, hookPreStop)
+	var drainErrs []error
+	for _, instance := range result.Instances {
+		if instance.OldPGID == 0 || slices.Contains(stoppedAhead, instance.Instance) {
+			continue
+		}
+		if drainErr := m.drainInstance(name, instance.OldPGID, policy, cfg.TickerPeriod); drainErr != nil {
+			drainErrs = append(drainErrs, fmt.Errorf("instance %d: %w", instance.Instance, drainErr))
+		}
+	}
+	m.runHookLogged(&target.service, config, hookPostStop)
+
+	result.OldPGID, result.NewPGID = result.Instances[0].OldPGID, result.Instances[0].NewPGID
+	if len(drainErrs) > 0 {
+		return result, fmt.Errorf("draining old instances for %s: %w", name, errors.Join(drainErrs...))
+	}
+	m.recordReloadCutover(name, target.instance.RestartCount)
+	return result, nil
+}
+
+// rolloutAwaitReady is awaitReady for one replacement of a rolling reload,
+// probing the port it listens on under increment_port.
+func (m *LocalManager) rolloutAwaitReady(target *reloadTarget, probe ReadinessProbe, pgid int, cfg ReloadConfig) bool {
+	entry, err := m.db.GetProcessHistoryEntryByPGID(m.ctx, pgid)
+	if err != nil {
+		m.logger.Error("reload: reading replacement's history row", "service", target.service.Name, "pgid", pgid, "error", err)
+		return false
+	}
+	readiness, err := m.ReadinessProbeFor(&target.service, m.ServiceConfigForLaunch(target.service.Name, pgid, target.config))
+	if err != nil {
+		m.logger.Error("reload: resolving readiness check", "service", target.service.Name, "error", err)
+		return false
+	}
+	if ServiceType(target.config.Type) == ServiceTypeNotify {
+		readiness = m.notifyReadiness(pgid, readiness)
+	}
+	return m.awaitReady(probe, pgid, entry.StartedAtTicks, readiness, cfg)
+}
+
+// rollbackRollout undoes a rolling reload whose step failed with cause:
+// every replacement launched so far is stopped per policy and its history
+// row removed, so each instance's outgoing row is its most recent again and
+// the health monitor goes on supervising it; an instance stopped ahead of
+// its replacement is launched again. It returns result, marked with the
+// failed step and the instances rolled back, and cause.
+func (m *LocalManager) rollbackRollout(target *reloadTarget, result *ReloadResult, failedStep int, stoppedAhead []int, policy StopPolicy, cfg ReloadConfig, cause error) (ReloadResult, error) {
+	name := target.service.Name
+	m.logger.Warn("reload: rollout step failed, rolling back", "service", name, "step", failedStep, "error", cause)
+	result.FailedStep = failedStep
+	for i := range result.Instances {
+		instance := &result.Instances[i]
+		if instance.NewPGID == 0 {
+			continue
+		}
+		m.retireReplacement(name, instance.NewPGID, policy, cfg)
+		instance.RolledBack = true
+	}
+	for _, index := range stoppedAhead {
+		pgid, err := m.launchInstance(&target.service, target.config, target.resolvedSinks, index, "reload rollback command")
+		if err != nil {
+			m.logger.Error("reload: relaunching instance stopped ahead of its replacement", "service", name, "instance", index, "error", err)
+			cause = errors.Join(cause, fmt.Errorf("relaunching instance %d: %w", index, err))
+			continue
+		}
+		result.Instances[index].NewPGID = pgid
+		result.Instances[index].Relaunched = true
+	}
+	result.OldPGID, result.NewPGID = result.Instances[0].OldPGID, result.Instances[0].NewPGID
+	return *result, cause
+}
+
+// retireReplacement stops one replacement a rollback undoes and removes its
+// history row. One that passed readiness may already be taking connections,
+// so it gets the service's stop policy; one that never did is killed.
+func (m *LocalManager) retireReplacement(name string, pgid int, policy StopPolicy, cfg ReloadConfig) {
+	entry, err := m.db.GetProcessHistoryEntryByPGID(m.ctx, pgid)
+	if err != nil || !m.tracker.aliveMatching(pgid, entry.StartedAtTicks) {
+		if killErr := m.tracker.signal(pgid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+			m.logger.Error("reload: killing rolled-back instance", "service", name, "pgid", pgid, "error", killErr)
+		}
+	} else if _, termErr := m.terminateInstance(name, pgid, entry.StartedAtTicks, policy, cfg.TickerPeriod); termErr != nil {
+		m.logger.Error("reload: stopping rolled-back instance", "service", name, "pgid", pgid, "error", termErr)
+	}
+	if _, delErr := m.db.RemoveProcessHistoryEntryViaPGID(m.ctx, pgid); delErr != nil {
+		m.logger.Error("reload: removing rolled-back instance history row", "service", name, "pgid", pgid, "error", delErr)
+	}
+}
@@ -0,0 +1,177 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolloutSteps(t *testing.T) {
+	tests := []struct {
+		config types.ServiceConfig
+		want   [][]int
+	}{
+		{types.ServiceConfig{Instances: 3}, [][]int{{0}, {1}, {2}}},
+		{types.ServiceConfig{Instances: 5, MaxSurge: 2}, [][]int{{0, 1}, {2, 3}, {4}}},
+		{types.ServiceConfig{Instances: 4, MaxSurge: 1, MaxUnavailable: 1}, [][]int{{0, 1}, {2, 3}}},
+		{types.ServiceConfig{Instances: 2, MaxUnavailable: 2}, [][]int{{0, 1}}},
+	}
+	for _, tt := range tests {
+		got := rolloutSteps(&tt.config)
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("rolloutSteps(instances %d, surge %d, unavailable %d) = %v, want %v",
+				tt.config.Instances, tt.config.MaxSurge, tt.config.MaxUnavailable, got, tt.want)
+		}
+	}
+}
+
+func TestRolloutValidate(t *testing.T) {
+	if errs := rolloutValidate(&types.ServiceConfig{Instances: 4, MaxSurge: 2, MaxUnavailable: 1}); len(errs) != 0 {
+		t.Errorf("rolloutValidate(surge 2, unavailable 1 of 4) = %v, want no errors", errs)
+	}
+	if errs := rolloutValidate(&types.ServiceConfig{Instances: 2, MaxSurge: 3, MaxUnavailable: -1}); len(errs) != 2 {
+		t.Errorf("rolloutValidate(surge 3, unavailable -1 of 2) = %v, want both out of range", errs)
+	}
+}
+
+// startInstancesService registers and starts a service running instances
+// copies of a long sleep, returning the manager and each instance's PGID.
+func startInstancesService(t *testing.T, name string, config types.ServiceConfig) (*LocalManager, []int) {
+	t.Helper()
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	t.Setenv("EOS_BASE_DIR", tempDir)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	dir := filepath.Join(tempDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	config.Name = name
+	config.Command = types.ServiceCommand{Shell: "sleep 300"}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.yaml"), data, 0644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	entry, err := NewServiceCatalogEntry(name, dir, "service.yaml")
+	if err != nil {
+		t.Fatalf("catalog entry: %v", err)
+	}
+	if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+		t.Fatalf("add catalog entry: %v", err)
+	}
+	if _, err := m.StartService(t.Context(), name); err != nil {
+		t.Fatalf("StartService: %v", err)
+	}
+	rows, err := m.GetInstanceProcessHistoryEntries(t.Context(), name)
+	if err != nil || len(rows) != config.Instances {
+		t.Fatalf("GetInstanceProcessHistoryEntries = %+v, %v; want %d rows", rows, err, config.Instances)
+	}
+	pgids := make([]int, len(rows))
+	for i, row := range rows {
+		pgids[i] = row.PGID
+		t.Cleanup(func() { killGroup(row.PGID) })
+	}
+	return m, pgids
+}
+
+// readyFirst returns a readiness probe that passes only the first n
+// replacements it is asked about.
+func readyFirst(n int) ReadinessProbe {
+	var mu sync.Mutex
+	var seen []int
+	return func(_ context.Context, pgid int, _ int64, _ HealthProbe) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if !slices.Contains(seen, pgid) {
+			seen = append(seen, pgid)
+		}
+		return slices.Index(seen, pgid) < n
+	}
+}
+
+var rolloutTestConfig = ReloadConfig{
+	GracePeriod:      2 * time.Second,
+	TickerPeriod:     20 * time.Millisecond,
+	ReadinessTimeout: 300 * time.Millisecond,
+	ProbeInterval:    20 * time.Millisecond,
+}
+
+func TestRollingReloadReplacesEveryInstance(t *testing.T) {
+	m, oldPGIDs := startInstancesService(t, "rollout-ok", types.ServiceConfig{Instances: 3, MaxSurge: 2})
+
+	result, err := m.ReloadService("rollout-ok", alwaysReady, rolloutTestConfig)
+	if err != nil {
+		t.Fatalf("ReloadService: %v", err)
+	}
+	if result.Steps != 2 || len(result.Instances) != 3 {
+		t.Fatalf("result = %+v, want 3 instances in 2 steps", result)
+	}
+	for i, instance := range result.Instances {
+		t.Cleanup(func() { killGroup(instance.NewPGID) })
+		if instance.OldPGID != oldPGIDs[i] || instance.NewPGID == 0 || instance.RolledBack {
+			t.Errorf("instance %d = %+v, want pgid %d replaced", i, instance, oldPGIDs[i])
+		}
+		if !waitGone(oldPGIDs[i]) {
+			t.Errorf("old instance %d pgid %d should have been drained", i, oldPGIDs[i])
+		}
+		if !procutil.IsAlive(instance.NewPGID) {
+			t.Errorf("new instance %d pgid %d should be running", i, instance.NewPGID)
+		}
+	}
+	if result.Instances[2].Step != 2 {
+		t.Errorf("instance 2 replaced in step %d, want 2", result.Instances[2].Step)
+	}
+}
+
+// TestRollingReloadRollsBack fails the second step and checks the rollout
+// undoes the first: its replacement is stopped and its history row removed,
+// while every old instance keeps running as its instance's latest row.
+func TestRollingReloadRollsBack(t *testing.T) {
+	m, oldPGIDs := startInstancesService(t, "rollout-fail", types.ServiceConfig{Instances: 3})
+
+	result, err := m.ReloadService("rollout-fail", readyFirst(1), rolloutTestConfig)
+	if !errors.Is(err, ErrReloadNotReady) {
+		t.Fatalf("ReloadService err = %v, want ErrReloadNotReady", err)
+	}
+	if result.FailedStep != 2 {
+		t.Errorf("FailedStep = %d, want 2", result.FailedStep)
+	}
+	for i, instance := range result.Instances {
+		if instance.NewPGID != 0 {
+			t.Cleanup(func() { killGroup(instance.NewPGID) })
+			if !instance.RolledBack || !waitGone(instance.NewPGID) {
+				t.Errorf("replacement of instance %d (pgid %d) should have been rolled back", i, instance.NewPGID)
+			}
+		}
+		if !procutil.IsAlive(oldPGIDs[i]) {
+			t.Errorf("old instance %d pgid %d must keep serving after a rollback", i, oldPGIDs[i])
+		}
+	}
+	if result.Instances[2].NewPGID != 0 {
+		t.Errorf("instance 2 = %+v, want it never reached", result.Instances[2])
+	}
+
+	rows, err := m.GetInstanceProcessHistoryEntries(t.Context(), "rollout-fail")
+	if err != nil {
+		t.Fatalf("GetInstanceProcessHistoryEntries: %v", err)
+	}
+	for i, row := range rows {
+		if row.PGID != oldPGIDs[i] {
+			t.Errorf("instance %d latest row pgid = %d, want the surviving old %d", i, row.PGID, oldPGIDs[i])
+		}
+	}
+}
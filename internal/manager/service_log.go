@@ -181,6 +181,7 @@ func (m *LocalManager) appendHealthEventToLog(serviceName string, errorLog bool,
 	if syncErr := w.Sync(); syncErr != nil {
 		return fmt.Errorf("syncing log file: %w", syncErr)
 	}
+	m.teeOutput(serviceName, logutil.HealthBreadcrumbSource, message)
 	return nil
 }
 
@@ -342,7 +342,7 @@ func TestGetServiceLastErrorLine_RealProcessPrefersErrorShapedLineOverTrailingBa
 
 	testFile := &types.ServiceConfig{
 		Name:    "crash-svc",
-		Command: `printf 'Error: listen EADDRINUSE: address already in use :::3000\n  code: EADDRINUSE,\n\nNode.js v20.20.2\n' >&2; exit 1`,
+		Command: types.ServiceCommand{Shell: `printf 'Error: listen EADDRINUSE: address already in use :::3000\n  code: EADDRINUSE,\n\nNode.js v20.20.2\n' >&2; exit 1`},
 		Runtime: types.Runtime{Type: "nodejs"},
 	}
 	yamlData, err := yaml.Marshal(testFile)
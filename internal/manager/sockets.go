@@ -0,0 +1,207 @@
+package manager
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// socketsListenPIDScript runs in place of the service's own command for a
+// launch with sockets: it exports its own PID as LISTEN_PID and execs the
+// service, which keeps that PID. exec.Cmd has no hook between fork and exec
+// to set it from, and sd_listen_fds(3) ignores LISTEN_FDS when LISTEN_PID
+// names some other process.
+const socketsListenPIDScript = `LISTEN_PID=$$; export LISTEN_PID; exec "$@"`
+
+// serviceSockets is the listeners the daemon holds open for one service,
+// shared by every launch until the service is stopped (see socketsRelease).
+type serviceSockets struct {
+	specs []types.ServiceSocket
+	files []*os.File
+	// paths are the Unix socket files to remove once the listeners close.
+	paths []string
+}
+
+// socketValidate reports each invalid sockets: entry.
+func socketValidate(config *types.ServiceConfig) []error {
+	var errs []error
+	seen := make(map[string]bool, len(config.Sockets))
+	for i, spec := range config.Sockets {
+		field := fmt.Sprintf("sockets[%d]", i)
+		switch {
+		case spec.Name == "":
+			errs = append(errs, fmt.Errorf("%s.name: is required", field))
+		case len(spec.Name) > 255 || strings.ContainsAny(spec.Name, ": \t\n"):
+			errs = append(errs, fmt.Errorf("%s.name: %q must be at most 255 characters, without colons or whitespace", field, spec.Name))
+		case seen[spec.Name]:
+			errs = append(errs, fmt.Errorf("%s.name: %q is used by another socket", field, spec.Name))
+		}
+		seen[spec.Name] = true
+		if (spec.TCP == "") == (spec.Unix == "") {
+			errs = append(errs, fmt.Errorf("%s: exactly one of tcp or unix is required", field))
+			continue
+		}
+		if spec.TCP != "" {
+			if _, port, err := net.SplitHostPort(spec.TCP); err != nil {
+				errs = append(errs, fmt.Errorf("%s.tcp: %w", field, err))
+			} else if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+				errs = append(errs, fmt.Errorf("%s.tcp: port %q must be between 1 and 65535", field, port))
+			}
+		}
+	}
+	return errs
+}
+
+// socketsAttach hands a launch about to start the listeners its sockets:
+// entries describe, binding them on the service's first launch and reusing
+// them on every later one, so a restart or reload never has a moment with
+// nothing listening. A changed sockets: list rebinds from scratch. The
+// listeners go to cmd as inherited descriptors from 3 on, right after
+// stdin, stdout and stderr, described by LISTEN_FDS and LISTEN_FDNAMES, and
+// cmd is wrapped in socketsListenPIDScript to set LISTEN_PID. A service
+// without sockets is left untouched. bound reports whether this call bound
+// the listeners, rather than reusing ones held for an earlier launch, so a
+// launch that then fails to start can give them back (see
+// launchAndCapture).
+func (m *LocalManager) socketsAttach(service *types.ServiceCatalogEntry, config *types.ServiceConfig, cmd *exec.Cmd) (bound bool, err error) {
+	if len(config.Sockets) == 0 {
+		return false, nil
+	}
+	m.socketsMu.Lock()
+	defer m.socketsMu.Unlock()
+
+	held, ok := m.sockets[service.Name]
+	if !ok || !slices.Equal(held.specs, config.Sockets) {
+		if ok {
+			held.close()
+			delete(m.sockets, service.Name)
+		}
+		cred, err := m.serviceCredential(config)
+		if err != nil {
+			return false, fmt.Errorf("resolving user/group for sockets: %w", err)
+		}
+		held, err = socketsBind(service.DirectoryPath, config.Sockets, cred)
+		if err != nil {
+			return false, err
+		}
+		m.sockets[service.Name] = held
+		bound = true
+	}
+
+	names := make([]string, len(config.Sockets))
+	for i, spec := range config.Sockets {
+		names[i] = spec.Name
+	}
+	cmd.ExtraFiles = held.files
+	cmd.Env = append(cmd.Env,
+		fmt.Sprintf("LISTEN_FDS=%d", len(held.files)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+	)
+	cmd.Args = append([]string{"/bin/sh", "-c", socketsListenPIDScript, "eos", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = "/bin/sh"
+	return bound, nil
+}
+
+// socketsBind binds every listener in specs, in order. A Unix socket is
+// handed to cred's user and group, as if the service had created it itself.
+// On failure nothing stays bound.
+func socketsBind(dir string, specs []types.ServiceSocket, cred *launchCredential) (*serviceSockets, error) {
+	held := &serviceSockets{specs: slices.Clone(specs)}
+	for _, spec := range specs {
+		file, path, err := socketsListen(dir, spec, cred)
+		if err != nil {
+			held.close()
+			return nil, fmt.Errorf("binding socket %s: %w", spec.Name, err)
+		}
+		held.files = append(held.files, file)
+		if path != "" {
+			held.paths = append(held.paths, path)
+		}
+	}
+	return held, nil
+}
+
+// socketsListen binds spec's listener and returns it as the file a launch
+// inherits, in blocking mode as sd_listen_fds(3) callers expect, plus the
+// path of its socket file for a Unix socket.
+func socketsListen(dir string, spec types.ServiceSocket, cred *launchCredential) (*os.File, string, error) {
+	var (
+		listener interface {
+			Close() error
+			File() (*os.File, error)
+		}
+		path string
+	)
+	if spec.TCP != "" {
+		l, err := net.Listen("tcp", spec.TCP)
+		if err != nil {
+			return nil, "", err
+		}
+		listener = l.(*net.TCPListener)
+	} else {
+		path = spec.Unix
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		// A socket file left behind by a daemon that didn't shut down
+		// cleanly would otherwise fail the bind with EADDRINUSE.
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(path)
+		}
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			return nil, "", err
+		}
+		// The listener is closed below once its descriptor is duplicated;
+		// the socket file must outlive it.
+		l.SetUnlinkOnClose(false)
+		listener = l
+		if cred != nil {
+			if err := os.Lchown(path, int(cred.uid), int(cred.gid)); err != nil {
+				_ = l.Close()
+				_ = os.Remove(path)
+				return nil, "", fmt.Errorf("handing socket to service user: %w", err)
+			}
+		}
+	}
+	file, err := listener.File()
+	_ = listener.Close()
+	if err != nil {
+		if path != "" {
+			_ = os.Remove(path)
+		}
+		return nil, "", err
+	}
+	// Fd puts the descriptor in blocking mode.
+	_ = file.Fd()
+	return file, path, nil
+}
+
+// close closes every listener and removes its Unix socket files.
+func (s *serviceSockets) close() {
+	for _, file := range s.files {
+		_ = file.Close()
+	}
+	for _, path := range s.paths {
+		_ = os.Remove(path)
+	}
+}
+
+// socketsRelease closes the listeners the daemon holds for name, once the
+// service is stopped rather than restarted: a stopped service refuses
+// connections like any other. A service without sockets is a no-op.
+func (m *LocalManager) socketsRelease(name string) {
+	m.socketsMu.Lock()
+	defer m.socketsMu.Unlock()
+	if held, ok := m.sockets[name]; ok {
+		held.close()
+		delete(m.sockets, name)
+	}
+}
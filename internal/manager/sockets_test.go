@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestSocketValidate(t *testing.T) {
+	errs := socketValidate(&types.ServiceConfig{Sockets: []types.ServiceSocket{
+		{Name: "http", TCP: ":8080"},
+		{Name: "http", Unix: "admin.sock"},
+		{Name: "a:b", TCP: "localhost"},
+		{TCP: ":0", Unix: "both.sock"},
+	}})
+	want := []string{
+		"sockets[1].name:",
+		"sockets[2].name:",
+		"sockets[2].tcp:",
+		"sockets[3].name:",
+		"sockets[3]: exactly one of",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("socketValidate returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(errs[i].Error(), prefix) {
+			t.Errorf("error %d = %q, want prefix %q", i, errs[i], prefix)
+		}
+	}
+}
+
+// TestSocketsAttach_PassesListenersAcrossLaunches launches a service with a
+// Unix socket twice: both launches see it as fd 3 with LISTEN_PID naming
+// themselves, the second reuses the first's listener, and a stop closes it.
+func TestSocketsAttach_PassesListenersAcrossLaunches(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	dir := t.TempDir()
+	service := &types.ServiceCatalogEntry{Name: "socket-svc", DirectoryPath: dir}
+	config := &types.ServiceConfig{Sockets: []types.ServiceSocket{{Name: "api", Unix: "api.sock"}}}
+	script := types.ServiceCommand{Shell: `test "$LISTEN_PID" = "$$" && test -e /proc/$$/fd/3 && echo "$LISTEN_FDS $LISTEN_FDNAMES"`}
+
+	var first *os.File
+	for launch := range 2 {
+		cmd := m.commandFor(t.Context(), script, os.Environ())
+		if _, err := m.socketsAttach(service, config, cmd); err != nil {
+			t.Fatalf("socketsAttach: %v", err)
+		}
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("launch %d: %v", launch, err)
+		}
+		if got := strings.TrimSpace(string(out)); got != "1 api" {
+			t.Errorf("launch %d saw %q, want LISTEN_FDS 1 and LISTEN_FDNAMES api", launch, got)
+		}
+		if launch == 0 {
+			first = cmd.ExtraFiles[0]
+		} else if cmd.ExtraFiles[0] != first {
+			t.Error("second launch got a new listener, want the first one reused")
+		}
+	}
+
+	socketPath := filepath.Join(dir, "api.sock")
+	if _, err := os.Stat(socketPath); err != nil {
+		t.Fatalf("socket file missing while held: %v", err)
+	}
+	m.socketsRelease(service.Name)
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("socket file survived release: %v", err)
+	}
+}
+
+// TestSocketsAttach_ReleasedWhenLaunchFails proves a launch that binds its
+// sockets and then fails to start doesn't leave them bound.
+func TestSocketsAttach_ReleasedWhenLaunchFails(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	// A service directory that doesn't exist fails cmd.Start on its chdir.
+	service := &types.ServiceCatalogEntry{Name: "socket-svc", DirectoryPath: filepath.Join(tempDir, "missing")}
+	config := &types.ServiceConfig{
+		Command: types.ServiceCommand{Shell: "true"},
+		Sockets: []types.ServiceSocket{{Name: "api", TCP: "127.0.0.1:0"}},
+	}
+	if _, err := m.launchInstance(service, config, nil, 0, "start command"); err == nil {
+		t.Fatal("launchInstance succeeded in a missing service directory")
+	}
+	m.socketsMu.Lock()
+	_, held := m.sockets[service.Name]
+	m.socketsMu.Unlock()
+	if held {
+		t.Error("sockets stayed bound after their launch failed to start")
+	}
+}
@@ -0,0 +1,405 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// StackManifestFileName is the manifest eos apply reads when not given one.
+const StackManifestFileName = "eos.stack.yaml"
+
+// stackManifest is eos.stack.yaml as written.
+type stackManifest struct {
+	Services []stackManifestService `yaml:"services"`
+}
+
+// stackManifestService is one entry of a manifest's services: list: either
+// Path, to a service directory or service.yaml, or an inline Config run from
+// Directory. Both paths are relative to the manifest.
+type stackManifestService struct {
+	Path      string    `yaml:"path,omitempty"`
+	Directory string    `yaml:"directory,omitempty"`
+	Config    yaml.Node `yaml:"config,omitempty"`
+}
+
+// StackEntry is one service of a stack manifest, resolved to the catalog
+// entry it should have.
+type StackEntry struct {
+	Config *types.ServiceConfig
+	// Name is Config.Name.
+	Name string
+	// DirectoryPath and ConfigFileName are the catalog entry's: absolute
+	// and relative to it, respectively.
+	DirectoryPath  string
+	ConfigFileName string
+	// Rendered is an inline config as written to ConfigFileName by
+	// WriteStackConfig; nil for a path: entry, whose file is the user's.
+	Rendered []byte
+}
+
+// stackInlineConfigFileName is the file an inline config named name is
+// written to in its directory, hidden so it doesn't pass for one a user keeps.
+func stackInlineConfigFileName(name string) string {
+	return ".eos." + name + ".yaml"
+}
+
+// LoadStackManifest reads and validates the manifest at path, resolving each
+// of its services. Every service's config is checked as eos validate would,
+// and all the problems found are returned together.
+func LoadStackManifest(path string) ([]StackEntry, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving manifest path: %w", err)
+	}
+	data, err := os.ReadFile(absPath) //nolint:gosec // the manifest path is the operator's own argument
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var manifest stackManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if len(manifest.Services) == 0 {
+		return nil, fmt.Errorf("%s lists no services", path)
+	}
+
+	base := filepath.Dir(absPath)
+	entries := make([]StackEntry, 0, len(manifest.Services))
+	seen := make(map[string]int, len(manifest.Services))
+	var errs []error
+	for i := range manifest.Services {
+		entry, err := resolveStackService(base, &manifest.Services[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("services[%d]: %w", i, err))
+			continue
+		}
+		if first, ok := seen[entry.Name]; ok {
+			errs = append(errs, fmt.Errorf("services[%d]: %s is already listed as services[%d]", i, entry.Name, first))
+			continue
+		}
+		seen[entry.Name] = i
+		entries = append(entries, entry)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return entries, nil
+}
+
+func resolveStackService(base string, service *stackManifestService) (StackEntry, error) {
+	inline := !service.Config.IsZero()
+	switch {
+	case service.Path != "" && inline:
+		return StackEntry{}, fmt.Errorf("path and config can't both be set")
+	case service.Path == "" && !inline:
+		return StackEntry{}, fmt.Errorf("one of path or config is required")
+	case service.Directory != "" && !inline:
+		return StackEntry{}, fmt.Errorf("directory only applies to an inline config")
+	}
+	if !inline {
+		return resolveStackPath(stackJoin(base, service.Path))
+	}
+
+	dir := stackJoin(base, service.Directory)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return StackEntry{}, fmt.Errorf("directory %s does not exist", dir)
+	}
+	var config types.ServiceConfig
+	if err := service.Config.Decode(&config); err != nil {
+		return StackEntry{}, fmt.Errorf("parsing config: %w", err)
+	}
+	if errs := cfgvValidateParsedConfig(&config, dir); len(errs) > 0 {
+		return StackEntry{}, fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	rendered, err := yaml.Marshal(&service.Config)
+	if err != nil {
+		return StackEntry{}, fmt.Errorf("rendering config: %w", err)
+	}
+	return StackEntry{
+		Config:         &config,
+		Name:           config.Name,
+		DirectoryPath:  dir,
+		ConfigFileName: stackInlineConfigFileName(config.Name),
+		Rendered:       rendered,
+	}, nil
+}
+
+// resolveStackPath resolves a path: entry as eos add does: a directory
+// holding service.yaml or service.yml, or the file itself.
+func resolveStackPath(path string) (StackEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return StackEntry{}, fmt.Errorf("%s does not exist", path)
+	}
+	file := path
+	if info.IsDir() {
+		file = ""
+		for _, candidate := range []string{"service.yaml", "service.yml"} {
+			if _, statErr := os.Stat(filepath.Join(path, candidate)); statErr == nil {
+				file = filepath.Join(path, candidate)
+				break
+			}
+		}
+		if file == "" {
+			return StackEntry{}, fmt.Errorf("no service.yaml or service.yml found in %s", path)
+		}
+	} else if !strings.HasSuffix(file, ".yaml") && !strings.HasSuffix(file, ".yml") {
+		return StackEntry{}, fmt.Errorf("%s is not a directory nor a yaml file", path)
+	}
+
+	config, errs := ValidateServiceConfig(file)
+	if len(errs) > 0 {
+		return StackEntry{}, fmt.Errorf("invalid %s: %w", file, errors.Join(errs...))
+	}
+	return StackEntry{
+		Config:         config,
+		Name:           config.Name,
+		DirectoryPath:  filepath.Dir(file),
+		ConfigFileName: filepath.Base(file),
+	}, nil
+}
+
+func stackJoin(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// StackStartOrder orders entries so each comes after the entries it
+// depends_on, otherwise keeping the manifest's order. A dependency outside
+// the manifest is left to the dependency gate at start; a cycle within it is
+// an error.
+func StackStartOrder(entries []StackEntry) ([]StackEntry, error) {
+	index := make(map[string]int, len(entries))
+	for i := range entries {
+		index[entries[i].Name] = i
+	}
+	placed := make([]bool, len(entries))
+	ordered := make([]StackEntry, 0, len(entries))
+	for len(ordered) < len(entries) {
+		// Each round places the first entry listed whose dependencies are
+		// all placed, so one waiting on a later entry keeps its position
+		// relative to the entries after it.
+		next := -1
+		for i := range entries {
+			if !placed[i] && stackDependenciesPlaced(entries[i].Config.DependsOn, index, placed) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var stuck []string
+			for i := range entries {
+				if !placed[i] {
+					stuck = append(stuck, entries[i].Name)
+				}
+			}
+			return nil, fmt.Errorf("depends_on has a cycle between %s", strings.Join(stuck, ", "))
+		}
+		placed[next] = true
+		ordered = append(ordered, entries[next])
+	}
+	return ordered, nil
+}
+
+func stackDependenciesPlaced(dependsOn []string, index map[string]int, placed []bool) bool {
+	for _, dep := range dependsOn {
+		if i, ok := index[dep]; ok && !placed[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StackAction is what eos apply does to one service.
+type StackAction string
+
+const (
+	// StackActionAdd registers a service new to the catalog and starts it.
+	StackActionAdd StackAction = "add"
+	// StackActionUpdate repoints a registered service's catalog entry, or
+	// rewrites its inline config, then restarts it, or starts it if it
+	// isn't running.
+	StackActionUpdate StackAction = "update"
+	// StackActionRestart restarts a running service whose config changed
+	// on disk since it was started.
+	StackActionRestart StackAction = "restart"
+	// StackActionStart starts a registered service that isn't running.
+	StackActionStart StackAction = "start"
+	// StackActionUnchanged leaves a running, up-to-date service alone.
+	StackActionUnchanged StackAction = "unchanged"
+	// StackActionPrune stops and unregisters a service the manifest no
+	// longer lists.
+	StackActionPrune StackAction = "prune"
+)
+
+// StackStep is one service's part of a StackPlan.
+type StackStep struct {
+	// Entry is the manifest's service; nil for a prune.
+	Entry  *StackEntry
+	Name   string
+	Action StackAction
+	// Changes are the config fields an update or restart applies.
+	Changes []types.ConfigChange
+	// Running reports the service is running now.
+	Running bool
+	// Repoint reports an update moves the catalog entry to another file.
+	Repoint bool
+	// EnvChanged reports a restart picks up changed env files.
+	EnvChanged bool
+}
+
+// StackPlan is what eos apply will do, prunes first and then the manifest's
+// services in StackStartOrder.
+type StackPlan struct {
+	Steps []StackStep
+}
+
+// HasChanges reports whether applying the plan would do anything.
+func (p StackPlan) HasChanges() bool {
+	for _, step := range p.Steps {
+		if step.Action != StackActionUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// stackDriftReader is the optional manager capability PlanStack reads a
+// running service's config drift through (see LocalManager.GetConfigDrift).
+type stackDriftReader interface {
+	GetConfigDrift(ctx context.Context, name string) (*types.ConfigDrift, error)
+}
+
+// PlanStack compares ordered (see StackStartOrder) with mgr's catalog and
+// running services, changing nothing. With prune set, registered services
+// the manifest doesn't list are planned for removal.
+func PlanStack(ctx context.Context, mgr ServiceManager, ordered []StackEntry, prune bool) (StackPlan, error) {
+	catalog, err := mgr.GetAllServiceCatalogEntries(ctx)
+	if err != nil {
+		return StackPlan{}, fmt.Errorf("getting registered services: %w", err)
+	}
+	registered := make(map[string]types.ServiceCatalogEntry, len(catalog))
+	for _, service := range catalog {
+		registered[service.Name] = service
+	}
+
+	var plan StackPlan
+	if prune {
+		listed := make(map[string]bool, len(ordered))
+		for i := range ordered {
+			listed[ordered[i].Name] = true
+		}
+		for _, service := range catalog {
+			if listed[service.Name] {
+				continue
+			}
+			running, err := stackRunning(ctx, mgr, service.Name)
+			if err != nil {
+				return StackPlan{}, err
+			}
+			plan.Steps = append(plan.Steps, StackStep{Name: service.Name, Action: StackActionPrune, Running: running})
+		}
+	}
+
+	for i := range ordered {
+		entry := &ordered[i]
+		step, err := planStackEntry(ctx, mgr, entry, registered)
+		if err != nil {
+			return StackPlan{}, err
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
+
+func planStackEntry(ctx context.Context, mgr ServiceManager, entry *StackEntry, registered map[string]types.ServiceCatalogEntry) (StackStep, error) {
+	step := StackStep{Entry: entry, Name: entry.Name}
+	current, ok := registered[entry.Name]
+	if !ok {
+		step.Action = StackActionAdd
+		return step, nil
+	}
+	running, err := stackRunning(ctx, mgr, entry.Name)
+	if err != nil {
+		return StackStep{}, err
+	}
+	step.Running = running
+
+	step.Repoint = current.DirectoryPath != entry.DirectoryPath || current.ConfigFileName != entry.ConfigFileName
+	target := filepath.Join(entry.DirectoryPath, entry.ConfigFileName)
+	if step.Repoint || (entry.Rendered != nil && !stackFileHolds(target, entry.Rendered)) {
+		step.Action = StackActionUpdate
+		// An unreadable current config leaves nothing to diff against; the
+		// update replaces it either way.
+		if onDisk, loadErr := LoadServiceConfig(filepath.Join(current.DirectoryPath, current.ConfigFileName)); loadErr == nil {
+			if step.Changes, err = DiffServiceConfig(onDisk, entry.Config); err != nil {
+				return StackStep{}, err
+			}
+		}
+		return step, nil
+	}
+
+	if !running {
+		step.Action = StackActionStart
+		return step, nil
+	}
+	step.Action = StackActionUnchanged
+	if reader, ok := mgr.(stackDriftReader); ok {
+		drift, err := reader.GetConfigDrift(ctx, entry.Name)
+		if err != nil {
+			return StackStep{}, fmt.Errorf("comparing %s's config: %w", entry.Name, err)
+		}
+		if drift != nil && drift.Pending() {
+			step.Action = StackActionRestart
+			step.Changes = drift.Changes
+			step.EnvChanged = drift.EnvChanged
+		}
+	}
+	return step, nil
+}
+
+// stackRunning reports whether name's latest launch is starting or running.
+func stackRunning(ctx context.Context, mgr ServiceManager, name string) (bool, error) {
+	latest, err := mgr.GetMostRecentProcessHistoryEntry(ctx, name)
+	if errors.Is(err, ErrProcessNotFound) || errors.Is(err, ErrServiceNotRunning) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting %s's process history: %w", name, err)
+	}
+	return latest != nil && (latest.State == types.ProcessStateRunning || latest.State == types.ProcessStateStarting), nil
+}
+
+func stackFileHolds(path string, want []byte) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a manifest entry's own config file
+	return err == nil && bytes.Equal(data, want)
+}
+
+// WriteStackConfig writes an inline entry's config to its file, replacing
+// it atomically so a service started meanwhile never reads half of it. A
+// path: entry has nothing to write.
+func WriteStackConfig(entry *StackEntry) error {
+	if entry.Rendered == nil {
+		return nil
+	}
+	target := filepath.Join(entry.DirectoryPath, entry.ConfigFileName)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, entry.Rendered, 0644); err != nil { //nolint:gosec // a service config, readable like any service.yaml
+		return fmt.Errorf("writing %s: %w", target, err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+)
+
+// writeStackFile writes content to name under dir, creating its parents.
+func writeStackFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadStackManifestResolvesPathsAndInlineConfigs(t *testing.T) {
+	dir := t.TempDir()
+	writeStackFile(t, dir, "api/service.yaml", "name: api\ncommand: sleep 300\ndepends_on: [db]\n")
+	writeStackFile(t, dir, "db/db.yaml", "name: db\ncommand: sleep 300\n")
+	if err := os.MkdirAll(filepath.Join(dir, "cms"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifest := writeStackFile(t, dir, "eos.stack.yaml", `services:
+  - path: ./api
+  - path: db/db.yaml
+  - directory: cms
+    config:
+      name: cms
+      command: sleep 300
+      port: 3000
+`)
+
+	entries, err := LoadStackManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadStackManifest: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %+v, want three", entries)
+	}
+	if entries[0].DirectoryPath != filepath.Join(dir, "api") || entries[0].ConfigFileName != "service.yaml" {
+		t.Errorf("api entry = %+v, want its directory's service.yaml", entries[0])
+	}
+	if entries[1].ConfigFileName != "db.yaml" || entries[1].Rendered != nil {
+		t.Errorf("db entry = %+v, want the named file and nothing to write", entries[1])
+	}
+	cms := entries[2]
+	if cms.DirectoryPath != filepath.Join(dir, "cms") || cms.ConfigFileName != ".eos.cms.yaml" || cms.Config.Port != 3000 {
+		t.Errorf("cms entry = %+v, want the inline config run from cms/", cms)
+	}
+
+	ordered, err := StackStartOrder(entries)
+	if err != nil {
+		t.Fatalf("StackStartOrder: %v", err)
+	}
+	if ordered[0].Name != "db" || ordered[1].Name != "api" || ordered[2].Name != "cms" {
+		t.Errorf("start order = %s, %s, %s; want db before api, otherwise as listed", ordered[0].Name, ordered[1].Name, ordered[2].Name)
+	}
+}
+
+func TestLoadStackManifestRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"no services", "services: []\n", "lists no services"},
+		{"both path and config", "services:\n  - path: .\n    config:\n      name: a\n      command: x\n", "can't both be set"},
+		{"neither", "services:\n  - directory: .\n", "one of path or config"},
+		{"invalid inline", "services:\n  - config:\n      name: a\n", "command is required"},
+		{"duplicate", "services:\n  - config: {name: a, command: x}\n  - config: {name: a, command: y}\n", "already listed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := writeStackFile(t, t.TempDir(), "eos.stack.yaml", tt.manifest)
+			if _, err := LoadStackManifest(manifest); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadStackManifest err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackStartOrderRefusesCycles(t *testing.T) {
+	dir := t.TempDir()
+	manifest := writeStackFile(t, dir, "eos.stack.yaml", `services:
+  - config: {name: a, command: x, depends_on: [b]}
+  - config: {name: b, command: x, depends_on: [a]}
+`)
+	entries, err := LoadStackManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadStackManifest: %v", err)
+	}
+	if _, err := StackStartOrder(entries); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("StackStartOrder err = %v, want a cycle reported", err)
+	}
+}
+
+// TestPlanStack plans a manifest against a catalog holding a service it
+// lists unchanged, one whose inline config changed and one it doesn't list.
+func TestPlanStack(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	t.Setenv("EOS_BASE_DIR", tempDir)
+	m := NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	dir := t.TempDir()
+	writeStackFile(t, dir, "kept/service.yaml", "name: kept\ncommand: sleep 300\n")
+	writeStackFile(t, dir, "gone/service.yaml", "name: gone\ncommand: sleep 300\n")
+	writeStackFile(t, dir, "web/.eos.web.yaml", "name: web\ncommand: sleep 300\nport: 8080\n")
+	for _, name := range []string{"kept", "gone"} {
+		entry, _ := NewServiceCatalogEntry(name, filepath.Join(dir, name), "service.yaml")
+		if err := m.AddServiceCatalogEntry(t.Context(), entry); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+	web, _ := NewServiceCatalogEntry("web", filepath.Join(dir, "web"), ".eos.web.yaml")
+	if err := m.AddServiceCatalogEntry(t.Context(), web); err != nil {
+		t.Fatalf("add web: %v", err)
+	}
+
+	manifest := writeStackFile(t, dir, "eos.stack.yaml", `services:
+  - path: kept
+  - directory: web
+    config:
+      name: web
+      command: sleep 300
+      port: 9090
+  - config:
+      name: fresh
+      command: sleep 300
+`)
+	entries, err := LoadStackManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadStackManifest: %v", err)
+	}
+	plan, err := PlanStack(t.Context(), m, entries, true)
+	if err != nil {
+		t.Fatalf("PlanStack: %v", err)
+	}
+
+	want := []struct {
+		name   string
+		action StackAction
+	}{{"gone", StackActionPrune}, {"kept", StackActionStart}, {"web", StackActionUpdate}, {"fresh", StackActionAdd}}
+	if len(plan.Steps) != len(want) {
+		t.Fatalf("plan = %+v, want %d steps", plan.Steps, len(want))
+	}
+	for i, w := range want {
+		if plan.Steps[i].Name != w.name || plan.Steps[i].Action != w.action {
+			t.Errorf("step %d = %s %s, want %s %s", i, plan.Steps[i].Action, plan.Steps[i].Name, w.action, w.name)
+		}
+	}
+	if changes := plan.Steps[2].Changes; len(changes) != 1 || changes[0].Field != "port" || changes[0].New != "9090" {
+		t.Errorf("web changes = %+v, want its port", changes)
+	}
+	if !plan.HasChanges() {
+		t.Error("HasChanges = false, want true")
+	}
+
+	if err := WriteStackConfig(plan.Steps[2].Entry); err != nil {
+		t.Fatalf("WriteStackConfig: %v", err)
+	}
+	if replanned, _ := PlanStack(t.Context(), m, entries, false); replanned.Steps[1].Action != StackActionStart {
+		t.Errorf("web after writing its config = %s, want it only needing a start", replanned.Steps[1].Action)
+	}
+}
@@ -0,0 +1,214 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// KillMode is service.yaml's kill_mode: which of a launch's processes a stop
+// signals first.
+type KillMode string
+
+const (
+	// KillModeGroup signals every process of the launch: its whole process
+	// group, or its cgroup leaf when cgroup-tracked. The default.
+	KillModeGroup KillMode = "group"
+	// KillModeLeader signals only the process eos launched, leaving it to
+	// shut its own children down. Whatever is still alive when the stop
+	// times out is SIGKILLed as a whole.
+	KillModeLeader KillMode = "leader"
+	// KillModeMixed signals the leader, then SIGKILLs whatever of the launch
+	// is left as soon as the leader exits.
+	KillModeMixed KillMode = "mixed"
+)
+
+// stopSignals are the signals stop_signal accepts, by name without the SIG
+// prefix.
+var stopSignals = map[string]syscall.Signal{
+	"HUP":   syscall.SIGHUP,
+	"INT":   syscall.SIGINT,
+	"QUIT":  syscall.SIGQUIT,
+	"ABRT":  syscall.SIGABRT,
+	"KILL":  syscall.SIGKILL,
+	"USR1":  syscall.SIGUSR1,
+	"USR2":  syscall.SIGUSR2,
+	"TERM":  syscall.SIGTERM,
+	"WINCH": syscall.SIGWINCH,
+}
+
+// StopPolicy is how one service is stopped: the signal it gets first, which
+// of its processes get it, and how long it has to exit before SIGKILL. The
+// zero value's KillMode behaves as KillModeGroup.
+type StopPolicy struct {
+	KillMode KillMode
+	Timeout  time.Duration
+	Signal   syscall.Signal
+}
+
+// forceKillPolicy is ForceStopService's: SIGKILL to everything, no wait.
+var forceKillPolicy = StopPolicy{KillMode: KillModeGroup, Signal: syscall.SIGKILL}
+
+// ParseStopSignal resolves a stop_signal name, with or without its SIG
+// prefix and in any case, falling back to SIGTERM when empty.
+func ParseStopSignal(name string) (syscall.Signal, error) {
+	if strings.TrimSpace(name) == "" {
+		return syscall.SIGTERM, nil
+	}
+	sig, ok := stopSignals[strings.TrimPrefix(strings.ToUpper(name), "SIG")]
+	if !ok {
+		return 0, fmt.Errorf("unsupported signal %q", name)
+	}
+	return sig, nil
+}
+
+// ResolveStopPolicy reads config's stop_signal, stop_timeout and kill_mode,
+// using defaultTimeout (the caller's grace period) when stop_timeout is
+// unset.
+func ResolveStopPolicy(config *types.ServiceConfig, defaultTimeout time.Duration) (StopPolicy, error) {
+	policy := StopPolicy{KillMode: KillModeGroup, Signal: syscall.SIGTERM, Timeout: defaultTimeout}
+	var errs []error
+	sig, err := ParseStopSignal(config.StopSignal)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("stop_signal: %w", err))
+	}
+	policy.Signal = sig
+	if config.StopTimeout != "" {
+		timeout, parseErr := time.ParseDuration(config.StopTimeout)
+		switch {
+		case parseErr != nil:
+			errs = append(errs, fmt.Errorf("stop_timeout: invalid duration %q: %w", config.StopTimeout, parseErr))
+		case timeout <= 0:
+			errs = append(errs, fmt.Errorf("stop_timeout: invalid duration %q: must be positive", config.StopTimeout))
+		default:
+			policy.Timeout = timeout
+		}
+	}
+	if config.KillMode != "" {
+		mode := KillMode(config.KillMode)
+		if !slices.Contains([]KillMode{KillModeGroup, KillModeLeader, KillModeMixed}, mode) {
+			errs = append(errs, fmt.Errorf("kill_mode: %q is not one of group, leader or mixed", config.KillMode))
+		} else {
+			policy.KillMode = mode
+		}
+	}
+	return policy, errors.Join(errs...)
+}
+
+// stopValidate reports each invalid stop_signal, stop_timeout or kill_mode.
+func stopValidate(config *types.ServiceConfig) []error {
+	_, err := ResolveStopPolicy(config, 0)
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// stopPolicyFor resolves name's StopPolicy from config, or falls back to
+// SIGTERM to the whole group over defaultTimeout when config is nil or
+// invalid (logged): a stop must go ahead regardless of a broken
+// service.yaml.
+func (m *LocalManager) stopPolicyFor(name string, config *types.ServiceConfig, defaultTimeout time.Duration) StopPolicy {
+	fallback := StopPolicy{KillMode: KillModeGroup, Signal: syscall.SIGTERM, Timeout: defaultTimeout}
+	if config == nil {
+		return fallback
+	}
+	policy, err := ResolveStopPolicy(config, defaultTimeout)
+	if err != nil {
+		m.logger.Warn("invalid stop settings, stopping with SIGTERM to the whole group", "service", name, "error", err)
+		return fallback
+	}
+	return policy
+}
+
+// loadServiceForStop loads name's catalog entry and service.yaml for a stop's
+// policy and hooks. Unlike loadServiceForLaunch it doesn't resolve log sinks,
+// and a failure is logged rather than returned (ok=false): a stop must go
+// ahead without them.
+func (m *LocalManager) loadServiceForStop(name string) (service types.ServiceCatalogEntry, config *types.ServiceConfig, ok bool) {
+	service, err := m.GetServiceCatalogEntry(m.ctx, name)
+	if err != nil {
+		m.logger.Warn("loading service for stop, using default stop settings", "service", name, "error", err)
+		return types.ServiceCatalogEntry{}, nil, false
+	}
+	config, err = LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err != nil {
+		m.logger.Warn("loading service config for stop, using default stop settings", "service", name, "error", err)
+		return types.ServiceCatalogEntry{}, nil, false
+	}
+	return service, config, true
+}
+
+// stop delivers policy's signal to the launch led by pgid: to all of it for
+// KillModeGroup, to its main process alone otherwise — main, a type: forking
+// launch's main process or the MAINPID a type: notify launch named (see
+// mainPID), or the leader when main is 0. A main process
+// already gone has nobody left to pass the signal on, so the launch's
+// remaining processes get it directly instead (SIGKILL under KillModeMixed).
+// Like signal, a launch with nothing left to signal is syscall.ESRCH.
+func (t groupTracker) stop(pgid, main int, policy StopPolicy) error {
+	if policy.KillMode != KillModeLeader && policy.KillMode != KillModeMixed {
+		return t.signal(pgid, policy.Signal)
+	}
+	err := syscall.Kill(stopMainPID(pgid, main), policy.Signal)
+	if !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	if policy.KillMode == KillModeMixed {
+		return t.signal(pgid, syscall.SIGKILL)
+	}
+	return t.signal(pgid, policy.Signal)
+}
+
+// stopMainPID is the process kill_mode leader and mixed single out: main
+// when the launch named one, its leader pgid otherwise.
+func stopMainPID(pgid, main int) int {
+	if main > 1 {
+		return main
+	}
+	return pgid
+}
+
+// stopKillMixedRemainder is KillModeMixed's second step: once pgid's main
+// process (see stop) has exited, SIGKILL whatever of its launch is still
+// running. It reports whether it sent the SIGKILL.
+func stopKillMixedRemainder(pgid, main int, tracker groupTracker) bool {
+	if procutil.LeaderAlive(stopMainPID(pgid, main)) || !tracker.alive(pgid) {
+		return false
+	}
+	return tracker.signal(pgid, syscall.SIGKILL) == nil
+}
+
+// shutdownCancel is the cmd.Cancel for a launch under a daemon with a
+// shutdown grace period: policy's signal when m.ctx is canceled, and for
+// KillModeMixed a watcher, tracked by serviceWg so WaitServices covers it,
+// that SIGKILLs the rest of the launch once the leader exits, or all of it
+// once policy.Timeout is up.
+func (m *LocalManager) shutdownCancel(pgid int, policy StopPolicy) error {
+	if policy.KillMode == KillModeMixed {
+		m.serviceWg.Go(func() {
+			deadline := time.Now().Add(policy.Timeout)
+			for time.Now().Before(deadline) {
+				if stopKillMixedRemainder(pgid, m.mainPID(pgid), m.tracker) || !m.tracker.alive(pgid) {
+					return
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+			if m.tracker.alive(pgid) {
+				_ = m.tracker.signal(pgid, syscall.SIGKILL)
+			}
+		})
+	}
+	return m.tracker.stop(pgid, m.mainPID(pgid), policy)
+}
@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestResolveStopPolicy(t *testing.T) {
+	policy, err := ResolveStopPolicy(&types.ServiceConfig{}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ResolveStopPolicy(defaults): %v", err)
+	}
+	if policy != (StopPolicy{KillMode: KillModeGroup, Signal: syscall.SIGTERM, Timeout: 5 * time.Second}) {
+		t.Errorf("defaults = %+v, want SIGTERM to the group over the caller's grace period", policy)
+	}
+
+	policy, err = ResolveStopPolicy(&types.ServiceConfig{StopSignal: "int", StopTimeout: "60s", KillMode: "mixed"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ResolveStopPolicy: %v", err)
+	}
+	if policy != (StopPolicy{KillMode: KillModeMixed, Signal: syscall.SIGINT, Timeout: time.Minute}) {
+		t.Errorf("policy = %+v, want SIGINT, mixed, 60s", policy)
+	}
+
+	errs := stopValidate(&types.ServiceConfig{StopSignal: "SIGSTOP", StopTimeout: "0s", KillMode: "process"})
+	if len(errs) != 3 {
+		t.Fatalf("stopValidate returned %d errors, want 3: %v", len(errs), errs)
+	}
+	for i, field := range []string{"stop_signal", "stop_timeout", "kill_mode"} {
+		if !strings.HasPrefix(errs[i].Error(), field) {
+			t.Errorf("error %d = %q, want it to name %s", i, errs[i], field)
+		}
+	}
+}
+
+// launchWithBackgroundChild starts a group whose leader (sleep 31) has a
+// backgrounded sibling (sleep 30) in the same process group. The sibling,
+// like any background job of a non-interactive shell, ignores SIGINT.
+func launchWithBackgroundChild(t *testing.T) (*exec.Cmd, int) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", "sleep 30 & exec sleep 31")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	pgid := cmd.Process.Pid
+	t.Cleanup(func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+	// Let the shell fork the background sleep before anything is signaled.
+	time.Sleep(200 * time.Millisecond)
+	return cmd, pgid
+}
+
+func TestGroupTrackerStop_leaderAndMixed(t *testing.T) {
+	var tracker groupTracker
+
+	cmd, pgid := launchWithBackgroundChild(t)
+	if err := tracker.stop(pgid, 0, StopPolicy{KillMode: KillModeLeader, Signal: syscall.SIGINT}); err != nil {
+		t.Fatalf("stop(leader): %v", err)
+	}
+	_ = cmd.Wait()
+	if !tracker.alive(pgid) {
+		t.Fatal("kill_mode leader: the background child should have been left running")
+	}
+	if err := tracker.signal(pgid, syscall.SIGKILL); err != nil {
+		t.Fatalf("cleanup kill: %v", err)
+	}
+
+	cmd, pgid = launchWithBackgroundChild(t)
+	if err := tracker.stop(pgid, 0, StopPolicy{KillMode: KillModeMixed, Signal: syscall.SIGINT}); err != nil {
+		t.Fatalf("stop(mixed): %v", err)
+	}
+	_ = cmd.Wait()
+	if procutil.LeaderAlive(pgid) {
+		t.Fatal("the leader should have exited on SIGINT")
+	}
+	if !stopKillMixedRemainder(pgid, 0, tracker) {
+		t.Fatal("kill_mode mixed: expected the remainder to be SIGKILLed once the leader exited")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for tracker.alive(pgid) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if tracker.alive(pgid) {
+		t.Error("kill_mode mixed: the background child survived")
+	}
+}
@@ -35,7 +35,7 @@ type ServiceManager interface {
 	// the OS process table. status/info read paths have historically only
 	// ever consulted the most recent row, which hides a still-running
 	// process pinned to an older row entirely — even though the stop path
-	// already walks the whole history (see LocalManager.stopServiceWithSignal).
+	// already walks the whole history (see LocalManager.sendStopSignal).
 	GetLiveOrphanProcessGroups(ctx context.Context, name string) ([]types.ProcessHistory, error)
 
 	NewServiceLogFiles(ctx context.Context, serviceName string) (logPath string, errorLogPath string, err error)
@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// WatchDefaultDebounce is how long changes must settle for before a watched
+// service is restarted, when its watch: block doesn't say.
+const WatchDefaultDebounce = time.Second
+
+// watchAlwaysExcluded are directory names never watched, whatever a watch:
+// block's globs say: version control metadata and dependency trees change
+// far more often than anything a restart would pick up.
+var watchAlwaysExcluded = []string{".git", ".hg", ".svn", "node_modules"}
+
+// WatchSpec is a parsed watch: block.
+type WatchSpec struct {
+	include  [][]string
+	exclude  [][]string
+	Debounce time.Duration
+	Reload   bool
+}
+
+// ParseWatch parses watch, which must not be nil.
+func ParseWatch(watch *types.ServiceWatch) (WatchSpec, error) {
+	spec := WatchSpec{Debounce: WatchDefaultDebounce, Reload: watch.ReloadOnChange}
+	if strings.TrimSpace(watch.Debounce) != "" {
+		d, err := time.ParseDuration(watch.Debounce)
+		if err != nil {
+			return WatchSpec{}, fmt.Errorf("watch: invalid debounce %q: %w", watch.Debounce, err)
+		}
+		if d <= 0 {
+			return WatchSpec{}, fmt.Errorf("watch: invalid debounce %q: must be positive", watch.Debounce)
+		}
+		spec.Debounce = d
+	}
+	var err error
+	if spec.include, err = parseWatchGlobs("include", watch.Include); err != nil {
+		return WatchSpec{}, err
+	}
+	if spec.exclude, err = parseWatchGlobs("exclude", watch.Exclude); err != nil {
+		return WatchSpec{}, err
+	}
+	return spec, nil
+}
+
+// parseWatchGlobs splits each pattern into its slash-separated segments,
+// rejecting one that is absolute, climbs out with .., or that path.Match
+// can't parse.
+func parseWatchGlobs(field string, patterns []string) ([][]string, error) {
+	globs := make([][]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+		if trimmed == "" || strings.HasPrefix(trimmed, "/") {
+			return nil, fmt.Errorf("watch: %s: %q must be a relative path glob", field, pattern)
+		}
+		segments := strings.Split(strings.TrimSuffix(trimmed, "/"), "/")
+		for _, segment := range segments {
+			if segment == ".." {
+				return nil, fmt.Errorf("watch: %s: %q must stay inside the service directory", field, pattern)
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("watch: %s: %q: %w", field, pattern, err)
+			}
+		}
+		globs = append(globs, segments)
+	}
+	return globs, nil
+}
+
+// Matches reports whether a change to the file at rel, slash-separated and
+// relative to the service's directory, should restart the service.
+func (s WatchSpec) Matches(rel string) bool {
+	segments := strings.Split(rel, "/")
+	if s.excluded(segments) {
+		return false
+	}
+	return len(s.include) == 0 || watchGlobsMatch(s.include, segments)
+}
+
+// SkipDir reports whether the directory at rel is excluded, and so need not
+// be watched at all.
+func (s WatchSpec) SkipDir(rel string) bool {
+	return s.excluded(strings.Split(rel, "/"))
+}
+
+func (s WatchSpec) excluded(segments []string) bool {
+	for _, segment := range segments {
+		if slices.Contains(watchAlwaysExcluded, segment) {
+			return true
+		}
+	}
+	return watchGlobsMatch(s.exclude, segments)
+}
+
+// watchGlobsMatch reports whether any glob matches the path or one of the
+// directories above it. A glob of one segment matches any single segment,
+// a name at any depth.
+func watchGlobsMatch(globs [][]string, segments []string) bool {
+	for _, glob := range globs {
+		if len(glob) == 1 && glob[0] != "**" {
+			for _, segment := range segments {
+				if ok, _ := path.Match(glob[0], segment); ok {
+					return true
+				}
+			}
+			continue
+		}
+		for end := 1; end <= len(segments); end++ {
+			if watchGlobMatch(glob, segments[:end]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// watchGlobMatch matches glob against segments in full, ** standing for any
+// number of segments.
+func watchGlobMatch(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+	if glob[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if watchGlobMatch(glob[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(glob[0], segments[0]); !ok {
+		return false
+	}
+	return watchGlobMatch(glob[1:], segments[1:])
+}
+
+// watchValidate checks a watch: block parses.
+func watchValidate(config *types.ServiceConfig) []error {
+	if config.Watch == nil {
+		return nil
+	}
+	if _, err := ParseWatch(config.Watch); err != nil {
+		return []error{err}
+	}
+	return nil
+}
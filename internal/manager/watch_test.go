@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func TestParseWatch(t *testing.T) {
+	spec, err := ParseWatch(&types.ServiceWatch{
+		Include: []string{"src/**/*.go", "*.yaml"},
+		Exclude: []string{"*_test.go", "tmp"},
+	})
+	if err != nil {
+		t.Fatalf("ParseWatch: %v", err)
+	}
+	if spec.Debounce != WatchDefaultDebounce {
+		t.Errorf("Debounce = %v, want the default %v", spec.Debounce, WatchDefaultDebounce)
+	}
+
+	for rel, want := range map[string]bool{
+		"src/main.go":             true,
+		"src/api/handler.go":      true,
+		"config.yaml":             true,
+		"deploy/prod.yaml":        true,
+		"src/api/handler_test.go": false,
+		"tmp/cache.yaml":          false,
+		"README.md":               false,
+		"node_modules/x/x.yaml":   false,
+		".git/config.yaml":        false,
+	} {
+		if got := spec.Matches(rel); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", rel, got, want)
+		}
+	}
+	for rel, want := range map[string]bool{"src": false, "tmp": true, "src/node_modules": true} {
+		if got := spec.SkipDir(rel); got != want {
+			t.Errorf("SkipDir(%q) = %v, want %v", rel, got, want)
+		}
+	}
+
+	spec, err = ParseWatch(&types.ServiceWatch{Debounce: "250ms", ReloadOnChange: true})
+	if err != nil {
+		t.Fatalf("ParseWatch: %v", err)
+	}
+	if spec.Debounce != 250*time.Millisecond || !spec.Reload {
+		t.Errorf("spec = %+v, want a 250ms debounce and reload", spec)
+	}
+	if !spec.Matches("anything/at/all.txt") {
+		t.Error("a watch without include globs should match every file")
+	}
+
+	for _, watch := range []types.ServiceWatch{
+		{Debounce: "soon"},
+		{Debounce: "0s"},
+		{Include: []string{"/etc/app.conf"}},
+		{Exclude: []string{"../shared"}},
+		{Include: []string{"src/[.go"}},
+	} {
+		if _, err := ParseWatch(&watch); err == nil {
+			t.Errorf("ParseWatch(%+v) should have failed", watch)
+		}
+	}
+}
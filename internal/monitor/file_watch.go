@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/fswatch"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// Timing of the reload a watch: block with reload_on_change runs, matching
+// eos reload's defaults.
+const (
+	watchReloadTickerPeriod     = 200 * time.Millisecond
+	watchReloadReadinessTimeout = 30 * time.Second
+	watchReloadProbeInterval    = 500 * time.Millisecond
+)
+
+// fileWatch is the running watch of one service's directory.
+type fileWatch struct {
+	cancel context.CancelFunc
+	// key is the resolved directory and watch: block the watch was started
+	// for; a change to either replaces it.
+	key string
+}
+
+// syncFileWatch starts, replaces or stops service's file watch to match its
+// watch: block. The directory is resolved through symlinks, so a deploy
+// switching current moves the watch to the new release.
+func (hm *HealthMonitor) syncFileWatch(ctx context.Context, service *types.ServiceCatalogEntry) {
+	config, err := manager.LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err != nil {
+		return
+	}
+	if config.Watch == nil {
+		hm.stopFileWatch(service.Name)
+		return
+	}
+	dir, err := filepath.EvalSymlinks(service.DirectoryPath)
+	if err != nil {
+		hm.logger.Error("resolving service directory to watch", "service", service.Name, "error", err)
+		return
+	}
+	key := fmt.Sprintf("%s|%+v", dir, *config.Watch)
+	if current, ok := hm.fileWatches[service.Name]; ok && current.key == key {
+		return
+	}
+	spec, err := manager.ParseWatch(config.Watch)
+	if err != nil {
+		hm.logger.Error("parsing watch", "service", service.Name, "error", err)
+		return
+	}
+
+	hm.stopFileWatch(service.Name)
+	if hm.fileWatches == nil {
+		hm.fileWatches = make(map[string]*fileWatch)
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	hm.fileWatches[service.Name] = &fileWatch{cancel: cancel, key: key}
+	go hm.runFileWatch(watchCtx, service.Name, dir, spec)
+}
+
+func (hm *HealthMonitor) stopFileWatch(serviceName string) {
+	if current, ok := hm.fileWatches[serviceName]; ok {
+		current.cancel()
+		delete(hm.fileWatches, serviceName)
+	}
+}
+
+// pruneFileWatches stops the watches of services no longer registered.
+func (hm *HealthMonitor) pruneFileWatches(services []types.ServiceCatalogEntry) {
+	registered := make(map[string]bool, len(services))
+	for i := range services {
+		registered[services[i].Name] = true
+	}
+	for name := range hm.fileWatches {
+		if !registered[name] {
+			hm.stopFileWatch(name)
+		}
+	}
+}
+
+// runFileWatch restarts serviceName once changes to files spec matches
+// under dir have settled for spec.Debounce, until ctx is done.
+func (hm *HealthMonitor) runFileWatch(ctx context.Context, serviceName, dir string, spec manager.WatchSpec) {
+	changes, err := fswatch.Watch(ctx, dir, fswatch.Options{Recursive: true, SkipDir: spec.SkipDir})
+	if err != nil {
+		hm.logger.Error("watching service directory", "service", serviceName, "dir", dir, "error", err)
+		return
+	}
+	hm.logger.Debug("watching service directory", "service", serviceName, "dir", dir)
+
+	settle := time.NewTimer(spec.Debounce)
+	settle.Stop()
+	defer settle.Stop()
+	changed := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case rel, ok := <-changes:
+			if !ok {
+				if ctx.Err() == nil {
+					hm.logger.Error("service directory watch ended", "service", serviceName, "dir", dir)
+				}
+				return
+			}
+			if rel != fswatch.Overflow && !spec.Matches(rel) {
+				continue
+			}
+			if changed == "" {
+				changed = rel
+			}
+			settle.Reset(spec.Debounce)
+		case <-settle.C:
+			hm.watchRestart(ctx, serviceName, spec, changed)
+			changed = ""
+		}
+	}
+}
+
+// watchRestart restarts serviceName, or reloads it with reload_on_change,
+// after a change to the file changed. A service stopped by hand stays
+// stopped. The restart doesn't count toward crash backoff: the one count
+// RestartService adds is taken back off in SQL, keeping any crash restart the
+// health monitor counted meanwhile.
+func (hm *HealthMonitor) watchRestart(ctx context.Context, serviceName string, spec manager.WatchSpec, changed string) {
+	latest, err := hm.mgr.GetMostRecentProcessHistoryEntry(ctx, serviceName)
+	if err != nil || latest == nil || latest.State == types.ProcessStateStopped {
+		return
+	}
+	verb := "restarting"
+	if spec.Reload {
+		verb = "reloading"
+	}
+	if changed == fswatch.Overflow {
+		changed = "several files"
+	}
+	restartMsg := fmt.Sprintf("[%s] file change detected (%s), %s", serviceName, changed, verb)
+	hm.logger.Info(restartMsg)
+	if logErr := hm.mgr.LogToServiceStdout(serviceName, restartMsg); logErr != nil {
+		hm.logger.Error(logFailedLogServiceOutput, "service", serviceName, "error", logErr)
+	}
+
+	err = manager.ErrServiceNotRunning
+	if spec.Reload {
+		_, err = hm.mgr.ReloadService(serviceName, ProbeReady, manager.ReloadConfig{
+			GracePeriod:      hm.shutdownGracePeriod,
+			TickerPeriod:     watchReloadTickerPeriod,
+			ReadinessTimeout: watchReloadReadinessTimeout,
+			ProbeInterval:    watchReloadProbeInterval,
+		})
+	}
+	// A service that isn't up to reload, a failed one say, is restarted.
+	if errors.Is(err, manager.ErrServiceNotRunning) {
+		_, err = hm.mgr.RestartService(ctx, serviceName, hm.shutdownGracePeriod, 200*time.Millisecond)
+	}
+	if err != nil {
+		hm.logger.Error("watch restart failed", "service", serviceName, "error", err)
+		return
+	}
+	if err := hm.db.UpdateServiceInstance(ctx, serviceName, database.ServiceInstanceUpdate{RestartCountDelta: new(-1)}); err != nil {
+		hm.logger.Error("restoring restart count after watch restart", "service", serviceName, "error", err)
+	}
+}
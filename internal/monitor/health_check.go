@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// livenessState is one service's run of health_check liveness results. It
+// belongs to a single launch: a new PGID starts it over.
+type livenessState struct {
+	lastRun  time.Time
+	pgid     int
+	failures int
+}
+
+// hmStartupReady reports whether a Starting service may be promoted to
+// Running: a type: notify launch must first have sent READY=1 (see
+// hmNotifyReady), then its health_check's readiness check passes or, without
+// a health_check, its port (if any) accepts a connection — a port READY=1
+// already vouches for.
+func (hm *HealthMonitor) hmStartupReady(ctx context.Context, service *types.ServiceCatalogEntry, pgid int, config *types.ServiceConfig) bool {
+	if !hm.hmNotifyReady(service.Name, pgid, config) {
+		return false
+	}
+	if config.HealthCheck == nil {
+		if config.Port != 0 && !hm.hmNotifyManaged(pgid, config) && !hm.isPortReachable(ctx, config.Port) {
+			hm.logger.Debug("startup check: port not reachable yet", "service", service.Name, "port", config.Port)
+			return false
+		}
+		return true
+	}
+	check, err := hm.mgr.HealthCheckFor(service, config)
+	if err != nil {
+		hm.logger.Error("resolving health_check", "service", service.Name, "error", err)
+		return false
+	}
+	if err := check.Readiness.Check(ctx); err != nil {
+		hm.logger.Debug("startup check: not ready yet", "service", service.Name, "error", err)
+		return false
+	}
+	return true
+}
+
+// checkLiveness runs a Running service's health_check liveness check, at most
+// once per interval and not before start_period has passed since launch, and
+// restarts the service once failure_threshold checks in a row have failed. A
+// liveness failure restarts rather than marking the launch Failed: the
+// process is still alive, so checkFailedProcess would only flip it back to
+// Running. It reports whether it restarted the service, which ends the tick.
+func (hm *HealthMonitor) checkLiveness(ctx context.Context, service *types.ServiceCatalogEntry, process *types.ProcessHistory, instance *types.ServiceInstance, config *types.ServiceConfig) bool {
+	serviceName := service.Name
+	check, err := hm.mgr.HealthCheckFor(service, config)
+	if err != nil {
+		hm.logger.Error("resolving health_check", "service", serviceName, "error", err)
+		return false
+	}
+	if check == nil {
+		return false
+	}
+
+	launchKey := hmLaunchKey(serviceName, process.Instance)
+	state := hm.liveness[launchKey]
+	if state == nil || state.pgid != process.PGID {
+		state = &livenessState{pgid: process.PGID}
+		hm.liveness[launchKey] = state
+	}
+	now := time.Now()
+	if process.StartedAt != nil && now.Sub(*process.StartedAt) < check.StartPeriod {
+		return false
+	}
+	if !state.lastRun.IsZero() && now.Sub(state.lastRun) < check.Interval {
+		return false
+	}
+	state.lastRun = now
+
+	checkErr := check.Liveness.Check(ctx)
+	if checkErr == nil {
+		state.failures = 0
+		return false
+	}
+	state.failures++
+	hm.logger.Debug("liveness check failed", "service", serviceName, "failures", state.failures, "error", checkErr)
+	if state.failures < check.FailureThreshold {
+		return false
+	}
+	if !canRestart(instance.RestartCount, process.StartedAt, hm.backoff) {
+		return false
+	}
+
+	if _, err := hm.hmRestart(ctx, service, process, hm.shutdownGracePeriod, 200*time.Millisecond); err != nil {
+		hm.logger.Error("restarting on failed liveness check", "service", serviceName, "error", err)
+		return false
+	}
+	msg := hmLivenessRestartMessage(serviceName, state.failures, checkErr)
+	hm.logger.Warn(msg)
+	hm.writeServiceStderr(serviceName, msg)
+	delete(hm.liveness, launchKey)
+	delete(hm.lastMemSample, launchKey)
+	delete(hm.lastCPUSample, launchKey)
+	return true
+}
+
+func hmLivenessRestartMessage(serviceName string, failures int, err error) string {
+	return fmt.Sprintf("[%s] restarted after failing its liveness check %d times in a row: %v", serviceName, failures, err)
+}
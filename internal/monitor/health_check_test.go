@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/otelx"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// TestCheckLiveness_RestartsAtFailureThreshold covers the liveness half of a
+// health_check: nothing runs during start_period, a single failure is
+// tolerated, and failure_threshold failures in a row restart the service.
+func TestCheckLiveness_RestartsAtFailureThreshold(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	counter := &restartCallCountManager{monitorManager: realMgr}
+	hm := NewHealthMonitor(counter, db, testutil.NewTestLogger(t), newTestHealthConfig(t), *newTestShutdownConfig(t), otelx.NoopHandles())
+
+	const serviceName = "unhealthy-svc"
+	service := &types.ServiceCatalogEntry{Name: serviceName, DirectoryPath: t.TempDir()}
+	config := &types.ServiceConfig{
+		Name: serviceName,
+		HealthCheck: &types.ServiceHealthCheck{
+			Exec:             &types.ServiceCommand{Shell: "exit 1"},
+			Interval:         "1ms",
+			StartPeriod:      "1h",
+			FailureThreshold: 2,
+		},
+	}
+	justStarted := time.Now()
+	process := &types.ProcessHistory{PGID: 888896, StartedAt: &justStarted}
+	instance := &types.ServiceInstance{Name: serviceName}
+
+	if hm.checkLiveness(t.Context(), service, process, instance, config) || hm.liveness[serviceName].failures != 0 {
+		t.Fatalf("checked liveness during start_period (state %+v)", hm.liveness[serviceName])
+	}
+
+	config.HealthCheck.StartPeriod = ""
+	longAgo := time.Now().Add(-time.Hour)
+	process.StartedAt = &longAgo
+	if hm.checkLiveness(t.Context(), service, process, instance, config) {
+		t.Fatal("restarted after one failure, want failure_threshold 2")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if !hm.checkLiveness(t.Context(), service, process, instance, config) {
+		t.Fatal("did not restart after failure_threshold failures in a row")
+	}
+	if counter.calls != 1 {
+		t.Errorf("RestartService calls = %d, want 1", counter.calls)
+	}
+	if _, ok := hm.liveness[serviceName]; ok {
+		t.Error("liveness state survived the restart, want the new launch to start over")
+	}
+}
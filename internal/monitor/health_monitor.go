@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"syscall"
 	"time"
@@ -89,6 +90,37 @@ type monitorManager interface {
 	// service while this is true, so a reload's crash-on-start incoming instance
 	// can't be marked Failed and restarted out from under the cutover.
 	IsReloadInProgress(name string) bool
+	// IsProcessGroupAlive reports whether the instance launched as pgid still
+	// has a live process — through its cgroup when the daemon tracks one, its
+	// process group otherwise.
+	IsProcessGroupAlive(pgid int) bool
+	// GetServiceOOMKills returns how many of pgid's processes the kernel OOM
+	// killer has killed for breaching the launch's limits.memory_max, or
+	// ok=false when that can't be known (no cgroup memory accounting).
+	GetServiceOOMKills(pgid int) (kills int, ok bool)
+	// HealthCheckFor resolves a service's health_check, or returns nil when
+	// it has none.
+	HealthCheckFor(service *types.ServiceCatalogEntry, config *types.ServiceConfig) (*manager.HealthCheck, error)
+	// GetServiceNotifyStatus returns what the type: notify launch pgid has
+	// reported over its notify socket, or ok=false when it has no socket (not
+	// a notify launch, or one a previous daemon started).
+	GetServiceNotifyStatus(pgid int) (status types.NotifyStatus, ok bool)
+	// ServiceConfigForLaunch returns config with the port name's launch pgid
+	// actually listens on: the internal one behind a proxy, config's own
+	// otherwise.
+	ServiceConfigForLaunch(name string, pgid int, config *types.ServiceConfig) *types.ServiceConfig
+	// GetInstanceProcessHistoryEntries returns the most recent process-history
+	// entry of each of a service's instances, ordered by instance index.
+	GetInstanceProcessHistoryEntries(ctx context.Context, name string) ([]types.ProcessHistory, error)
+	// RestartServiceInstance replaces one instance of a service running
+	// several, leaving the others running.
+	RestartServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error)
+	// ConfigDriftForLaunch compares the config pgid was launched with
+	// against name's config on disk, or returns nil when none was recorded.
+	ConfigDriftForLaunch(ctx context.Context, name string, pgid int) (*types.ConfigDrift, error)
+	// ReloadService swaps a running service for a fresh launch without
+	// downtime, for a watch: block with reload_on_change.
+	ReloadService(name string, probe manager.ReadinessProbe, cfg manager.ReloadConfig) (manager.ReloadResult, error)
 }
 
 var _ monitorManager = (*manager.LocalManager)(nil)
@@ -98,13 +130,23 @@ type HealthMonitor struct {
 	telemetry     *otelx.Handles
 	lastMemSample map[string]time.Time
 	lastCPUSample map[string]cpuSample
+	// scope, when set (see Scope), is the only services the monitor looks
+	// after; nil means every registered service.
+	scope map[string]bool
+	// fileWatches holds, per service with a watch: block, its running
+	// directory watch (see syncFileWatch). Only the monitor's own loop
+	// touches it.
+	fileWatches map[string]*fileWatch
 	// crashLoopLog tracks, per service, the collapsed-repeat log summary
 	// state used once a service has crossed the sustained-failure-loop
 	// threshold. Absent from the map means "not currently collapsing" —
 	// deleted whenever a service leaves the loop (a differing signature, or
 	// resetRestartCounterIfStable), so the next entry into a loop always
 	// starts by logging its first occurrence in full again.
-	crashLoopLog              map[string]*crashLoopLogState
+	crashLoopLog map[string]*crashLoopLogState
+	// liveness tracks, per service, when its health_check last ran and how
+	// many times in a row it has failed (see checkLiveness).
+	liveness                  map[string]*livenessState
 	db                        *database.DB
 	logger                    *slog.Logger
 	memory                    config.MemoryThresholdConfig
@@ -135,6 +177,7 @@ func NewHealthMonitor(
 		lastMemSample:             make(map[string]time.Time),
 		lastCPUSample:             make(map[string]cpuSample),
 		crashLoopLog:              make(map[string]*crashLoopLogState),
+		liveness:                  make(map[string]*livenessState),
 		timeoutEnable:             healthConfig.Timeout.Enable,
 		timeoutLimit:              healthConfig.Timeout.Limit,
 		restartCounterResetWindow: healthConfig.RestartCounterResetWindow,
@@ -145,6 +188,16 @@ func NewHealthMonitor(
 	}
 }
 
+// Scope limits the monitor to names, for eos up, which supervises only the
+// services it started and leaves the rest of the catalog alone. It must be
+// called before Start.
+func (hm *HealthMonitor) Scope(names []string) {
+	hm.scope = make(map[string]bool, len(names))
+	for _, name := range names {
+		hm.scope[name] = true
+	}
+}
+
 func hmResolvedCheckInterval(configured time.Duration) time.Duration {
 	if configured <= 0 {
 		return 2 * time.Second
@@ -198,6 +251,11 @@ func (hm *HealthMonitor) Start(ctx context.Context) {
 				continue
 			}
 
+			if hm.scope != nil {
+				services = slices.DeleteFunc(services, func(service types.ServiceCatalogEntry) bool {
+					return !hm.scope[service.Name]
+				})
+			}
 			hm.checkAllServices(ctx, services)
 		case <-ctx.Done():
 			return
@@ -213,6 +271,7 @@ func (hm *HealthMonitor) checkAllServices(ctx context.Context, services []types.
 	for i := range services {
 		hm.checkService(ctx, &services[i])
 	}
+	hm.pruneFileWatches(services)
 }
 
 // checkService runs the health check for a single service, recovering from any panic
@@ -243,7 +302,29 @@ func (hm *HealthMonitor) checkService(ctx context.Context, service *types.Servic
 
 	hm.logger.Debug("health tick", "service", serviceName, "state", processHistoryEntry.State)
 
-	hm.hmDispatchByState(ctx, service, processHistoryEntry, instance)
+	if processHistoryEntry.State == types.ProcessStateStopped {
+		hm.stopFileWatch(serviceName)
+	} else {
+		hm.syncFileWatch(ctx, service)
+	}
+
+	entries := hm.hmInstanceEntries(ctx, serviceName)
+	if len(entries) < 2 {
+		hm.hmDispatchByState(ctx, service, processHistoryEntry, instance)
+		return
+	}
+	for index := range entries {
+		if index > 0 {
+			// An earlier instance's check may have restarted the service or
+			// moved its shared restart count: act on what's current.
+			if instance, processHistoryEntry, ok = hm.hmFetchInstanceState(ctx, serviceName, entries[index].Instance); !ok {
+				continue
+			}
+		} else {
+			processHistoryEntry = &entries[0]
+		}
+		hm.hmDispatchByState(ctx, service, processHistoryEntry, instance)
+	}
 }
 
 // hmFetchServiceState loads the instance and most recent process-history entry
@@ -317,12 +398,12 @@ func (hm *HealthMonitor) checkStartProcess(
 	}
 
 	// A process can be alive before its listener is bound (e.g. a framework
-	// compiling routes on cold start). Hold Starting until the port answers
-	// instead of flipping to Running here only for checkRunningProcess to
-	// find it unreachable and mark it Failed on the very next tick. The
-	// timeout check above still applies each tick, so this can't wait forever.
-	if config.Port != 0 && !hm.isPortReachable(ctx, config.Port) {
-		hm.logger.Debug("startup check: port not reachable yet", "service", serviceName, "port", config.Port)
+	// compiling routes on cold start). Hold Starting until the port answers,
+	// or the health_check's readiness check passes, instead of flipping to
+	// Running here only for checkRunningProcess to find it unreachable and
+	// mark it Failed on the very next tick. The timeout check above still
+	// applies each tick, so this can't wait forever.
+	if !hm.hmStartupReady(ctx, service, pgid, hm.mgr.ServiceConfigForLaunch(serviceName, pgid, config)) {
 		return
 	}
 
@@ -401,17 +482,31 @@ func (hm *HealthMonitor) checkRunningProcess(ctx context.Context, service *types
 		return
 	}
 
-	if config.Port != 0 && !hm.isPortReachable(ctx, config.Port) {
+	if hm.checkWatchdog(ctx, service, process, instance, config) {
+		return
+	}
+	// Checks go to the port this launch listens on, which behind a proxy
+	// isn't the one the proxy answers on for every launch.
+	launchConfig := hm.mgr.ServiceConfigForLaunch(serviceName, pgid, config)
+	if config.HealthCheck != nil {
+		if hm.checkLiveness(ctx, service, process, instance, launchConfig) {
+			return
+		}
+	} else if config.Port != 0 && !hm.hmNotifyManaged(pgid, config) && !hm.isPortReachable(ctx, launchConfig.Port) {
 		msg := fmt.Sprintf("[%s] is not reachable on port %d", serviceName, config.Port)
 		hm.markProcessFailed(ctx, pgid, serviceName, instance, slog.LevelError, msg, msg)
 		return
 	}
 
+	if config.AutoRestartOnChange && hm.checkConfigChangeRestart(ctx, serviceName, pgid) {
+		return
+	}
 	hm.checkCronRestart(ctx, service, instance, config.CronRestart)
 	hm.resetRestartCounterIfStable(ctx, serviceName, process, instance)
 
-	rssKb, sampled := hm.measureRSS(ctx, pgid, serviceName)
-	cpuPct, cpuSampled := hm.measureCPU(ctx, pgid, serviceName)
+	launchKey := hmLaunchKey(serviceName, process.Instance)
+	rssKb, sampled := hm.measureRSS(ctx, pgid, launchKey)
+	cpuPct, cpuSampled := hm.measureCPU(ctx, pgid, launchKey)
 
 	action := hm.evaluateMemoryThresholds(config.MemoryLimitMb, rssKb)
 	hm.dispatchMemoryAction(ctx, service, process, instance, action, memorySample{
@@ -458,6 +553,30 @@ func (hm *HealthMonitor) checkCronRestart(ctx context.Context, service *types.Se
 	hm.scheduleNextCronRestart(ctx, serviceName, cronExpr, time.Now())
 }
 
+// checkConfigChangeRestart restarts a running auto_restart_on_change service
+// whose launch pgid was started with a config other than the one on disk,
+// reporting whether it did.
+func (hm *HealthMonitor) checkConfigChangeRestart(ctx context.Context, serviceName string, pgid int) bool {
+	drift, err := hm.mgr.ConfigDriftForLaunch(ctx, serviceName, pgid)
+	if err != nil {
+		hm.logger.Error("comparing launch config", "service", serviceName, "pgid", pgid, "error", err)
+		return false
+	}
+	if drift == nil || !drift.Pending() {
+		return false
+	}
+
+	restartMsg := fmt.Sprintf("[%s] config changed, restarting to apply it", serviceName)
+	hm.logger.Info(restartMsg, "changes", len(drift.Changes), "env_changed", drift.EnvChanged)
+	if logErr := hm.mgr.LogToServiceStdout(serviceName, restartMsg); logErr != nil {
+		hm.logger.Error(logFailedLogServiceOutput, "service", serviceName, "error", logErr)
+	}
+	if _, err := hm.mgr.RestartService(ctx, serviceName, hm.shutdownGracePeriod, 200*time.Millisecond); err != nil {
+		hm.logger.Error("config change restart failed", "service", serviceName, "error", err)
+	}
+	return true
+}
+
 // scheduleNextCronRestart computes the next fire time for cronExpr after from
 // and persists it on the service instance.
 func (hm *HealthMonitor) scheduleNextCronRestart(ctx context.Context, serviceName, cronExpr string, from time.Time) {
@@ -475,15 +594,14 @@ func (hm *HealthMonitor) scheduleNextCronRestart(ctx context.Context, serviceNam
 // accepts connections. It only catches a listener that stopped accepting entirely
 // (e.g. crashed internally without exiting the process) — a raw TCP connect can
 // still succeed against a hung app via the kernel's accept backlog, so this is not
-// a substitute for an application-level health check.
+// a substitute for an application-level health check (health_check:, see
+// checkLiveness).
 func (hm *HealthMonitor) isPortReachable(ctx context.Context, port int) bool {
 	return portReachable(ctx, port)
 }
 
-// portReachable is the liveness-agnostic core of isPortReachable, shared with
-// ProbeReady so a reload cutover gates on the exact same TCP reachability test
-// the running health monitor applies each tick, with no HealthMonitor instance
-// to hand.
+// portReachable is the core of isPortReachable, with no HealthMonitor
+// instance needed.
 func portReachable(ctx context.Context, port int) bool {
 	dialer := net.Dialer{Timeout: 500 * time.Millisecond}
 	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("localhost:%d", port))
@@ -496,25 +614,25 @@ func portReachable(ctx context.Context, port int) bool {
 
 // ProbeReady reports whether a freshly launched instance is ready to take over:
 // the process group is still the one that was started (alive, start time
-// matching, so a recycled PGID can't read as ready) and, when the service
-// declares a port, that port accepts a connection. It is the same
-// liveness-plus-port check checkStartProcess and checkRunningProcess apply, so a
+// matching, so a recycled PGID can't read as ready) and check, the service's
+// readiness check (manager.ReadinessProbeFor: its health_check's, or a dial to
+// its port), passes. It is the same gate checkStartProcess applies, so a
 // reload cutover holds the outgoing instance until the incoming one passes the
-// gate the health monitor would itself use. port 0 means no port to probe, so
-// liveness alone decides.
+// gate the health monitor would itself use. A nil check means there is
+// nothing to probe, so liveness alone decides.
 //
 // Under SO_REUSEPORT both instances share the port during the overlap, so a
-// reachable port only proves some instance is listening, not specifically the
+// passing check only proves some instance is answering, not specifically the
 // new one; the caller pairs this with the incoming process staying alive and a
 // probe interval that gives it time to bind before the old instance is drained.
-func ProbeReady(ctx context.Context, pgid int, startedAtTicks int64, port int) bool {
+func ProbeReady(ctx context.Context, pgid int, startedAtTicks int64, check manager.HealthProbe) bool {
 	if !procutil.IsAliveMatching(pgid, startedAtTicks) {
 		return false
 	}
-	if port == 0 {
+	if check == nil {
 		return true
 	}
-	return portReachable(ctx, port)
+	return check.Check(ctx) == nil
 }
 
 // handleLivenessFailure marks a running-state process as failed because it is
@@ -608,7 +726,7 @@ func (hm *HealthMonitor) restartOnMemoryThreshold(ctx context.Context, service *
 	}
 
 	hm.logger.Debug("memory threshold: "+restart.label+" restart", "service", serviceName, "mem_kb", restart.rssKb, "attempt", instance.RestartCount+1)
-	newPgid, err := hm.mgr.RestartService(ctx, service.Name, restart.gracePeriod, restart.tickerPeriod)
+	newPgid, err := hm.hmRestart(ctx, service, process, restart.gracePeriod, restart.tickerPeriod)
 	if err != nil {
 		hm.updateProcessEntry(ctx, pgid, restart.rssPtr, restart.peakPtr, nil, serviceName)
 		hm.logger.Error("restarting on "+restart.label+" restart threshold", "service", serviceName, "error", err)
@@ -620,11 +738,12 @@ func (hm *HealthMonitor) restartOnMemoryThreshold(ctx context.Context, service *
 	if logErr := hm.mgr.LogToServiceStderr(serviceName, restartMsg); logErr != nil {
 		hm.logger.Error(logFailedLogServiceErrOutput, "service", serviceName, "error", logErr)
 	}
-	delete(hm.lastMemSample, serviceName)
+	launchKey := hmLaunchKey(serviceName, process.Instance)
+	delete(hm.lastMemSample, launchKey)
 	// The restarted service has a new PGID; drop the old CPU baseline so the
 	// next tick reseeds instead of diffing against the dead process's total.
-	delete(hm.lastCPUSample, serviceName)
-	newRssKb, newSampled := hm.measureRSS(ctx, newPgid, serviceName)
+	delete(hm.lastCPUSample, launchKey)
+	newRssKb, newSampled := hm.measureRSS(ctx, newPgid, launchKey)
 	// A new PGID means a fresh process_history row: peak has no prior value to
 	// carry over, so it starts from this sample rather than the killed
 	// process's peak.
@@ -669,7 +788,7 @@ func (hm *HealthMonitor) checkFailedProcess(ctx context.Context, service *types.
 	}
 
 	if !hm.isProcessAlive(pgid) {
-		hm.hmAttemptFailedRestart(ctx, service, process, instance, config.Port)
+		hm.hmAttemptFailedRestart(ctx, service, process, instance, config)
 		return
 	}
 
@@ -677,33 +796,49 @@ func (hm *HealthMonitor) checkFailedProcess(ctx context.Context, service *types.
 }
 
 // hmAttemptFailedRestart restarts a dead, Failed-state process once backoff
-// allows it, surfacing any restart error through handleRestartFailure.
-func (hm *HealthMonitor) hmAttemptFailedRestart(ctx context.Context, service *types.ServiceCatalogEntry, process *types.ProcessHistory, instance *types.ServiceInstance, configPort int) {
+// allows it, surfacing any restart error through handleRestartFailure. A
+// service the monitor has given up on stays down, and the service's restart
+// policy can give up on it here (see hmRestartRefusal).
+func (hm *HealthMonitor) hmAttemptFailedRestart(ctx context.Context, service *types.ServiceCatalogEntry, process *types.ProcessHistory, instance *types.ServiceInstance, serviceConfig *types.ServiceConfig) {
 	serviceName := service.Name
 	pgid := process.PGID
 	restartCount := instance.RestartCount
 	backoffConfig := hm.effectiveBackoff(instance)
 	inLoop := instance.FailureLoopCount >= config.HealthCrashLoopThreshold
 
+	if instance.GaveUpReason != "" {
+		return
+	}
+	policy := hm.restartPolicyFrom(serviceName, serviceConfig)
+	if reason := hm.hmRestartRefusal(serviceName, pgid, policy); reason != "" {
+		hm.giveUpRestarts(ctx, serviceName, pgid, reason)
+		return
+	}
+
 	// TODO: Do we want to incorporate instance.last_health_check instead process?
 	if !canRestart(restartCount, process.StoppedAt, backoffConfig) {
 		hm.logger.Debug("restart deferred", "service", serviceName, "count", restartCount)
 		return
 	}
 
+	if reason := hm.hmCountRestart(ctx, serviceName, instance, policy); reason != "" {
+		hm.giveUpRestarts(ctx, serviceName, pgid, reason)
+		return
+	}
+
 	// Snapshot the service's last captured stderr line before logging our own
 	// "restarting" breadcrumb below (which lands in the same error log via
 	// LogToServiceStderr) — otherwise a failed restart's error message would
 	// surface our own breadcrumb instead of the child process's real cause.
 	lastErrLine, hadLastErrLine := hm.mgr.GetServiceLastErrorLine(serviceName, pgid)
 
-	errorString := hmRestartingMessage(serviceName, configPort)
+	errorString := hmRestartingMessage(serviceName, serviceConfig.Port)
 
 	backoff := calculateBackoffDelay(restartCount, backoffConfig.BaseMs, backoffConfig.MaxMs)
 	hm.logger.Debug("scheduling restart", "service", serviceName, "attempt", restartCount+1, "backoff", backoff)
 	hm.logger.Info(errorString)
 	hm.logCrashLoopAware(serviceName, errorString, inLoop)
-	_, err := hm.mgr.RestartService(ctx, serviceName, hm.shutdownGracePeriod, 200*time.Millisecond)
+	_, err := hm.hmRestart(ctx, service, process, hm.shutdownGracePeriod, 200*time.Millisecond)
 
 	if err != nil {
 		hm.handleRestartFailure(ctx, serviceName, pgid, restartCount, err, lastErrLine, hadLastErrLine)
@@ -840,30 +975,60 @@ func (hm *HealthMonitor) markProcessRunning(ctx context.Context, pgid int, servi
 // failMsg is a thunk rather than a plain string so the Failed path's message
 // build (e.g. checkStartProcess's stderr scan for a crash reason) only runs
 // when the process didn't just exit clean — the common case for the exact
-// service shape this exists for.
+// service shape this exists for. An OOM kill recorded in the launch's cgroup
+// overrides failMsg's cause outright: it's the one death whose reason the
+// kernel states for certain.
+//
+// The service's restart policy decides what counts as clean, and an exit it
+// doesn't restart after leaves the service given up on (see giveUpRestarts)
+// as soon as it's recorded Failed.
 func (hm *HealthMonitor) handleDeadProcessGroup(ctx context.Context, pgid int, serviceName string, instance *types.ServiceInstance, level slog.Level, failMsg func() (message, signature string)) {
-	if hm.markProcessStoppedIfCleanExit(ctx, serviceName, pgid) {
+	policy := hm.restartPolicyFor(ctx, serviceName)
+	code, known := hm.mgr.GetServiceExitCode(pgid)
+	exit := processExit{code: code, known: known}
+	if hm.markProcessStoppedIfCleanExit(ctx, serviceName, pgid, exit, policy) {
 		return
 	}
 	message, signature := failMsg()
+	if kills, ok := hm.mgr.GetServiceOOMKills(pgid); ok && kills > 0 {
+		message, signature = hmOOMKillMessage(serviceName, pgid, kills), hmOOMKillSignature
+	} else if known && code == 0 {
+		message, signature = hmCleanExitMessage(serviceName, pgid), ""
+	}
 	hm.markProcessFailed(ctx, pgid, serviceName, instance, level, message, signature)
-}
-
-// markProcessStoppedIfCleanExit checks whether pgid's reaped exit code is a
-// clean zero and, if so, records it as Stopped instead of the caller falling
-// through to markProcessFailed. This is what keeps a one-shot command with no
-// server to keep running (a build step with no `port:` in its service.yaml,
-// say) from being logged as "died" and endlessly restarted for having
-// finished successfully: eos has no separate service type for "runs once and
-// exits", so the only signal available to tell that apart from an actual
-// crash is the exit code itself. ok=false (no exit code known yet) is treated
-// the same as a nonzero one — the caller's existing Failed path — rather than
+	if !policy.Restarts(code, known) {
+		hm.giveUpRestarts(ctx, serviceName, pgid, hmRestartNeverMessage(serviceName, exit))
+	}
+}
+
+// hmOOMKillSignature is the failure signature of every OOM-killed launch. It
+// deliberately ignores the kill count and PGID, so a service that keeps
+// outgrowing its memory_max reads as one sustained failure loop.
+const hmOOMKillSignature = "oom_kill"
+
+// hmOOMKillMessage replaces the generic death message when the launch's
+// cgroup recorded an OOM kill: the kernel killed the service for exceeding
+// limits.memory_max, which a stderr line (usually none at all, SIGKILL
+// leaves no time to write one) or "is not running" would never say.
+func hmOOMKillMessage(serviceName string, pgid int, kills int) string {
+	return fmt.Sprintf("[%s] killed by the kernel OOM killer for exceeding limits.memory_max (PGID %d, %d process(es) killed)", serviceName, pgid, kills)
+}
+
+// markProcessStoppedIfCleanExit checks whether pgid's reaped exit is one
+// policy counts as Completed (by default, a clean zero) and, if so, records it
+// as Stopped instead of the caller falling through to markProcessFailed. This
+// is what keeps a one-shot command with no server to keep running (a build
+// step with no `port:` in its service.yaml, say) from being logged as "died"
+// and endlessly restarted for having finished successfully: eos has no
+// separate service type for "runs once and exits", so the only signal
+// available to tell that apart from an actual crash is the exit code itself.
+// An unknown exit (no exit code captured yet) is treated the same as a
+// nonzero one — the caller's existing Failed path — rather than
 // guessing, since that is the behavior already in place today. Not called
 // directly by health-check dispatch code; go through handleDeadProcessGroup
 // instead, which is the one place callers should reach for.
-func (hm *HealthMonitor) markProcessStoppedIfCleanExit(ctx context.Context, serviceName string, pgid int) bool {
-	code, ok := hm.mgr.GetServiceExitCode(pgid)
-	if !ok || code != 0 {
+func (hm *HealthMonitor) markProcessStoppedIfCleanExit(ctx context.Context, serviceName string, pgid int, exit processExit, policy manager.RestartPolicy) bool {
+	if !exit.known || !policy.Completed(exit.code) {
 		return false
 	}
 
@@ -1008,15 +1173,17 @@ func calculateBackoffDelay(restartCount, baseMs, maxMs int) time.Duration {
 }
 
 // isProcessAlive reports whether any live process exists in the given process
-// group, delegating to procutil.IsAlive rather than re-deriving the check
-// here: a prior hand-rolled version of this only inspected /proc/<pgid>/stat
-// (the group leader's own PID) for the Linux zombie case, which reads the
-// whole group as dead the moment the leader exits and is reaped even though
-// another member is still running — exactly the wrapper-spawns-server shape
-// (`sh -c "npm start"` -> npm -> node) eos launches services through (#197).
-// procutil.IsAlive already scans every group member for this reason.
+// group, delegating to the manager rather than re-deriving the check here: a
+// prior hand-rolled version of this only inspected /proc/<pgid>/stat (the
+// group leader's own PID) for the Linux zombie case, which reads the whole
+// group as dead the moment the leader exits and is reaped even though another
+// member is still running — exactly the wrapper-spawns-server shape (`sh -c
+// "npm start"` -> npm -> node) eos launches services through (#197). The
+// manager answers from the launch's cgroup when it has one, which also covers
+// a member that left the group via setsid, and from procutil.IsAlive's
+// whole-group scan otherwise.
 func (hm *HealthMonitor) isProcessAlive(pgid int) bool {
-	return procutil.IsAlive(pgid)
+	return hm.mgr.IsProcessGroupAlive(pgid)
 }
 
 // scanStatusFieldBytes finds a field in /proc/N/status without allocating.
@@ -492,7 +492,7 @@ func TestHealthMonitor_MarkProcessStoppedIfCleanExit_NoExitCodeKnown(t *testing.
 	hm := &HealthMonitor{mgr: mgr, db: db, logger: testutil.NewTestLogger(t)}
 
 	const neverLaunchedPGID = 999998
-	if hm.markProcessStoppedIfCleanExit(t.Context(), "no-such-service", neverLaunchedPGID) {
+	if hm.markProcessStoppedIfCleanExit(t.Context(), "no-such-service", neverLaunchedPGID, processExit{}, manager.RestartPolicy{}) {
 		t.Error("expected false when no exit code has been recorded for this pgid")
 	}
 }
@@ -1799,7 +1799,7 @@ func TestHealthMonitor_CheckFailedProcess_RetriesIndefinitely(t *testing.T) {
 }
 
 func TestHealthMonitor_IsProcessAlive(t *testing.T) {
-	hm := &HealthMonitor{}
+	hm := &HealthMonitor{mgr: manager.NewLocalManager(nil, t.TempDir(), t.Context(), testutil.NewTestLogger(t))}
 
 	pgid, err := syscall.Getpgid(os.Getpid())
 	if err != nil {
@@ -1812,7 +1812,7 @@ func TestHealthMonitor_IsProcessAlive(t *testing.T) {
 }
 
 func TestHealthMonitor_IsProcessAlive_NonExistent(t *testing.T) {
-	hm := &HealthMonitor{}
+	hm := &HealthMonitor{mgr: manager.NewLocalManager(nil, t.TempDir(), t.Context(), testutil.NewTestLogger(t))}
 	isAlive := hm.isProcessAlive(rand.Intn(99999))
 
 	if isAlive {
@@ -1834,7 +1834,7 @@ func TestHealthMonitor_IsProcessAlive_LeaderExitedChildSurvives(t *testing.T) {
 		t.Skip("leader-exited-child-alive zombie detection is Linux-specific")
 	}
 
-	hm := &HealthMonitor{}
+	hm := &HealthMonitor{mgr: manager.NewLocalManager(nil, t.TempDir(), t.Context(), testutil.NewTestLogger(t))}
 
 	leader := exec.Command("sh", "-c", "sleep 5 &")
 	leader.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
@@ -3037,7 +3037,7 @@ func TestHmAttemptFailedRestart_Deferred(t *testing.T) {
 	// restartCount=1 against a 1000ms base backoff means the just-now StoppedAt
 	// is well within the backoff window, so canRestart is false and the
 	// function must return early without touching hm.mgr (nil here).
-	hm.hmAttemptFailedRestart(t.Context(), service, process, &types.ServiceInstance{RestartCount: 1}, 0)
+	hm.hmAttemptFailedRestart(t.Context(), service, process, &types.ServiceInstance{RestartCount: 1}, &types.ServiceConfig{})
 }
 
 func TestNewHealthMonitor_CheckIntervalDefault(t *testing.T) {
@@ -3655,6 +3655,76 @@ func TestHealthMonitor_CheckCronRestart_DueTriggersRestart(t *testing.T) {
 	}
 }
 
+func TestHealthMonitor_CheckConfigChangeRestart_RestartsOnlyOnChange(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(mgr.WaitPipes)
+	hm := NewHealthMonitor(mgr, db, testutil.NewTestLogger(t), newTestHealthConfig(t), *newTestShutdownConfig(t), otelx.NoopHandles())
+
+	const serviceName = "config-change-svc"
+	fullDirPath := filepath.Join(tempDir, "config-change-project")
+	if err := os.MkdirAll(fullDirPath, 0755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+	testServiceScript := testutil.NewTestServiceScript(t, testutil.WithDirPath(fullDirPath))
+	testutil.NewTestServiceScriptAtLocation(t, *testServiceScript)
+
+	testFile := testutil.NewTestServiceConfigFile(t,
+		testutil.WithoutRuntime(),
+		testutil.WithName(serviceName),
+		testutil.WithCommand("./"+testServiceScript.FileName))
+	testFile.AutoRestartOnChange = true
+	fullPath := filepath.Join(fullDirPath, "service.yaml")
+	writeConfig := func() {
+		yamlData, err := yaml.Marshal(testFile)
+		if err != nil {
+			t.Fatalf("Failed to marshal test config: %v", err)
+		}
+		if err := os.WriteFile(fullPath, yamlData, 0644); err != nil {
+			t.Fatalf("Failed to write the service.yaml file, got: %v", err)
+		}
+	}
+	writeConfig()
+
+	serviceCatalogEntry, err := manager.NewServiceCatalogEntry(testFile.Name, fullDirPath, filepath.Base(fullPath))
+	if err != nil {
+		t.Fatalf("Create service catalog entry failed: %v", err)
+	}
+	if err = mgr.AddServiceCatalogEntry(t.Context(), serviceCatalogEntry); err != nil {
+		t.Fatalf("Error registering service: %v", err)
+	}
+	pgid, err := mgr.StartService(t.Context(), serviceName)
+	if err != nil {
+		t.Fatalf("Service unable to start, got: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		if latest, latestErr := mgr.GetMostRecentProcessHistoryEntry(t.Context(), serviceName); latestErr == nil && latest != nil {
+			_ = syscall.Kill(-latest.PGID, syscall.SIGKILL)
+		}
+	})
+
+	if hm.checkConfigChangeRestart(t.Context(), serviceName, pgid) {
+		t.Fatal("expected no restart while the config on disk is the one launched")
+	}
+
+	testFile.MemoryLimitMb = 256
+	writeConfig()
+	if !hm.checkConfigChangeRestart(t.Context(), serviceName, pgid) {
+		t.Fatal("expected a restart once the config on disk changed")
+	}
+	newProcess, err := mgr.GetMostRecentProcessHistoryEntry(t.Context(), serviceName)
+	if err != nil || newProcess == nil {
+		t.Fatalf("failed to get process history after config change restart: %v", err)
+	}
+	if newProcess.PGID == pgid {
+		t.Errorf("expected a new PGID after config change restart, still %d", pgid)
+	}
+	if hm.checkConfigChangeRestart(t.Context(), serviceName, newProcess.PGID) {
+		t.Error("expected the restarted launch to be running the config on disk")
+	}
+}
+
 // restartFailManager wraps a monitorManager and forces RestartService to return a
 // configured error, to exercise checkFailedProcess's restart-failure handling.
 type restartFailManager struct {
@@ -4086,6 +4156,63 @@ func (m *exitCodeManager) GetServiceExitCode(int) (int, bool) {
 	return m.code, m.ok
 }
 
+// oomKillManager wraps a monitorManager and reports a configured OOM-kill
+// count for every PGID, standing in for a cgroup leaf's memory.events.
+type oomKillManager struct {
+	monitorManager
+	kills int
+}
+
+func (m *oomKillManager) GetServiceOOMKills(int) (int, bool) {
+	return m.kills, true
+}
+
+// TestHandleDeadProcessGroup_OOMKillIsDistinctCause covers a launch the
+// kernel OOM-killed: its failure must be recorded as the OOM kill, with the
+// fixed oom_kill signature, rather than the caller's generic death message.
+func TestHandleDeadProcessGroup_OOMKillIsDistinctCause(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	healthConfig := newTestHealthConfig(t)
+	shutdownConfig := newTestShutdownConfig(t)
+
+	const serviceName = "oom-svc"
+	const pgid = 888884
+	if err := db.RegisterServiceInstance(t.Context(), serviceName); err != nil {
+		t.Fatalf("RegisterServiceInstance failed: %v", err)
+	}
+	if _, err := db.RegisterProcessHistoryEntry(t.Context(), pgid, 0, serviceName, types.ProcessStateRunning); err != nil {
+		t.Fatalf("failed to seed process history: %v", err)
+	}
+	instance, err := db.GetServiceInstance(t.Context(), serviceName)
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+
+	mgr := &oomKillManager{monitorManager: &exitCodeManager{monitorManager: realMgr, code: 137, ok: true}, kills: 1}
+	hm := NewHealthMonitor(mgr, db, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
+
+	hm.handleDeadProcessGroup(t.Context(), pgid, serviceName, &instance, slog.LevelError, func() (string, string) {
+		return "[oom-svc] is not running", "[oom-svc] is not running"
+	})
+
+	entry, err := db.GetProcessHistoryEntryByPGID(t.Context(), pgid)
+	if err != nil {
+		t.Fatalf("GetProcessHistoryEntryByPGID failed: %v", err)
+	}
+	if entry.State != types.ProcessStateFailed || entry.Error == nil || !strings.Contains(*entry.Error, "OOM killer") {
+		t.Errorf("expected a Failed row naming the OOM killer, got state=%s error=%v", entry.State, entry.Error)
+	}
+	updated, err := db.GetServiceInstance(t.Context(), serviceName)
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+	if updated.FailureSignature != hmOOMKillSignature {
+		t.Errorf("expected failure signature %q, got %q", hmOOMKillSignature, updated.FailureSignature)
+	}
+}
+
 func readDaemonLog(t *testing.T, daemonConfig config.DaemonConfig) string {
 	t.Helper()
 	content, err := os.ReadFile(filepath.Join(daemonConfig.Standalone.Log.LogDir, daemonConfig.Standalone.Log.LogFileName))
@@ -4305,7 +4432,7 @@ func TestHealthMonitor_MarkProcessStoppedIfCleanExit_LogWriteFailureLogged(t *te
 	}
 	hm := NewHealthMonitor(mgr, db, logger, healthConfig, *shutdownConfig, otelx.NoopHandles())
 
-	if !hm.markProcessStoppedIfCleanExit(t.Context(), serviceName, unregisteredPGID) {
+	if !hm.markProcessStoppedIfCleanExit(t.Context(), serviceName, unregisteredPGID, processExit{code: 0, known: true}, manager.RestartPolicy{}) {
 		t.Fatal("expected markProcessStoppedIfCleanExit to return true for a clean exit")
 	}
 
@@ -4345,7 +4472,7 @@ func TestHealthMonitor_MarkProcessStoppedIfCleanExit_DBFailureLogged(t *testing.
 	mgr := &exitCodeManager{monitorManager: realMgr, code: 0, ok: true}
 	hm := NewHealthMonitor(mgr, db, logger, healthConfig, *shutdownConfig, otelx.NoopHandles())
 
-	if !hm.markProcessStoppedIfCleanExit(t.Context(), serviceName, unregisteredPGID) {
+	if !hm.markProcessStoppedIfCleanExit(t.Context(), serviceName, unregisteredPGID, processExit{code: 0, known: true}, manager.RestartPolicy{}) {
 		t.Fatal("expected markProcessStoppedIfCleanExit to return true for a clean exit")
 	}
 
@@ -5032,7 +5159,7 @@ func TestHmAttemptFailedRestart_WidensBackoffOnceInLoop(t *testing.T) {
 		hm := NewHealthMonitor(mgr, nil, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
 		process := &types.ProcessHistory{PGID: 1, StoppedAt: &stoppedAt}
 		instance := &types.ServiceInstance{RestartCount: 10, FailureLoopCount: 0}
-		hm.hmAttemptFailedRestart(t.Context(), service, process, instance, 0)
+		hm.hmAttemptFailedRestart(t.Context(), service, process, instance, &types.ServiceConfig{})
 		if mgr.calls != 1 {
 			t.Errorf("expected the normal ceiling to allow a retry, got %d RestartService calls", mgr.calls)
 		}
@@ -5043,7 +5170,7 @@ func TestHmAttemptFailedRestart_WidensBackoffOnceInLoop(t *testing.T) {
 		hm := NewHealthMonitor(mgr, nil, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
 		process := &types.ProcessHistory{PGID: 1, StoppedAt: &stoppedAt}
 		instance := &types.ServiceInstance{RestartCount: 10, FailureLoopCount: config.HealthCrashLoopThreshold}
-		hm.hmAttemptFailedRestart(t.Context(), service, process, instance, 0)
+		hm.hmAttemptFailedRestart(t.Context(), service, process, instance, &types.ServiceConfig{})
 		if mgr.calls != 0 {
 			t.Errorf("expected the widened ceiling to defer the retry, got %d RestartService calls", mgr.calls)
 		}
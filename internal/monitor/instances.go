@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// hmInstanceEntries returns the most recent process-history entry of each of
+// serviceName's instances. On error it returns nil, and checkService falls
+// back to checking the service's most recent entry alone.
+func (hm *HealthMonitor) hmInstanceEntries(ctx context.Context, serviceName string) []types.ProcessHistory {
+	entries, err := hm.mgr.GetInstanceProcessHistoryEntries(ctx, serviceName)
+	if err != nil {
+		hm.logger.Error("getting instance process history", "service", serviceName, "error", err)
+		return nil
+	}
+	return entries
+}
+
+// hmFetchInstanceState is hmFetchServiceState for instance index of a
+// service running several.
+func (hm *HealthMonitor) hmFetchInstanceState(ctx context.Context, serviceName string, index int) (*types.ServiceInstance, *types.ProcessHistory, bool) {
+	instance, err := hm.mgr.GetServiceInstance(ctx, serviceName)
+	if err != nil || instance == nil {
+		return nil, nil, false
+	}
+	for _, entry := range hm.hmInstanceEntries(ctx, serviceName) {
+		if entry.Instance == index {
+			return instance, &entry, true
+		}
+	}
+	return nil, nil, false
+}
+
+// hmRestart restarts the launch process belongs to: just that instance for a
+// service running several, so one crashing never bounces its siblings, the
+// whole service otherwise. The restart count and backoff stay the service's,
+// shared by its instances.
+func (hm *HealthMonitor) hmRestart(ctx context.Context, service *types.ServiceCatalogEntry, process *types.ProcessHistory, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error) {
+	config, err := manager.LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err == nil && manager.InstanceCount(config) > 1 {
+		return hm.mgr.RestartServiceInstance(ctx, service.Name, process.Instance, gracePeriod, tickerPeriod)
+	}
+	return hm.mgr.RestartService(ctx, service.Name, gracePeriod, tickerPeriod)
+}
+
+// hmLaunchKey keys the monitor's per-launch state (liveness failures, memory
+// and CPU samples): the service name for its first instance, as it always
+// was, "name:index" for the others.
+func hmLaunchKey(serviceName string, index int) string {
+	if index == 0 {
+		return serviceName
+	}
+	return manager.InstanceName(serviceName, index)
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// hmNotifyManaged reports whether pgid is a type: notify launch this daemon
+// holds the notify socket for, so READY=1 and WATCHDOG=1 speak for it. A
+// notify launch a previous daemon started has lost its socket, and is judged
+// from outside like a type: simple one.
+func (hm *HealthMonitor) hmNotifyManaged(pgid int, config *types.ServiceConfig) bool {
+	if manager.ServiceType(config.Type) != manager.ServiceTypeNotify {
+		return false
+	}
+	_, ok := hm.mgr.GetServiceNotifyStatus(pgid)
+	return ok
+}
+
+// hmNotifyReady reports whether a Starting launch has cleared the notify half
+// of startup: always for a launch that isn't notify-managed (see
+// hmNotifyManaged), once it has sent READY=1 otherwise.
+func (hm *HealthMonitor) hmNotifyReady(serviceName string, pgid int, config *types.ServiceConfig) bool {
+	if !hm.hmNotifyManaged(pgid, config) {
+		return true
+	}
+	status, _ := hm.mgr.GetServiceNotifyStatus(pgid)
+	if !status.Ready {
+		hm.logger.Debug("startup check: READY=1 not received yet", "service", serviceName, "pgid", pgid)
+		return false
+	}
+	return true
+}
+
+// checkWatchdog restarts a Running type: notify service that has gone
+// watchdog_sec without sending WATCHDOG=1, counting from launch until its
+// first ping. Like checkLiveness it restarts rather than marking the launch
+// Failed, and reports whether it did, which ends the tick.
+func (hm *HealthMonitor) checkWatchdog(ctx context.Context, service *types.ServiceCatalogEntry, process *types.ProcessHistory, instance *types.ServiceInstance, config *types.ServiceConfig) bool {
+	if config.WatchdogSec <= 0 || !hm.hmNotifyManaged(process.PGID, config) {
+		return false
+	}
+	status, _ := hm.mgr.GetServiceNotifyStatus(process.PGID)
+	last := status.LastWatchdog
+	if last == nil {
+		last = process.StartedAt
+	}
+	limit := time.Duration(config.WatchdogSec) * time.Second
+	if last == nil || time.Since(*last) <= limit {
+		return false
+	}
+	if !canRestart(instance.RestartCount, process.StartedAt, hm.backoff) {
+		return false
+	}
+
+	serviceName := service.Name
+	if _, err := hm.hmRestart(ctx, service, process, hm.shutdownGracePeriod, 200*time.Millisecond); err != nil {
+		hm.logger.Error("restarting on missed watchdog", "service", serviceName, "error", err)
+		return false
+	}
+	msg := hmWatchdogRestartMessage(serviceName, config.WatchdogSec)
+	hm.logger.Warn(msg)
+	hm.writeServiceStderr(serviceName, msg)
+	launchKey := hmLaunchKey(serviceName, process.Instance)
+	delete(hm.lastMemSample, launchKey)
+	delete(hm.lastCPUSample, launchKey)
+	return true
+}
+
+func hmWatchdogRestartMessage(serviceName string, watchdogSec int) string {
+	return fmt.Sprintf("[%s] restarted after sending no WATCHDOG=1 for %ds", serviceName, watchdogSec)
+}
@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/otelx"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// notifyStatusManager reports a fixed notify status for every launch, the
+// way LocalManager would for one whose notify socket it holds.
+type notifyStatusManager struct {
+	restartCallCountManager
+	status types.NotifyStatus
+}
+
+func (m *notifyStatusManager) GetServiceNotifyStatus(int) (types.NotifyStatus, bool) {
+	return m.status, true
+}
+
+// TestHmNotifyReady_GatesOnReady covers startup for type: notify: READY=1
+// alone promotes the launch, while a type: simple service is never held by it.
+func TestHmNotifyReady_GatesOnReady(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	fake := &notifyStatusManager{restartCallCountManager: restartCallCountManager{monitorManager: realMgr}}
+	hm := NewHealthMonitor(fake, db, testutil.NewTestLogger(t), newTestHealthConfig(t), *newTestShutdownConfig(t), otelx.NoopHandles())
+
+	config := &types.ServiceConfig{Name: "notify-svc", Type: "notify"}
+	if hm.hmNotifyReady(config.Name, 888897, config) {
+		t.Fatal("ready before READY=1")
+	}
+	fake.status.Ready = true
+	if !hm.hmNotifyReady(config.Name, 888897, config) {
+		t.Fatal("not ready after READY=1")
+	}
+	fake.status.Ready = false
+	if !hm.hmNotifyReady(config.Name, 888897, &types.ServiceConfig{Name: "simple-svc"}) {
+		t.Fatal("type: simple held on READY=1")
+	}
+}
+
+// TestCheckWatchdog_RestartsOnMissedPing covers watchdog_sec: a recent
+// WATCHDOG=1 keeps the service running, a stale one restarts it.
+func TestCheckWatchdog_RestartsOnMissedPing(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	fake := &notifyStatusManager{restartCallCountManager: restartCallCountManager{monitorManager: realMgr}}
+	hm := NewHealthMonitor(fake, db, testutil.NewTestLogger(t), newTestHealthConfig(t), *newTestShutdownConfig(t), otelx.NoopHandles())
+
+	const serviceName = "watchdog-svc"
+	service := &types.ServiceCatalogEntry{Name: serviceName, DirectoryPath: t.TempDir()}
+	config := &types.ServiceConfig{Name: serviceName, Type: "notify", WatchdogSec: 5}
+	longAgo := time.Now().Add(-time.Hour)
+	process := &types.ProcessHistory{PGID: 888898, StartedAt: &longAgo}
+	instance := &types.ServiceInstance{Name: serviceName}
+
+	fake.status.LastWatchdog = new(time.Now())
+	if hm.checkWatchdog(t.Context(), service, process, instance, config) {
+		t.Fatal("restarted despite a recent WATCHDOG=1")
+	}
+	fake.status.LastWatchdog = new(time.Now().Add(-10 * time.Second))
+	if !hm.checkWatchdog(t.Context(), service, process, instance, config) {
+		t.Fatal("did not restart after watchdog_sec without WATCHDOG=1")
+	}
+	if fake.calls != 1 {
+		t.Errorf("RestartService calls = %d, want 1", fake.calls)
+	}
+}
@@ -64,7 +64,7 @@ func TestReloadService_LiveMonitorCrashOnStart(t *testing.T) {
 	// check does not depend on the launch cwd.
 	marker := filepath.Join(dir, "started.marker")
 	command := "if [ -e " + marker + " ]; then exit 1; fi; touch " + marker + "; exec sleep 300"
-	cfg := &types.ServiceConfig{Name: name, Command: command}
+	cfg := &types.ServiceConfig{Name: name, Command: types.ServiceCommand{Shell: command}}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
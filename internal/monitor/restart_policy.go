@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// processExit is a dead launch's exit code as the reaper captured it;
+// known=false when it captured none.
+type processExit struct {
+	code  int
+	known bool
+}
+
+// restartPolicyFor loads serviceName's restart policy from its service.yaml.
+// A service whose config can't be loaded gets the default policy (logged), so
+// a broken service.yaml never changes how a crash is handled.
+func (hm *HealthMonitor) restartPolicyFor(ctx context.Context, serviceName string) manager.RestartPolicy {
+	service, err := hm.db.GetServiceCatalogEntry(ctx, serviceName)
+	if err != nil {
+		hm.logger.Debug("loading service for restart policy, using the default", "service", serviceName, "error", err)
+		return manager.RestartPolicy{Mode: manager.RestartOnFailure}
+	}
+	serviceConfig, err := manager.LoadServiceConfig(filepath.Join(service.DirectoryPath, service.ConfigFileName))
+	if err != nil {
+		hm.logger.Warn("loading service config for restart policy, using the default", "service", serviceName, "error", err)
+		return manager.RestartPolicy{Mode: manager.RestartOnFailure}
+	}
+	return hm.restartPolicyFrom(serviceName, serviceConfig)
+}
+
+// restartPolicyFrom resolves serviceConfig's restart policy, falling back to
+// the default (logged) when it's invalid.
+func (hm *HealthMonitor) restartPolicyFrom(serviceName string, serviceConfig *types.ServiceConfig) manager.RestartPolicy {
+	policy, err := manager.ResolveRestartPolicy(serviceConfig)
+	if err != nil {
+		hm.logger.Warn("invalid restart settings, restarting on failure", "service", serviceName, "error", err)
+		return manager.RestartPolicy{Mode: manager.RestartOnFailure}
+	}
+	return policy
+}
+
+// hmRestartRefusal returns why a dead, Failed-state pgid must not be
+// restarted under restart: never, or "" when it may be. Deaths observed by
+// handleDeadProcessGroup were already judged there, which consumed their exit
+// code; an exit code still on hand here belongs to a launch marked Failed
+// while it was alive (unreachable port, startup timeout) that has since died.
+func (hm *HealthMonitor) hmRestartRefusal(serviceName string, pgid int, policy manager.RestartPolicy) string {
+	if policy.Mode != manager.RestartNever {
+		return ""
+	}
+	code, known := hm.mgr.GetServiceExitCode(pgid)
+	if !known || policy.Restarts(code, known) {
+		return ""
+	}
+	return hmRestartNeverMessage(serviceName, processExit{code: code, known: known})
+}
+
+// hmCountRestart counts the restart about to be made against policy's
+// max_restarts and returns why the service is given up on instead, or "" to
+// go ahead. The count starts over once restart_window has passed since the
+// first restart it holds; without a restart_window it runs until the next
+// eos run.
+func (hm *HealthMonitor) hmCountRestart(ctx context.Context, serviceName string, instance *types.ServiceInstance, policy manager.RestartPolicy) string {
+	if policy.MaxRestarts == 0 {
+		return ""
+	}
+	now := time.Now()
+	windowStart, count := instance.RestartWindowStart, instance.RestartWindowCount
+	if windowStart == nil || (policy.Window > 0 && now.Sub(*windowStart) >= policy.Window) {
+		windowStart, count = &now, 0
+	}
+	if count >= policy.MaxRestarts {
+		return hmRestartLimitMessage(serviceName, count, policy.Window)
+	}
+	count++
+	if err := hm.db.UpdateServiceInstance(ctx, serviceName, database.ServiceInstanceUpdate{
+		RestartWindowStart: windowStart,
+		RestartWindowCount: &count,
+	}); err != nil {
+		hm.logger.Error("failed to count restart", "service", serviceName, "error", err)
+	}
+	return ""
+}
+
+// giveUpRestarts leaves serviceName Failed for good: the monitor no longer
+// restarts it, and reason replaces the error on its latest process-history
+// row, which is where eos status reads it from. Only eos run, which registers
+// a fresh service instance, brings it back.
+func (hm *HealthMonitor) giveUpRestarts(ctx context.Context, serviceName string, pgid int, reason string) {
+	hm.logger.Warn(reason)
+	hm.writeServiceStderr(serviceName, reason)
+	delete(hm.crashLoopLog, serviceName)
+	if err := hm.db.UpdateServiceInstance(ctx, serviceName, database.ServiceInstanceUpdate{GaveUpReason: &reason}); err != nil {
+		hm.logger.Error("failed to record giving up on restarts", "service", serviceName, "error", err)
+	}
+	if err := hm.db.UpdateProcessHistoryEntry(ctx, pgid, database.ProcessHistoryUpdate{Error: &reason}); err != nil {
+		hm.logger.Error(logFailedUpdateProcessHistory, "service", serviceName, "error", err)
+	}
+}
+
+// hmCleanExitMessage is the failure message of a launch that exited 0 but is
+// restarted anyway: under restart: always, or with 0 in restart_exit_codes.
+func hmCleanExitMessage(serviceName string, pgid int) string {
+	return fmt.Sprintf("[%s] exited with code 0 (PGID %d)", serviceName, pgid)
+}
+
+func hmRestartNeverMessage(serviceName string, exit processExit) string {
+	if exit.known {
+		return fmt.Sprintf("[%s] exited with code %d; not restarting (restart: never)", serviceName, exit.code)
+	}
+	return fmt.Sprintf("[%s] is not running; not restarting (restart: never)", serviceName)
+}
+
+func hmRestartLimitMessage(serviceName string, restarts int, window time.Duration) string {
+	if window > 0 {
+		return fmt.Sprintf("[%s] gave up after %d restarts within %s (max_restarts)", serviceName, restarts, window)
+	}
+	return fmt.Sprintf("[%s] gave up after %d restarts (max_restarts)", serviceName, restarts)
+}
@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/otelx"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// seedRestartPolicyService registers serviceName with a service.yaml holding
+// restartYAML, plus an instance and a Running process-history row for pgid.
+func seedRestartPolicyService(t *testing.T, db *database.DB, serviceName string, pgid int, restartYAML string) {
+	t.Helper()
+	dir := t.TempDir()
+	config := "name: " + serviceName + "\ncommand: sleep 30\n" + restartYAML
+	if err := os.WriteFile(filepath.Join(dir, "service.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("write service.yaml: %v", err)
+	}
+	if err := db.RegisterService(t.Context(), serviceName, dir, "service.yaml"); err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+	if err := db.RegisterServiceInstance(t.Context(), serviceName); err != nil {
+		t.Fatalf("RegisterServiceInstance failed: %v", err)
+	}
+	if _, err := db.RegisterProcessHistoryEntry(t.Context(), pgid, 0, serviceName, types.ProcessStateRunning); err != nil {
+		t.Fatalf("failed to seed process history: %v", err)
+	}
+}
+
+func TestHandleDeadProcessGroup_RestartPolicy(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	healthConfig := newTestHealthConfig(t)
+	shutdownConfig := newTestShutdownConfig(t)
+
+	tests := []struct {
+		name         string
+		restartYAML  string
+		wantState    types.ProcessState
+		wantGaveUp   string
+		code         int
+		pgid         int
+		wantRestarts bool
+	}{
+		{name: "never-svc", restartYAML: "restart: never\n", code: 1, pgid: 888891, wantState: types.ProcessStateFailed, wantGaveUp: "exited with code 1; not restarting (restart: never)"},
+		{name: "never-forced-svc", restartYAML: "restart: never\nrestart_exit_codes: [75]\n", code: 75, pgid: 888892, wantState: types.ProcessStateFailed, wantRestarts: true},
+		{name: "always-svc", restartYAML: "restart: always\n", code: 0, pgid: 888893, wantState: types.ProcessStateFailed, wantRestarts: true},
+		{name: "success-codes-svc", restartYAML: "success_exit_codes: [143]\n", code: 143, pgid: 888894, wantState: types.ProcessStateStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedRestartPolicyService(t, db, tt.name, tt.pgid, tt.restartYAML)
+			instance, err := db.GetServiceInstance(t.Context(), tt.name)
+			if err != nil {
+				t.Fatalf("GetServiceInstance failed: %v", err)
+			}
+			mgr := &exitCodeManager{monitorManager: realMgr, code: tt.code, ok: true}
+			hm := NewHealthMonitor(mgr, db, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
+
+			hm.handleDeadProcessGroup(t.Context(), tt.pgid, tt.name, &instance, slog.LevelError, func() (string, string) {
+				return "[" + tt.name + "] is not running", ""
+			})
+
+			entry, err := db.GetProcessHistoryEntryByPGID(t.Context(), tt.pgid)
+			if err != nil {
+				t.Fatalf("GetProcessHistoryEntryByPGID failed: %v", err)
+			}
+			if entry.State != tt.wantState {
+				t.Errorf("state = %s, want %s", entry.State, tt.wantState)
+			}
+			updated, err := db.GetServiceInstance(t.Context(), tt.name)
+			if err != nil {
+				t.Fatalf("GetServiceInstance failed: %v", err)
+			}
+			if tt.wantGaveUp == "" && updated.GaveUpReason != "" {
+				t.Errorf("gave up with %q, want the service left to restart", updated.GaveUpReason)
+			}
+			if tt.wantGaveUp != "" && (!strings.Contains(updated.GaveUpReason, tt.wantGaveUp) || entry.Error == nil || *entry.Error != updated.GaveUpReason) {
+				t.Errorf("gave up with %q (row error %v), want %q on both", updated.GaveUpReason, entry.Error, tt.wantGaveUp)
+			}
+			if tt.wantState != types.ProcessStateFailed {
+				return
+			}
+
+			counter := &restartCallCountManager{monitorManager: realMgr}
+			hm = NewHealthMonitor(counter, db, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
+			config, err := manager.LoadServiceConfig(filepath.Join(mustCatalogDir(t, db, tt.name), "service.yaml"))
+			if err != nil {
+				t.Fatalf("LoadServiceConfig failed: %v", err)
+			}
+			longAgo := time.Now().Add(-time.Hour)
+			entry.StoppedAt = &longAgo
+			hm.hmAttemptFailedRestart(t.Context(), &types.ServiceCatalogEntry{Name: tt.name}, &entry, &updated, config)
+			if got := counter.calls == 1; got != tt.wantRestarts {
+				t.Errorf("restarted = %v, want %v", got, tt.wantRestarts)
+			}
+		})
+	}
+}
+
+func mustCatalogDir(t *testing.T, db *database.DB, name string) string {
+	t.Helper()
+	service, err := db.GetServiceCatalogEntry(t.Context(), name)
+	if err != nil {
+		t.Fatalf("GetServiceCatalogEntry failed: %v", err)
+	}
+	return service.DirectoryPath
+}
+
+// TestHmAttemptFailedRestart_GivesUpAtMaxRestarts covers max_restarts within
+// restart_window: the restarts inside the window go ahead, the next one gives
+// up for good, and eos run's fresh instance row clears the give-up.
+func TestHmAttemptFailedRestart_GivesUpAtMaxRestarts(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	realMgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(realMgr.WaitPipes)
+	healthConfig := newTestHealthConfig(t, WithBackoff(1, 1))
+	shutdownConfig := newTestShutdownConfig(t)
+
+	const serviceName = "limited-svc"
+	const pgid = 888895
+	seedRestartPolicyService(t, db, serviceName, pgid, "max_restarts: 2\nrestart_window: 1h\n")
+	counter := &restartCallCountManager{monitorManager: realMgr}
+	hm := NewHealthMonitor(counter, db, testutil.NewTestLogger(t), healthConfig, *shutdownConfig, otelx.NoopHandles())
+	service := &types.ServiceCatalogEntry{Name: serviceName}
+	config := &types.ServiceConfig{Name: serviceName, MaxRestarts: 2, RestartWindow: "1h"}
+	longAgo := time.Now().Add(-time.Hour)
+	process := &types.ProcessHistory{PGID: pgid, StoppedAt: &longAgo}
+
+	for range 3 {
+		instance, err := db.GetServiceInstance(t.Context(), serviceName)
+		if err != nil {
+			t.Fatalf("GetServiceInstance failed: %v", err)
+		}
+		hm.hmAttemptFailedRestart(t.Context(), service, process, &instance, config)
+	}
+	if counter.calls != 2 {
+		t.Errorf("RestartService calls = %d, want 2", counter.calls)
+	}
+	instance, err := db.GetServiceInstance(t.Context(), serviceName)
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+	if !strings.Contains(instance.GaveUpReason, "gave up after 2 restarts within 1h0m0s") {
+		t.Errorf("GaveUpReason = %q, want the max_restarts give-up", instance.GaveUpReason)
+	}
+
+	hm.hmAttemptFailedRestart(t.Context(), service, process, &instance, config)
+	if counter.calls != 2 {
+		t.Errorf("a given-up service was restarted again (%d calls)", counter.calls)
+	}
+
+	if err := db.RegisterServiceInstance(t.Context(), serviceName); err != nil {
+		t.Fatalf("RegisterServiceInstance failed: %v", err)
+	}
+	instance, err = db.GetServiceInstance(t.Context(), serviceName)
+	if err != nil {
+		t.Fatalf("GetServiceInstance failed: %v", err)
+	}
+	if instance.GaveUpReason != "" || instance.RestartWindowCount != 0 || instance.RestartWindowStart != nil {
+		t.Errorf("re-registered instance = %+v, want the give-up and restart window cleared", instance)
+	}
+}
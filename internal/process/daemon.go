@@ -22,14 +22,17 @@ import (
 	"time"
 
 	"github.com/Elysium-Labs-EU/eos/internal/buildinfo"
+	"github.com/Elysium-Labs-EU/eos/internal/cgroup"
 	"github.com/Elysium-Labs-EU/eos/internal/config"
 	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/logutil"
 	"github.com/Elysium-Labs-EU/eos/internal/manager"
 	"github.com/Elysium-Labs-EU/eos/internal/monitor"
 	"github.com/Elysium-Labs-EU/eos/internal/otelx"
 	"github.com/Elysium-Labs-EU/eos/internal/ownership"
 	"github.com/Elysium-Labs-EU/eos/internal/procutil"
 	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
 )
 
 type daemon struct {
@@ -38,12 +41,20 @@ type daemon struct {
 	logger       *slog.Logger
 	db           *database.DB
 	mgr          *manager.LocalManager
+	cgroups      *cgroup.Hierarchy
 	otelProvider *otelx.Provider
 	otelHandles  *otelx.Handles
 	stop         context.CancelFunc
 	sigChan      chan os.Signal
-	pidFile      string
-	socketPath   string
+	// held are the children handleSIGCHLDRequest leaves to their own Wait,
+	// shared with mgr (see manager.WithHeldChildren).
+	held       *procutil.HeldChildren
+	pidFile    string
+	socketPath string
+	// termChan receives SIGTERM and SIGINT in PID-1 mode, where they stop the
+	// services one by one (see waitPID1) rather than canceling ctx; nil
+	// otherwise.
+	termChan chan os.Signal
 }
 
 // otelShutdownTimeout bounds how long daemon shutdown waits for the OTel SDK
@@ -58,14 +69,24 @@ const otelShutdownTimeout = 3 * time.Second
 // from the *config.* parameters, which stay as their own arguments since
 // they're already-assembled config structs rather than loose scalars.
 type StandaloneDaemonStartOptions struct {
-	BaseDir             string
+	BaseDir string
+	// ServicesDir is the drop-in services directory the daemon registers
+	// services from and watches (see servicesDirWatcher). Empty disables it.
+	ServicesDir         string
 	LogToFileAndConsole bool
 	Verbose             bool
 	UnderSystemd        bool
+	// PID1 runs the daemon as a container's entrypoint: it logs to stdout,
+	// becomes the child subreaper of its services, boots the persisted ones,
+	// and exits once they're all down (see waitPID1).
+	PID1 bool
+	// Identity is the one the daemon runs as, from userutil.ResolveIdentity;
+	// a service's user:/group: is judged against it (see manager.WithIdentity).
+	Identity userutil.Identity
 }
 
 func StartStandaloneDaemon(ctx context.Context, opts StandaloneDaemonStartOptions, standaloneDaemonConfig *config.StandaloneDaemonConfig, healthConfig *config.HealthConfig, shutdownConfig config.ShutdownConfig, telemetryConfig config.TelemetryConfig) error {
-	d, err := newStandaloneDaemon(ctx, opts.LogToFileAndConsole, opts.Verbose, opts.BaseDir, standaloneDaemonConfig, shutdownConfig, telemetryConfig)
+	d, err := newStandaloneDaemon(ctx, opts.LogToFileAndConsole, opts.Verbose, opts.PID1, opts.BaseDir, opts.Identity, standaloneDaemonConfig, shutdownConfig, telemetryConfig)
 	if err != nil {
 		return err
 	}
@@ -76,7 +97,7 @@ func StartStandaloneDaemon(ctx context.Context, opts StandaloneDaemonStartOption
 	}
 
 	reconcileCtx, reconcileSpan := d.otelHandles.Tracer.Start(ctx, "eos.daemon.reconcile_orphans")
-	reconcileOrphans(reconcileCtx, d.db, d.logger)
+	reconcileOrphans(reconcileCtx, d.db, d.cgroups, d.logger)
 	reconcileSpan.End()
 
 	// Bring the health monitor up before recovering persisted services: the
@@ -85,14 +106,21 @@ func StartStandaloneDaemon(ctx context.Context, opts StandaloneDaemonStartOption
 	// never be observed ready and every dependent would stall to max_wait.
 	d.serve(healthConfig, shutdownConfig)
 
-	if opts.UnderSystemd {
+	if opts.UnderSystemd || opts.PID1 {
 		if err := d.recover(); err != nil {
 			return err
 		}
 	}
 
+	if opts.ServicesDir != "" {
+		go newServicesDirWatcher(d.mgr, d.logger, opts.ServicesDir, shutdownConfig.GracePeriod).run(d.ctx)
+	}
+
 	d.logger.Info("daemon started successfully")
 
+	if opts.PID1 {
+		return d.waitPID1(shutdownConfig.GracePeriod)
+	}
 	d.wait()
 	return nil
 }
@@ -160,6 +188,19 @@ func bootService(ctx context.Context, mgr *manager.LocalManager, logger *slog.Lo
 	}
 }
 
+// reconcileCgroupLaunches SIGKILLs every populated launch leaf under service
+// through cgroup.kill, then prunes the emptied leaves. The history rows those
+// launches belong to are corrected by reconcileOrphans's own row walk right
+// after: their processes now read as dead, so they're marked Stopped there.
+func reconcileCgroupLaunches(cgroups cgroup.Hierarchy, service string, logger *slog.Logger) {
+	for _, pgid := range cgroup.LiveLaunches(cgroups, service) {
+		if killErr := cgroup.Signal(cgroup.LaunchDir(cgroups, service, pgid), syscall.SIGKILL); killErr != nil {
+			logger.Info("reconcile orphans: kill cgroup", "service", service, "pgid", pgid, "error", killErr)
+		}
+	}
+	cgroup.Prune(cgroups, service)
+}
+
 // reconcileOrphans runs once at daemon startup and checks every known PGID
 // for every service against the real OS process table, regardless of what
 // the DB's last-known state for that row says. A row recorded Stopped/Failed
@@ -174,7 +215,13 @@ func bootService(ctx context.Context, mgr *manager.LocalManager, logger *slog.Lo
 // starting — so every such row is guaranteed stale, rather than waiting out
 // manager.DependencyWaitStaleAfter for GetDependencyWaitStatus's own
 // self-heal to catch up.
-func reconcileOrphans(ctx context.Context, db *database.DB, logger *slog.Logger) {
+//
+// With cgroup tracking (cgroups non-nil), every still-populated launch leaf
+// under a service is killed first, whether or not a history row names it:
+// anything alive in eos.slice at boot was launched by the previous daemon,
+// including a worker that detached from its process group and would be
+// invisible to the PGID checks below.
+func reconcileOrphans(ctx context.Context, db *database.DB, cgroups *cgroup.Hierarchy, logger *slog.Logger) {
 	if clearErr := db.ClearAllDependencyWaits(ctx); clearErr != nil {
 		logger.Error("reconcile orphans: clearing stale dependency waits", "error", clearErr)
 	}
@@ -186,6 +233,10 @@ func reconcileOrphans(ctx context.Context, db *database.DB, logger *slog.Logger)
 	}
 
 	for _, entry := range entries {
+		if cgroups != nil {
+			reconcileCgroupLaunches(*cgroups, entry.Name, logger)
+		}
+
 		history, err := db.GetProcessHistoryEntriesByServiceName(ctx, entry.Name)
 		if err != nil {
 			logger.Error("reconcile orphans: fetching history", "service", entry.Name, "error", err)
@@ -250,8 +301,9 @@ func reconcileMarkStopped(ctx context.Context, db *database.DB, logger *slog.Log
 // same cancellation is what triggers each process's graceful stop: the
 // LocalManager built for this daemon is configured with a non-zero
 // shutdownGracePeriod (see WithShutdownGracePeriod), so every launched cmd.Cmd
-// has cmd.Cancel set to SIGTERM the process group and cmd.WaitDelay set to the
-// grace period — Go's exec runtime enforces signal-then-wait-then-kill on
+// has cmd.Cancel set to send the service's stop signal (SIGTERM to the process
+// group unless its stop_signal/kill_mode say otherwise) and cmd.WaitDelay set
+// to its stop_timeout, or the grace period — Go's exec runtime enforces signal-then-wait-then-kill on
 // context cancellation independent of this function, with no DB/manager call
 // needed here (issue #93: d.mgr's own ctx is this same d.ctx, so any manager
 // call made after d.stop() would itself see a canceled context and fail).
@@ -352,15 +404,22 @@ func removeExistingSocket(socketPath string, logger *slog.Logger) error {
 	return nil
 }
 
-func newStandaloneDaemon(ctx context.Context, logToFileAndConsole bool, verbose bool, baseDir string, standaloneDaemonConfig *config.StandaloneDaemonConfig, shutdownConfig config.ShutdownConfig, telemetryConfig config.TelemetryConfig) (*daemon, error) {
+func newStandaloneDaemon(ctx context.Context, logToFileAndConsole bool, verbose bool, pid1 bool, baseDir string, identity userutil.Identity, standaloneDaemonConfig *config.StandaloneDaemonConfig, shutdownConfig config.ShutdownConfig, telemetryConfig config.TelemetryConfig) (*daemon, error) {
 	startedAt := time.Now()
 
-	logger, err := manager.NewDaemonLogger(baseDir, logToFileAndConsole, verbose, standaloneDaemonConfig.Log.LogDir, standaloneDaemonConfig.Log.LogFileName, standaloneDaemonConfig.Log.LogMaxFiles, config.DaemonLogFileSizeLimit)
+	logger, err := newStandaloneDaemonLogger(pid1, logToFileAndConsole, verbose, baseDir, standaloneDaemonConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup daemon logger: %w", err)
 	}
 
 	logger.Info("daemon logger started")
+	if pid1 {
+		if subreaperErr := procutil.SetChildSubreaper(); subreaperErr != nil {
+			errorMessage := fmt.Errorf("failed to become child subreaper: %w", subreaperErr)
+			logger.Info(errorMessage.Error())
+			return nil, errorMessage
+		}
+	}
 	pidFile := standaloneDaemonConfig.PIDFile
 	socketPath := standaloneDaemonConfig.SocketPath
 
@@ -394,7 +453,7 @@ func newStandaloneDaemon(ctx context.Context, logToFileAndConsole bool, verbose
 	}
 	logger.Debug("PID written", "path", pidFile, "pid", myPID)
 
-	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	ctx, stop, termChan := daemonSignalContext(ctx, pid1)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGCHLD)
@@ -429,7 +488,28 @@ func newStandaloneDaemon(ctx context.Context, logToFileAndConsole bool, verbose
 	}
 	logger.Debug("database connected")
 
-	tel, err := setupDaemonTelemetry(ctx, telemetryConfig, shutdownConfig, db, baseDir, logger, startedAt)
+	// Outside PID-1 mode the daemon becomes the child subreaper only for a
+	// catalog with a type: forking service, whose main process must be
+	// reparented to it to be waited for (see manager.forkingWaitMain); other
+	// services' orphans keep going to PID 1. The decision is made once, here.
+	if !pid1 && catalogHasForkingService(ctx, db, logger) {
+		if subreaperErr := procutil.SetChildSubreaper(); subreaperErr != nil {
+			logger.Warn("becoming child subreaper, forked main processes won't be reaped by eos", "error", subreaperErr)
+		}
+	}
+
+	// cgroup v2 tracking is an upgrade over process-group tracking, not a
+	// requirement: a host without a delegated subtree keeps running services
+	// exactly as before, so a detection failure is reported, not fatal.
+	cgroups, cgroupErr := cgroup.Detect()
+	if cgroupErr != nil {
+		logger.Info("cgroup tracking unavailable, tracking services by process group", "reason", cgroupErr)
+	} else {
+		logger.Debug("tracking services by cgroup", "slice", cgroups.Slice)
+	}
+
+	held := procutil.NewHeldChildren()
+	tel, err := setupDaemonTelemetry(ctx, telemetryConfig, shutdownConfig, db, cgroups, held, identity, baseDir, logger, startedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -438,17 +518,64 @@ func newStandaloneDaemon(ctx context.Context, logToFileAndConsole bool, verbose
 		logger:       logger,
 		db:           db,
 		mgr:          tel.mgr,
+		cgroups:      cgroups,
 		otelProvider: tel.provider,
 		otelHandles:  tel.handles,
 		listener:     listener,
 		ctx:          ctx,
 		stop:         stop,
 		sigChan:      sigChan,
+		held:         held,
+		termChan:     termChan,
 		pidFile:      pidFile,
 		socketPath:   socketPath,
 	}, nil
 }
 
+// catalogHasForkingService reports whether any registered service is
+// type: forking. A catalog or service config that can't be read counts as
+// none: the daemon only loses the main process's exit code by it.
+func catalogHasForkingService(ctx context.Context, db *database.DB, logger *slog.Logger) bool {
+	entries, err := db.GetAllServiceCatalogEntries(ctx)
+	if err != nil {
+		logger.Debug("listing service catalog for type: forking", "error", err)
+		return false
+	}
+	for _, entry := range entries {
+		cfg, cfgErr := manager.LoadServiceConfig(filepath.Join(entry.DirectoryPath, entry.ConfigFileName))
+		if cfgErr == nil && manager.ServiceType(cfg.Type) == manager.ServiceTypeForking {
+			return true
+		}
+	}
+	return false
+}
+
+// newStandaloneDaemonLogger logs to stdout in PID-1 mode, where the
+// container runtime collects it, and to the rotating daemon.log otherwise.
+func newStandaloneDaemonLogger(pid1 bool, logToFileAndConsole bool, verbose bool, baseDir string, standaloneDaemonConfig *config.StandaloneDaemonConfig) (*slog.Logger, error) {
+	if pid1 {
+		return logutil.NewJSONLogger(os.Stdout, verbose), nil
+	}
+	return manager.NewDaemonLogger(baseDir, logToFileAndConsole, verbose, standaloneDaemonConfig.Log.LogDir, standaloneDaemonConfig.Log.LogFileName, standaloneDaemonConfig.Log.LogMaxFiles, config.DaemonLogFileSizeLimit)
+}
+
+// daemonSignalContext derives the daemon's context. SIGTERM and SIGINT
+// cancel it, which stops every service at once; in PID-1 mode they go to the
+// returned channel instead, for waitPID1 to stop the services in order.
+func daemonSignalContext(ctx context.Context, pid1 bool) (context.Context, context.CancelFunc, chan os.Signal) {
+	if !pid1 {
+		ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+		return ctx, stop, nil
+	}
+	termChan := make(chan os.Signal, 1)
+	signal.Notify(termChan, syscall.SIGTERM, syscall.SIGINT)
+	ctx, stop := context.WithCancel(ctx)
+	return ctx, func() {
+		signal.Stop(termChan)
+		stop()
+	}, termChan
+}
+
 // daemonTelemetry bundles the pieces setupDaemonTelemetry assembles: the
 // OTel provider (real or no-op), the instrument handles built from it, and
 // the LocalManager wired to record through them.
@@ -467,7 +594,7 @@ type daemonTelemetry struct {
 // services, so a construction failure on the real provider falls back to the
 // disabled (no-op) one — cfg.Enable false, which otelx.NewProvider never
 // errors on — rather than failing daemon startup.
-func setupDaemonTelemetry(ctx context.Context, telemetryConfig config.TelemetryConfig, shutdownConfig config.ShutdownConfig, db *database.DB, baseDir string, logger *slog.Logger, startedAt time.Time) (daemonTelemetry, error) {
+func setupDaemonTelemetry(ctx context.Context, telemetryConfig config.TelemetryConfig, shutdownConfig config.ShutdownConfig, db *database.DB, cgroups *cgroup.Hierarchy, held *procutil.HeldChildren, identity userutil.Identity, baseDir string, logger *slog.Logger, startedAt time.Time) (daemonTelemetry, error) {
 	otelx.SetErrorHandler(logger)
 
 	otelProvider, err := otelx.NewProvider(ctx, otelx.Config{
@@ -488,7 +615,7 @@ func setupDaemonTelemetry(ctx context.Context, telemetryConfig config.TelemetryC
 		return daemonTelemetry{}, fmt.Errorf("failed to set up telemetry instruments: %w", err)
 	}
 
-	mgr := manager.NewLocalManager(db, baseDir, ctx, logger, manager.WithTelemetry(otelHandles), manager.WithShutdownGracePeriod(shutdownConfig.GracePeriod))
+	mgr := manager.NewLocalManager(db, baseDir, ctx, logger, manager.WithTelemetry(otelHandles), manager.WithShutdownGracePeriod(shutdownConfig.GracePeriod), manager.WithCgroups(cgroups), manager.WithHeldChildren(held), manager.WithIdentity(identity))
 
 	if regErr := otelx.RegisterDaemonGauges(otelProvider.MeterProvider, startedAt,
 		func(gaugeCtx context.Context) int { return len(catalogEntriesOrEmpty(gaugeCtx, mgr, logger)) },
@@ -528,7 +655,7 @@ func (d *daemon) wait() {
 		select {
 		case sig := <-d.sigChan:
 			if sig == syscall.SIGCHLD {
-				handleSIGCHLDRequest(d.ctx, d.db, d.logger)
+				handleSIGCHLDRequest(d.ctx, d.db, d.logger, d.held)
 			}
 		case <-d.ctx.Done():
 			return
@@ -595,6 +722,15 @@ func handleReapedChild(ctx context.Context, db *database.DB, logger *slog.Logger
 		return reapContinue
 	}
 
+	if _, lookupErr := db.GetProcessHistoryEntryByPGID(ctx, pid); errors.Is(lookupErr, database.ErrProcessHistoryNotFound) {
+		// Not a launch of ours but a process reparented to the daemon as
+		// child subreaper, in PID-1 mode or for a type: forking service (see
+		// newStandaloneDaemon): reaping it is all there is to do, there's no
+		// history row to update.
+		logger.Debug("reaped adopted orphan", "pid", pid, "exit_status", status.ExitStatus())
+		return reapContinue
+	}
+
 	logger.Info(fmt.Sprintf("reaped zombie process: %d\n", pid))
 	if pgroupStillAlive(pid, logger) {
 		return reapContinue
@@ -604,10 +740,12 @@ func handleReapedChild(ctx context.Context, db *database.DB, logger *slog.Logger
 	return reapContinue
 }
 
-func handleSIGCHLDRequest(ctx context.Context, db *database.DB, logger *slog.Logger) {
+// handleSIGCHLDRequest drains exited children, leaving those in held to
+// their own Wait (see procutil.StartHeld).
+func handleSIGCHLDRequest(ctx context.Context, db *database.DB, logger *slog.Logger, held *procutil.HeldChildren) {
 	for {
 		var status syscall.WaitStatus
-		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		pid, err := procutil.ReapExited(held, &status)
 		if handleReapedChild(ctx, db, logger, pid, err, status) == reapStop {
 			break
 		}
@@ -1003,7 +1141,12 @@ var requestHandlers = map[types.MethodName]requestHandler{
 	types.MethodRestartService:         handleRestartService,
 	types.MethodStopService:            handleStopService,
 	types.MethodForceStopService:       handleForceStopService,
+	types.MethodStartServiceInstance:   handleStartServiceInstance,
+	types.MethodStopServiceInstance:    handleStopServiceInstance,
+	types.MethodRestartServiceInstance: handleRestartServiceInstance,
 	types.MethodReloadService:          handleReloadService,
+	types.MethodDeployService:          handleDeployService,
+	types.MethodRollbackService:        handleRollbackService,
 	types.MethodAddServiceCatalogEntry: handleAddServiceCatalogEntry,
 	types.MethodGetAllServiceCatalogEntries: func(ctx context.Context, mgr manager.ServiceManager, _ json.RawMessage) types.DaemonResponse {
 		return handleGetAllServiceCatalogEntries(ctx, mgr)
@@ -1015,9 +1158,17 @@ var requestHandlers = map[types.MethodName]requestHandler{
 	types.MethodSetServiceEnabled:                handleSetServiceEnabled,
 	types.MethodGetMostRecentProcessHistoryEntry: handleGetMostRecentProcessHistoryEntry,
 	types.MethodGetLiveOrphanProcessGroups:       handleGetLiveOrphanProcessGroups,
+	types.MethodGetInstanceProcessHistoryEntries: handleGetInstanceProcessHistoryEntries,
 	types.MethodSetDependencyWaitStatus:          handleSetDependencyWaitStatus,
 	types.MethodClearDependencyWaitStatus:        handleClearDependencyWaitStatus,
 	types.MethodGetDependencyWaitStatus:          handleGetDependencyWaitStatus,
+	types.MethodGetEffectiveLimits:               handleGetEffectiveLimits,
+	types.MethodGetNotifyStatus:                  handleGetNotifyStatus,
+	types.MethodGetProxyStatus:                   handleGetProxyStatus,
+	types.MethodGetCanaryResult:                  handleGetCanaryResult,
+	types.MethodGetReleases:                      handleGetReleases,
+	types.MethodGetConfigDrift:                   handleGetConfigDrift,
+	types.MethodResolveServiceEnvironment:        handleResolveServiceEnvironment,
 	types.MethodNewServiceLogFiles:               handleNewServiceLogFiles,
 	types.MethodGetServiceLogFilePath:            handleGetServiceLogFilePath,
 	types.MethodGetVersion: func(ctx context.Context, mgr manager.ServiceManager, _ json.RawMessage) types.DaemonResponse {
@@ -1202,6 +1353,100 @@ func handleForceStopService(ctx context.Context, mgr manager.ServiceManager, raw
 	}
 }
 
+// instanceController is the slice of a manager the 3 instance handlers
+// below need. Only *manager.LocalManager implements it: instances: is
+// launched by the daemon, one process group per instance.
+type instanceController interface {
+	StartServiceInstance(ctx context.Context, name string, index int) (int, error)
+	StopServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (manager.StopServiceResult, error)
+	RestartServiceInstance(ctx context.Context, name string, index int, gracePeriod time.Duration, tickerPeriod time.Duration) (int, error)
+}
+
+func handleStartServiceInstance(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	controller, ok := mgr.(instanceController)
+	if !ok {
+		return errorResponse("instances not supported by this manager")
+	}
+	var args types.StartServiceInstanceArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodStartServiceInstance args: %v", err))
+	}
+	pid, err := controller.StartServiceInstance(ctx, args.Name, args.Instance)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	data, err := json.Marshal(map[string]int{"pid": pid})
+	if err != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", err))
+	}
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
+func handleStopServiceInstance(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	controller, ok := mgr.(instanceController)
+	if !ok {
+		return errorResponse("instances not supported by this manager")
+	}
+	var args types.StopServiceInstanceArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodStopServiceInstance args: %v", err))
+	}
+	gracePeriod, err := time.ParseDuration(args.GracePeriod)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("invalid grace period: %s", args.GracePeriod))
+	}
+	tickerPeriod, err := time.ParseDuration(args.TickerPeriod)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("invalid ticker period: %s", args.TickerPeriod))
+	}
+	result, err := controller.StopServiceInstance(ctx, args.Name, args.Instance, gracePeriod, tickerPeriod)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", err))
+	}
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
+func handleRestartServiceInstance(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	controller, ok := mgr.(instanceController)
+	if !ok {
+		return errorResponse("instances not supported by this manager")
+	}
+	var args types.RestartServiceInstanceArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodRestartServiceInstance args: %v", err))
+	}
+	gracePeriod, err := time.ParseDuration(args.GracePeriod)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("invalid grace period: %s", args.GracePeriod))
+	}
+	tickerPeriod, err := time.ParseDuration(args.TickerPeriod)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("invalid ticker period: %s", args.TickerPeriod))
+	}
+	pid, err := controller.RestartServiceInstance(ctx, args.Name, args.Instance, gracePeriod, tickerPeriod)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	data, err := json.Marshal(map[string]int{"pid": pid})
+	if err != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", err))
+	}
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
 // handleReloadService drives a zero-downtime cutover. Reload is not part of the
 // ServiceManager interface — its readiness gate needs the monitor package, which
 // imports manager — so it runs only against the concrete LocalManager the daemon
@@ -1217,35 +1462,124 @@ func handleReloadService(ctx context.Context, mgr manager.ServiceManager, rawArg
 	if err := json.Unmarshal(rawArgs, &args); err != nil {
 		return errorResponse("invalid MethodReloadService args")
 	}
+	cfg, err := reloadConfigFromArgs(args)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+
+	result, err := lm.ReloadService(args.Name, monitor.ProbeReady, cfg)
+	data, marshalErr := json.Marshal(reloadResponseFrom(result))
+	if err != nil {
+		response := sentinelErrorResponse(err)
+		if marshalErr == nil && (len(result.Instances) > 0 || result.Canary != nil) {
+			response.Data = data
+		}
+		return response
+	}
+	if marshalErr != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", marshalErr))
+	}
+	return types.DaemonResponse{Success: true, Data: data}
+}
+
+// reloadConfigFromArgs parses the durations of a reload's wire args, shared
+// by reload, deploy and rollback.
+func reloadConfigFromArgs(args types.ReloadServiceArgs) (manager.ReloadConfig, error) {
 	gracePeriod, err := time.ParseDuration(args.GracePeriod)
 	if err != nil {
-		return errorResponse(fmt.Sprintf("invalid grace period: %s", args.GracePeriod))
+		return manager.ReloadConfig{}, fmt.Errorf("invalid grace period: %s", args.GracePeriod)
 	}
 	tickerPeriod, err := time.ParseDuration(args.TickerPeriod)
 	if err != nil {
-		return errorResponse(fmt.Sprintf("invalid ticker period: %s", args.TickerPeriod))
+		return manager.ReloadConfig{}, fmt.Errorf("invalid ticker period: %s", args.TickerPeriod)
 	}
 	readinessTimeout, err := time.ParseDuration(args.ReadinessTimeout)
 	if err != nil {
-		return errorResponse(fmt.Sprintf("invalid readiness timeout: %s", args.ReadinessTimeout))
+		return manager.ReloadConfig{}, fmt.Errorf("invalid readiness timeout: %s", args.ReadinessTimeout)
 	}
 	probeInterval, err := time.ParseDuration(args.ProbeInterval)
 	if err != nil {
-		return errorResponse(fmt.Sprintf("invalid probe interval: %s", args.ProbeInterval))
+		return manager.ReloadConfig{}, fmt.Errorf("invalid probe interval: %s", args.ProbeInterval)
 	}
-
-	result, err := lm.ReloadService(args.Name, monitor.ProbeReady, manager.ReloadConfig{
+	var canary time.Duration
+	if args.Canary != "" {
+		if canary, err = time.ParseDuration(args.Canary); err != nil {
+			return manager.ReloadConfig{}, fmt.Errorf("invalid canary window: %s", args.Canary)
+		}
+	}
+	return manager.ReloadConfig{
 		GracePeriod:      gracePeriod,
 		TickerPeriod:     tickerPeriod,
 		ReadinessTimeout: readinessTimeout,
 		ProbeInterval:    probeInterval,
-	})
+		Canary:           canary,
+	}, nil
+}
+
+func reloadResponseFrom(result manager.ReloadResult) types.ReloadServiceResponse {
+	return types.ReloadServiceResponse{
+		Instances:  result.Instances,
+		OldPGID:    result.OldPGID,
+		NewPGID:    result.NewPGID,
+		Steps:      result.Steps,
+		FailedStep: result.FailedStep,
+		Canary:     result.Canary,
+	}
+}
+
+func handleDeployService(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	lm, ok := mgr.(*manager.LocalManager)
+	if !ok {
+		return errorResponse("deploy is only supported by the standalone daemon")
+	}
+	var args types.DeployServiceArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodDeployService args: %v", err))
+	}
+	cfg, err := reloadConfigFromArgs(args.ReloadServiceArgs)
 	if err != nil {
-		return sentinelErrorResponse(err)
+		return errorResponse(err.Error())
 	}
-	data, err := json.Marshal(types.ReloadServiceResponse{OldPGID: result.OldPGID, NewPGID: result.NewPGID})
+	result, err := lm.DeployService(args.Name, args.Artifact, monitor.ProbeReady, manager.DeployConfig{ReloadConfig: cfg, Keep: args.Keep})
+	return deployResponse(result, err)
+}
+
+func handleRollbackService(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	lm, ok := mgr.(*manager.LocalManager)
+	if !ok {
+		return errorResponse("rollback is only supported by the standalone daemon")
+	}
+	var args types.RollbackServiceArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodRollbackService args: %v", err))
+	}
+	cfg, err := reloadConfigFromArgs(args.ReloadServiceArgs)
 	if err != nil {
-		return errorResponse(fmt.Sprintf("marshaling response: %v", err))
+		return errorResponse(err.Error())
+	}
+	result, err := lm.RollbackService(args.Name, args.Release, monitor.ProbeReady, cfg)
+	return deployResponse(result, err)
+}
+
+// deployResponse marshals a deploy's or rollback's result, attaching it to
+// the error of one that got as far as unpacking a release.
+func deployResponse(result manager.DeployResult, err error) types.DaemonResponse {
+	data, marshalErr := json.Marshal(types.DeployServiceResponse{
+		Release:    result.Release,
+		Previous:   result.Previous,
+		Activation: result.Activation,
+		Reload:     reloadResponseFrom(result.Reload),
+		Pruned:     result.Pruned,
+	})
+	if err != nil {
+		response := sentinelErrorResponse(err)
+		if marshalErr == nil && result.Release.Name != "" {
+			response.Data = data
+		}
+		return response
+	}
+	if marshalErr != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", marshalErr))
 	}
 	return types.DaemonResponse{Success: true, Data: data}
 }
@@ -1402,6 +1736,38 @@ func handleGetLiveOrphanProcessGroups(ctx context.Context, mgr manager.ServiceMa
 	}
 }
 
+// instanceHistoryReader is the slice of a manager
+// handleGetInstanceProcessHistoryEntries needs.
+type instanceHistoryReader interface {
+	GetInstanceProcessHistoryEntries(ctx context.Context, name string) ([]types.ProcessHistory, error)
+}
+
+func handleGetInstanceProcessHistoryEntries(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(instanceHistoryReader)
+	if !ok {
+		return errorResponse("instances not supported by this manager")
+	}
+	var args types.GetInstanceProcessHistoryEntriesArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetInstanceProcessHistoryEntries args: %v", err))
+	}
+	entries, err := reader.GetInstanceProcessHistoryEntries(ctx, args.Name)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	data, err := json.Marshal(types.GetInstanceProcessHistoryEntriesResponse{
+		Entries: entries,
+	})
+	if err != nil {
+		return errorResponse(fmt.Sprintf("marshaling response: %v", err))
+	}
+
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
 // dependencyWaitStatusStore is the slice of a manager the 3 handlers below
 // need: recording, clearing, and reading a service's current depends_on wait.
 // Only *manager.LocalManager implements it (see local_manager.go) — every mgr
@@ -1533,3 +1899,188 @@ func sendErrorResponse(conn net.Conn, message string, logger *slog.Logger) {
 		logClientWriteError(logger, "sending error response", err)
 	}
 }
+
+// effectiveLimitsReader is the slice of a manager handleGetEffectiveLimits
+// needs. Like dependencyWaitStatusStore, only *manager.LocalManager
+// implements it: reading a launch's cgroup leaf and rlimits takes the
+// daemon's own view of the process, which no other manager has.
+type effectiveLimitsReader interface {
+	GetEffectiveLimits(ctx context.Context, name string) (types.EffectiveLimits, error)
+}
+
+func handleGetEffectiveLimits(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(effectiveLimitsReader)
+	if !ok {
+		return errorResponse("effective limits not supported by this manager")
+	}
+	var args types.GetEffectiveLimitsArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetEffectiveLimits args: %v", err))
+	}
+	limits, err := reader.GetEffectiveLimits(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// limits is strings/int/bool only: nothing here can fail to marshal.
+	data, _ := json.Marshal(limits)
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
+// notifyStatusReader is the slice of a manager handleGetNotifyStatus needs.
+// Like effectiveLimitsReader, only *manager.LocalManager implements it: a
+// launch's notify socket lives in the daemon that started it.
+type notifyStatusReader interface {
+	GetNotifyStatus(ctx context.Context, name string) (types.NotifyStatus, error)
+}
+
+func handleGetNotifyStatus(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(notifyStatusReader)
+	if !ok {
+		return errorResponse("notify status not supported by this manager")
+	}
+	var args types.GetNotifyStatusArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetNotifyStatus args: %v", err))
+	}
+	status, err := reader.GetNotifyStatus(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// status is strings/ints/bool/time only: nothing here can fail to marshal.
+	data, _ := json.Marshal(status)
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
+// proxyStatusReader is the slice of a manager handleGetProxyStatus needs.
+// Like notifyStatusReader, only *manager.LocalManager implements it: the
+// proxy lives in the daemon.
+type proxyStatusReader interface {
+	GetProxyStatus(ctx context.Context, name string) (types.ProxyStatus, error)
+}
+
+func handleGetProxyStatus(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(proxyStatusReader)
+	if !ok {
+		return errorResponse("proxy status not supported by this manager")
+	}
+	var args types.GetProxyStatusArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetProxyStatus args: %v", err))
+	}
+	status, err := reader.GetProxyStatus(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// status is ints/bools only: nothing here can fail to marshal.
+	data, _ := json.Marshal(status)
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
+
+// releaseLister is the slice of a manager handleGetReleases needs.
+type releaseLister interface {
+	GetReleases(ctx context.Context, name string) ([]types.Release, error)
+}
+
+func handleGetReleases(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	lister, ok := mgr.(releaseLister)
+	if !ok {
+		return errorResponse("releases not supported by this manager")
+	}
+	var args types.GetReleasesArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetReleases args: %v", err))
+	}
+	releases, err := lister.GetReleases(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	data, err := json.Marshal(releases)
+	if err != nil {
+		return errorResponse(fmt.Sprintf("marshaling releases: %v", err))
+	}
+	return types.DaemonResponse{Success: true, Data: data}
+}
+
+// configDriftReader is the slice of a manager handleGetConfigDrift needs.
+type configDriftReader interface {
+	GetConfigDrift(ctx context.Context, name string) (*types.ConfigDrift, error)
+}
+
+func handleGetConfigDrift(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(configDriftReader)
+	if !ok {
+		return errorResponse("config drift not supported by this manager")
+	}
+	var args types.GetConfigDriftArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetConfigDrift args: %v", err))
+	}
+	drift, err := reader.GetConfigDrift(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// drift is strings, ints and bools only, or nil when nothing is
+	// running: nothing here can fail to marshal.
+	data, _ := json.Marshal(drift)
+	return types.DaemonResponse{Success: true, Data: data}
+}
+
+// serviceEnvironmentResolver is the slice of a manager
+// handleResolveServiceEnvironment needs.
+type serviceEnvironmentResolver interface {
+	ResolveServiceEnvironment(ctx context.Context, name string) ([]manager.ResolvedEnvVar, error)
+}
+
+func handleResolveServiceEnvironment(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	resolver, ok := mgr.(serviceEnvironmentResolver)
+	if !ok {
+		return errorResponse("resolving a service's environment not supported by this manager")
+	}
+	var args types.ResolveServiceEnvironmentArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodResolveServiceEnvironment args: %v", err))
+	}
+	env, err := resolver.ResolveServiceEnvironment(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// env is strings only: nothing here can fail to marshal.
+	data, _ := json.Marshal(env)
+	return types.DaemonResponse{Success: true, Data: data}
+}
+
+// canaryResultReader is the slice of a manager handleGetCanaryResult needs.
+type canaryResultReader interface {
+	GetCanaryResult(ctx context.Context, name string) (*types.CanaryResult, error)
+}
+
+func handleGetCanaryResult(ctx context.Context, mgr manager.ServiceManager, rawArgs json.RawMessage) types.DaemonResponse {
+	reader, ok := mgr.(canaryResultReader)
+	if !ok {
+		return errorResponse("canary results not supported by this manager")
+	}
+	var args types.GetCanaryResultArgs
+	if err := json.Unmarshal(rawArgs, &args); err != nil {
+		return errorResponse(fmt.Sprintf("invalid MethodGetCanaryResult args: %v", err))
+	}
+	result, err := reader.GetCanaryResult(ctx, args.ServiceName)
+	if err != nil {
+		return sentinelErrorResponse(err)
+	}
+	// result is strings/ints/times only, or nil for a service never
+	// canaried: nothing here can fail to marshal.
+	data, _ := json.Marshal(result)
+	return types.DaemonResponse{
+		Success: true,
+		Data:    data,
+	}
+}
@@ -57,7 +57,7 @@ func TestBootService_NoDependencies(t *testing.T) {
 	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
-	entry := bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "solo", Command: "/bin/sleep 5"})
+	entry := bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "solo", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}})
 
 	bootService(t.Context(), mgr, testutil.NewTestLogger(t), &entry)
 
@@ -78,7 +78,7 @@ func TestBootService_WaitsThenStarts(t *testing.T) {
 	}
 
 	entry := bootTestService(t, mgr, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/sleep 5", DependsOn: []string{"proxy"}, MaxWait: "2s",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}, DependsOn: []string{"proxy"}, MaxWait: "2s",
 	})
 
 	bootService(t.Context(), mgr, testutil.NewTestLogger(t), &entry)
@@ -101,7 +101,7 @@ func TestBootService_UnmetDependencyNeverStarts(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	entry := writeBootTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/sleep 5", DependsOn: []string{"never-started"}, MaxWait: "150ms",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}, DependsOn: []string{"never-started"}, MaxWait: "150ms",
 	})
 
 	bootService(t.Context(), mgr, testutil.NewTestLogger(t), &entry)
@@ -121,7 +121,7 @@ func TestBootService_MaxWaitParseError(t *testing.T) {
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
 	entry := writeBootTestService(t, tempDir, &types.ServiceConfig{
-		Name: "web", Command: "/bin/sleep 5", DependsOn: []string{"proxy"}, MaxWait: "not-a-duration",
+		Name: "web", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}, DependsOn: []string{"proxy"}, MaxWait: "not-a-duration",
 	})
 
 	bootService(t.Context(), mgr, testutil.NewTestLogger(t), &entry)
@@ -149,8 +149,8 @@ func TestBootPersistedServices_SkipsDisabled(t *testing.T) {
 	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
 	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
 
-	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "kept-running", Command: "/bin/sleep 5"})
-	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "stopped-by-hand", Command: "/bin/sleep 5"})
+	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "kept-running", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}})
+	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "stopped-by-hand", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}})
 
 	if err := db.SetServiceCatalogEnabled(t.Context(), "stopped-by-hand", false); err != nil {
 		t.Fatalf("SetServiceCatalogEnabled: %v", err)
@@ -29,13 +29,13 @@ func newTestDaemonWithService(t *testing.T, gracePeriod time.Duration, serviceNa
 	standalone := daemonInitCfg(sockDir)
 	shutdownConfig := config.ShutdownConfig{GracePeriod: gracePeriod}
 
-	d, err := newStandaloneDaemon(t.Context(), false /* logToFileAndConsole */, false /* verbose */, dbDir, standalone, shutdownConfig, config.TelemetryConfig{})
+	d, err := newStandaloneDaemon(t.Context(), false /* logToFileAndConsole */, false /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), standalone, shutdownConfig, config.TelemetryConfig{})
 	if err != nil {
 		t.Fatalf("newStandaloneDaemon: %v", err)
 	}
 
 	serviceDir := t.TempDir()
-	serviceConfig := &types.ServiceConfig{Name: serviceName, Command: command}
+	serviceConfig := &types.ServiceConfig{Name: serviceName, Command: types.ServiceCommand{Shell: command}}
 	yamlData, err := yaml.Marshal(serviceConfig)
 	if err != nil {
 		t.Fatalf("marshal service config: %v", err)
@@ -75,7 +75,7 @@ func TestNewStandaloneDaemon_E2E_VerboseOn_WritesDebugLifecycleLogs(t *testing.T
 	ctx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 
-	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, true /* verbose */, dbDir, standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
+	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, true /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
 	if err != nil {
 		t.Fatalf("newStandaloneDaemon: %v", err)
 	}
@@ -114,7 +114,7 @@ func TestNewStandaloneDaemon_E2E_VerboseOff_NoDebugLifecycleLogs(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 
-	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, dbDir, standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
+	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
 	if err != nil {
 		t.Fatalf("newStandaloneDaemon: %v", err)
 	}
@@ -142,7 +142,7 @@ func TestNewStandaloneDaemon_E2E_SocketIsOwnerOnly(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 
-	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, dbDir, standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
+	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
 	if err != nil {
 		t.Fatalf("newStandaloneDaemon: %v", err)
 	}
@@ -196,7 +196,7 @@ func TestNewStandaloneDaemon_E2E_RootAlignsPIDFileAndLogOwnership(t *testing.T)
 	ctx, cancel := context.WithCancel(t.Context())
 	defer cancel()
 
-	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, dbDir, standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
+	d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), standalone, config.ShutdownConfig{}, config.TelemetryConfig{})
 	if err != nil {
 		t.Fatalf("newStandaloneDaemon: %v", err)
 	}
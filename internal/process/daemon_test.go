@@ -392,7 +392,7 @@ func TestHandleGetDependencyWaitStatus_storeError(t *testing.T) {
 
 func TestReconcileOrphans_Empty(t *testing.T) {
 	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
-	reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+	reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 }
 
 // TestReconcileOrphans_ClearsDependencyWaits proves a fresh daemon boot wipes
@@ -407,7 +407,7 @@ func TestReconcileOrphans_ClearsDependencyWaits(t *testing.T) {
 		t.Fatalf("SetDependencyWaitStatus: %v", err)
 	}
 
-	reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+	reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 	if _, waiting, err := db.GetDependencyWaitStatus(t.Context(), "web"); err != nil || waiting {
 		t.Errorf("expected reconcileOrphans to clear the stale wait, waiting=%v err=%v", waiting, err)
@@ -424,7 +424,7 @@ func TestReconcileOrphans_ClearAllDependencyWaitsErrorIsLogged(t *testing.T) {
 	}
 
 	logger, buf := capturingLogger()
-	reconcileOrphans(t.Context(), db, logger)
+	reconcileOrphans(t.Context(), db, nil, logger)
 
 	if !strings.Contains(buf.String(), "clearing stale dependency waits") {
 		t.Errorf("expected the clear failure to be logged, got: %s", buf.String())
@@ -438,7 +438,7 @@ func TestReconcileOrphans_NoHistory(t *testing.T) {
 		t.Fatalf("RegisterService: %v", err)
 	}
 
-	reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+	reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 	_, err := db.GetMostRecentProcessHistoryEntryByName(t.Context(), "website")
 	if !errors.Is(err, database.ErrProcessHistoryNotFound) {
@@ -458,7 +458,7 @@ func TestReconcileOrphans_TerminalStates(t *testing.T) {
 				t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 			}
 
-			reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+			reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 			hist, err := db.GetMostRecentProcessHistoryEntryByName(t.Context(), "svc")
 			if err != nil {
@@ -506,7 +506,7 @@ func TestReconcileOrphans_TerminalStateButAlive(t *testing.T) {
 				t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 			}
 
-			reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+			reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 			hist, err := db.GetMostRecentProcessHistoryEntryByName(t.Context(), "svc")
 			if err != nil {
@@ -588,7 +588,7 @@ func TestReconcileOrphans_PGIDReuse(t *testing.T) {
 				t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 			}
 
-			reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+			reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 			// The core security assertion: the innocent process survives.
 			if !procutil.IsAlive(pgid) {
@@ -625,7 +625,7 @@ func TestReconcileOrphans_ActiveStates(t *testing.T) {
 				t.Fatalf("RegisterProcessHistoryEntry: %v", err)
 			}
 
-			reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+			reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 			hist, err := db.GetMostRecentProcessHistoryEntryByName(t.Context(), "svc")
 			if err != nil {
@@ -669,7 +669,7 @@ func TestReconcileOrphans_Mixed(t *testing.T) {
 		}
 	}
 
-	reconcileOrphans(t.Context(), db, testutil.NewTestLogger(t))
+	reconcileOrphans(t.Context(), db, nil, testutil.NewTestLogger(t))
 
 	for _, svc := range services {
 		hist, err := db.GetMostRecentProcessHistoryEntryByName(t.Context(), svc.name)
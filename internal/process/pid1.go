@@ -0,0 +1,141 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// pid1ExitCheckInterval is how often a daemon in PID-1 mode checks whether
+// every service is down for good, the point at which it exits.
+const pid1ExitCheckInterval = time.Second
+
+// ErrServicesFailed is what StartStandaloneDaemon returns in PID-1 mode when
+// it exits because every service is down and at least one of them failed,
+// so the container's exit code reports the failure.
+var ErrServicesFailed = errors.New("services failed")
+
+// waitPID1 is wait for a daemon in PID-1 mode. It reaps every child as wait
+// does, adopted orphans included, and returns:
+//   - nil once SIGTERM or SIGINT has stopped every service, in reverse
+//     dependency order (see stopServicesInReverseOrder);
+//   - nil once every service has exited cleanly;
+//   - ErrServicesFailed once every service is down and any of them failed.
+//
+// The services are stopped here rather than by d.stop()'s cancellation,
+// which would stop them all at once: d.ctx, and so d.mgr, has to still be
+// alive for the manager calls that stop them one at a time.
+func (d *daemon) waitPID1(gracePeriod time.Duration) error {
+	ticker := time.NewTicker(pid1ExitCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case sig := <-d.sigChan:
+			if sig == syscall.SIGCHLD {
+				handleSIGCHLDRequest(d.ctx, d.db, d.logger, d.held)
+			}
+		case sig := <-d.termChan:
+			d.logger.Info("received signal, stopping services", "signal", sig.String())
+			stopServicesInReverseOrder(d.ctx, d.mgr, d.logger, gracePeriod)
+			return nil
+		case <-ticker.C:
+			down, failed := servicesDown(d.ctx, d.mgr, d.logger)
+			if !down {
+				continue
+			}
+			if len(failed) > 0 {
+				d.logger.Error("every service is down, exiting", "failed", failed)
+				return fmt.Errorf("%w: %s", ErrServicesFailed, strings.Join(failed, ", "))
+			}
+			d.logger.Info("every service exited cleanly, exiting")
+			return nil
+		case <-d.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// servicesDown reports whether every registered service is down for good,
+// and which of them failed. A service is down for good once its latest
+// launch is Stopped, or Failed with the health monitor having given up on
+// restarting it; an enabled service that has yet to launch is not. With no
+// services registered yet nothing is down, so a daemon whose services are
+// still to be registered, from its services_dir say, doesn't exit at once.
+func servicesDown(ctx context.Context, mgr *manager.LocalManager, logger *slog.Logger) (down bool, failed []string) {
+	entries, err := mgr.GetAllServiceCatalogEntries(ctx)
+	if err != nil {
+		logger.Error("listing services to check for exit", "error", err)
+		return false, nil
+	}
+	if len(entries) == 0 {
+		return false, nil
+	}
+	for i := range entries {
+		name := entries[i].Name
+		latest, err := mgr.GetMostRecentProcessHistoryEntry(ctx, name)
+		if errors.Is(err, manager.ErrProcessNotFound) && !entries[i].Enabled {
+			continue
+		}
+		if err != nil {
+			return false, nil
+		}
+		switch latest.State {
+		case types.ProcessStateStopped:
+		case types.ProcessStateFailed:
+			instance, err := mgr.GetServiceInstance(ctx, name)
+			if err != nil || instance.GaveUpReason == "" {
+				return false, nil
+			}
+			failed = append(failed, name)
+		default:
+			return false, nil
+		}
+	}
+	return true, failed
+}
+
+// stopServicesInReverseOrder stops every registered service, each one before
+// the services it depends_on, so a dependent never outlives what it needs.
+// A service whose config can't be loaded is stopped as if it had no
+// dependencies, and a depends_on cycle falls back to catalog order: shutdown
+// must reach every service either way.
+func stopServicesInReverseOrder(ctx context.Context, mgr *manager.LocalManager, logger *slog.Logger, gracePeriod time.Duration) {
+	entries, err := mgr.GetAllServiceCatalogEntries(ctx)
+	if err != nil {
+		logger.Error("listing services to stop", "error", err)
+		return
+	}
+	stack := make([]manager.StackEntry, 0, len(entries))
+	for _, entry := range entries {
+		cfg, err := manager.LoadServiceConfig(filepath.Join(entry.DirectoryPath, entry.ConfigFileName))
+		if err != nil {
+			logger.Warn("loading service config for shutdown order", "service", entry.Name, "error", err)
+			cfg = &types.ServiceConfig{Name: entry.Name}
+		}
+		stack = append(stack, manager.StackEntry{Config: cfg, Name: entry.Name, DirectoryPath: entry.DirectoryPath, ConfigFileName: entry.ConfigFileName})
+	}
+	ordered, err := manager.StackStartOrder(stack)
+	if err != nil {
+		logger.Warn("ordering services for shutdown, stopping in catalog order", "error", err)
+		ordered = stack
+	}
+
+	for _, entry := range slices.Backward(ordered) {
+		if _, err := mgr.StopService(ctx, entry.Name, gracePeriod, 200*time.Millisecond); err != nil {
+			if !errors.Is(err, manager.ErrServiceNotRunning) {
+				logger.Error("stopping service", "service", entry.Name, "error", err)
+			}
+			continue
+		}
+		logger.Info("stopped service", "service", entry.Name)
+	}
+}
@@ -0,0 +1,83 @@
+package process
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// TestHandleReapedChild_AdoptedOrphan proves a reaped pid with no history
+// row, a process adopted as child subreaper, is reaped quietly instead of
+// logging a failed history update.
+func TestHandleReapedChild_AdoptedOrphan(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	logger, buf := capturingLogger()
+
+	const adoptedPID = 999989
+	if got := handleReapedChild(t.Context(), db, logger, adoptedPID, nil, syscall.WaitStatus(0)); got != reapContinue {
+		t.Errorf("expected reapContinue for an adopted orphan, got %v", got)
+	}
+	out := buf.String()
+	if strings.Contains(out, `"level":"ERROR"`) || !strings.Contains(out, "reaped adopted orphan") {
+		t.Errorf("expected only a DEBUG 'reaped adopted orphan' line, got: %s", out)
+	}
+}
+
+// TestPID1_StopsInReverseOrderThenExits starts a service and the one
+// depending on it, then proves the PID-1 shutdown stops the dependent first
+// and that the daemon counts both as down afterwards, cleanly or, once the
+// monitor gives up on one, as failed.
+func TestPID1_StopsInReverseOrderThenExits(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+
+	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "api", Command: types.ServiceCommand{Shell: "/bin/sleep 30"}, DependsOn: []string{"store"}})
+	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "store", Command: types.ServiceCommand{Shell: "/bin/sleep 30"}})
+	for _, name := range []string{"store", "api"} {
+		if _, err := mgr.StartService(t.Context(), name); err != nil {
+			t.Fatalf("StartService(%s): %v", name, err)
+		}
+	}
+
+	if down, _ := servicesDown(t.Context(), mgr, discardLogger()); down {
+		t.Fatal("services just started must not count as down")
+	}
+
+	logger, buf := capturingLogger()
+	stopServicesInReverseOrder(t.Context(), mgr, logger, 2*time.Second)
+	out := buf.String()
+	stopAPI, stopStore := strings.Index(out, `"service":"api"`), strings.Index(out, `"service":"store"`)
+	if stopAPI < 0 || stopStore < 0 || stopAPI > stopStore {
+		t.Errorf("expected api to be stopped before store, got: %s", out)
+	}
+
+	down, failed := servicesDown(t.Context(), mgr, discardLogger())
+	if !down || len(failed) != 0 {
+		t.Fatalf("servicesDown = %v, %v; want every service down cleanly", down, failed)
+	}
+
+	latest, err := mgr.GetMostRecentProcessHistoryEntry(t.Context(), "api")
+	if err != nil {
+		t.Fatalf("GetMostRecentProcessHistoryEntry: %v", err)
+	}
+	reason := "[api] exited with code 1; not restarting (restart: never)"
+	if err := db.UpdateProcessHistoryEntry(t.Context(), latest.PGID, database.ProcessHistoryUpdate{State: new(types.ProcessStateFailed)}); err != nil {
+		t.Fatalf("UpdateProcessHistoryEntry: %v", err)
+	}
+	if down, _ := servicesDown(t.Context(), mgr, discardLogger()); down {
+		t.Fatal("a failed service the monitor may still restart must not count as down")
+	}
+	if err := db.UpdateServiceInstance(t.Context(), "api", database.ServiceInstanceUpdate{GaveUpReason: &reason}); err != nil {
+		t.Fatalf("UpdateServiceInstance: %v", err)
+	}
+	down, failed = servicesDown(t.Context(), mgr, discardLogger())
+	if !down || len(failed) != 1 || failed[0] != "api" {
+		t.Errorf("servicesDown = %v, %v; want down with api failed", down, failed)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/Elysium-Labs-EU/eos/internal/config"
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/procutil"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// childSubreaper reads and then clears PR_SET_CHILD_SUBREAPER on the test
+// process, so each case starts from a process that isn't one.
+func childSubreaper(t *testing.T) bool {
+	t.Helper()
+	var subreaper int32
+	if err := unix.Prctl(unix.PR_GET_CHILD_SUBREAPER, uintptr(unsafe.Pointer(&subreaper)), 0, 0, 0); err != nil {
+		t.Fatalf("PR_GET_CHILD_SUBREAPER: %v", err)
+	}
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 0, 0, 0, 0); err != nil {
+		t.Fatalf("PR_SET_CHILD_SUBREAPER: %v", err)
+	}
+	return subreaper != 0
+}
+
+// TestNewStandaloneDaemon_ChildSubreaperOnlyForForking proves a daemon
+// outside PID-1 mode becomes the child subreaper only when its catalog has
+// a type: forking service, leaving every other service's orphans to PID 1.
+func TestNewStandaloneDaemon_ChildSubreaperOnlyForForking(t *testing.T) {
+	for _, cfg := range []types.ServiceConfig{
+		{Name: "simple", Command: types.ServiceCommand{Shell: "true"}},
+		{Name: "legacy", Command: types.ServiceCommand{Shell: "true"}, Type: "forking", PIDFile: "legacy.pid"},
+	} {
+		sockDir := shortTempDir(t)
+		dbDir := t.TempDir()
+		db, err := database.NewDB(t.Context(), dbDir)
+		if err != nil {
+			t.Fatalf("NewDB: %v", err)
+		}
+		entry := writeBootTestService(t, dbDir, &cfg)
+		if err := db.RegisterService(t.Context(), entry.Name, entry.DirectoryPath, entry.ConfigFileName); err != nil {
+			t.Fatalf("RegisterService: %v", err)
+		}
+		_ = db.CloseDBConnection()
+		childSubreaper(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		d, err := newStandaloneDaemon(ctx, false /* logToFileAndConsole */, false /* verbose */, false /* pid1 */, dbDir, testutil.ResolveIdentity(t), daemonInitCfg(sockDir), config.ShutdownConfig{}, config.TelemetryConfig{})
+		if err != nil {
+			cancel()
+			t.Fatalf("newStandaloneDaemon: %v", err)
+		}
+		d.shutdown(ctx)
+		cancel()
+
+		if got, want := childSubreaper(t), cfg.Type == "forking"; got != want {
+			t.Errorf("catalog with service %s: child subreaper = %v, want %v", cfg.Name, got, want)
+		}
+	}
+}
+
+// TestHandleSIGCHLDRequest_LeavesHeldChild proves the daemon's reaper leaves
+// a held child, a type: forking launch's command say, to its own Wait, which
+// so still sees a failed exit.
+func TestHandleSIGCHLDRequest_LeavesHeldChild(t *testing.T) {
+	db, _, _ := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	held := procutil.NewHeldChildren()
+
+	cmd := exec.Command("sh", "-c", "exit 3")
+	if err := procutil.StartHeld(held, cmd); err != nil {
+		t.Fatalf("StartHeld: %v", err)
+	}
+	defer procutil.ReleaseHeld(held, cmd.Process.Pid)
+	if err := procutil.AwaitExit(cmd.Process.Pid); err != nil {
+		t.Fatalf("AwaitExit: %v", err)
+	}
+
+	handleSIGCHLDRequest(t.Context(), db, discardLogger(), held)
+	if err := cmd.Wait(); cmd.ProcessState == nil || cmd.ProcessState.ExitCode() != 3 {
+		t.Errorf("Wait after the reaper ran = %v, want exit code 3 left for it", err)
+	}
+}
@@ -239,7 +239,7 @@ func runningReusePortService(t *testing.T) (mgr *manager.LocalManager, serviceNa
 	ctx, cancel := context.WithCancel(context.Background())
 	mgr = manager.NewLocalManager(db, tempDir, ctx, testutil.NewTestLogger(t))
 
-	cfg := &types.ServiceConfig{Name: serviceName, Command: os.Args[0], Port: port}
+	cfg := &types.ServiceConfig{Name: serviceName, Command: types.ServiceCommand{Shell: os.Args[0]}, Port: port}
 	yamlData, err := yaml.Marshal(cfg)
 	if err != nil {
 		t.Fatalf("marshal config: %v", err)
@@ -0,0 +1,269 @@
+package process
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/fswatch"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+// servicesDirSettle is how long a scan waits after a change for more to
+// arrive, so a file written in several steps, or a batch of files written
+// together, is read once it is complete.
+const servicesDirSettle = 300 * time.Millisecond
+
+// servicesDirWatcher keeps the services registered from the drop-in services
+// directory (services_dir: in config.yaml) in line with the service files in
+// it. A service it registers has the directory as its DirectoryPath and its
+// file as its ConfigFileName, which is how a later scan tells its own entries
+// from ones registered with eos add.
+type servicesDirWatcher struct {
+	mgr    *manager.LocalManager
+	logger *slog.Logger
+	// seen is each file's content hash as of the last scan, so a scan only
+	// acts on files that changed since.
+	seen        map[string]string
+	dir         string
+	gracePeriod time.Duration
+}
+
+// servicesDirFile is one service file read from the services directory.
+// Config is nil when the file failed ValidateServiceConfig.
+type servicesDirFile struct {
+	config *types.ServiceConfig
+	name   string
+	hash   string
+	errs   []error
+}
+
+func newServicesDirWatcher(mgr *manager.LocalManager, logger *slog.Logger, dir string, gracePeriod time.Duration) *servicesDirWatcher {
+	return &servicesDirWatcher{
+		mgr:         mgr,
+		logger:      logger,
+		seen:        make(map[string]string),
+		dir:         filepath.Clean(dir),
+		gracePeriod: gracePeriod,
+	}
+}
+
+// run scans the directory once, then again after each change fswatch
+// reports, until ctx is done. The watch is set up before the first scan so a
+// file written in between isn't missed.
+func (w *servicesDirWatcher) run(ctx context.Context) {
+	changes, err := fswatch.Watch(ctx, w.dir, fswatch.Options{})
+	if err != nil {
+		w.logger.Error("watching services dir, changes to it need a daemon restart", "dir", w.dir, "error", err)
+	}
+	w.scan(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-changes:
+			if !ok {
+				if ctx.Err() == nil {
+					w.logger.Error("services dir was removed, no longer watching it", "dir", w.dir)
+				}
+				return
+			}
+			if !w.settle(ctx, changes) {
+				return
+			}
+			w.scan(ctx)
+		}
+	}
+}
+
+// settle drains changes until none has arrived for servicesDirSettle. It
+// returns false once ctx is done.
+func (w *servicesDirWatcher) settle(ctx context.Context, changes <-chan string) bool {
+	timer := time.NewTimer(servicesDirSettle)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changes:
+			timer.Reset(servicesDirSettle)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// scan reconciles the catalog with the directory: it registers and starts a
+// service whose file is new, restarts one whose file changed what it runs,
+// and stops and deregisters one whose file was removed or now names another
+// service. An invalid file is logged and skipped, leaving whatever it
+// registered before running as it is. The first scan has no earlier hashes
+// to compare against, so it restarts nothing: the services already
+// registered are started by the daemon's own boot.
+func (w *servicesDirWatcher) scan(ctx context.Context) {
+	files, err := readServicesDir(w.dir)
+	if err != nil {
+		w.logger.Error("reading services dir", "dir", w.dir, "error", err)
+		return
+	}
+	entries, err := w.mgr.GetAllServiceCatalogEntries(ctx)
+	if err != nil {
+		w.logger.Error("services dir: getting service catalog entries", "error", err)
+		return
+	}
+	registered := make(map[string]*types.ServiceCatalogEntry, len(entries))
+	for i := range entries {
+		registered[entries[i].Name] = &entries[i]
+	}
+
+	for _, entry := range entries {
+		if !w.owns(&entry) {
+			continue
+		}
+		file, found := files[entry.ConfigFileName]
+		reason := ""
+		switch {
+		case !found:
+			reason = "its file was removed"
+		case file.config != nil && file.config.Name != entry.Name:
+			reason = "its file now names " + file.config.Name
+		}
+		if reason != "" && w.remove(ctx, entry.Name, reason) {
+			delete(registered, entry.Name)
+		}
+	}
+
+	seen := make(map[string]string, len(files))
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		file := files[name]
+		seen[name] = file.hash
+		changed := file.hash != w.seen[name]
+		if file.config == nil {
+			if changed {
+				w.logger.Error("services dir: skipping invalid service file", "file", filepath.Join(w.dir, name), "error", errors.Join(file.errs...))
+			}
+			continue
+		}
+		entry, found := registered[file.config.Name]
+		switch {
+		case !found:
+			w.add(ctx, file)
+		case !w.owns(entry) || entry.ConfigFileName != name:
+			if changed {
+				w.logger.Error("services dir: skipping service file, its name is already registered", "file", filepath.Join(w.dir, name), "service", file.config.Name, "registered_from", filepath.Join(entry.DirectoryPath, entry.ConfigFileName))
+			}
+		case changed && w.seen[name] != "":
+			w.apply(ctx, entry)
+		}
+	}
+	w.seen = seen
+}
+
+func (w *servicesDirWatcher) owns(entry *types.ServiceCatalogEntry) bool {
+	return filepath.Clean(entry.DirectoryPath) == w.dir
+}
+
+// add registers file's service and starts it, gated on its depends_on as a
+// boot is.
+func (w *servicesDirWatcher) add(ctx context.Context, file servicesDirFile) {
+	entry, err := manager.NewServiceCatalogEntry(file.config.Name, w.dir, file.name)
+	if err != nil {
+		w.logger.Error("services dir: creating service catalog entry", "file", file.name, "error", err)
+		return
+	}
+	if err := w.mgr.AddServiceCatalogEntry(ctx, entry); err != nil {
+		w.logger.Error("services dir: adding service catalog entry", "service", entry.Name, "error", err)
+		return
+	}
+	w.logger.Info("services dir: registered service", "service", entry.Name, "file", filepath.Join(w.dir, file.name))
+	go bootService(ctx, w.mgr, w.logger, entry)
+}
+
+// apply restarts entry's service when its running launch's config differs
+// from its file, and starts it when it is enabled but not running. An edit
+// that leaves the parsed config as it was, a comment say, changes nothing.
+func (w *servicesDirWatcher) apply(ctx context.Context, entry *types.ServiceCatalogEntry) {
+	drift, err := w.mgr.GetConfigDrift(ctx, entry.Name)
+	if err != nil {
+		w.logger.Error("services dir: comparing launch config", "service", entry.Name, "error", err)
+		return
+	}
+	if drift == nil {
+		if entry.Enabled {
+			go bootService(ctx, w.mgr, w.logger, entry)
+		}
+		return
+	}
+	if !drift.Pending() {
+		return
+	}
+	w.logger.Info("services dir: service file changed, restarting", "service", entry.Name, "changes", len(drift.Changes), "env_changed", drift.EnvChanged)
+	if _, err := w.mgr.RestartService(ctx, entry.Name, w.gracePeriod, 200*time.Millisecond); err != nil {
+		w.logger.Error("services dir: restarting service", "service", entry.Name, "error", err)
+	}
+}
+
+// remove stops and deregisters name as eos stop and eos remove would,
+// reporting whether it is gone.
+func (w *servicesDirWatcher) remove(ctx context.Context, name, reason string) bool {
+	w.logger.Info("services dir: removing service, "+reason, "service", name)
+	if _, err := w.mgr.StopService(ctx, name, w.gracePeriod, 200*time.Millisecond); err != nil && !errors.Is(err, manager.ErrServiceNotRunning) {
+		w.logger.Error("services dir: stopping service", "service", name, "error", err)
+		return false
+	}
+	if _, err := w.mgr.RemoveServiceInstance(ctx, name); err != nil && !errors.Is(err, manager.ErrServiceNotRunning) {
+		w.logger.Error("services dir: removing service instance", "service", name, "error", err)
+		return false
+	}
+	removed, err := w.mgr.RemoveServiceCatalogEntry(ctx, name)
+	if err != nil || !removed {
+		w.logger.Error("services dir: removing service catalog entry", "service", name, "removed", removed, "error", err)
+		return false
+	}
+	return true
+}
+
+// readServicesDir reads every *.yaml and *.yml file directly in dir, keyed
+// by file name. Dotfiles are skipped, as the temporary files editors and
+// config management write beside the one they replace usually are.
+func readServicesDir(dir string) (map[string]servicesDirFile, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]servicesDirFile)
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || strings.HasPrefix(name, ".") || !isServiceFileName(name) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path) // #nosec G304 -- path is a file directly in the operator-configured services dir
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		sum := sha256.Sum256(data)
+		file := servicesDirFile{name: name, hash: hex.EncodeToString(sum[:])}
+		file.config, file.errs = manager.ValidateServiceConfig(path)
+		files[name] = file
+	}
+	return files, nil
+}
+
+func isServiceFileName(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,84 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Elysium-Labs-EU/eos/internal/database"
+	"github.com/Elysium-Labs-EU/eos/internal/manager"
+	"github.com/Elysium-Labs-EU/eos/internal/testutil"
+	"github.com/Elysium-Labs-EU/eos/internal/types"
+)
+
+func writeServicesDirFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func waitForServiceInstance(t *testing.T, mgr *manager.LocalManager, name string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if instance, err := mgr.GetServiceInstance(t.Context(), name); err == nil && instance != nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("expected %s to have started", name)
+}
+
+// TestServicesDirWatcherScan drives scan directly: new files are registered
+// and started, invalid ones and ones naming a service registered elsewhere
+// are skipped, and a removed file's service is stopped and deregistered.
+func TestServicesDirWatcherScan(t *testing.T) {
+	db, _, tempDir := testutil.SetupTestDB(t, database.MigrationsFS, database.MigrationsPath)
+	mgr := manager.NewLocalManager(db, tempDir, t.Context(), testutil.NewTestLogger(t))
+	t.Cleanup(mgr.WaitPipes)
+
+	bootTestService(t, mgr, tempDir, &types.ServiceConfig{Name: "elsewhere", Command: types.ServiceCommand{Shell: "/bin/sleep 5"}})
+
+	dir := filepath.Join(tempDir, "services.d")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeServicesDirFile(t, dir, "api.yaml", "name: api\ncommand: /bin/sleep 5\n")
+	writeServicesDirFile(t, dir, "broken.yaml", "name: broken\n")
+	writeServicesDirFile(t, dir, "clash.yml", "name: elsewhere\ncommand: /bin/sleep 5\n")
+	writeServicesDirFile(t, dir, ".api.yaml.tmp", "name: tmp\ncommand: /bin/sleep 5\n")
+	writeServicesDirFile(t, dir, "README", "not a service\n")
+
+	w := newServicesDirWatcher(mgr, testutil.NewTestLogger(t), dir, time.Second)
+	w.scan(t.Context())
+
+	entry, err := mgr.GetServiceCatalogEntry(t.Context(), "api")
+	if err != nil {
+		t.Fatalf("expected api to be registered: %v", err)
+	}
+	if entry.DirectoryPath != dir || entry.ConfigFileName != "api.yaml" {
+		t.Errorf("api registered from %s/%s, want %s/api.yaml", entry.DirectoryPath, entry.ConfigFileName, dir)
+	}
+	for _, name := range []string{"broken", "tmp"} {
+		if registered, _ := mgr.IsServiceRegistered(t.Context(), name); registered {
+			t.Errorf("%s should not have been registered", name)
+		}
+	}
+	if elsewhere, _ := mgr.GetServiceCatalogEntry(t.Context(), "elsewhere"); elsewhere.ConfigFileName != "service.yaml" {
+		t.Errorf("elsewhere should still point at its own service.yaml, got %s", elsewhere.ConfigFileName)
+	}
+	waitForServiceInstance(t, mgr, "api")
+
+	if err := os.Remove(filepath.Join(dir, "api.yaml")); err != nil {
+		t.Fatalf("remove api.yaml: %v", err)
+	}
+	w.scan(t.Context())
+	if registered, _ := mgr.IsServiceRegistered(t.Context(), "api"); registered {
+		t.Error("api should have been deregistered once its file was removed")
+	}
+	if registered, _ := mgr.IsServiceRegistered(t.Context(), "elsewhere"); !registered {
+		t.Error("a service registered outside the services dir should be left alone")
+	}
+}
@@ -40,6 +40,21 @@ func parseStartTimeField(afterComm string) (int64, bool) {
 	return ticks, true
 }
 
+// parsePPIDField extracts the parent pid (field 4, "ppid") from the
+// post-comm portion of a /proc/<pid>/stat line.
+func parsePPIDField(afterComm string) (int, bool) {
+	const ppidFieldIndex = 1
+	fields := strings.Fields(afterComm)
+	if len(fields) <= ppidFieldIndex {
+		return 0, false
+	}
+	ppid, err := strconv.Atoi(fields[ppidFieldIndex])
+	if err != nil {
+		return 0, false
+	}
+	return ppid, true
+}
+
 // parseCPUFields extracts the process group (field 5, "pgrp") and the total CPU
 // jiffies (utime field 14 + stime field 15) from the post-comm portion of a
 // /proc/<pid>/stat line. Indices are 0-based into the whitespace-split fields
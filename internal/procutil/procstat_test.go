@@ -24,6 +24,15 @@ func TestCommEnd(t *testing.T) {
 	}
 }
 
+func TestParsePPIDField(t *testing.T) {
+	if ppid, ok := parsePPIDField("Z 4321 1234 1234 0"); !ok || ppid != 4321 {
+		t.Errorf("parsePPIDField = (%d, %v), want (4321, true)", ppid, ok)
+	}
+	if _, ok := parsePPIDField("Z"); ok {
+		t.Error("parsePPIDField(no ppid) = ok true, want false")
+	}
+}
+
 func TestParseStartTimeField(t *testing.T) {
 	// 20 fields after "pid (comm) ": state through starttime (index 19).
 	const afterComm = "S 1 1234 1234 0 -1 4194304 0 0 0 0 0 0 0 0 20 0 1 0 98765"
@@ -32,10 +32,25 @@ func IsAlive(pgid int) bool {
 	return anyProcessRunning(pgid)
 }
 
+// LeaderAlive reports whether pgid's group leader itself is still running,
+// as opposed to IsAlive's "any member of the group". A stop that signals
+// only the leader (kill_mode: leader or mixed) needs to know when the leader
+// is done, independently of the children it may leave behind.
+func LeaderAlive(pgid int) bool {
+	if pgid <= 1 {
+		return false
+	}
+	if err := syscall.Kill(pgid, 0); err != nil {
+		return false
+	}
+	return leaderRunning(pgid)
+}
+
 // StartTime returns an opaque, platform-specific integer identifying when the
-// kernel started pid. It is only meaningful compared for equality against
-// another value obtained the same way on the same host — never persisted
-// across platforms and never converted to wall-clock time.
+// kernel started pid. It is only meaningful compared against another value
+// obtained the same way on the same host — for equality, or for which process
+// started first, since both platforms count up from a fixed point — never
+// persisted across platforms and never converted to wall-clock time.
 //
 // This exists to detect PGID reuse: kill(-pgid, 0) only proves some process
 // group with that PGID is alive, not that it's the same process a stored
@@ -46,6 +61,23 @@ func StartTime(pid int) (int64, error) {
 	return platformStartTime(pid)
 }
 
+// AwaitExit blocks until pid, a child of the caller, has exited, without
+// reaping it (waitid(2) with WNOWAIT). Until the caller does reap it, pid
+// lingers as a zombie, so neither pid nor a process group it leads can be
+// reused: a kill(-pid, ...) sent in between still reaches only what that
+// child left behind. Outside Linux it returns an error at once.
+func AwaitExit(pid int) error {
+	return platformAwaitExit(pid)
+}
+
+// SetChildSubreaper makes the calling process the child subreaper of
+// everything it launches: a process orphaned anywhere below it, a
+// double-forked grandchild say, is reparented to it rather than to PID 1, so
+// it can still wait for that process and reap it.
+func SetChildSubreaper() error {
+	return platformSetChildSubreaper()
+}
+
 // CPUTime returns the cumulative CPU time (user+system) consumed by every live
 // process in the given process group, as a Duration. It is meant to be sampled
 // repeatedly: the difference between two readings over a wall-clock interval,
@@ -91,3 +123,44 @@ func IsAliveMatching(pgid int, startedAtTicks int64) bool {
 	}
 	return current == startedAtTicks
 }
+
+// Rlimit identifies one of the per-process resource limits SetRlimit and
+// GetRlimit deal in.
+type Rlimit int
+
+const (
+	RlimitNofile Rlimit = iota
+	RlimitCore
+	RlimitAS
+)
+
+// RlimInfinity is the "no limit" value SetRlimit takes and GetRlimit
+// returns.
+const RlimInfinity = ^uint64(0)
+
+func (r Rlimit) String() string {
+	switch r {
+	case RlimitNofile:
+		return "RLIMIT_NOFILE"
+	case RlimitCore:
+		return "RLIMIT_CORE"
+	case RlimitAS:
+		return "RLIMIT_AS"
+	}
+	return "RLIMIT_UNKNOWN"
+}
+
+// SetRlimit sets both the soft and the hard value of resource on the
+// already-running process pid (prlimit(2)). os/exec has no hook between fork
+// and exec to call setrlimit in the child itself; a caller that needs the
+// limits in force before the process runs sets them from StartAtExec's
+// atExec.
+func SetRlimit(pid int, resource Rlimit, value uint64) error {
+	return platformSetRlimit(pid, resource, value)
+}
+
+// GetRlimit returns pid's current soft value for resource, RlimInfinity for
+// none.
+func GetRlimit(pid int, resource Rlimit) (uint64, error) {
+	return platformGetRlimit(pid, resource)
+}
@@ -32,6 +32,12 @@ func anyProcessRunning(_ int) bool {
 	return true
 }
 
+// leaderRunning always reports true on macOS, where kill(pgid, 0) — the
+// caller in LeaderAlive — already fails for a zombie.
+func leaderRunning(_ int) bool {
+	return true
+}
+
 // platformStartTime reads p_starttime from the kernel's kinfo_proc for pid
 // via sysctl (kern.proc.pid.<pid>) — macOS has no procfs equivalent, so this
 // is the cheap, non-procfs mechanism the kernel exposes for a process's start
@@ -133,3 +139,33 @@ func platformCPUTime(pgid int) (time.Duration, error) {
 	}
 	return total, nil
 }
+
+// platformSetRlimit has no implementation on macOS: there is no prlimit(2),
+// and setrlimit(2) only ever applies to the calling process itself.
+func platformSetRlimit(_ int, r Rlimit, _ uint64) error {
+	return fmt.Errorf("setting %s on another process not supported on %s", r, runtime.GOOS)
+}
+
+// platformGetRlimit has no implementation on macOS, for the same reason as
+// platformSetRlimit.
+func platformGetRlimit(_ int, r Rlimit) (uint64, error) {
+	return 0, fmt.Errorf("reading %s of another process not supported on %s", r, runtime.GOOS)
+}
+
+// platformAwaitExit has no implementation on macOS, whose waitid(2) x/sys
+// doesn't expose.
+func platformAwaitExit(_ int) error {
+	return fmt.Errorf("waiting for a process without reaping it not supported on %s", runtime.GOOS)
+}
+
+// platformNextExited returns -1, any child, on macOS, whose waitid(2) x/sys
+// doesn't expose: the next exited child can't be told without reaping it.
+func platformNextExited() (int, error) {
+	return -1, nil
+}
+
+// platformChildren is never reached on macOS, where platformNextExited
+// never names a child for ReapExited to look past.
+func platformChildren() ([]int, error) {
+	return nil, fmt.Errorf("listing child processes not supported on %s", runtime.GOOS)
+}
@@ -4,9 +4,13 @@ package procutil
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
 )
 
 // linuxClockTicks is the kernel's USER_HZ (sysconf(_SC_CLK_TCK)): the number of
@@ -59,6 +63,12 @@ func anyProcessRunning(pgid int) bool {
 	return anyProcessRunningFrom(realProcReader{}, pgid)
 }
 
+// leaderRunning reports whether pgid's leader is running rather than a
+// zombie awaiting its reaper.
+func leaderRunning(pgid int) bool {
+	return nonZombieMemberFrom(realProcReader{}, pgid, pgid)
+}
+
 // platformCPUTime sums utime+stime across every live process whose process
 // group (pgrp) is pgid — the same scope as the RSS sampler — and converts the
 // jiffies to a Duration via the fixed USER_HZ. eos launches each service as
@@ -110,3 +120,100 @@ func platformReadEnviron(pid int) ([]string, error) {
 	}
 	return env, nil
 }
+
+// linuxRlimitResource maps r to its RLIMIT_* resource number.
+func linuxRlimitResource(r Rlimit) (int, error) {
+	switch r {
+	case RlimitNofile:
+		return unix.RLIMIT_NOFILE, nil
+	case RlimitCore:
+		return unix.RLIMIT_CORE, nil
+	case RlimitAS:
+		return unix.RLIMIT_AS, nil
+	}
+	return 0, fmt.Errorf("unknown rlimit %d", int(r))
+}
+
+func platformSetRlimit(pid int, r Rlimit, value uint64) error {
+	resource, err := linuxRlimitResource(r)
+	if err != nil {
+		return err
+	}
+	if err := unix.Prlimit(pid, resource, &unix.Rlimit{Cur: value, Max: value}, nil); err != nil {
+		return fmt.Errorf("prlimit %s on pid %d: %w", r, pid, err)
+	}
+	return nil
+}
+
+func platformGetRlimit(pid int, r Rlimit) (uint64, error) {
+	resource, err := linuxRlimitResource(r)
+	if err != nil {
+		return 0, err
+	}
+	var current unix.Rlimit
+	if err := unix.Prlimit(pid, resource, nil, &current); err != nil {
+		return 0, fmt.Errorf("prlimit %s on pid %d: %w", r, pid, err)
+	}
+	return current.Cur, nil
+}
+
+func platformAwaitExit(pid int) error {
+	var info unix.Siginfo
+	for {
+		err := unix.Waitid(unix.P_PID, pid, &info, unix.WEXITED|unix.WNOWAIT, nil)
+		if !errors.Is(err, unix.EINTR) {
+			return err
+		}
+	}
+}
+
+// platformNextExited peeks at the next exited child with waitid(2) and
+// WNOWAIT, leaving it to be reaped, and returns its pid, or 0 when none has
+// exited.
+func platformNextExited() (int, error) {
+	var info unix.Siginfo
+	for {
+		err := unix.Waitid(unix.P_ALL, 0, &info, unix.WEXITED|unix.WNOHANG|unix.WNOWAIT, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return siginfoPID(&info), nil
+	}
+}
+
+// siginfoPID reads si_pid, which x/sys leaves in Siginfo's padding: it opens
+// the union that follows si_signo, si_errno and si_code, aligned to a
+// pointer. waitid(2) with WNOHANG leaves it 0 when no child has exited.
+func siginfoPID(info *unix.Siginfo) int {
+	const align = unsafe.Sizeof(uintptr(0))
+	offset := (3*unsafe.Sizeof(int32(0)) + align - 1) &^ (align - 1)
+	return int(*(*int32)(unsafe.Add(unsafe.Pointer(info), offset)))
+}
+
+// platformChildren lists this process's children from the ppid field of
+// each /proc/<pid>/stat.
+func platformChildren() ([]int, error) {
+	pids, err := listPidsIn("/proc")
+	if err != nil {
+		return nil, err
+	}
+	self := os.Getpid()
+	var children []int
+	for _, pid := range pids {
+		stat, ok := realProcReader{}.stat(pid)
+		if !ok {
+			continue
+		}
+		end, ok := commEnd(stat)
+		if !ok {
+			continue
+		}
+		if ppid, ok := parsePPIDField(stat[end+2:]); ok && ppid == self {
+			children = append(children, pid)
+		}
+	}
+	return children, nil
+}
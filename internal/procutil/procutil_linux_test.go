@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -111,3 +113,112 @@ func waitForRootChild(sudoPid int) (int, error) {
 	}
 	return 0, fmt.Errorf("timed out waiting for a child pid: %w", lastErr)
 }
+
+// TestSetRlimit_RoundTrip lowers a live child's RLIMIT_NOFILE and reads it
+// back. Lowering needs no privilege, so this runs as any user.
+func TestSetRlimit_RoundTrip(t *testing.T) {
+	cmd, pid := launchGroupLeader(t)
+	defer killAndReap(t, cmd)
+
+	if err := SetRlimit(pid, RlimitNofile, 64); err != nil {
+		t.Fatalf("SetRlimit(%d, RLIMIT_NOFILE, 64): %v", pid, err)
+	}
+	got, err := GetRlimit(pid, RlimitNofile)
+	if err != nil {
+		t.Fatalf("GetRlimit(%d, RLIMIT_NOFILE): %v", pid, err)
+	}
+	if got != 64 {
+		t.Errorf("GetRlimit(%d, RLIMIT_NOFILE) = %d, want 64", pid, got)
+	}
+}
+
+// TestAwaitExit_leavesChildUnreaped proves AwaitExit returns once the child
+// has exited but leaves it for the caller to reap, exit status intact.
+func TestAwaitExit_leavesChildUnreaped(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "exit 3")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting child: %v", err)
+	}
+	if err := AwaitExit(cmd.Process.Pid); err != nil {
+		t.Fatalf("AwaitExit: %v", err)
+	}
+	if err := syscall.Kill(cmd.Process.Pid, 0); err != nil {
+		t.Errorf("kill(%d, 0) = %v, want the exited child still holding its pid", cmd.Process.Pid, err)
+	}
+	if err := cmd.Wait(); cmd.ProcessState == nil || cmd.ProcessState.ExitCode() != 3 {
+		t.Errorf("Wait after AwaitExit = %v, want exit code 3 still there to reap", err)
+	}
+}
+
+// TestReapExited_leavesHeldChild proves ReapExited leaves a held child's exit
+// status to its own Wait and reaps the child that exited behind it.
+func TestReapExited_leavesHeldChild(t *testing.T) {
+	held := NewHeldChildren()
+	heldCmd := exec.Command("sh", "-c", "exit 3")
+	if err := StartHeld(held, heldCmd); err != nil {
+		t.Fatalf("StartHeld: %v", err)
+	}
+	defer ReleaseHeld(held, heldCmd.Process.Pid)
+	other := exec.Command("sh", "-c", "exit 4")
+	if err := other.Start(); err != nil {
+		t.Fatalf("starting child: %v", err)
+	}
+	for _, pid := range []int{heldCmd.Process.Pid, other.Process.Pid} {
+		if err := AwaitExit(pid); err != nil {
+			t.Fatalf("AwaitExit(%d): %v", pid, err)
+		}
+	}
+
+	var status syscall.WaitStatus
+	if pid, err := ReapExited(held, &status); pid != other.Process.Pid || err != nil || status.ExitStatus() != 4 {
+		t.Errorf("ReapExited = %d, %v (exit %d); want child %d with exit code 4", pid, err, status.ExitStatus(), other.Process.Pid)
+	}
+	if pid, err := ReapExited(held, &status); pid != 0 || err != nil {
+		t.Errorf("ReapExited = %d, %v; want 0 with only the held child left", pid, err)
+	}
+	if err := heldCmd.Wait(); heldCmd.ProcessState == nil || heldCmd.ProcessState.ExitCode() != 3 {
+		t.Errorf("Wait on the held child = %v, want its exit code 3", err)
+	}
+}
+
+// TestStartAtExec_inForceFromTheFirstInstruction proves what atExec sets is
+// already in force on the program's own first instruction, and in what it
+// forks, with argv untouched.
+func TestStartAtExec_inForceFromTheFirstInstruction(t *testing.T) {
+	held := NewHeldChildren()
+	cmd := exec.Command("/bin/sh", "-c", "ulimit -n; sh -c 'ulimit -n'")
+	var out strings.Builder
+	cmd.Stdout = &out
+	if err := StartAtExec(held, cmd, func(pid int) error { return SetRlimit(pid, RlimitNofile, 64) }); err != nil {
+		t.Fatalf("StartAtExec: %v", err)
+	}
+	ReleaseHeld(held, cmd.Process.Pid)
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if got := strings.Fields(out.String()); len(got) != 2 || got[0] != "64" || got[1] != "64" {
+		t.Errorf("nofile limits = %q, want 64 in the program and in its child", out.String())
+	}
+	if cmd.Args[0] != "/bin/sh" || cmd.Path != "/bin/sh" {
+		t.Errorf("cmd = %s %q, want its argv as given", cmd.Path, cmd.Args)
+	}
+}
+
+func TestStartAtExec_atExecFailureNeverRuns(t *testing.T) {
+	held := NewHeldChildren()
+	marker := filepath.Join(t.TempDir(), "ran")
+	cmd := exec.Command("/bin/sh", "-c", "touch "+marker)
+	refused := errors.New("refused")
+	if err := StartAtExec(held, cmd, func(int) error { return refused }); !errors.Is(err, refused) {
+		t.Fatalf("StartAtExec err = %v, want atExec's error", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("the command ran after atExec failed: stat = %v", err)
+	}
+	if cmd.ProcessState == nil {
+		t.Error("the command was not waited for after atExec failed")
+	}
+	if len(held.pids) != 0 {
+		t.Errorf("held = %v, want the hold ended", held.pids)
+	}
+}
@@ -14,6 +14,12 @@ func anyProcessRunning(pgid int) bool {
 	return true
 }
 
+// leaderRunning always reports true outside Linux and macOS: kill(pgid, 0)
+// is the only liveness signal available here.
+func leaderRunning(pgid int) bool {
+	return true
+}
+
 // platformStartTime has no implementation outside Linux and macOS, the two
 // platforms eos supports (systemd and launchd persistence respectively).
 // Callers must treat this error as a hard failure rather than silently
@@ -35,3 +41,31 @@ func platformCPUTime(pgid int) (time.Duration, error) {
 func platformReadEnviron(pid int) ([]string, error) {
 	return nil, fmt.Errorf("process environment not supported on %s", runtime.GOOS)
 }
+
+// platformSetRlimit has no implementation outside Linux, the only platform
+// with prlimit(2).
+func platformSetRlimit(pid int, r Rlimit, value uint64) error {
+	return fmt.Errorf("setting %s on another process not supported on %s", r, runtime.GOOS)
+}
+
+// platformGetRlimit has no implementation outside Linux.
+func platformGetRlimit(pid int, r Rlimit) (uint64, error) {
+	return 0, fmt.Errorf("reading %s of another process not supported on %s", r, runtime.GOOS)
+}
+
+// platformAwaitExit has no implementation outside Linux.
+func platformAwaitExit(pid int) error {
+	return fmt.Errorf("waiting for a process without reaping it not supported on %s", runtime.GOOS)
+}
+
+// platformNextExited returns -1, any child, outside Linux and macOS: the next
+// exited child can't be told without reaping it.
+func platformNextExited() (int, error) {
+	return -1, nil
+}
+
+// platformChildren is never reached outside Linux and macOS, where platformNextExited
+// never names a child for ReapExited to look past.
+func platformChildren() ([]int, error) {
+	return nil, fmt.Errorf("listing child processes not supported on %s", runtime.GOOS)
+}
@@ -270,3 +270,33 @@ func TestIsAliveMatching_LeaderReapedChildSurvives(t *testing.T) {
 		t.Errorf("IsAliveMatching(%d, %d) = false, want true: leader reaped but child alive should still count as a match", pgid, startedAtTicks)
 	}
 }
+
+// TestLeaderAlive_FollowsLeaderNotGroup checks LeaderAlive tracks the leader
+// alone: a wrapper that backgrounds a child and exits leaves the group alive
+// but the leader gone.
+func TestLeaderAlive_FollowsLeaderNotGroup(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start wrapper: %v", err)
+	}
+	pgid := cmd.Process.Pid
+	defer func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) }()
+
+	if !LeaderAlive(pgid) {
+		t.Fatalf("LeaderAlive(%d) = false, want true for a running leader", pgid)
+	}
+
+	// Give the wrapper time to fork the backgrounded sleep before its leader
+	// is killed out from under it.
+	time.Sleep(200 * time.Millisecond)
+	_ = syscall.Kill(pgid, syscall.SIGKILL)
+	_ = cmd.Wait()
+
+	if LeaderAlive(pgid) {
+		t.Errorf("LeaderAlive(%d) = true, want false once the leader is reaped", pgid)
+	}
+	if !IsAlive(pgid) {
+		t.Errorf("IsAlive(%d) = false, want true: the backgrounded sleep is still running", pgid)
+	}
+}
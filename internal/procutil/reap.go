@@ -0,0 +1,119 @@
+package procutil
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// HeldChildren are the children whose exit status belongs to whoever
+// started them (see StartHeld), not to ReapExited. The process that reaps
+// its children creates one with NewHeldChildren and hands it to everything
+// starting a child it must wait for itself; a nil *HeldChildren, for a
+// process with no such reaper, holds nothing.
+type HeldChildren struct {
+	mu   sync.Mutex
+	pids map[int]struct{}
+}
+
+// NewHeldChildren returns an empty hold set.
+func NewHeldChildren() *HeldChildren {
+	return &HeldChildren{pids: make(map[int]struct{})}
+}
+
+// StartHeld starts cmd and keeps it out of ReapExited over held until
+// ReleaseHeld, so its own cmd.Wait always sees how it exited rather than a
+// process-wide reaper taking that first. The hold is in place before cmd can
+// exit, so even a command that exits at once is kept. With a nil held it is
+// cmd.Start.
+func StartHeld(held *HeldChildren, cmd *exec.Cmd) error {
+	if held == nil {
+		return cmd.Start()
+	}
+	held.mu.Lock()
+	defer held.mu.Unlock()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	held.pids[cmd.Process.Pid] = struct{}{}
+	return nil
+}
+
+// StartAtExec is StartHeld, except that cmd is stopped the moment its exec
+// succeeds, before it runs an instruction of the new program, and atExec is
+// called with its pid there: whatever atExec sets on the process, with
+// prlimit(2) say, is in force from that first instruction and in everything
+// the program forks, while cmd's argv is left as given. If atExec fails,
+// cmd is killed and waited for without ever running, and the error
+// returned. On Linux the stop is ptrace(2)'s at exec, so a kernel that
+// forbids ptrace (Yama's ptrace_scope 3) fails the start, and a set-user-ID
+// program started this way without CAP_SYS_PTRACE runs without its
+// privileges. Elsewhere atExec runs just after cmd starts.
+func StartAtExec(held *HeldChildren, cmd *exec.Cmd, atExec func(pid int) error) error {
+	return platformStartAtExec(held, cmd, atExec)
+}
+
+// abortStarted kills a cmd StartAtExec started, waits for it and ends its
+// hold.
+func abortStarted(held *HeldChildren, cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	ReleaseHeld(held, cmd.Process.Pid)
+}
+
+// ReleaseHeld ends pid's hold in held once its holder has waited for it.
+func ReleaseHeld(held *HeldChildren, pid int) {
+	if held == nil {
+		return
+	}
+	held.mu.Lock()
+	delete(held.pids, pid)
+	held.mu.Unlock()
+}
+
+// ReapExited reaps one exited child as wait4(-1, WNOHANG) would, filling in
+// status, except one in held: a held child is left to its holder and the
+// other children are looked at instead, so one waiting on its holder doesn't
+// keep the rest zombies. It returns 0 when no child it may reap has exited.
+// Outside Linux, where the next exited child can't be told without reaping
+// it, holds aren't honoured and any child may be reaped.
+func ReapExited(held *HeldChildren, status *syscall.WaitStatus) (int, error) {
+	if held == nil {
+		return syscall.Wait4(-1, status, syscall.WNOHANG, nil)
+	}
+	held.mu.Lock()
+	defer held.mu.Unlock()
+	pid, err := platformNextExited()
+	if err != nil || pid == 0 {
+		return pid, err
+	}
+	if _, ok := held.pids[pid]; !ok {
+		reaped, err := syscall.Wait4(pid, status, syscall.WNOHANG, nil)
+		// A child's own cmd.Wait can reap it between the two calls; look
+		// past it then.
+		if pid < 0 || !errors.Is(err, syscall.ECHILD) {
+			return reaped, err
+		}
+	}
+	return reapUnheld(held, status)
+}
+
+// reapUnheld reaps the first exited child of this process not in held,
+// waiting on each child by pid in turn.
+func reapUnheld(held *HeldChildren, status *syscall.WaitStatus) (int, error) {
+	children, err := platformChildren()
+	if err != nil {
+		return 0, err
+	}
+	for _, child := range children {
+		if _, ok := held.pids[child]; ok {
+			continue
+		}
+		// 0 is a child still running; ECHILD one its own Wait took first.
+		if reaped, err := syscall.Wait4(child, status, syscall.WNOHANG, nil); err == nil && reaped > 0 {
+			return reaped, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package procutil
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// platformStartAtExec starts cmd traced (SysProcAttr.Ptrace), so the kernel
+// stops it with SIGTRAP once its exec succeeds, runs atExec there and
+// detaches. ptrace(2) requests must come from the thread that started the
+// tracee, so all of it runs on one locked OS thread. The hold keeps the
+// daemon's reaper from taking the stop itself.
+func platformStartAtExec(held *HeldChildren, cmd *exec.Cmd, atExec func(pid int) error) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Ptrace = true
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := StartHeld(held, cmd); err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+	if err := awaitExecStop(pid); err != nil {
+		abortStarted(held, cmd)
+		return err
+	}
+	if err := atExec(pid); err != nil {
+		abortStarted(held, cmd)
+		return err
+	}
+	if err := unix.PtraceDetach(pid); err != nil {
+		abortStarted(held, cmd)
+		return fmt.Errorf("detaching from pid %d: %w", pid, err)
+	}
+	return nil
+}
+
+// awaitExecStop waits for the traced pid to stop at its exec, passing on
+// any other signal that stops it first.
+func awaitExecStop(pid int) error {
+	for {
+		var status unix.WaitStatus
+		_, err := unix.Wait4(pid, &status, 0, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("waiting for pid %d to exec: %w", pid, err)
+		}
+		if !status.Stopped() {
+			return fmt.Errorf("pid %d ended before its exec completed", pid)
+		}
+		if status.StopSignal() == unix.SIGTRAP {
+			return nil
+		}
+		if err := unix.PtraceCont(pid, int(status.StopSignal())); err != nil {
+			return fmt.Errorf("resuming pid %d: %w", pid, err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package procutil
+
+import "os/exec"
+
+// platformStartAtExec has no ptrace stop at exec to use outside Linux:
+// atExec runs once cmd has started.
+func platformStartAtExec(held *HeldChildren, cmd *exec.Cmd, atExec func(pid int) error) error {
+	if err := StartHeld(held, cmd); err != nil {
+		return err
+	}
+	if err := atExec(cmd.Process.Pid); err != nil {
+		abortStarted(held, cmd)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build linux
+
+package procutil
+
+import "golang.org/x/sys/unix"
+
+// platformSetChildSubreaper sets PR_SET_CHILD_SUBREAPER on the calling
+// process. It is idempotent, so every caller can ask again.
+func platformSetChildSubreaper() error {
+	return unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0)
+}
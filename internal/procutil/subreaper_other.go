@@ -0,0 +1,14 @@
+//go:build !linux
+
+package procutil
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// platformSetChildSubreaper has no implementation outside Linux:
+// PR_SET_CHILD_SUBREAPER is Linux-only.
+func platformSetChildSubreaper() error {
+	return fmt.Errorf("child subreaping not supported on %s", runtime.GOOS)
+}
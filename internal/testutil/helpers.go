@@ -15,6 +15,7 @@ import (
 	"github.com/Elysium-Labs-EU/eos/internal/database"
 	"github.com/Elysium-Labs-EU/eos/internal/logutil"
 	"github.com/Elysium-Labs-EU/eos/internal/types"
+	"github.com/Elysium-Labs-EU/eos/internal/userutil"
 )
 
 func SetupTestDB(t testing.TB, migrationsFS embed.FS, migrationsPath string) (*database.DB, *sql.DB, string) {
@@ -65,7 +66,7 @@ func WithName(name string) ServiceConfigOption {
 
 func WithCommand(command string) ServiceConfigOption {
 	return func(sc *types.ServiceConfig) {
-		sc.Command = command
+		sc.Command = types.ServiceCommand{Shell: command}
 	}
 }
 
@@ -77,7 +78,7 @@ func WithPort(port int) ServiceConfigOption {
 
 func WithEnvFile(envFile string) ServiceConfigOption {
 	return func(sc *types.ServiceConfig) {
-		sc.EnvFile = envFile
+		sc.EnvFile = types.EnvFileList{envFile}
 	}
 }
 
@@ -112,7 +113,7 @@ func NewTestServiceConfigFile(t *testing.T, opts ...ServiceConfigOption) *types.
 
 	config := &types.ServiceConfig{
 		Name:    "cms",
-		Command: "/home/user/start-script.sh",
+		Command: types.ServiceCommand{Shell: "/home/user/start-script.sh"},
 		Port:    1337,
 		Runtime: types.Runtime{
 			Type: "nodejs",
@@ -281,6 +282,17 @@ func NewTestStandaloneDaemonConfig(t *testing.T, baseDir string, opts ...Standal
 	return config.DaemonConfig{Standalone: &standaloneDaemonConfig, Systemd: nil}
 }
 
+// ResolveIdentity returns userutil.ResolveIdentity's answer for the test
+// process, failing t when there is none.
+func ResolveIdentity(t testing.TB) userutil.Identity {
+	t.Helper()
+	identity, err := userutil.ResolveIdentity()
+	if err != nil {
+		t.Fatalf("resolving identity: %v", err)
+	}
+	return identity
+}
+
 type testWriter struct {
 	t    *testing.T
 	done chan struct{}
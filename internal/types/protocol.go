@@ -16,10 +16,16 @@ const (
 
 	MethodForceStopService = "ForceStopService"
 	MethodReloadService    = "ReloadService"
+	MethodDeployService    = "DeployService"
+	MethodRollbackService  = "RollbackService"
 	MethodRestartService   = "RestartService"
 	MethodStartService     = "StartService"
 	MethodStopService      = "StopService"
 
+	MethodStartServiceInstance   = "StartServiceInstance"
+	MethodStopServiceInstance    = "StopServiceInstance"
+	MethodRestartServiceInstance = "RestartServiceInstance"
+
 	MethodAddServiceCatalogEntry      = "AddServiceCatalogEntry"
 	MethodGetAllServiceCatalogEntries = "GetAllServiceCatalogEntries"
 	MethodGetServiceCatalogEntry      = "GetServiceCatalogEntry"
@@ -30,11 +36,21 @@ const (
 
 	MethodGetMostRecentProcessHistoryEntry = "GetMostRecentProcessHistoryEntry"
 	MethodGetLiveOrphanProcessGroups       = "GetLiveOrphanProcessGroups"
+	MethodGetInstanceProcessHistoryEntries = "GetInstanceProcessHistoryEntries"
 
 	MethodSetDependencyWaitStatus   = "SetDependencyWaitStatus"
 	MethodClearDependencyWaitStatus = "ClearDependencyWaitStatus"
 	MethodGetDependencyWaitStatus   = "GetDependencyWaitStatus"
 
+	MethodGetEffectiveLimits = "GetEffectiveLimits"
+	MethodGetNotifyStatus    = "GetNotifyStatus"
+	MethodGetProxyStatus     = "GetProxyStatus"
+	MethodGetCanaryResult    = "GetCanaryResult"
+	MethodGetReleases        = "GetReleases"
+	MethodGetConfigDrift     = "GetConfigDrift"
+
+	MethodResolveServiceEnvironment = "ResolveServiceEnvironment"
+
 	MethodNewServiceLogFiles    = "NewServiceLogFiles"
 	MethodGetServiceLogFilePath = "GetServiceLogFilePath"
 
@@ -48,10 +64,16 @@ var ValidMethods = map[MethodName]bool{
 
 	MethodForceStopService: true,
 	MethodReloadService:    true,
+	MethodDeployService:    true,
+	MethodRollbackService:  true,
 	MethodRestartService:   true,
 	MethodStartService:     true,
 	MethodStopService:      true,
 
+	MethodStartServiceInstance:   true,
+	MethodStopServiceInstance:    true,
+	MethodRestartServiceInstance: true,
+
 	MethodAddServiceCatalogEntry:      true,
 	MethodGetAllServiceCatalogEntries: true,
 	MethodGetServiceCatalogEntry:      true,
@@ -62,11 +84,21 @@ var ValidMethods = map[MethodName]bool{
 
 	MethodGetMostRecentProcessHistoryEntry: true,
 	MethodGetLiveOrphanProcessGroups:       true,
+	MethodGetInstanceProcessHistoryEntries: true,
 
 	MethodSetDependencyWaitStatus:   true,
 	MethodClearDependencyWaitStatus: true,
 	MethodGetDependencyWaitStatus:   true,
 
+	MethodGetEffectiveLimits: true,
+	MethodGetNotifyStatus:    true,
+	MethodGetProxyStatus:     true,
+	MethodGetCanaryResult:    true,
+	MethodGetReleases:        true,
+	MethodGetConfigDrift:     true,
+
+	MethodResolveServiceEnvironment: true,
+
 	MethodNewServiceLogFiles:    true,
 	MethodGetServiceLogFilePath: true,
 
@@ -132,12 +164,51 @@ type ReloadServiceArgs struct {
 	TickerPeriod     string `json:"ticker_period"`
 	ReadinessTimeout string `json:"readiness_timeout"`
 	ProbeInterval    string `json:"probe_interval"`
+	// Canary, when set, is how long both instances serve side by side
+	// before the cutover finishes (eos reload --canary).
+	Canary string `json:"canary,omitempty"`
 }
 
 // ReloadServiceResponse reports the process groups the reload swapped between.
+// A rolling reload of a service running several instances also reports each
+// instance, and the step it stopped at if one failed; that response rides
+// along with the error of a failed rollout, as a canary reload's result does
+// with the error of one rolled back.
 type ReloadServiceResponse struct {
-	OldPGID int `json:"old_pgid"`
-	NewPGID int `json:"new_pgid"`
+	Instances  []ReloadInstance `json:"instances,omitempty"`
+	OldPGID    int              `json:"old_pgid"`
+	NewPGID    int              `json:"new_pgid"`
+	Steps      int              `json:"steps,omitempty"`
+	FailedStep int              `json:"failed_step,omitempty"`
+	Canary     *CanaryResult    `json:"canary,omitempty"`
+}
+
+// DeployServiceArgs asks the daemon to deploy Artifact, a directory or
+// tarball on its filesystem, as a new release of Name, keeping Keep releases
+// (0 for the default). The reload it finishes with takes the same knobs as
+// eos reload.
+type DeployServiceArgs struct {
+	ReloadServiceArgs
+	Artifact string `json:"artifact"`
+	Keep     int    `json:"keep,omitempty"`
+}
+
+// RollbackServiceArgs asks the daemon to switch Name back to Release, or to
+// the release before its current one when empty.
+type RollbackServiceArgs struct {
+	ReloadServiceArgs
+	Release string `json:"release,omitempty"`
+}
+
+// DeployServiceResponse reports the release eos deploy or eos rollback put
+// live, how, and the reload that did it. It rides along with the error of
+// one that failed, as a reload's response does.
+type DeployServiceResponse struct {
+	Release    Release               `json:"release"`
+	Previous   string                `json:"previous,omitempty"`
+	Activation DeployActivation      `json:"activation,omitempty"`
+	Reload     ReloadServiceResponse `json:"reload"`
+	Pruned     []string              `json:"pruned,omitempty"`
 }
 
 type StopServiceArgs struct {
@@ -150,6 +221,31 @@ type ForceStopServiceArgs struct {
 	Name string `json:"name"`
 }
 
+// StartServiceInstanceArgs starts instance Instance of Name on its own (see
+// ServiceConfig.Instances).
+type StartServiceInstanceArgs struct {
+	Name     string `json:"name"`
+	Instance int    `json:"instance"`
+}
+
+// StopServiceInstanceArgs stops instance Instance of Name, leaving its other
+// instances running; the durations are as in StopServiceArgs.
+type StopServiceInstanceArgs struct {
+	Name         string `json:"name"`
+	GracePeriod  string `json:"grace_period"`
+	TickerPeriod string `json:"ticker_period"`
+	Instance     int    `json:"instance"`
+}
+
+// RestartServiceInstanceArgs restarts instance Instance of Name, leaving its
+// other instances running; the durations are as in RestartServiceArgs.
+type RestartServiceInstanceArgs struct {
+	Name         string `json:"name"`
+	GracePeriod  string `json:"grace_period"`
+	TickerPeriod string `json:"ticker_period"`
+	Instance     int    `json:"instance"`
+}
+
 type AddServiceCatalogEntryArgs struct {
 	Service *ServiceCatalogEntry `json:"service"`
 }
@@ -191,6 +287,16 @@ type GetLiveOrphanProcessGroupsResponse struct {
 	Entries []ProcessHistory `json:"entries"`
 }
 
+type GetInstanceProcessHistoryEntriesArgs struct {
+	Name string `json:"name"`
+}
+
+// GetInstanceProcessHistoryEntriesResponse carries the most recent entry of
+// each of a service's instances, ordered by instance index.
+type GetInstanceProcessHistoryEntriesResponse struct {
+	Entries []ProcessHistory `json:"entries"`
+}
+
 // SetDependencyWaitStatusArgs records that ServiceName is currently blocked
 // waiting on Pending to become ready (see manager.RecordDependencyWait).
 // Deadline is this wait's own resolved max_wait ceiling, used for staleness
@@ -219,6 +325,47 @@ type GetDependencyWaitStatusResponse struct {
 	Waiting bool                  `json:"waiting"`
 }
 
+// GetEffectiveLimitsArgs asks for the limits the kernel is enforcing on
+// ServiceName's most recent live launch (see EffectiveLimits).
+type GetEffectiveLimitsArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// GetNotifyStatusArgs asks for what ServiceName's most recent live launch
+// has reported over its notify socket (see NotifyStatus).
+type GetNotifyStatusArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// GetProxyStatusArgs asks for ServiceName's proxy and the instances behind
+// it (see ProxyStatus).
+type GetProxyStatusArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// GetCanaryResultArgs asks for ServiceName's most recent canary reload (see
+// CanaryResult).
+type GetCanaryResultArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// GetReleasesArgs asks for ServiceName's recorded releases (see Release).
+type GetReleasesArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// GetConfigDriftArgs asks how ServiceName's running launch's config differs
+// from its config on disk (see ConfigDrift).
+type GetConfigDriftArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
+// ResolveServiceEnvironmentArgs asks for the environment ServiceName would
+// launch with, over the daemon's own.
+type ResolveServiceEnvironmentArgs struct {
+	ServiceName string `json:"service_name"`
+}
+
 type NewServiceLogFilesArgs struct {
 	ServiceName string `json:"service_name"`
 }
@@ -2,8 +2,11 @@
 package types
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -96,30 +99,753 @@ func (r LogSinkRef) MarshalYAML() (any, error) {
 	return r.Name, nil
 }
 
+// ServiceCommand is ServiceConfig.Command in either of its two forms: a
+// string run through /bin/sh -c (Shell), or a YAML list exec'd directly with
+// no shell in between (Argv), so argv[0] is the process eos launches and
+// signals, and an argument is never word-split or glob-expanded. Exactly one
+// is set on a parsed config.
+type ServiceCommand struct {
+	Argv  []string
+	Shell string
+}
+
+// IsExec reports whether c is the exec (list) form.
+func (c ServiceCommand) IsExec() bool {
+	return len(c.Argv) > 0
+}
+
+// IsZero reports whether no command was configured in either form.
+func (c ServiceCommand) IsZero() bool {
+	return c.Shell == "" && len(c.Argv) == 0
+}
+
+// String renders c for display: the shell form as written, the exec form as
+// the equivalent shell command line, quoting any argument sh would
+// otherwise split or expand.
+func (c ServiceCommand) String() string {
+	if !c.IsExec() {
+		return c.Shell
+	}
+	quoted := make([]string, 0, len(c.Argv))
+	for _, arg := range c.Argv {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote single-quotes arg unless it's made only of characters sh
+// passes through literally.
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// UnmarshalYAML distinguishes a scalar node (`command: ./start.sh`, the
+// shell form) from a sequence node (`command: ["node", "server.js"]`, the
+// exec form).
+func (c *ServiceCommand) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		c.Shell = node.Value
+		return nil
+	case yaml.SequenceNode:
+		if err := node.Decode(&c.Argv); err != nil {
+			return fmt.Errorf("decoding exec-form command: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("line %d: command must be a string or a list of strings", node.Line)
+	}
+}
+
+// MarshalYAML is the inverse of UnmarshalYAML.
+func (c ServiceCommand) MarshalYAML() (any, error) {
+	if c.IsExec() {
+		return c.Argv, nil
+	}
+	return c.Shell, nil
+}
+
+// UnmarshalJSON mirrors UnmarshalYAML: a JSON string is the shell form, an
+// array the exec form.
+func (c *ServiceCommand) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &c.Argv)
+	}
+	return json.Unmarshal(data, &c.Shell)
+}
+
+// MarshalJSON is the inverse of UnmarshalJSON, so `eos api` output shows
+// command exactly as service.yaml wrote it.
+func (c ServiceCommand) MarshalJSON() ([]byte, error) {
+	if c.IsExec() {
+		return json.Marshal(c.Argv)
+	}
+	return json.Marshal(c.Shell)
+}
+
+// EnvFileList is ServiceConfig.EnvFile: one path (`env_file: .env`) or a
+// list of them (`env_file: [.env, .env.local]`), layered in order so a later
+// file overrides an earlier one.
+type EnvFileList []string
+
+// UnmarshalYAML accepts a scalar as a one-element list, and an empty one
+// (`env_file:` or `env_file: ""`) as no env file at all.
+func (l *EnvFileList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = nil
+		if node.Value != "" {
+			*l = EnvFileList{node.Value}
+		}
+		return nil
+	case yaml.SequenceNode:
+		var paths []string
+		if err := node.Decode(&paths); err != nil {
+			return fmt.Errorf("decoding env_file list: %w", err)
+		}
+		*l = paths
+		return nil
+	default:
+		return fmt.Errorf("line %d: env_file must be a path or a list of paths", node.Line)
+	}
+}
+
+// MarshalYAML writes a single path back as a scalar, so a config that never
+// used the list form round-trips unchanged.
+func (l EnvFileList) MarshalYAML() (any, error) {
+	if len(l) == 1 {
+		return l[0], nil
+	}
+	return []string(l), nil
+}
+
+// UnmarshalJSON mirrors UnmarshalYAML: a JSON string is a one-element list.
+func (l *EnvFileList) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var path string
+		if err := json.Unmarshal(data, &path); err != nil {
+			return err
+		}
+		*l = nil
+		if path != "" {
+			*l = EnvFileList{path}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// MarshalJSON is the inverse of UnmarshalJSON.
+func (l EnvFileList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return json.Marshal(l[0])
+	}
+	return json.Marshal([]string(l))
+}
+
+// EnvVar is one entry of ServiceConfig.Env.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// ServiceEnv is service.yaml's env: map, kept in the order it was written so
+// a value's ${VAR} can refer to an entry defined above it (see
+// manager.interpolateEnvValue). Values are the map's scalars as written:
+// `DEBUG: true` is the string "true".
+type ServiceEnv []EnvVar
+
+// UnmarshalYAML reads a mapping node in document order, rejecting a
+// duplicate name or a non-scalar value.
+func (e *ServiceEnv) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: env must be a map of NAME: value", node.Line)
+	}
+	seen := make(map[string]bool, len(node.Content)/2)
+	vars := make(ServiceEnv, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: env.%s must be a string, number or boolean", value.Line, key.Value)
+		}
+		if seen[key.Value] {
+			return fmt.Errorf("line %d: env.%s is defined more than once", key.Line, key.Value)
+		}
+		seen[key.Value] = true
+		text := value.Value
+		if value.Tag == "!!null" {
+			text = ""
+		}
+		vars = append(vars, EnvVar{Name: key.Value, Value: text})
+	}
+	*e = vars
+	return nil
+}
+
+// MarshalYAML is the inverse of UnmarshalYAML, keeping the entries' order.
+func (e ServiceEnv) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, v := range e {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: v.Name},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.Value},
+		)
+	}
+	return node, nil
+}
+
+// MarshalJSON writes e as a JSON object, keeping the entries' order.
+func (e ServiceEnv) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range e {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON, reading the object's members
+// in document order.
+func (e *ServiceEnv) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("env must be a JSON object")
+	}
+	var vars ServiceEnv
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decoding env: %w", err)
+		}
+		name, _ := tok.(string)
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("decoding env.%s: %w", name, err)
+		}
+		vars = append(vars, EnvVar{Name: name, Value: value})
+	}
+	*e = vars
+	return nil
+}
+
 type ServiceConfig struct {
-	Runtime     Runtime `json:"runtime"                  yaml:"runtime"`
-	Name        string  `json:"name"                     yaml:"name"`
-	Command     string  `json:"command"                  yaml:"command"`
-	EnvFile     string  `json:"env_file,omitempty"       yaml:"env_file,omitempty"`
-	CronRestart string  `json:"cron_restart,omitempty"   yaml:"cron_restart,omitempty"`
+	Runtime     Runtime        `json:"runtime"                  yaml:"runtime"`
+	Name        string         `json:"name"                     yaml:"name"`
+	Command     ServiceCommand `json:"command"                  yaml:"command"`
+	CronRestart string         `json:"cron_restart,omitempty"   yaml:"cron_restart,omitempty"`
+	// EnvFile lists dotenv files, relative to the service directory, read in
+	// order (see manager.ParseEnvFile).
+	EnvFile EnvFileList `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+	// Env sets variables inline, layered over EnvFile's (see
+	// manager.buildEnvironment). Each value may interpolate ${VAR} or
+	// ${VAR:-default} against the environment built so far.
+	Env ServiceEnv `json:"env,omitempty" yaml:"env,omitempty"`
+	// PassEnv, with CleanEnv, names the only variables the service inherits
+	// from the daemon's environment; every other one is withheld.
+	PassEnv []string `json:"pass_env,omitempty" yaml:"pass_env,omitempty"`
 	// MaxWait caps how long starting this service blocks on DependsOn becoming
 	// ready before failing loud. Empty uses DependencyDefaultMaxWait. It's the
 	// ceiling on retry-until-ready, not a fixed per-check timeout: a dependency
 	// that comes up slowly still releases the dependent the moment it's ready.
 	MaxWait string `json:"max_wait,omitempty" yaml:"max_wait,omitempty"`
+	// StopSignal names the signal a stop sends first, with or without its
+	// SIG prefix ("SIGINT", "quit"). Empty is SIGTERM.
+	StopSignal string `json:"stop_signal,omitempty" yaml:"stop_signal,omitempty"`
+	// StopTimeout is how long, as a Go duration, a stop waits after
+	// StopSignal before SIGKILL. Empty uses the daemon's shutdown grace
+	// period.
+	StopTimeout string `json:"stop_timeout,omitempty" yaml:"stop_timeout,omitempty"`
+	// KillMode picks which processes StopSignal goes to: "group" (the
+	// default), "leader" or "mixed" (see manager.KillMode).
+	KillMode string `json:"kill_mode,omitempty" yaml:"kill_mode,omitempty"`
+	// Restart is when the health monitor restarts the service after its
+	// process exits: "on-failure" (the default), "always" or "never" (see
+	// manager.RestartMode).
+	Restart string `json:"restart,omitempty" yaml:"restart,omitempty"`
+	// RestartWindow is the Go duration MaxRestarts is counted over. Empty
+	// counts every restart since the service was last started with eos run.
+	RestartWindow string `json:"restart_window,omitempty" yaml:"restart_window,omitempty"`
+	// Type is how the service reports that it has started: "simple" (the
+	// default) is judged by the health monitor from outside, "notify" sends
+	// READY=1 over the sd_notify protocol, "forking" forks its main process
+	// and exits (see manager.ServiceType).
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// PIDFile is where a type: forking service writes its main process's
+	// PID, relative to the service directory unless absolute.
+	PIDFile string `json:"pid_file,omitempty" yaml:"pid_file,omitempty"`
+	// User and Group name the identity the service process runs as, each
+	// either a name or a numeric id. Empty keeps the daemon's own identity;
+	// a User with no Group uses that user's primary group. Switching to
+	// anything other than the daemon's own identity requires a root daemon
+	// (see manager.resolveLaunchCredential).
+	User  string `json:"user,omitempty"  yaml:"user,omitempty"`
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
 	// DependsOn names services that must report healthy (state Running, the
 	// health monitor's own readiness signal) before this service is started.
 	// Empty means start immediately, exactly as a service with no ordering.
-	DependsOn     []string     `json:"depends_on,omitempty"     yaml:"depends_on,omitempty"`
-	LogSinks      []LogSinkRef `json:"log_sinks,omitempty"      yaml:"log_sinks,omitempty"`
-	Port          int          `json:"port,omitempty"           yaml:"port,omitempty"`
-	MemoryLimitMb int          `json:"memory_limit_mb,omitempty" yaml:"memory_limit_mb,omitempty"`
+	DependsOn []string     `json:"depends_on,omitempty"     yaml:"depends_on,omitempty"`
+	LogSinks  []LogSinkRef `json:"log_sinks,omitempty"      yaml:"log_sinks,omitempty"`
+	// SupplementaryGroups replaces the process's supplementary group list
+	// once it drops to User/Group. Empty gives it User's own groups, as a
+	// login would, or none without a User — never the daemon's own (root's).
+	SupplementaryGroups []string `json:"supplementary_groups,omitempty" yaml:"supplementary_groups,omitempty"`
+	// SuccessExitCodes are the exit codes that count as a clean exit, in
+	// addition to 0. RestartExitCodes always restart the service, whatever
+	// Restart says.
+	SuccessExitCodes []int `json:"success_exit_codes,omitempty" yaml:"success_exit_codes,omitempty"`
+	RestartExitCodes []int `json:"restart_exit_codes,omitempty" yaml:"restart_exit_codes,omitempty"`
+	// Sockets are listeners the daemon binds once and hands to every launch
+	// through LISTEN_FDS, keeping them open across restarts and reloads (see
+	// manager.socketsAttach).
+	Sockets []ServiceSocket `json:"sockets,omitempty" yaml:"sockets,omitempty"`
+	// HealthCheck replaces the health monitor's TCP dial to Port with an
+	// application-level check (see manager.HealthCheck). Nil keeps the dial.
+	HealthCheck *ServiceHealthCheck `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+	// Hooks are commands run around the service's own process: before and
+	// after it starts, and before and after it stops (see manager.runHook).
+	Hooks ServiceHooks `json:"hooks,omitzero" yaml:"hooks,omitempty"`
+	// Limits caps the resources the service may use, enforced by the kernel
+	// rather than sampled after the fact like MemoryLimitMb (see
+	// manager.planLimits for which mechanism enforces each field).
+	Limits        ServiceLimits `json:"limits,omitzero" yaml:"limits,omitempty"`
+	Port          int           `json:"port,omitempty"           yaml:"port,omitempty"`
+	MemoryLimitMb int           `json:"memory_limit_mb,omitempty" yaml:"memory_limit_mb,omitempty"`
 	// LogMaxFiles caps how many rotated stdout/stderr log files this service keeps
 	// (active file plus this many rotated siblings). 0 uses the daemon's own default.
 	LogMaxFiles int `json:"log_max_files,omitempty" yaml:"log_max_files,omitempty"`
+	// MaxRestarts caps how many times the health monitor restarts the
+	// service within RestartWindow before giving up on it. 0 is unlimited.
+	MaxRestarts int `json:"max_restarts,omitempty" yaml:"max_restarts,omitempty"`
+	// WatchdogSec is how many seconds a type: notify service may go without
+	// sending WATCHDOG=1 before the health monitor restarts it. 0 disables
+	// the watchdog.
+	WatchdogSec int `json:"watchdog_sec,omitempty" yaml:"watchdog_sec,omitempty"`
 	// LogFileSizeLimitBytes rotates this service's stdout/stderr log once it
 	// reaches this size. 0 uses the daemon's own default.
 	LogFileSizeLimitBytes int64 `json:"log_file_size_limit_bytes,omitempty" yaml:"log_file_size_limit_bytes,omitempty"`
+	// CleanEnv starts the service's environment from PassEnv alone instead
+	// of the daemon's whole environment (see manager.envLayers).
+	CleanEnv bool `json:"clean_env,omitempty" yaml:"clean_env,omitempty"`
+	// Proxy has the daemon listen on Port itself and forward TCP connections
+	// to the service, which is launched on an internal port passed in PORT
+	// (see manager.serviceProxy). There is no HTTP mode.
+	Proxy bool `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// Instances is how many independent process groups to run the service
+	// as, each with EOS_INSTANCE_ID set to its index from 0; unset means 1.
+	Instances int `json:"instances,omitempty" yaml:"instances,omitempty"`
+	// IncrementPort gives instance i of a service with Instances set the
+	// port Port+i in PORT instead of Port itself.
+	IncrementPort bool `json:"increment_port,omitempty" yaml:"increment_port,omitempty"`
+	// MaxSurge and MaxUnavailable size the steps a reload of a service with
+	// Instances set replaces its instances in: each step launches MaxSurge
+	// instances beside the ones they replace and stops MaxUnavailable ahead
+	// of their replacements. Both unset means one at a time, with overlap.
+	MaxSurge       int `json:"max_surge,omitempty"       yaml:"max_surge,omitempty"`
+	MaxUnavailable int `json:"max_unavailable,omitempty" yaml:"max_unavailable,omitempty"`
+	// Build is run by eos deploy in a freshly unpacked release directory,
+	// before the release goes live; a failure abandons the deploy. It takes
+	// the same forms as a hook (see manager.runBuild).
+	Build *ServiceHook `json:"build,omitempty" yaml:"build,omitempty"`
+	// Watch has the daemon restart the service, or reload it, once files
+	// under its directory change (see manager.ParseWatch). Nil watches
+	// nothing.
+	Watch *ServiceWatch `json:"watch,omitempty" yaml:"watch,omitempty"`
+	// AutoRestartOnChange has the health monitor restart the service once
+	// its service.yaml or env files differ from what its running launch was
+	// started with (see manager.ConfigDriftForLaunch).
+	AutoRestartOnChange bool `json:"auto_restart_on_change,omitempty" yaml:"auto_restart_on_change,omitempty"`
+}
+
+// ServiceSocket is one entry of service.yaml's sockets: list. Exactly one of
+// TCP and Unix is set.
+type ServiceSocket struct {
+	// Name is passed to the service in LISTEN_FDNAMES, so it can tell its
+	// sockets apart without relying on their order.
+	Name string `json:"name" yaml:"name"`
+	// TCP is the address to listen on, as host:port; ":8080" listens on
+	// every interface.
+	TCP string `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	// Unix is the path of a Unix stream socket, relative to the service
+	// directory unless absolute.
+	Unix string `json:"unix,omitempty" yaml:"unix,omitempty"`
+}
+
+// ServiceHooks is service.yaml's hooks: block. Every hook is optional. Each
+// runs to completion in the service's directory, with its environment and
+// user:/group:, and its output lands in the service log tagged as hook
+// output.
+type ServiceHooks struct {
+	// PreStart runs before the service launches; a failure aborts the start.
+	PreStart *ServiceHook `json:"pre_start,omitempty" yaml:"pre_start,omitempty"`
+	// PostStart runs once the service has launched. A failure is logged but
+	// leaves the service running.
+	PostStart *ServiceHook `json:"post_start,omitempty" yaml:"post_start,omitempty"`
+	// PreStop runs before the service is sent SIGTERM. A failure is logged
+	// and the stop goes ahead.
+	PreStop *ServiceHook `json:"pre_stop,omitempty" yaml:"pre_stop,omitempty"`
+	// PostStop runs once the service has exited. A failure is logged.
+	PostStop *ServiceHook `json:"post_stop,omitempty" yaml:"post_stop,omitempty"`
+}
+
+// ServiceHook is one lifecycle hook. In YAML it is either a bare command
+// (`pre_start: npm run migrate`) or a mapping with a timeout
+// (`pre_start: {command: npm run migrate, timeout: 5m}`).
+type ServiceHook struct {
+	// Command takes the same string or list forms as ServiceConfig.Command.
+	Command ServiceCommand `json:"command" yaml:"command"`
+	// Timeout is a Go duration after which the hook is killed and counted
+	// as failed. Empty uses manager.HookDefaultTimeout.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// ServiceWatch is service.yaml's watch: block.
+type ServiceWatch struct {
+	// Include and Exclude are globs over paths relative to the service's
+	// directory: a pattern without a slash matches a name at any depth,
+	// ** matches any number of directories, and a directory matching
+	// matches everything under it. Empty Include watches every file.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Debounce is the Go duration changes must settle for before the
+	// service is restarted. Empty uses manager.WatchDefaultDebounce.
+	Debounce string `json:"debounce,omitempty" yaml:"debounce,omitempty"`
+	// ReloadOnChange reloads the service with zero downtime instead of
+	// restarting it.
+	ReloadOnChange bool `json:"reload_on_change,omitempty" yaml:"reload_on_change,omitempty"`
+}
+
+// UnmarshalYAML accepts a bare command as shorthand for {command: ...}.
+func (h *ServiceHook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		var command ServiceCommand
+		if err := node.Decode(&command); err != nil {
+			return err
+		}
+		*h = ServiceHook{Command: command}
+		return nil
+	}
+	type plain ServiceHook
+	var hook plain
+	if err := node.Decode(&hook); err != nil {
+		return err
+	}
+	*h = ServiceHook(hook)
+	return nil
+}
+
+// ServiceHealthCheck is service.yaml's health_check: block. Exactly one of
+// HTTP, TCP and Exec is the liveness check: the health monitor runs it every
+// Interval once StartPeriod has passed, and restarts the service after
+// FailureThreshold failures in a row. Readiness, when set, is a separate check
+// for whether the service is ready to serve: it holds a fresh launch in
+// Starting and gates eos reload's cutover. It defaults to the liveness check.
+type ServiceHealthCheck struct {
+	HTTP      *HTTPHealthCheck  `json:"http,omitempty"      yaml:"http,omitempty"`
+	TCP       *TCPHealthCheck   `json:"tcp,omitempty"       yaml:"tcp,omitempty"`
+	Exec      *ServiceCommand   `json:"exec,omitempty"      yaml:"exec,omitempty"`
+	Readiness *HealthCheckProbe `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	// Interval, Timeout and StartPeriod are Go durations. Empty uses
+	// manager.HealthCheckDefaultInterval, manager.HealthCheckDefaultTimeout
+	// and no start period.
+	Interval    string `json:"interval,omitempty"     yaml:"interval,omitempty"`
+	Timeout     string `json:"timeout,omitempty"      yaml:"timeout,omitempty"`
+	StartPeriod string `json:"start_period,omitempty" yaml:"start_period,omitempty"`
+	// FailureThreshold is how many failed liveness checks in a row restart
+	// the service. 0 uses manager.HealthCheckDefaultFailureThreshold.
+	FailureThreshold int `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+}
+
+// Liveness returns the check's top-level probe.
+func (c *ServiceHealthCheck) Liveness() HealthCheckProbe {
+	return HealthCheckProbe{HTTP: c.HTTP, TCP: c.TCP, Exec: c.Exec}
+}
+
+// HealthCheckProbe is one check: exactly one of HTTP, TCP and Exec.
+type HealthCheckProbe struct {
+	HTTP *HTTPHealthCheck `json:"http,omitempty" yaml:"http,omitempty"`
+	TCP  *TCPHealthCheck  `json:"tcp,omitempty"  yaml:"tcp,omitempty"`
+	// Exec runs a command, in the same string or list forms as
+	// ServiceConfig.Command, with the service's directory, environment and
+	// user. Exit code 0 passes.
+	Exec *ServiceCommand `json:"exec,omitempty" yaml:"exec,omitempty"`
+}
+
+// HTTPHealthCheck is a GET request to localhost. It passes when the response
+// status falls in Status and, when set, the body contains BodyContains.
+type HTTPHealthCheck struct {
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Path is the request path. Empty is "/".
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Status is the accepted status code, or an inclusive range of them
+	// ("200-299"). Empty is "200-399".
+	Status       string `json:"status,omitempty"        yaml:"status,omitempty"`
+	BodyContains string `json:"body_contains,omitempty" yaml:"body_contains,omitempty"`
+	// Port defaults to the service's own port.
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// TCPHealthCheck passes when a connection to localhost on Port succeeds.
+type TCPHealthCheck struct {
+	// Port defaults to the service's own port.
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// ServiceLimits is service.yaml's limits: block. Every field is optional;
+// an omitted one leaves that resource at whatever the daemon itself runs
+// with. With a delegated cgroup v2 subtree, MemoryMax, CPUQuota and PidsMax
+// are written to the launch's own cgroup (memory.max, cpu.max, pids.max), so
+// they cap the service as a whole; without one, MemoryMax falls back to
+// RLIMIT_AS on the launched process, and CPUQuota and PidsMax can't be
+// enforced at all. Nofile and Core are always per-process rlimits.
+type ServiceLimits struct {
+	// MemoryMax is a byte count with an optional K, M, G or T suffix
+	// (powers of 1024), or "max" for no limit.
+	MemoryMax string `json:"memory_max,omitempty" yaml:"memory_max,omitempty"`
+	// CPUQuota is a percentage of one CPU, e.g. "50%" or "200%" for two
+	// full cores.
+	CPUQuota string `json:"cpu_quota,omitempty" yaml:"cpu_quota,omitempty"`
+	// Core is the largest core dump the service may write, in the same
+	// format as MemoryMax; "0" disables core dumps and "unlimited" lifts the
+	// cap.
+	Core    string `json:"core,omitempty"     yaml:"core,omitempty"`
+	PidsMax int    `json:"pids_max,omitempty" yaml:"pids_max,omitempty"`
+	Nofile  int    `json:"nofile,omitempty"   yaml:"nofile,omitempty"`
+}
+
+// NotifyStatus is what a type: notify service's most recent live launch has
+// reported over its NOTIFY_SOCKET, as the daemon holds it in memory.
+type NotifyStatus struct {
+	// LastWatchdog is when the launch last sent WATCHDOG=1; nil if never.
+	LastWatchdog *time.Time `json:"last_watchdog,omitempty"`
+	// Status is the text of the launch's latest STATUS= message.
+	Status string `json:"status,omitempty"`
+	// MainPID is the process the launch named with MAINPID=, or 0 for the
+	// process eos launched.
+	MainPID int `json:"main_pid,omitempty"`
+	// PGID is the launch the status belongs to; 0 means the service has no
+	// live launch with a notify socket, and every other field is empty.
+	PGID int `json:"pgid"`
+	// Ready is true once the launch has sent READY=1.
+	Ready bool `json:"ready"`
+}
+
+// ProxyStatus is the daemon's proxy for a service with proxy: true, as it
+// holds it in memory: the public port it listens on and the instances it
+// forwards to.
+type ProxyStatus struct {
+	Backends []ProxyBackendStatus `json:"backends,omitempty"`
+	// Port is the public port the proxy listens on; 0 means the service has
+	// no proxy running, and Backends is empty.
+	Port int `json:"port"`
+}
+
+// ProxyBackendStatus is one instance behind a service's proxy.
+type ProxyBackendStatus struct {
+	// PGID is the instance's launch.
+	PGID int `json:"pgid"`
+	// Port is the internal port the instance was given in PORT.
+	Port int `json:"port"`
+	// Connections is how many proxied connections to the instance are open.
+	Connections int64 `json:"connections"`
+	// TotalConnections is how many connections the proxy has forwarded to
+	// the instance since it launched.
+	TotalConnections int64 `json:"total_connections"`
+	// Serving is true for the one instance new connections go to; the rest
+	// are draining their open connections ahead of being stopped.
+	Serving bool `json:"serving"`
+}
+
+// ReloadInstance is what a rolling reload did to one instance of a service
+// running several.
+type ReloadInstance struct {
+	// Instance is the instance's index.
+	Instance int `json:"instance"`
+	// OldPGID is the launch the reload replaced; 0 when the instance wasn't
+	// running.
+	OldPGID int `json:"old_pgid"`
+	// NewPGID is the launch that replaced it; 0 when the rollout stopped
+	// before reaching the instance.
+	NewPGID int `json:"new_pgid"`
+	// Step is the rollout step, from 1, the instance was replaced in.
+	Step int `json:"step"`
+	// RolledBack is true when a failed step undid the instance's
+	// replacement, leaving OldPGID running again.
+	RolledBack bool `json:"rolled_back,omitempty"`
+	// Relaunched is true when OldPGID had been stopped ahead of its
+	// replacement (max_unavailable), so the rollback launched the instance
+	// again as NewPGID instead.
+	Relaunched bool `json:"relaunched,omitempty"`
+}
+
+// CanaryOutcome is how a canary reload (eos reload --canary) ended.
+type CanaryOutcome string
+
+const (
+	// CanaryOutcomePromoted means the incoming instance held up for the whole
+	// window and the cutover finished.
+	CanaryOutcomePromoted CanaryOutcome = "promoted"
+	// CanaryOutcomeRolledBack means the incoming instance was stopped and the
+	// outgoing one kept serving; Reason says why.
+	CanaryOutcomeRolledBack CanaryOutcome = "rolled_back"
+)
+
+// CanaryResult is a service's most recent canary reload: its outcome and the
+// metrics, gathered over the window from the two instances, it was decided
+// on. It's persisted to state.db, one row per service, for eos info.
+type CanaryResult struct {
+	ServiceName string        `json:"service_name"`
+	Outcome     CanaryOutcome `json:"outcome"`
+	// Reason names the check that rolled the canary back; empty when it
+	// was promoted.
+	Reason    string        `json:"reason,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+	EndedAt   time.Time     `json:"ended_at"`
+	Window    time.Duration `json:"window"`
+	OldPGID   int           `json:"old_pgid"`
+	NewPGID   int           `json:"new_pgid"`
+	// NewExited is true when the incoming instance's process group exited
+	// during the window.
+	NewExited bool `json:"new_exited,omitempty"`
+	// NewErrorLines and OldErrorLines count the stderr lines each instance
+	// wrote during the window that look like errors (see
+	// logutil.CountErrorLines).
+	NewErrorLines int `json:"new_error_lines"`
+	OldErrorLines int `json:"old_error_lines"`
+	// HealthChecks is how many times the incoming instance was probed,
+	// HealthFailures how many of those failed.
+	HealthChecks   int `json:"health_checks"`
+	HealthFailures int `json:"health_failures"`
+}
+
+// ReleaseState is where a release deployed with eos deploy stands.
+type ReleaseState string
+
+const (
+	// ReleaseStateCurrent is the release the service's current symlink
+	// points at. A service has at most one.
+	ReleaseStateCurrent ReleaseState = "current"
+	// ReleaseStateRetired is a release that was current once, or was
+	// unpacked but never went live, and can be rolled back to.
+	ReleaseStateRetired ReleaseState = "retired"
+	// ReleaseStateFailed is a release whose build failed, or whose reload
+	// never became healthy and was switched away from again.
+	ReleaseStateFailed ReleaseState = "failed"
+)
+
+// Release is one directory under a service's releases/, as recorded in
+// state.db by eos deploy and eos rollback.
+type Release struct {
+	ServiceName string `json:"service_name"`
+	// Name is the release's directory name under releases/, its UTC
+	// deploy timestamp.
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Source is the artifact directory or tarball it was unpacked from.
+	Source    string       `json:"source"`
+	State     ReleaseState `json:"state"`
+	CreatedAt time.Time    `json:"created_at"`
+	// ActivatedAt is when current last switched to it; nil if it never
+	// went live.
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	// Error is why it failed, for a ReleaseStateFailed release.
+	Error string `json:"error,omitempty"`
+}
+
+// LaunchConfig is the config a launch was started with, recorded beside its
+// process_history row so a later edit to service.yaml can be told apart from
+// what is actually running.
+type LaunchConfig struct {
+	RecordedAt  time.Time `json:"recorded_at"`
+	ServiceName string    `json:"service_name"`
+	ConfigHash  string    `json:"config_hash"`
+	// EnvFileHash covers the contents of the env files Config names, which
+	// ConfigHash alone doesn't see.
+	EnvFileHash string        `json:"env_file_hash"`
+	Config      ServiceConfig `json:"config"`
+	PGID        int           `json:"pgid"`
+}
+
+// ConfigChange is one field that differs between a launch's config and the
+// one on disk, named by its dotted service.yaml path. Old or New is empty
+// when the field was unset on that side.
+type ConfigChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// ConfigDrift compares a running launch's recorded config with the service's
+// config on disk now.
+type ConfigDrift struct {
+	ServiceName string         `json:"service_name"`
+	Changes     []ConfigChange `json:"changes,omitempty"`
+	PGID        int            `json:"pgid"`
+	// EnvChanged reports that an env file's contents changed, which shows
+	// up in no Changes field.
+	EnvChanged bool `json:"env_changed,omitempty"`
+}
+
+// Pending reports whether the launch is running a config other than the
+// one on disk, so a restart would change something.
+func (d ConfigDrift) Pending() bool {
+	return len(d.Changes) > 0 || d.EnvChanged
+}
+
+// DeployActivation is how eos deploy or eos rollback put a release live.
+type DeployActivation string
+
+const (
+	// DeployActivationReloaded swapped the running service onto the
+	// release with a reload.
+	DeployActivationReloaded DeployActivation = "reloaded"
+	// DeployActivationRestarted restarted a service that had failed, as
+	// there was nothing running to reload.
+	DeployActivationRestarted DeployActivation = "restarted"
+	// DeployActivationNotRunning only switched current: the service is
+	// stopped, and runs the release the next time it is started.
+	DeployActivationNotRunning DeployActivation = "not_running"
+)
+
+// EffectiveLimits is what the kernel reports it is actually enforcing on a
+// service's most recent live launch, read back from its cgroup leaf and
+// prlimit(2) rather than from service.yaml — so eos info can show a limit
+// that was configured but couldn't be applied. Values are in the kernel's
+// own formats ("max", "unlimited", a byte count, "<quota> <period>"); an
+// empty field wasn't readable, e.g. a cgroup file whose controller isn't
+// enabled for the leaf.
+type EffectiveLimits struct {
+	// MemoryMax, CPUMax and PidsMax are the launch cgroup's memory.max,
+	// cpu.max and pids.max. All empty when the launch isn't cgroup-tracked.
+	MemoryMax string `json:"memory_max,omitempty"`
+	CPUMax    string `json:"cpu_max,omitempty"`
+	PidsMax   string `json:"pids_max,omitempty"`
+	// Nofile, Core and AddressSpace are the launch leader's RLIMIT_NOFILE,
+	// RLIMIT_CORE and RLIMIT_AS.
+	Nofile       string `json:"nofile,omitempty"`
+	Core         string `json:"core,omitempty"`
+	AddressSpace string `json:"address_space,omitempty"`
+	// PGID is the launch the values were read from; 0 means the service has
+	// no live launch and every other field is empty.
+	PGID int `json:"pgid"`
+	// Cgroup is true when the launch lives in its own cgroup leaf.
+	Cgroup bool `json:"cgroup"`
 }
 
 // DependencyWaitStatus records that a service's start is currently gated on
@@ -145,15 +871,23 @@ type DependencyWaitStatus struct {
 }
 
 type ServiceInstance struct {
-	CreatedAt        time.Time  `json:"created_at" yaml:"created_at"`
-	LastHealthCheck  *time.Time `json:"last_health_check,omitempty" yaml:"last_health_check,omitempty"`
-	StartedAt        *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
-	UpdatedAt        *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
-	NextRestartAt    *time.Time `json:"next_restart_at,omitempty" yaml:"next_restart_at,omitempty"`
-	Name             string     `json:"name" yaml:"name"`
-	FailureSignature string     `json:"failure_signature,omitempty" yaml:"failure_signature,omitempty"`
-	RestartCount     int        `json:"restart_count,omitempty" yaml:"restart_count,omitempty"`
-	FailureLoopCount int        `json:"failure_loop_count,omitempty" yaml:"failure_loop_count,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" yaml:"created_at"`
+	LastHealthCheck *time.Time `json:"last_health_check,omitempty" yaml:"last_health_check,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	// RestartWindowStart and RestartWindowCount are the health monitor's
+	// count of restarts against the service's max_restarts: how many it has
+	// made since the current restart_window opened.
+	RestartWindowStart *time.Time `json:"restart_window_start,omitempty" yaml:"restart_window_start,omitempty"`
+	NextRestartAt      *time.Time `json:"next_restart_at,omitempty" yaml:"next_restart_at,omitempty"`
+	Name               string     `json:"name" yaml:"name"`
+	FailureSignature   string     `json:"failure_signature,omitempty" yaml:"failure_signature,omitempty"`
+	// GaveUpReason is set once the health monitor has stopped restarting the
+	// service for good, per its restart policy; eos run clears it.
+	GaveUpReason       string `json:"gave_up_reason,omitempty" yaml:"gave_up_reason,omitempty"`
+	RestartCount       int    `json:"restart_count,omitempty" yaml:"restart_count,omitempty"`
+	FailureLoopCount   int    `json:"failure_loop_count,omitempty" yaml:"failure_loop_count,omitempty"`
+	RestartWindowCount int    `json:"restart_window_count,omitempty" yaml:"restart_window_count,omitempty"`
 }
 
 type ProcessState string
@@ -192,6 +926,9 @@ type ProcessHistory struct {
 	// during liveness checks rules out a false match against an unrelated
 	// later process that reused the same PGID.
 	StartedAtTicks int64 `json:"started_at_ticks" yaml:"started_at_ticks"`
+	// Instance is which of the service's instances this launch is, counting
+	// from 0; always 0 for a service without instances: set.
+	Instance int `json:"instance" yaml:"instance"`
 }
 
 type RunningProcess struct {
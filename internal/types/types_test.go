@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"slices"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -60,7 +62,7 @@ func TestLogSinkRef_UnmarshalYAML_List(t *testing.T) {
 func TestLogSinkRef_MarshalYAML_RoundTrip(t *testing.T) {
 	sc := ServiceConfig{
 		Name:    "api",
-		Command: "dist/server.js",
+		Command: ServiceCommand{Shell: "dist/server.js"},
 		LogSinks: []LogSinkRef{
 			{Name: "prod-loki"},
 			{Inline: &LogSink{Type: "file", Address: "/var/log/eos"}},
@@ -84,3 +86,198 @@ func TestLogSinkRef_MarshalYAML_RoundTrip(t *testing.T) {
 		t.Errorf("entry 1: expected inline file sink, got %+v", got.LogSinks[1])
 	}
 }
+
+func TestServiceCommand_UnmarshalYAML(t *testing.T) {
+	var shell ServiceConfig
+	if err := yaml.Unmarshal([]byte("command: npm start\n"), &shell); err != nil {
+		t.Fatalf("unmarshal shell form: %v", err)
+	}
+	if shell.Command.IsExec() || shell.Command.Shell != "npm start" {
+		t.Errorf("shell form: got %+v, want Shell %q", shell.Command, "npm start")
+	}
+
+	var exec ServiceConfig
+	if err := yaml.Unmarshal([]byte(`command: ["node", "dist/server.js", "--port", "3000"]`), &exec); err != nil {
+		t.Fatalf("unmarshal exec form: %v", err)
+	}
+	if !exec.Command.IsExec() || len(exec.Command.Argv) != 4 || exec.Command.Argv[0] != "node" {
+		t.Errorf("exec form: got %+v, want Argv [node dist/server.js --port 3000]", exec.Command)
+	}
+
+	var bad ServiceConfig
+	if err := yaml.Unmarshal([]byte("command: {run: node}\n"), &bad); err == nil {
+		t.Error("expected a mapping command to be rejected")
+	}
+}
+
+func TestServiceCommand_RoundTrip(t *testing.T) {
+	for _, c := range []ServiceCommand{{Shell: "./start.sh"}, {Argv: []string{"node", "server.js"}}} {
+		data, err := yaml.Marshal(ServiceConfig{Name: "api", Command: c})
+		if err != nil {
+			t.Fatalf("yaml marshal: %v", err)
+		}
+		var fromYAML ServiceConfig
+		if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+			t.Fatalf("yaml unmarshal: %v", err)
+		}
+		if fromYAML.Command.String() != c.String() || fromYAML.Command.IsExec() != c.IsExec() {
+			t.Errorf("yaml round trip: got %+v, want %+v", fromYAML.Command, c)
+		}
+
+		data, err = json.Marshal(c)
+		if err != nil {
+			t.Fatalf("json marshal: %v", err)
+		}
+		var fromJSON ServiceCommand
+		if err := json.Unmarshal(data, &fromJSON); err != nil {
+			t.Fatalf("json unmarshal: %v", err)
+		}
+		if fromJSON.String() != c.String() || fromJSON.IsExec() != c.IsExec() {
+			t.Errorf("json round trip: got %+v, want %+v", fromJSON, c)
+		}
+	}
+}
+
+func TestServiceCommand_String(t *testing.T) {
+	c := ServiceCommand{Argv: []string{"node", "dist/server.js", "hello world", "it's"}}
+	want := `node dist/server.js 'hello world' 'it'\''s'`
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestServiceEnv_KeepsOrderThroughYAMLAndJSON(t *testing.T) {
+	var cfg ServiceConfig
+	doc := "env:\n  ZED: last-alphabetically\n  PORT: 3000\n  DEBUG: true\n  EMPTY:\n"
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := ServiceEnv{{"ZED", "last-alphabetically"}, {"PORT", "3000"}, {"DEBUG", "true"}, {"EMPTY", ""}}
+	if len(cfg.Env) != len(want) {
+		t.Fatalf("Env = %v, want %v", cfg.Env, want)
+	}
+	for i := range want {
+		if cfg.Env[i] != want[i] {
+			t.Errorf("Env[%d] = %+v, want %+v", i, cfg.Env[i], want[i])
+		}
+	}
+
+	data, err := yaml.Marshal(cfg.Env)
+	if err != nil {
+		t.Fatalf("yaml marshal: %v", err)
+	}
+	var fromYAML ServiceEnv
+	if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatalf("yaml unmarshal: %v", err)
+	}
+	data, err = json.Marshal(fromYAML)
+	if err != nil {
+		t.Fatalf("json marshal: %v", err)
+	}
+	var fromJSON ServiceEnv
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+	for i := range want {
+		if i >= len(fromJSON) || fromJSON[i] != want[i] {
+			t.Errorf("round trip: got %v, want %v", fromJSON, want)
+			break
+		}
+	}
+}
+
+func TestServiceEnv_RejectsDuplicatesAndNesting(t *testing.T) {
+	var cfg ServiceConfig
+	if err := yaml.Unmarshal([]byte("env:\n  A: 1\n  A: 2\n"), &cfg); err == nil {
+		t.Error("expected a duplicate env name to be rejected")
+	}
+	if err := yaml.Unmarshal([]byte("env:\n  A:\n    nested: 1\n"), &cfg); err == nil {
+		t.Error("expected a nested env value to be rejected")
+	}
+	if err := yaml.Unmarshal([]byte("env:\n  - A=1\n"), &cfg); err == nil {
+		t.Error("expected a list-shaped env to be rejected")
+	}
+}
+
+func TestEnvFileList_AcceptsPathOrList(t *testing.T) {
+	cases := []struct {
+		doc  string
+		want EnvFileList
+	}{
+		{"env_file: .env\n", EnvFileList{".env"}},
+		{"env_file: [.env, .env.local]\n", EnvFileList{".env", ".env.local"}},
+		{"env_file:\n", nil},
+		{"env_file: \"\"\n", nil},
+	}
+	for _, tc := range cases {
+		var cfg ServiceConfig
+		if err := yaml.Unmarshal([]byte(tc.doc), &cfg); err != nil {
+			t.Fatalf("unmarshal %q: %v", tc.doc, err)
+		}
+		if !slices.Equal(cfg.EnvFile, tc.want) {
+			t.Errorf("unmarshal %q: EnvFile = %v, want %v", tc.doc, cfg.EnvFile, tc.want)
+		}
+
+		data, err := json.Marshal(cfg.EnvFile)
+		if err != nil {
+			t.Fatalf("json marshal: %v", err)
+		}
+		var fromJSON EnvFileList
+		if err := json.Unmarshal(data, &fromJSON); err != nil {
+			t.Fatalf("json unmarshal %s: %v", data, err)
+		}
+		if !slices.Equal(fromJSON, tc.want) {
+			t.Errorf("json round trip of %v: got %v", tc.want, fromJSON)
+		}
+	}
+
+	data, err := yaml.Marshal(map[string]EnvFileList{"env_file": {".env"}})
+	if err != nil {
+		t.Fatalf("yaml marshal: %v", err)
+	}
+	if string(data) != "env_file: .env\n" {
+		t.Errorf("single env_file marshals as %q, want a plain scalar", data)
+	}
+	if err := yaml.Unmarshal([]byte("env_file:\n  a: b\n"), new(ServiceConfig)); err == nil {
+		t.Error("expected a mapping env_file to be rejected")
+	}
+}
+
+func TestServiceHook_AcceptsCommandOrMapping(t *testing.T) {
+	doc := `hooks:
+  pre_start: npm run migrate
+  post_start: ["./bin/notify", "up"]
+  pre_stop:
+    command: ./drain.sh
+    timeout: 5m
+`
+	var cfg ServiceConfig
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	hooks := cfg.Hooks
+	if hooks.PreStart == nil || hooks.PreStart.Command.Shell != "npm run migrate" || hooks.PreStart.Timeout != "" {
+		t.Errorf("pre_start = %+v, want shell command with no timeout", hooks.PreStart)
+	}
+	if hooks.PostStart == nil || !slices.Equal(hooks.PostStart.Command.Argv, []string{"./bin/notify", "up"}) {
+		t.Errorf("post_start = %+v, want exec command", hooks.PostStart)
+	}
+	if hooks.PreStop == nil || hooks.PreStop.Command.Shell != "./drain.sh" || hooks.PreStop.Timeout != "5m" {
+		t.Errorf("pre_stop = %+v, want ./drain.sh with a 5m timeout", hooks.PreStop)
+	}
+	if hooks.PostStop != nil {
+		t.Errorf("post_stop = %+v, want nil", hooks.PostStop)
+	}
+
+	data, err := json.Marshal(cfg.Hooks)
+	if err != nil {
+		t.Fatalf("json marshal: %v", err)
+	}
+	var fromJSON ServiceHooks
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json unmarshal %s: %v", data, err)
+	}
+	if fromJSON.PreStop == nil || fromJSON.PreStop.Timeout != "5m" || fromJSON.PostStart.Command.String() != hooks.PostStart.Command.String() {
+		t.Errorf("json round trip: got %s", data)
+	}
+}
@@ -33,6 +33,64 @@ func UserCredentials(u *user.User) (uid uint32, gid uint32, err error) {
 	return uint32(uidInt), uint32(gidInt), nil
 }
 
+// UserGroupIDs returns the gids of every group u belongs to, its primary
+// group included: the list initgroups(3) would give a login as u.
+func UserGroupIDs(u *user.User) ([]uint32, error) {
+	ids, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("listing groups of %s: %w", u.Username, err)
+	}
+	gids := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		gid, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing gid %q: %w", id, err)
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
+// LookupUser resolves a service.yaml user: value, which may be either a
+// login name or a numeric uid. A bare number is tried as a name first, so a
+// user literally named "1000" still wins over uid 1000, matching how
+// chown(1) and systemd's User= resolve the same ambiguity.
+func LookupUser(nameOrID string) (*user.User, error) {
+	u, err := user.Lookup(nameOrID)
+	if err == nil {
+		return u, nil
+	}
+	if _, parseErr := strconv.ParseUint(nameOrID, 10, 32); parseErr != nil {
+		return nil, fmt.Errorf("looking up user %q: %w", nameOrID, err)
+	}
+	u, idErr := user.LookupId(nameOrID)
+	if idErr != nil {
+		return nil, fmt.Errorf("looking up user %q: %w", nameOrID, idErr)
+	}
+	return u, nil
+}
+
+// LookupGroupID resolves a group name or numeric gid to a gid suitable for
+// syscall.Credential, with the same name-before-number precedence as
+// LookupUser.
+func LookupGroupID(nameOrID string) (uint32, error) {
+	g, err := user.LookupGroup(nameOrID)
+	if err != nil {
+		if _, parseErr := strconv.ParseUint(nameOrID, 10, 32); parseErr != nil {
+			return 0, fmt.Errorf("looking up group %q: %w", nameOrID, err)
+		}
+		g, err = user.LookupGroupId(nameOrID)
+		if err != nil {
+			return 0, fmt.Errorf("looking up group %q: %w", nameOrID, err)
+		}
+	}
+	gid, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parsing gid of group %q: %w", nameOrID, err)
+	}
+	return uint32(gid), nil
+}
+
 // Identity is the resolved answer to "which user is this process really
 // running as." The field is unexported so the only way to obtain a value is
 // through ResolveIdentity — callers cannot fake or re-derive one by hand,
@@ -42,6 +100,10 @@ type Identity struct {
 	u   *user.User
 	uid uint32
 	gid uint32
+	// euid and egid are the credentials the process itself holds, which
+	// differ from uid and gid under sudo: there the process is still root.
+	euid uint32
+	egid uint32
 }
 
 // ResolveIdentity is the only way to obtain an Identity. It wraps
@@ -57,7 +119,8 @@ func ResolveIdentity() (Identity, error) {
 	if err != nil {
 		return Identity{}, fmt.Errorf("resolving user credentials: %w", err)
 	}
-	return Identity{u: u, uid: uid, gid: gid}, nil
+	//nolint:gosec // G115: euid/egid are never negative on the POSIX platforms eos targets (linux, darwin)
+	return Identity{u: u, uid: uid, gid: gid, euid: uint32(os.Geteuid()), egid: uint32(os.Getegid())}, nil
 }
 
 // HomeDir returns the identity's home directory.
@@ -71,3 +134,11 @@ func (i Identity) UID() uint32 { return i.uid }
 
 // GID returns the identity's numeric gid.
 func (i Identity) GID() uint32 { return i.gid }
+
+// ProcessUID returns the effective uid this process runs with. It is UID
+// except under sudo, where UID is the invoking user and this is still 0.
+func (i Identity) ProcessUID() uint32 { return i.euid }
+
+// ProcessGID returns the effective gid this process runs with, GID except
+// under sudo.
+func (i Identity) ProcessGID() uint32 { return i.egid }
@@ -3,6 +3,8 @@ package userutil
 import (
 	"os"
 	"os/user"
+	"slices"
+	"strconv"
 	"testing"
 )
 
@@ -189,6 +191,28 @@ func TestResolveIdentity(t *testing.T) {
 			t.Errorf("expected fallback to current user %q, got %q", cur.Username, id.Username())
 		}
 	})
+
+	t.Run("root, SUDO_USER set: process ids stay root's", func(t *testing.T) {
+		if os.Geteuid() != 0 {
+			t.Skip("must run as root")
+		}
+		nobody, err := user.Lookup("nobody")
+		if err != nil {
+			t.Skip("no nobody user on this host")
+		}
+		t.Setenv("SUDO_USER", nobody.Username)
+
+		id, err := ResolveIdentity()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strconv.FormatUint(uint64(id.UID()), 10) != nobody.Uid {
+			t.Errorf("expected UID to be the invoking user's %s, got %d", nobody.Uid, id.UID())
+		}
+		if id.ProcessUID() != 0 || int(id.ProcessGID()) != os.Getegid() {
+			t.Errorf("expected process ids 0/%d, got %d/%d", os.Getegid(), id.ProcessUID(), id.ProcessGID())
+		}
+	})
 }
 
 func TestUserCredentials(t *testing.T) {
@@ -222,3 +246,92 @@ func TestUserCredentials(t *testing.T) {
 		}
 	})
 }
+
+func TestUserGroupIDs(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+	_, gid, err := UserCredentials(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gids, err := UserGroupIDs(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(gids, gid) {
+		t.Errorf("expected the primary gid %d among %v", gid, gids)
+	}
+}
+
+func TestLookupUser(t *testing.T) {
+	cur, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		got, err := LookupUser(cur.Username)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Uid != cur.Uid {
+			t.Errorf("expected uid %s, got %s", cur.Uid, got.Uid)
+		}
+	})
+
+	t.Run("by numeric uid", func(t *testing.T) {
+		got, err := LookupUser(cur.Uid)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Username != cur.Username {
+			t.Errorf("expected username %q, got %q", cur.Username, got.Username)
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		if _, err := LookupUser("eos-no-such-user-xyz"); err == nil {
+			t.Error("expected error for unknown user")
+		}
+	})
+}
+
+func TestLookupGroupID(t *testing.T) {
+	cur, err := user.Current()
+	if err != nil {
+		t.Skip("cannot determine current user")
+	}
+	g, err := user.LookupGroupId(cur.Gid)
+	if err != nil {
+		t.Skip("cannot resolve current user's primary group")
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		got, err := LookupGroupID(g.Name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := cur.Gid; strconv.FormatUint(uint64(got), 10) != want {
+			t.Errorf("expected gid %s, got %d", want, got)
+		}
+	})
+
+	t.Run("by numeric gid", func(t *testing.T) {
+		got, err := LookupGroupID(cur.Gid)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strconv.FormatUint(uint64(got), 10) != cur.Gid {
+			t.Errorf("expected gid %s, got %d", cur.Gid, got)
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		if _, err := LookupGroupID("eos-no-such-group-xyz"); err == nil {
+			t.Error("expected error for unknown group")
+		}
+	})
+}
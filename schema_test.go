@@ -186,6 +186,104 @@ func TestServiceSchemaLogSinkMatchesLogSinkStruct(t *testing.T) {
 	}
 }
 
+// TestServiceSchemaPropertiesMatchServiceConfig guards schemas/service.schema.json's
+// top-level properties against types.ServiceConfig's yaml fields. The schema sets
+// additionalProperties: false, so a field added to the struct but not the schema
+// turns every service.yaml using it into an editor-side validation error even
+// though eos itself accepts it.
+func TestServiceSchemaPropertiesMatchServiceConfig(t *testing.T) {
+	raw, err := os.ReadFile("schemas/service.schema.json")
+	if err != nil {
+		t.Fatalf("reading schemas/service.schema.json: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("parsing schemas/service.schema.json: %v", err)
+	}
+
+	schemaFields := make([]string, 0, len(schema.Properties))
+	for k := range schema.Properties {
+		schemaFields = append(schemaFields, k)
+	}
+	sort.Strings(schemaFields)
+
+	structFields := yamlFieldNames(types.ServiceConfig{})
+	sort.Strings(structFields)
+
+	if !reflect.DeepEqual(schemaFields, structFields) {
+		t.Errorf("schema properties %v do not match types.ServiceConfig yaml fields %v", schemaFields, structFields)
+	}
+}
+
+// TestServiceSchemaLimitsMatchServiceLimits is the same guard one level down,
+// for the limits: object, which also sets additionalProperties: false.
+func TestServiceSchemaLimitsMatchServiceLimits(t *testing.T) {
+	raw, err := os.ReadFile("schemas/service.schema.json")
+	if err != nil {
+		t.Fatalf("reading schemas/service.schema.json: %v", err)
+	}
+
+	var schema struct {
+		Properties struct {
+			Limits struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"limits"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("parsing schemas/service.schema.json: %v", err)
+	}
+
+	schemaFields := make([]string, 0, len(schema.Properties.Limits.Properties))
+	for k := range schema.Properties.Limits.Properties {
+		schemaFields = append(schemaFields, k)
+	}
+	sort.Strings(schemaFields)
+
+	structFields := yamlFieldNames(types.ServiceLimits{})
+	sort.Strings(structFields)
+
+	if !reflect.DeepEqual(schemaFields, structFields) {
+		t.Errorf("schema limits properties %v do not match types.ServiceLimits yaml fields %v", schemaFields, structFields)
+	}
+}
+
+// TestServiceSchemaHooksMatchServiceHooks is the same guard for the hooks:
+// object.
+func TestServiceSchemaHooksMatchServiceHooks(t *testing.T) {
+	raw, err := os.ReadFile("schemas/service.schema.json")
+	if err != nil {
+		t.Fatalf("reading schemas/service.schema.json: %v", err)
+	}
+
+	var schema struct {
+		Properties struct {
+			Hooks struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"hooks"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("parsing schemas/service.schema.json: %v", err)
+	}
+
+	schemaFields := make([]string, 0, len(schema.Properties.Hooks.Properties))
+	for k := range schema.Properties.Hooks.Properties {
+		schemaFields = append(schemaFields, k)
+	}
+	sort.Strings(schemaFields)
+
+	structFields := yamlFieldNames(types.ServiceHooks{})
+	sort.Strings(structFields)
+
+	if !reflect.DeepEqual(schemaFields, structFields) {
+		t.Errorf("schema hooks properties %v do not match types.ServiceHooks yaml fields %v", schemaFields, structFields)
+	}
+}
+
 // yamlFieldNames returns the yaml tag name (stripped of ",omitempty" etc.) for
 // every field of v's type, skipping fields tagged "-".
 func yamlFieldNames(v any) []string {
@@ -201,3 +299,36 @@ func yamlFieldNames(v any) []string {
 	}
 	return names
 }
+
+// TestServiceSchemaHealthCheckMatchesServiceHealthCheck is the same guard for
+// the health_check: object.
+func TestServiceSchemaHealthCheckMatchesServiceHealthCheck(t *testing.T) {
+	raw, err := os.ReadFile("schemas/service.schema.json")
+	if err != nil {
+		t.Fatalf("reading schemas/service.schema.json: %v", err)
+	}
+
+	var schema struct {
+		Properties struct {
+			HealthCheck struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"health_check"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("parsing schemas/service.schema.json: %v", err)
+	}
+
+	schemaFields := make([]string, 0, len(schema.Properties.HealthCheck.Properties))
+	for k := range schema.Properties.HealthCheck.Properties {
+		schemaFields = append(schemaFields, k)
+	}
+	sort.Strings(schemaFields)
+
+	structFields := yamlFieldNames(types.ServiceHealthCheck{})
+	sort.Strings(structFields)
+
+	if !reflect.DeepEqual(schemaFields, structFields) {
+		t.Errorf("schema health_check properties %v do not match types.ServiceHealthCheck yaml fields %v", schemaFields, structFields)
+	}
+}